@@ -1,17 +1,28 @@
 package main
 
 import (
+	"context"
 	"crypto/rand"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"io"
 	"log"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"strings"
+	"syscall"
 	"time"
 
+	"github.com/ak68a/agentid-core/pkg/authz"
+	"github.com/ak68a/agentid-core/pkg/capability"
 	"github.com/ak68a/agentid-core/pkg/key"
+	"github.com/ak68a/agentid-core/pkg/key/pkcs11"
 	"github.com/ak68a/agentid-core/pkg/models"
+	"github.com/ak68a/agentid-core/pkg/replay"
+	"github.com/ak68a/agentid-core/pkg/revocation"
+	"github.com/ak68a/agentid-core/pkg/server"
 	"github.com/ak68a/agentid-core/pkg/signer"
 	"github.com/urfave/cli/v2"
 )
@@ -24,19 +35,45 @@ func main() {
 			{
 				Name:  "generate",
 				Usage: "Generate a new agent identity",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "keystore",
+						Usage: "Path of the encrypted keystore to write (default: build/agent_<address>.json)",
+					},
+					&cli.StringFlag{
+						Name:  "password-file",
+						Usage: "File containing the keystore password; without it the key is saved in plaintext",
+					},
+					&cli.BoolFlag{
+						Name:  "light-kdf",
+						Usage: "Use cheaper scrypt parameters for the keystore",
+					},
+					&cli.BoolFlag{
+						Name:  "mnemonic",
+						Usage: "Derive the key from a BIP-39 mnemonic; a new one is generated unless --mnemonic-file is given",
+					},
+					&cli.StringFlag{
+						Name:  "mnemonic-file",
+						Usage: "File containing an existing BIP-39 mnemonic to derive from",
+					},
+					&cli.UintFlag{
+						Name:  "index",
+						Usage: "Index of the agent under the derivation path",
+					},
+					&cli.StringFlag{
+						Name:  "derivation-path",
+						Usage: "BIP-32 path the agent index is appended to",
+						Value: key.DefaultAgentDerivationPath,
+					},
+				},
 				Action: func(c *cli.Context) error {
-					return generateAgent()
+					return generateAgent(c)
 				},
 			},
 			{
 				Name:  "create-claim",
 				Usage: "Create and sign a new claim",
-				Flags: []cli.Flag{
-					&cli.StringFlag{
-						Name:     "private-key",
-						Usage:    "Private key in hex format",
-						Required: true,
-					},
+				Flags: append(signerFlags(),
 					&cli.StringFlag{
 						Name:     "owner-did",
 						Usage:    "Owner's DID (e.g., did:web:acme-corp.com)",
@@ -61,7 +98,7 @@ func main() {
 						Usage: "Expiration time in hours from now",
 						Value: 24,
 					},
-				},
+				),
 				Action: func(c *cli.Context) error {
 					return createClaim(c)
 				},
@@ -80,6 +117,129 @@ func main() {
 					return verifyClaim(c)
 				},
 			},
+			{
+				Name:  "serve",
+				Usage: "Serve verification, authorization and revocation status over HTTP",
+				Flags: append(signerFlags(),
+					&cli.StringFlag{
+						Name:  "addr",
+						Usage: "Address to listen on",
+						Value: ":8080",
+					},
+					&cli.Int64Flag{
+						Name:  "max-body-size",
+						Usage: "Largest request body accepted, in bytes",
+						Value: server.DefaultMaxBodySize,
+					},
+					&cli.StringSliceFlag{
+						Name:  "trusted-issuer",
+						Usage: "DID trusted to issue authorizations, repeatable (/v1/authorize is disabled without one)",
+					},
+					&cli.StringFlag{
+						Name:  "nonce-db",
+						Usage: "bbolt database recording request nonces, making each request single-use",
+					},
+					&cli.DurationFlag{
+						Name:  "clock-skew",
+						Usage: "How far request timestamps may be from the local clock",
+						Value: replay.DefaultClockSkew,
+					},
+					&cli.StringFlag{
+						Name:  "revocation-feed",
+						Usage: "Revocation feed file or publisher URL to check credentials against",
+					},
+					&cli.StringFlag{
+						Name:  "revocation-issuer",
+						Usage: "DID of the revocation list issuer (required with --revocation-feed)",
+					},
+					&cli.StringFlag{
+						Name:  "revocation-list-id",
+						Usage: "ID of the revocation list (required with --revocation-feed)",
+					},
+					&cli.DurationFlag{
+						Name:  "revocation-sync-interval",
+						Usage: "How often to pull revocation list updates",
+						Value: time.Minute,
+					},
+				),
+				Action: func(c *cli.Context) error {
+					return serve(c)
+				},
+			},
+			{
+				Name:  "capability",
+				Usage: "Convert capabilities between action:scope[:qualifier] and their on-chain bytes32 IDs",
+				Subcommands: []*cli.Command{
+					{
+						Name:      "encode",
+						Usage:     "Print the bytes32 ID of each capability",
+						ArgsUsage: "action:scope[:qualifier]...",
+						Action: func(c *cli.Context) error {
+							return encodeCapabilities(c)
+						},
+					},
+					{
+						Name:      "decode",
+						Usage:     "Print the capability behind each well-known bytes32 ID",
+						ArgsUsage: "0xID...",
+						Action: func(c *cli.Context) error {
+							return decodeCapabilities(c)
+						},
+					},
+				},
+			},
+			{
+				Name:  "key",
+				Usage: "Move agent keys between hex and encrypted keystores",
+				Subcommands: []*cli.Command{
+					{
+						Name:  "import",
+						Usage: "Encrypt a hex private key into a keystore",
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:  "private-key-file",
+								Usage: "File containing the hex private key (default: read from stdin)",
+							},
+							&cli.StringFlag{
+								Name:     "keystore",
+								Usage:    "Path of the keystore to write",
+								Required: true,
+							},
+							&cli.StringFlag{
+								Name:     "password-file",
+								Usage:    "File containing the keystore password",
+								Required: true,
+							},
+							&cli.BoolFlag{
+								Name:  "light-kdf",
+								Usage: "Use cheaper scrypt parameters for the keystore",
+							},
+						},
+						Action: func(c *cli.Context) error {
+							return importKey(c)
+						},
+					},
+					{
+						Name:  "export",
+						Usage: "Decrypt a keystore and print the hex private key",
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:     "keystore",
+								Usage:    "Path of the keystore to read",
+								Required: true,
+							},
+							&cli.StringFlag{
+								Name:     "password-file",
+								Usage:    "File containing the keystore password",
+								Required: true,
+							},
+						},
+						Action: func(c *cli.Context) error {
+							return exportKey(c)
+						},
+					},
+				},
+			},
 		},
 	}
 
@@ -96,7 +256,50 @@ func ensureBuildDir() error {
 	return nil
 }
 
-func generateAgent() error {
+// signerFlags are the flags shared by commands that sign with the agent key
+func signerFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:  "keystore",
+			Usage: "Path to an encrypted keystore holding the agent key",
+		},
+		&cli.StringFlag{
+			Name:  "password-file",
+			Usage: "File containing the keystore password",
+		},
+		&cli.StringFlag{
+			Name:  "private-key",
+			Usage: "Private key in hex format (insecure: prefer --keystore)",
+		},
+		&cli.StringFlag{
+			Name:  "pkcs11-lib",
+			Usage: "Path to a PKCS#11 module; signs with a token key instead of --private-key",
+		},
+		&cli.StringFlag{
+			Name:  "pkcs11-token",
+			Usage: "Label of the PKCS#11 token holding the key",
+		},
+		&cli.StringFlag{
+			Name:    "pkcs11-pin",
+			Usage:   "User PIN of the PKCS#11 token",
+			EnvVars: []string{"AGENTID_PKCS11_PIN"},
+		},
+		&cli.StringFlag{
+			Name:  "pkcs11-key",
+			Usage: "Label of the key on the PKCS#11 token",
+		},
+	}
+}
+
+// keystoreParams returns the KDF parameters selected by --light-kdf
+func keystoreParams(c *cli.Context) key.KeystoreParams {
+	if c.Bool("light-kdf") {
+		return key.LightKeystoreParams
+	}
+	return key.StandardKeystoreParams
+}
+
+func generateAgent(c *cli.Context) error {
 	fmt.Println("🔐 Generating new agent identity...")
 	
 	// Ensure build directory exists
@@ -105,9 +308,35 @@ func generateAgent() error {
 	}
 	
 	// Generate a new agent key
-	agentKey, err := key.GenerateAgentKey()
+	agentKey, err := newAgentKey(c)
 	if err != nil {
-		return fmt.Errorf("failed to generate agent key: %w", err)
+		return err
+	}
+
+	filename := c.String("keystore")
+	if filename == "" {
+		filename = filepath.Join("build", fmt.Sprintf("agent_%s.json", agentKey.Address.Hex()[:8]))
+	}
+
+	// With a password, save an encrypted keystore and never print the key
+	if passwordFile := c.String("password-file"); passwordFile != "" {
+		password, err := key.ReadPasswordFile(passwordFile)
+		if err != nil {
+			return err
+		}
+		if err := key.SaveKeystore(filename, agentKey, password, keystoreParams(c)); err != nil {
+			return err
+		}
+
+		fmt.Printf("\n✅ Agent Generated:\n")
+		fmt.Printf("   DID: %s\n", agentKey.DID)
+		fmt.Printf("   Address: %s\n", agentKey.Address.Hex())
+		fmt.Printf("   Public Key: %s\n", agentKey.GetPublicKeyHex())
+		fmt.Printf("\n🔒 Encrypted keystore saved to: %s\n", filename)
+		return nil
+	}
+	if c.String("keystore") != "" {
+		return fmt.Errorf("--keystore requires --password-file")
 	}
 
 	// Print the agent details
@@ -130,28 +359,169 @@ func generateAgent() error {
 		return fmt.Errorf("failed to marshal agent data: %w", err)
 	}
 	
-	filename := filepath.Join("build", fmt.Sprintf("agent_%s.json", agentKey.Address.Hex()[:8]))
 	if err := os.WriteFile(filename, jsonData, 0600); err != nil {
 		return fmt.Errorf("failed to save agent data: %w", err)
 	}
 	
 	fmt.Printf("\n📄 Agent data saved to: %s\n", filename)
+	fmt.Printf("⚠️  The private key is stored unencrypted; use --password-file to write a keystore instead\n")
 	return nil
 }
 
-func createClaim(c *cli.Context) error {
-	// Import the agent key
+// newAgentKey generates a random key, or derives one from a mnemonic when requested
+func newAgentKey(c *cli.Context) (*key.AgentKey, error) {
+	mnemonicFile := c.String("mnemonic-file")
+	if !c.Bool("mnemonic") && mnemonicFile == "" {
+		agentKey, err := key.GenerateAgentKey()
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate agent key: %w", err)
+		}
+		return agentKey, nil
+	}
+
+	index := c.Uint("index")
+	if index >= 1<<31 {
+		return nil, fmt.Errorf("--index must be below 2^31")
+	}
+
+	var mnemonic string
+	if mnemonicFile != "" {
+		data, err := os.ReadFile(mnemonicFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read mnemonic file: %w", err)
+		}
+		mnemonic = string(data)
+	} else {
+		var err error
+		mnemonic, err = key.NewMnemonic(256)
+		if err != nil {
+			return nil, err
+		}
+		fmt.Printf("\n📝 Mnemonic (write it down, it recovers every agent derived from it):\n   %s\n", mnemonic)
+	}
+
+	wallet, err := key.NewHDWalletFromMnemonic(mnemonic, "")
+	if err != nil {
+		return nil, err
+	}
+	if err := wallet.SetBasePath(c.String("derivation-path")); err != nil {
+		return nil, err
+	}
+	agentKey, err := wallet.DeriveAgent(uint32(index))
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive agent key: %w", err)
+	}
+	fmt.Printf("\n🌱 Derived agent %d at %s/%d\n", index, c.String("derivation-path"), index)
+	return agentKey, nil
+}
+
+// importKey encrypts a hex private key read from a file or stdin into a keystore
+func importKey(c *cli.Context) error {
+	var hexKey []byte
+	var err error
+	if path := c.String("private-key-file"); path != "" {
+		hexKey, err = os.ReadFile(path)
+	} else {
+		hexKey, err = io.ReadAll(os.Stdin)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read private key: %w", err)
+	}
+
+	agentKey, err := key.ImportFromHex(strings.TrimSpace(string(hexKey)))
+	if err != nil {
+		return fmt.Errorf("failed to import private key: %w", err)
+	}
+	password, err := key.ReadPasswordFile(c.String("password-file"))
+	if err != nil {
+		return err
+	}
+
+	filename := c.String("keystore")
+	if err := key.SaveKeystore(filename, agentKey, password, keystoreParams(c)); err != nil {
+		return err
+	}
+
+	fmt.Printf("✅ Imported %s\n", agentKey.DID)
+	fmt.Printf("🔒 Encrypted keystore saved to: %s\n", filename)
+	return nil
+}
+
+// exportKey decrypts a keystore and prints the hex private key to stdout
+func exportKey(c *cli.Context) error {
+	password, err := key.ReadPasswordFile(c.String("password-file"))
+	if err != nil {
+		return err
+	}
+	agentKey, err := key.LoadKeystore(c.String("keystore"), password)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(agentKey.GetPrivateKeyHex())
+	return nil
+}
+
+// loadSigner returns the signer selected by the command flags.
+// The returned function releases any resources held by the signer.
+func loadSigner(c *cli.Context) (key.Signer, func(), error) {
+	if library := c.String("pkcs11-lib"); library != "" {
+		hsmSigner, err := pkcs11.NewSigner(pkcs11.Config{
+			LibraryPath: library,
+			TokenLabel:  c.String("pkcs11-token"),
+			PIN:         c.String("pkcs11-pin"),
+			KeyLabel:    c.String("pkcs11-key"),
+		})
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to open PKCS#11 key: %w", err)
+		}
+		return hsmSigner, func() { hsmSigner.Close() }, nil
+	}
+
+	if path := c.String("keystore"); path != "" {
+		passwordFile := c.String("password-file")
+		if passwordFile == "" {
+			return nil, nil, fmt.Errorf("--keystore requires --password-file")
+		}
+		password, err := key.ReadPasswordFile(passwordFile)
+		if err != nil {
+			return nil, nil, err
+		}
+		agentKey, err := key.LoadKeystore(path, password)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to open keystore: %w", err)
+		}
+		return agentKey, func() {}, nil
+	}
+
 	privateKey := c.String("private-key")
+	if privateKey == "" {
+		return nil, nil, fmt.Errorf("one of --keystore, --private-key or --pkcs11-lib is required")
+	}
 	agentKey, err := key.ImportFromHex(privateKey)
 	if err != nil {
-		return fmt.Errorf("failed to import private key: %w", err)
+		return nil, nil, fmt.Errorf("failed to import private key: %w", err)
+	}
+	return agentKey, func() {}, nil
+}
+
+func createClaim(c *cli.Context) error {
+	// Load the agent's signing key
+	agentKey, closeSigner, err := loadSigner(c)
+	if err != nil {
+		return err
 	}
+	defer closeSigner()
 
 	// Create the claim
 	ownerDID := c.String("owner-did")
 	action := c.String("action")
 	scope := c.String("scope")
 	expiresIn := c.Int64("expires-in")
+	granted, err := capability.New(action, scope)
+	if err != nil {
+		return err
+	}
 	
 	// Generate a nonce
 	nonceBytes := make([]byte, 16)
@@ -165,7 +535,7 @@ func createClaim(c *cli.Context) error {
 	
 	// Create the delegation claim
 	claim := &models.DelegationClaim{
-		DelegatorDID: agentKey.DID,
+		DelegatorDID: agentKey.GetDID(),
 		DelegateDID:  ownerDID,
 		Action:       action,
 		Scope:        scope,
@@ -174,7 +544,7 @@ func createClaim(c *cli.Context) error {
 		Nonce:        nonce,
 		Type:         models.AgentAuthorizationCredentialType,
 		Context:      models.StandardContexts,
-		Issuer:       agentKey.DID,
+		Issuer:       agentKey.GetDID(),
 		Subject:      ownerDID,
 		Constraints:  make(map[string]interface{}),
 	}
@@ -196,11 +566,15 @@ func createClaim(c *cli.Context) error {
 	fmt.Printf("   Delegate DID: %s\n", claim.DelegateDID)
 	fmt.Printf("   Action: %s\n", claim.Action)
 	fmt.Printf("   Scope: %s\n", claim.Scope)
+	fmt.Printf("   Capability: %s (%s)\n", granted, granted.ID())
 	if maxAmount, ok := claim.Constraints["max_amount"].(string); ok {
 		fmt.Printf("   Max Amount: %s\n", maxAmount)
 	}
 	fmt.Printf("   Expires: %s\n", time.Unix(claim.ExpiresAt, 0).Format(time.RFC3339))
 	fmt.Printf("   Nonce: %s\n", claim.Nonce)
+	if id, err := claim.CredentialID(); err == nil {
+		fmt.Printf("   Credential ID: %s\n", id)
+	}
 	if claim.Proof != nil {
 		fmt.Printf("   Proof: %s\n", claim.Proof.ProofValue[:16] + "...")
 	}
@@ -225,6 +599,38 @@ func createClaim(c *cli.Context) error {
 	return nil
 }
 
+func encodeCapabilities(c *cli.Context) error {
+	if c.NArg() == 0 {
+		return fmt.Errorf("no capabilities given")
+	}
+	for _, arg := range c.Args().Slice() {
+		granted, err := capability.Parse(arg)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s %s\n", granted.ID(), granted)
+	}
+	return nil
+}
+
+func decodeCapabilities(c *cli.Context) error {
+	if c.NArg() == 0 {
+		return fmt.Errorf("no capability IDs given")
+	}
+	for _, arg := range c.Args().Slice() {
+		id, err := capability.ParseID(arg)
+		if err != nil {
+			return err
+		}
+		granted, ok := capability.Default.Lookup(id)
+		if !ok {
+			return fmt.Errorf("%s is not a well-known capability", id)
+		}
+		fmt.Printf("%s %s\n", id, granted)
+	}
+	return nil
+}
+
 func verifyClaim(c *cli.Context) error {
 	// Read claim file
 	claimFile := c.String("claim-file")
@@ -264,6 +670,9 @@ func verifyClaim(c *cli.Context) error {
 		fmt.Printf("   Delegate: %s\n", claim.DelegateDID)
 		fmt.Printf("   Action: %s\n", claim.Action)
 		fmt.Printf("   Scope: %s\n", claim.Scope)
+		if granted, err := capability.FromClaim(&claim); err == nil {
+			fmt.Printf("   Capability: %s (%s)\n", granted, granted.ID())
+		}
 		fmt.Printf("   Expires: %s\n", time.Unix(claim.ExpiresAt, 0).Format(time.RFC3339))
 		if claim.IsExpired() {
 			fmt.Printf("   ⚠️  Claim has expired\n")
@@ -273,4 +682,65 @@ func verifyClaim(c *cli.Context) error {
 	}
 	
 	return nil
-} 
\ No newline at end of file
+} 
+
+// serve runs the HTTP service until interrupted, signing answers with the agent key
+func serve(c *cli.Context) error {
+	responder, closeSigner, err := loadSigner(c)
+	if err != nil {
+		return err
+	}
+	defer closeSigner()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	opts := []server.Option{
+		server.WithMaxBodySize(c.Int64("max-body-size")),
+		server.WithEngineOptions(authz.WithClockSkew(c.Duration("clock-skew"))),
+	}
+
+	if issuers := c.StringSlice("trusted-issuer"); len(issuers) > 0 {
+		opts = append(opts, server.WithEngineOptions(authz.WithTrustedIssuers(issuers...)))
+	} else {
+		fmt.Printf("⚠️  No --trusted-issuer given, /v1/authorize is disabled\n")
+		opts = append(opts, server.WithoutAuthorization())
+	}
+
+	if path := c.String("nonce-db"); path != "" {
+		nonces, err := replay.NewBoltStore(path)
+		if err != nil {
+			return err
+		}
+		defer nonces.Close()
+		opts = append(opts, server.WithEngineOptions(authz.WithNonceStore(nonces)))
+	}
+
+	if feed := c.String("revocation-feed"); feed != "" {
+		issuer, listID := c.String("revocation-issuer"), c.String("revocation-list-id")
+		if issuer == "" || listID == "" {
+			return fmt.Errorf("--revocation-feed requires --revocation-issuer and --revocation-list-id")
+		}
+		var source revocation.Source = &revocation.FileSource{Path: feed}
+		if strings.HasPrefix(feed, "http://") || strings.HasPrefix(feed, "https://") {
+			source = &revocation.HTTPSource{URL: feed}
+		}
+		syncer := revocation.NewSyncer(source, issuer, listID)
+		if err := syncer.Sync(ctx); err != nil {
+			return fmt.Errorf("failed to load revocation list: %w", err)
+		}
+		go func() {
+			if err := syncer.Run(ctx, c.Duration("revocation-sync-interval")); err != nil && ctx.Err() == nil {
+				log.Printf("revocation list sync stopped: %v", err)
+			}
+		}()
+		opts = append(opts, server.WithRevocationChecker(syncer))
+	}
+
+	fmt.Printf("🌐 Serving as %s on %s\n", responder.GetDID(), c.String("addr"))
+	if err := server.New(responder, opts...).ListenAndServe(ctx, c.String("addr")); err != nil {
+		return err
+	}
+	fmt.Printf("👋 Server stopped\n")
+	return nil
+}
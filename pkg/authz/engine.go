@@ -0,0 +1,646 @@
+// Package authz decides AuthorizationRequests from the credentials an agent presents
+// and answers with a signed AuthorizationResponse.
+package authz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ak68a/agentid-core/pkg/budget"
+	"github.com/ak68a/agentid-core/pkg/did"
+	"github.com/ak68a/agentid-core/pkg/key"
+	"github.com/ak68a/agentid-core/pkg/models"
+	"github.com/ak68a/agentid-core/pkg/replay"
+	"github.com/ak68a/agentid-core/pkg/signer"
+)
+
+// Credentials are what an agent presents to prove its authority. Authority comes
+// from an AgentClaim issued by the owner, a delegation chain ending at the agent,
+// or both, in which case the chain's root delegator must be the claim's agent.
+type Credentials struct {
+	Ownership     *models.OwnershipClaim  `json:"ownership,omitempty"`     // Optional link from the agent to its owner
+	Authorization *models.AgentClaim      `json:"authorization,omitempty"` // Grant of an action and scope to an agent
+	Delegations   *models.DelegationChain `json:"delegations,omitempty"`   // Delegations, root first, ending at the agent
+}
+
+// RegionResolver determines the ISO 3166 region a request comes from, e.g. by
+// geolocating the connection it arrived on. The request's own fields are
+// chosen by the requester and cannot be trusted for this.
+type RegionResolver interface {
+	ResolveRegion(ctx context.Context, req *models.AuthorizationRequest) (string, error)
+}
+
+// RegionResolverFunc adapts a function to the RegionResolver interface
+type RegionResolverFunc func(ctx context.Context, req *models.AuthorizationRequest) (string, error)
+
+// ResolveRegion calls f(ctx, req)
+func (f RegionResolverFunc) ResolveRegion(ctx context.Context, req *models.AuthorizationRequest) (string, error) {
+	return f(ctx, req)
+}
+
+// Engine evaluates authorization requests and signs the decisions
+type Engine struct {
+	responder      *signer.ClaimSigner
+	responderDID   string
+	verifier       *signer.ClaimSigner
+	revocations    RevocationChecker
+	statuses       StatusChecker
+	ledger         budget.Ledger
+	rates          models.RateCounter
+	regions        RegionResolver
+	nonces         replay.NonceStore
+	clockSkew      time.Duration
+	trustedIssuers map[string]bool
+	anyIssuer      bool
+	now            func() time.Time
+}
+
+// Option configures an Engine
+type Option func(*engineOptions)
+
+type engineOptions struct {
+	signerOpts     []signer.Option
+	revocations    RevocationChecker
+	statuses       StatusChecker
+	ledger         budget.Ledger
+	rates          models.RateCounter
+	regions        RegionResolver
+	nonces         replay.NonceStore
+	clockSkew      time.Duration
+	trustedIssuers []string
+	anyIssuer      bool
+	now            func() time.Time
+}
+
+// WithResolver sets the DID resolver used to verify credential signatures
+func WithResolver(resolver did.Resolver) Option {
+	return func(o *engineOptions) {
+		o.signerOpts = append(o.signerOpts, signer.WithResolver(resolver))
+	}
+}
+
+// WithDomain sets the EIP-712 domain responses are signed under
+func WithDomain(domain models.EIP712Domain) Option {
+	return func(o *engineOptions) {
+		o.signerOpts = append(o.signerOpts, signer.WithDomain(domain))
+	}
+}
+
+// WithRevocationChecker makes the engine reject revoked credentials. A revoked
+// delegation fails every chain through it, but only if the revocation is signed
+// by a delegator at or above it.
+func WithRevocationChecker(checker RevocationChecker) Option {
+	return func(o *engineOptions) {
+		o.revocations = checker
+		o.signerOpts = append(o.signerOpts, signer.WithRevocationChecker(checker))
+	}
+}
+
+// WithStatusChecker makes the engine check the status list entries of credentials
+func WithStatusChecker(checker StatusChecker) Option {
+	return func(o *engineOptions) {
+		o.statuses = checker
+	}
+}
+
+// WithLedger enforces amount limits cumulatively by recording every authorized
+// amount against the authorization and each delegation in the chain
+func WithLedger(ledger budget.Ledger) Option {
+	return func(o *engineOptions) {
+		o.ledger = ledger
+	}
+}
+
+// WithRateCounter sets the counter rate_limit constraints are enforced with.
+// Without one, delegations with a rate limit are rejected. Denied requests
+// release the slots they took, so only authorized requests count.
+func WithRateCounter(rates models.RateCounter) Option {
+	return func(o *engineOptions) {
+		o.rates = rates
+	}
+}
+
+// WithRegionResolver sets where geo constraints get the request's region from.
+// Without one, delegations with a geo constraint are rejected.
+func WithRegionResolver(regions RegionResolver) Option {
+	return func(o *engineOptions) {
+		o.regions = regions
+	}
+}
+
+// WithNonceStore makes every request nonce single-use. Authorized requests have
+// their nonce recorded per agent until their timestamp leaves the clock skew
+// window, after which the timestamp check rejects them instead. Unless
+// WithClockSkew is also given, the window is replay.DefaultClockSkew. A nonce
+// is released again if the request's budget turns out to be insufficient.
+func WithNonceStore(store replay.NonceStore) Option {
+	return func(o *engineOptions) {
+		o.nonces = store
+	}
+}
+
+// WithClockSkew rejects requests whose timestamp is more than skew away from the engine's clock
+func WithClockSkew(skew time.Duration) Option {
+	return func(o *engineOptions) {
+		o.clockSkew = skew
+	}
+}
+
+// WithTrustedIssuers only accepts authority rooted at one of the given DIDs.
+// An engine without trusted issuers denies every request unless WithAnyIssuer
+// is given.
+func WithTrustedIssuers(dids ...string) Option {
+	return func(o *engineOptions) {
+		o.trustedIssuers = append(o.trustedIssuers, dids...)
+	}
+}
+
+// WithAnyIssuer accepts authority rooted at any DID other than the agent's own.
+// Anyone can issue credentials, including an OwnershipClaim over any agent, so
+// this only shows that the credentials are consistent, not who stands behind
+// them. Use it where the relying party checks the authority in the response.
+func WithAnyIssuer() Option {
+	return func(o *engineOptions) {
+		o.anyIssuer = true
+	}
+}
+
+// WithClock sets the time source used for expiry checks and timestamps
+func WithClock(now func() time.Time) Option {
+	return func(o *engineOptions) {
+		o.now = now
+	}
+}
+
+// NewEngine creates an Engine that signs its responses with the responder's key
+func NewEngine(responder key.Signer, opts ...Option) *Engine {
+	o := &engineOptions{now: time.Now}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	e := &Engine{
+		responder:    signer.NewClaimSigner(responder, o.signerOpts...),
+		responderDID: responder.GetDID(),
+		verifier:     signer.NewClaimSigner(nil, o.signerOpts...),
+		revocations:  o.revocations,
+		statuses:     o.statuses,
+		ledger:       o.ledger,
+		rates:        o.rates,
+		regions:      o.regions,
+		nonces:       o.nonces,
+		clockSkew:    o.clockSkew,
+		anyIssuer:    o.anyIssuer,
+		now:          o.now,
+	}
+	if e.nonces != nil && e.clockSkew == 0 {
+		e.clockSkew = replay.DefaultClockSkew
+	}
+	if len(o.trustedIssuers) > 0 {
+		e.trustedIssuers = make(map[string]bool)
+		for _, issuer := range o.trustedIssuers {
+			e.trustedIssuers[issuer] = true
+		}
+	}
+	return e
+}
+
+// Authorize evaluates a request against the presented credentials and returns a
+// signed response. A denial is a response with Authorized false and a Reason;
+// an error is only returned if the response cannot be produced. The response
+// Metadata binds it to the request and, once authorized, names the "authority"
+// the grant is rooted at.
+func (e *Engine) Authorize(ctx context.Context, req *models.AuthorizationRequest, creds *Credentials) (*models.AuthorizationResponse, error) {
+	at := e.now()
+	now := at.Unix()
+	response := &models.AuthorizationResponse{
+		Timestamp:    now,
+		ResponderDID: e.responderDID,
+		Metadata:     map[string]interface{}{},
+	}
+	if req != nil {
+		// Bind the response to the request it answers
+		response.Metadata["request_id"] = req.RequestID
+		response.Metadata["request_nonce"] = req.Nonce
+		response.Metadata["agent_did"] = req.AgentDID
+	}
+
+	slots := &rateSlots{counter: e.rates}
+	grant, err := e.evaluate(ctx, req, creds, now, slots)
+	recorded := false
+	if err == nil && e.nonces != nil {
+		// Recorded only once authorized, so denied requests cannot use up an agent's nonces
+		err = e.nonces.Record(ctx, req.AgentDID, req.Nonce, time.Unix(req.Timestamp, 0).Add(e.clockSkew))
+		recorded = err == nil
+	}
+	if err == nil && len(grant.limits) > 0 {
+		err = e.spend(ctx, grant, at)
+	}
+	if err != nil {
+		// The request is denied, so it uses up neither its nonce nor rate limit slots
+		if releaseErr := e.release(ctx, req, recorded, slots); releaseErr != nil {
+			err = fmt.Errorf("%w (%v)", err, releaseErr)
+		}
+	}
+	if err != nil {
+		response.Reason = err.Error()
+	} else {
+		response.Authorized = true
+		response.Reason = fmt.Sprintf("%s on %s authorized by %s", req.TargetAction, req.TargetScope, grant.authority)
+		response.Metadata["authority"] = grant.authority
+		response.ValidUntil = grant.validUntil
+		if grant.remaining != nil {
+			response.RemainingBudget = grant.remaining.String()
+		}
+	}
+
+	if err := e.responder.SignCredentialContext(ctx, response); err != nil {
+		return nil, fmt.Errorf("failed to sign authorization response: %w", err)
+	}
+	return response, nil
+}
+
+// release gives back the nonce, if it was recorded, and the rate limit slots
+// taken for a request that was denied
+func (e *Engine) release(ctx context.Context, req *models.AuthorizationRequest, recorded bool, slots *rateSlots) error {
+	var errs []error
+	if recorded {
+		if err := e.nonces.Release(ctx, req.AgentDID, req.Nonce); err != nil {
+			errs = append(errs, fmt.Errorf("nonce could not be released: %w", err))
+		}
+	}
+	if err := slots.release(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("rate limit slots could not be released: %w", err))
+	}
+	return errors.Join(errs...)
+}
+
+// rateSlots remembers the rate limit slots taken for a request, so they can
+// be given back if it is denied
+type rateSlots struct {
+	counter models.RateCounter
+	taken   []rateSlot
+}
+
+type rateSlot struct {
+	key string
+	at  time.Time
+}
+
+// Take takes a slot from the engine's counter and remembers it
+func (r *rateSlots) Take(ctx context.Context, key string, limit int, window time.Duration, at time.Time) (bool, error) {
+	ok, err := r.counter.Take(ctx, key, limit, window, at)
+	if ok && err == nil {
+		r.taken = append(r.taken, rateSlot{key: key, at: at})
+	}
+	return ok, err
+}
+
+// Release gives a slot back to the engine's counter
+func (r *rateSlots) Release(ctx context.Context, key string, at time.Time) error {
+	return r.counter.Release(ctx, key, at)
+}
+
+func (r *rateSlots) release(ctx context.Context) error {
+	var errs []error
+	for _, slot := range r.taken {
+		if err := r.counter.Release(ctx, slot.key, slot.at); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	r.taken = nil
+	return errors.Join(errs...)
+}
+
+// grant is the authority an agent was found to hold for a request
+type grant struct {
+	authority  string   // DID the authority is rooted at
+	validUntil int64    // Earliest expiry across the credentials, 0 if none expire
+	remaining  *big.Int // Budget left after the request, nil if unlimited
+	amount     *big.Int // Amount requested
+	limits     []budget.Limit
+}
+
+// evaluate returns the agent's grant for the request, or the reason it is denied
+func (e *Engine) evaluate(ctx context.Context, req *models.AuthorizationRequest, creds *Credentials, now int64, slots *rateSlots) (*grant, error) {
+	if req == nil || req.AgentDID == "" || req.TargetAction == "" {
+		return nil, fmt.Errorf("malformed authorization request")
+	}
+	if e.nonces != nil && req.Nonce == "" {
+		return nil, fmt.Errorf("request has no nonce")
+	}
+	if e.clockSkew > 0 {
+		if err := replay.CheckTimestamp(req.Timestamp, time.Unix(now, 0), e.clockSkew); err != nil {
+			return nil, fmt.Errorf("request %w", err)
+		}
+	}
+	if creds == nil {
+		return nil, fmt.Errorf("no credentials presented")
+	}
+	// An empty chain is no chain, so it cannot stand in for the agent check below
+	delegations := creds.Delegations
+	if delegations != nil && len(delegations.Delegations) == 0 {
+		delegations = nil
+	}
+	if creds.Authorization == nil && delegations == nil {
+		return nil, fmt.Errorf("no credentials presented")
+	}
+
+	var (
+		action, scope string
+		limit         *big.Int
+		g             = &grant{}
+	)
+
+	if claim := creds.Authorization; claim != nil {
+		if err := e.checkAgentClaim(ctx, claim, now); err != nil {
+			return nil, err
+		}
+		action, scope = claim.Action, claim.Scope
+		if claim.MaxAmount != "" {
+			amount, err := models.ParseAmount(claim.MaxAmount)
+			if err != nil {
+				return nil, fmt.Errorf("authorization max amount: %w", err)
+			}
+			limit = amount
+		}
+		g.authority = claim.Issuer
+		if g.authority == "" {
+			g.authority = claim.OwnerDID
+		}
+		g.validUntil = claim.ExpiresAt
+
+		if delegations == nil && claim.AgentDID != req.AgentDID {
+			return nil, fmt.Errorf("authorization is for %s, not %s", claim.AgentDID, req.AgentDID)
+		}
+	}
+
+	if chain := delegations; chain != nil {
+		if err := e.checkChain(ctx, chain, now); err != nil {
+			return nil, err
+		}
+		root, leaf := chain.GetRootDelegation(), chain.GetLeafDelegation()
+		if leaf.DelegateDID != req.AgentDID {
+			return nil, fmt.Errorf("delegation chain ends at %s, not %s", leaf.DelegateDID, req.AgentDID)
+		}
+		if err := e.evaluateConstraints(ctx, req, chain, now, slots); err != nil {
+			return nil, err
+		}
+
+		if claim := creds.Authorization; claim != nil {
+			// The chain re-delegates the authorization, so it may only narrow it
+			if root.DelegatorDID != claim.AgentDID {
+				return nil, fmt.Errorf("delegation chain starts at %s, not the authorized agent %s", root.DelegatorDID, claim.AgentDID)
+			}
+			if !models.PatternCovers(claim.Action, root.Action) || !models.PatternCovers(claim.Scope, root.Scope) {
+				return nil, fmt.Errorf("delegation chain exceeds the authorization's action or scope")
+			}
+		} else {
+			g.authority = root.DelegatorDID
+		}
+
+		action, scope = leaf.Action, leaf.Scope
+		if value, ok := leaf.Constraints[models.ConstraintMaxAmount]; ok {
+			amount, err := models.ParseAmount(value)
+			if err != nil {
+				return nil, fmt.Errorf("delegation max amount: %w", err)
+			}
+			if limit == nil || amount.Cmp(limit) < 0 {
+				limit = amount
+			}
+		}
+		for _, delegation := range chain.Delegations {
+			g.validUntil = earliest(g.validUntil, delegation.ExpiresAt)
+		}
+	}
+
+	// The agent the authority granted its authority to
+	owned := req.AgentDID
+	if creds.Authorization != nil {
+		owned = creds.Authorization.AgentDID
+	}
+	switch {
+	case e.trustedIssuers != nil:
+		if !e.trustedIssuers[g.authority] {
+			return nil, fmt.Errorf("authority %s is not a trusted issuer", g.authority)
+		}
+	case e.anyIssuer:
+		if g.authority == owned {
+			return nil, fmt.Errorf("agent %s cannot authorize itself", owned)
+		}
+	default:
+		return nil, fmt.Errorf("no trusted issuers are configured")
+	}
+
+	if ownership := creds.Ownership; ownership != nil {
+		if err := e.checkOwnership(ctx, ownership, g.authority, owned, now); err != nil {
+			return nil, err
+		}
+		g.validUntil = earliest(g.validUntil, ownership.ExpiresAt)
+	}
+
+	if !models.PatternCovers(action, req.TargetAction) {
+		return nil, fmt.Errorf("action %s is not authorized (granted %s)", req.TargetAction, action)
+	}
+	if !models.PatternCovers(scope, req.TargetScope) {
+		return nil, fmt.Errorf("scope %s is not authorized (granted %s)", req.TargetScope, scope)
+	}
+
+	g.amount = new(big.Int)
+	if req.Amount != "" {
+		amount, err := models.ParseAmount(req.Amount)
+		if err != nil {
+			return nil, fmt.Errorf("request amount: %w", err)
+		}
+		g.amount = amount
+	}
+	if limit != nil {
+		if g.amount.Cmp(limit) > 0 {
+			return nil, fmt.Errorf("amount %s exceeds the limit of %s", g.amount, limit)
+		}
+		g.remaining = new(big.Int).Sub(limit, g.amount)
+	}
+
+	if e.ledger != nil {
+		limits, err := budget.ChainLimits(creds.Authorization, delegations)
+		if err != nil {
+			return nil, err
+		}
+		g.limits = limits
+	}
+	return g, nil
+}
+
+// spend records the grant's amount in the ledger and updates its remaining budget
+func (e *Engine) spend(ctx context.Context, g *grant, at time.Time) error {
+	remaining, err := e.ledger.Spend(ctx, g.limits, g.amount, at)
+	if errors.Is(err, budget.ErrInsufficientBudget) {
+		return err
+	}
+	if err != nil {
+		return fmt.Errorf("budget is unavailable: %w", err)
+	}
+	g.remaining = remaining
+	return nil
+}
+
+// checkAgentClaim verifies an owner's authorization of an agent
+func (e *Engine) checkAgentClaim(ctx context.Context, claim *models.AgentClaim, now int64) error {
+	if claim.Status == models.StatusRevoked || claim.Status == models.StatusSuspended {
+		return fmt.Errorf("authorization is %s", claim.Status)
+	}
+	if expired(claim.ExpiresAt, now) {
+		return fmt.Errorf("authorization expired at %d", claim.ExpiresAt)
+	}
+	if value, ok := claim.Metadata[models.ConstraintTime]; ok {
+		constraint, err := models.ParseConstraint(models.ConstraintTime, value)
+		if err != nil {
+			return fmt.Errorf("authorization: %w", err)
+		}
+		if err := constraint.(*models.TimeConstraint).Check(time.Unix(now, 0)); err != nil {
+			return fmt.Errorf("authorization: %w", err)
+		}
+	}
+	if err := e.verify(ctx, claim, "authorization"); err != nil {
+		return err
+	}
+	if err := e.checkStatus(ctx, claim, claim.CredentialStatus, "authorization"); err != nil {
+		return err
+	}
+	return e.checkRevocation(ctx, claim, claim.AgentDID, "authorization")
+}
+
+// checkOwnership verifies that agentDID is owned by the authority
+func (e *Engine) checkOwnership(ctx context.Context, claim *models.OwnershipClaim, authority, agentDID string, now int64) error {
+	if claim.AgentDID != agentDID {
+		return fmt.Errorf("ownership is of %s, not %s", claim.AgentDID, agentDID)
+	}
+	if claim.OwnerDID == claim.AgentDID {
+		return fmt.Errorf("agent %s cannot own itself", claim.AgentDID)
+	}
+	if claim.OwnerDID != authority {
+		return fmt.Errorf("agent is owned by %s, not the authority %s", claim.OwnerDID, authority)
+	}
+	if expired(claim.ExpiresAt, now) {
+		return fmt.Errorf("ownership expired at %d", claim.ExpiresAt)
+	}
+	if err := e.verify(ctx, claim, "ownership"); err != nil {
+		return err
+	}
+	if err := e.checkStatus(ctx, claim, claim.CredentialStatus, "ownership"); err != nil {
+		return err
+	}
+	return e.checkRevocation(ctx, claim, claim.AgentDID, "ownership")
+}
+
+// checkChain verifies every signature in a delegation chain, its structure and its attenuation
+func (e *Engine) checkChain(ctx context.Context, chain *models.DelegationChain, now int64) error {
+	for i, delegation := range chain.Delegations {
+		if expired(delegation.ExpiresAt, now) {
+			return fmt.Errorf("delegation %d expired at %d", i, delegation.ExpiresAt)
+		}
+		if err := e.verify(ctx, delegation, fmt.Sprintf("delegation %d", i)); err != nil {
+			return err
+		}
+		if err := e.checkStatus(ctx, delegation, delegation.CredentialStatus, fmt.Sprintf("delegation %d", i)); err != nil {
+			return err
+		}
+	}
+	if err := e.verifier.CheckChainRevocations(ctx, chain); err != nil {
+		return err
+	}
+	if !chain.ValidateChainAt(time.Unix(now, 0)) {
+		return fmt.Errorf("invalid delegation chain: %s", chain.Reason)
+	}
+	if err := chain.ValidateChainConstraints(); err != nil {
+		return fmt.Errorf("invalid delegation chain: %w", err)
+	}
+	return nil
+}
+
+// evaluateConstraints evaluates the typed constraints of every delegation in the chain against the request
+func (e *Engine) evaluateConstraints(ctx context.Context, req *models.AuthorizationRequest, chain *models.DelegationChain, now int64, slots *rateSlots) error {
+	constraintReq := &models.ConstraintRequest{
+		AuthorizationRequest: req,
+		Time:                 time.Unix(now, 0),
+	}
+	if e.rates != nil {
+		constraintReq.Rates = slots
+	}
+	if e.regions != nil && hasConstraint(chain, models.ConstraintGeo) {
+		region, err := e.regions.ResolveRegion(ctx, req)
+		if err != nil {
+			return fmt.Errorf("region of the request could not be resolved: %w", err)
+		}
+		constraintReq.Region = region
+	}
+	for i, delegation := range chain.Delegations {
+		if err := delegation.EvaluateConstraints(ctx, constraintReq); err != nil {
+			return fmt.Errorf("delegation %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// hasConstraint reports whether any delegation in the chain has the named constraint
+func hasConstraint(chain *models.DelegationChain, name string) bool {
+	for _, delegation := range chain.Delegations {
+		if _, ok := delegation.Constraints[name]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (e *Engine) verify(ctx context.Context, credential interface{}, name string) error {
+	valid, err := e.verifier.VerifyCredentialContext(ctx, credential)
+	if err != nil {
+		return fmt.Errorf("%s could not be verified: %w", name, err)
+	}
+	if !valid {
+		return fmt.Errorf("%s has an invalid signature", name)
+	}
+	return nil
+}
+
+func (e *Engine) checkRevocation(ctx context.Context, credential models.Credential, agentDID, name string) error {
+	if e.revocations == nil {
+		return nil
+	}
+	credentialID, err := credential.CredentialID()
+	if err != nil {
+		return err
+	}
+	revocation, err := e.revocations.CheckRevocation(ctx, credentialID, agentDID)
+	if err != nil {
+		return fmt.Errorf("revocation status of %s is unavailable: %w", name, err)
+	}
+	if revocation != nil {
+		return fmt.Errorf("%s was revoked: %s", name, revocation.Reason)
+	}
+	return nil
+}
+
+// checkStatus looks up a credential's status list entry, if it has one
+func (e *Engine) checkStatus(ctx context.Context, credential interface{}, entry *models.CredentialStatus, name string) error {
+	if e.statuses == nil {
+		return nil
+	}
+	return CheckStatus(ctx, e.statuses, credential, entry, name)
+}
+
+// expired reports whether an expiry timestamp (0 = never) has passed
+func expired(expiresAt, now int64) bool {
+	return expiresAt != 0 && now > expiresAt
+}
+
+// earliest returns the earlier of two expiry timestamps, where 0 means never
+func earliest(a, b int64) int64 {
+	if a == 0 || (b != 0 && b < a) {
+		return b
+	}
+	return a
+}
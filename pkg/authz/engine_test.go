@@ -0,0 +1,585 @@
+package authz
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ak68a/agentid-core/pkg/budget"
+	"github.com/ak68a/agentid-core/pkg/key"
+	"github.com/ak68a/agentid-core/pkg/models"
+	"github.com/ak68a/agentid-core/pkg/replay"
+	"github.com/ak68a/agentid-core/pkg/signer"
+	"github.com/ak68a/agentid-core/pkg/status"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testKeys struct {
+	owner, agent, subagent, responder *key.AgentKey
+}
+
+func setupKeys(t *testing.T) testKeys {
+	var keys [4]*key.AgentKey
+	for i := range keys {
+		k, err := key.GenerateAgentKey()
+		require.NoError(t, err)
+		keys[i] = k
+	}
+	return testKeys{owner: keys[0], agent: keys[1], subagent: keys[2], responder: keys[3]}
+}
+
+// credentials returns an owner's signed transfer authorization and ownership claim for the agent
+func credentials(t *testing.T, keys testKeys, maxAmount string, expiresAt int64) *Credentials {
+	owner := signer.NewClaimSigner(keys.owner)
+	claim := models.NewTransferClaim(keys.agent.DID, keys.owner.DID, models.ScopeETH, maxAmount, expiresAt, "authz-1")
+	require.NoError(t, owner.SignCredential(claim))
+	ownership := models.NewOwnershipClaim(keys.agent.DID, keys.owner.DID, "own-1")
+	require.NoError(t, owner.SignCredential(ownership))
+	return &Credentials{Ownership: ownership, Authorization: claim}
+}
+
+func delegate(t *testing.T, from, to *key.AgentKey, maxAmount string, expiresAt int64) *models.DelegationClaim {
+	return delegateWith(t, from, to, map[string]interface{}{models.ConstraintMaxAmount: maxAmount}, expiresAt)
+}
+
+func delegateWith(t *testing.T, from, to *key.AgentKey, constraints map[string]interface{}, expiresAt int64) *models.DelegationClaim {
+	delegation := &models.DelegationClaim{
+		DelegatorDID: from.DID,
+		DelegateDID:  to.DID,
+		Action:       models.ActionTransfer,
+		Scope:        models.ScopeETH,
+		Constraints:  constraints,
+		IssuedAt:     time.Now().Unix(),
+		ExpiresAt:    expiresAt,
+		Nonce:        "delegation-1",
+		MaxDepth:     1,
+	}
+	require.NoError(t, signer.NewClaimSigner(from).SignDelegationClaim(delegation))
+	return delegation
+}
+
+func verifyResponse(t *testing.T, response *models.AuthorizationResponse) {
+	valid, err := signer.NewClaimSigner(nil).VerifyCredential(response)
+	require.NoError(t, err)
+	assert.True(t, valid)
+}
+
+func TestAuthorize(t *testing.T) {
+	keys := setupKeys(t)
+	expiresAt := time.Now().Add(time.Hour).Unix()
+	engine := NewEngine(keys.responder, WithTrustedIssuers(keys.owner.DID))
+
+	req := models.NewAuthorizationRequest(keys.agent.DID, models.ActionTransfer, models.ScopeETH, keys.responder.DID, "req-1")
+	req.RequestID = "r-1"
+	req.Amount = "400"
+
+	response, err := engine.Authorize(context.Background(), req, credentials(t, keys, "1000", expiresAt))
+	require.NoError(t, err)
+	assert.True(t, response.Authorized, response.Reason)
+	assert.Equal(t, expiresAt, response.ValidUntil)
+	assert.Equal(t, "600", response.RemainingBudget)
+	assert.Equal(t, keys.responder.DID, response.ResponderDID)
+	assert.Equal(t, "r-1", response.Metadata["request_id"])
+	assert.Equal(t, "req-1", response.Metadata["request_nonce"])
+	assert.Equal(t, keys.owner.DID, response.Metadata["authority"])
+	verifyResponse(t, response)
+
+	// Without trusted issuers, authority from anyone is only accepted on request
+	req.Nonce = "req-2"
+	response, err = NewEngine(keys.responder).Authorize(context.Background(), req, credentials(t, keys, "1000", expiresAt))
+	require.NoError(t, err)
+	assert.False(t, response.Authorized)
+	response, err = NewEngine(keys.responder, WithAnyIssuer()).Authorize(context.Background(), req, credentials(t, keys, "1000", expiresAt))
+	require.NoError(t, err)
+	assert.True(t, response.Authorized, response.Reason)
+	assert.Equal(t, keys.owner.DID, response.Metadata["authority"])
+}
+
+func TestAuthorizeDenials(t *testing.T) {
+	keys := setupKeys(t)
+	expiresAt := time.Now().Add(time.Hour).Unix()
+	// Revocations are keyed by credential ID, which is only known once the credentials are issued
+	revoked := RevocationLists{{}}
+
+	tests := []struct {
+		name    string
+		modify  func(req *models.AuthorizationRequest, creds *Credentials)
+		opts    []Option
+		issuers []Option // Defaults to trusting the owner
+		reason  string
+	}{
+		{
+			name:   "amount over limit",
+			modify: func(req *models.AuthorizationRequest, _ *Credentials) { req.Amount = "1001" },
+			reason: "exceeds the limit",
+		},
+		{
+			name:   "action not granted",
+			modify: func(req *models.AuthorizationRequest, _ *Credentials) { req.TargetAction = models.ActionBooking },
+			reason: "action booking is not authorized",
+		},
+		{
+			name:   "scope not granted",
+			modify: func(req *models.AuthorizationRequest, _ *Credentials) { req.TargetScope = models.ScopeUSD },
+			reason: "scope USD is not authorized",
+		},
+		{
+			name:   "another agent",
+			modify: func(req *models.AuthorizationRequest, _ *Credentials) { req.AgentDID = keys.subagent.DID },
+			reason: "not " + keys.subagent.DID,
+		},
+		{
+			name:   "tampered authorization",
+			modify: func(_ *models.AuthorizationRequest, creds *Credentials) { creds.Authorization.MaxAmount = "1000000" },
+			reason: "invalid signature",
+		},
+		{
+			name: "ownership by someone else",
+			modify: func(_ *models.AuthorizationRequest, creds *Credentials) {
+				creds.Ownership = models.NewOwnershipClaim(keys.agent.DID, keys.subagent.DID, "own-2")
+				require.NoError(t, signer.NewClaimSigner(keys.subagent).SignCredential(creds.Ownership))
+			},
+			reason: "not the authority",
+		},
+		{
+			name: "empty chain",
+			modify: func(req *models.AuthorizationRequest, creds *Credentials) {
+				req.AgentDID = keys.subagent.DID
+				creds.Delegations = &models.DelegationChain{}
+			},
+			reason: "not " + keys.subagent.DID,
+		},
+		{
+			name: "ownership of another agent",
+			modify: func(_ *models.AuthorizationRequest, creds *Credentials) {
+				creds.Ownership = models.NewOwnershipClaim(keys.subagent.DID, keys.owner.DID, "own-2")
+				require.NoError(t, signer.NewClaimSigner(keys.owner).SignCredential(creds.Ownership))
+			},
+			reason: "ownership is of " + keys.subagent.DID,
+		},
+		{
+			name: "self-issued",
+			modify: func(_ *models.AuthorizationRequest, creds *Credentials) {
+				self := signer.NewClaimSigner(keys.agent)
+				creds.Authorization = models.NewTransferClaim(keys.agent.DID, keys.agent.DID, models.ScopeETH, "1000", expiresAt, "authz-2")
+				require.NoError(t, self.SignCredential(creds.Authorization))
+				creds.Ownership = nil
+			},
+			issuers: []Option{WithAnyIssuer()},
+			reason:  "cannot authorize itself",
+		},
+		{
+			name: "self-owned",
+			modify: func(_ *models.AuthorizationRequest, creds *Credentials) {
+				self := signer.NewClaimSigner(keys.agent)
+				creds.Authorization = models.NewTransferClaim(keys.agent.DID, keys.agent.DID, models.ScopeETH, "1000", expiresAt, "authz-2")
+				require.NoError(t, self.SignCredential(creds.Authorization))
+				creds.Ownership = models.NewOwnershipClaim(keys.agent.DID, keys.agent.DID, "own-2")
+				require.NoError(t, self.SignCredential(creds.Ownership))
+			},
+			issuers: []Option{WithTrustedIssuers(keys.agent.DID)},
+			reason:  "cannot own itself",
+		},
+		{
+			name:   "expired",
+			opts:   []Option{WithClock(func() time.Time { return time.Unix(expiresAt+1, 0) })},
+			reason: "authorization expired",
+		},
+		{
+			name: "revoked",
+			modify: func(_ *models.AuthorizationRequest, creds *Credentials) {
+				id, err := creds.Authorization.CredentialID()
+				require.NoError(t, err)
+				revoked[0].Revocations = []*models.RevocationClaim{{RevokedCredentialID: id, RevokedAgentDID: keys.agent.DID, Reason: models.RevocationReasonCompromised}}
+			},
+			opts:   []Option{WithRevocationChecker(revoked)},
+			reason: "authorization was revoked",
+		},
+		{
+			name: "agent revoked",
+			opts: []Option{WithRevocationChecker(RevocationLists{{
+				Revocations: []*models.RevocationClaim{{RevokedAgentDID: keys.agent.DID, Reason: models.RevocationReasonCompromised}},
+			}})},
+			reason: "was revoked",
+		},
+		{
+			name:    "untrusted issuer",
+			issuers: []Option{WithTrustedIssuers(keys.responder.DID)},
+			reason:  "not a trusted issuer",
+		},
+		{
+			name:    "no trusted issuers",
+			issuers: []Option{WithTrustedIssuers()},
+			reason:  "no trusted issuers are configured",
+		},
+		{
+			name:   "no credentials",
+			modify: func(_ *models.AuthorizationRequest, creds *Credentials) { *creds = Credentials{} },
+			reason: "no credentials",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := models.NewAuthorizationRequest(keys.agent.DID, models.ActionTransfer, models.ScopeETH, keys.responder.DID, "req")
+			req.Amount = "10"
+			creds := credentials(t, keys, "1000", expiresAt)
+			if tt.modify != nil {
+				tt.modify(req, creds)
+			}
+
+			issuers := tt.issuers
+			if issuers == nil {
+				issuers = []Option{WithTrustedIssuers(keys.owner.DID)}
+			}
+			response, err := NewEngine(keys.responder, append(issuers, tt.opts...)...).Authorize(context.Background(), req, creds)
+			require.NoError(t, err)
+			assert.False(t, response.Authorized)
+			assert.Contains(t, response.Reason, tt.reason)
+			assert.Empty(t, response.RemainingBudget)
+			verifyResponse(t, response)
+		})
+	}
+}
+
+func TestAuthorizeDelegationChain(t *testing.T) {
+	keys := setupKeys(t)
+	expiresAt := time.Now().Add(time.Hour).Unix()
+	engine := NewEngine(keys.responder, WithTrustedIssuers(keys.owner.DID))
+
+	// The agent passes part of its authorization on to a subagent
+	creds := credentials(t, keys, "1000", expiresAt)
+	creds.Ownership = nil
+	creds.Delegations = &models.DelegationChain{Delegations: []*models.DelegationClaim{
+		delegate(t, keys.agent, keys.subagent, "300", expiresAt-60),
+	}}
+
+	req := models.NewAuthorizationRequest(keys.subagent.DID, models.ActionTransfer, models.ScopeETH, keys.responder.DID, "req-1")
+	req.Amount = "100"
+	response, err := engine.Authorize(context.Background(), req, creds)
+	require.NoError(t, err)
+	assert.True(t, response.Authorized, response.Reason)
+	assert.Equal(t, "200", response.RemainingBudget)
+	assert.Equal(t, expiresAt-60, response.ValidUntil)
+	verifyResponse(t, response)
+
+	// The subagent is bound by the delegation's smaller limit
+	req.Amount = "500"
+	response, err = engine.Authorize(context.Background(), req, creds)
+	require.NoError(t, err)
+	assert.False(t, response.Authorized)
+
+	// The agent itself cannot present the chain
+	req.AgentDID = keys.agent.DID
+	req.Amount = "100"
+	response, err = engine.Authorize(context.Background(), req, creds)
+	require.NoError(t, err)
+	assert.False(t, response.Authorized)
+	assert.Contains(t, response.Reason, "delegation chain ends at")
+
+	// A chain started by someone other than the authorized agent is rejected
+	req.AgentDID = keys.subagent.DID
+	creds.Delegations.Delegations[0] = delegate(t, keys.responder, keys.subagent, "300", expiresAt)
+	response, err = engine.Authorize(context.Background(), req, creds)
+	require.NoError(t, err)
+	assert.False(t, response.Authorized)
+	assert.Contains(t, response.Reason, "not the authorized agent")
+}
+
+func TestAuthorizeRevokedDelegation(t *testing.T) {
+	keys := setupKeys(t)
+	expiresAt := time.Now().Add(time.Hour).Unix()
+
+	creds := credentials(t, keys, "1000", expiresAt)
+	creds.Delegations = &models.DelegationChain{Delegations: []*models.DelegationClaim{
+		delegate(t, keys.agent, keys.subagent, "300", expiresAt),
+	}}
+	req := models.NewAuthorizationRequest(keys.subagent.DID, models.ActionTransfer, models.ScopeETH, keys.responder.DID, "req-1")
+	req.Amount = "100"
+
+	authorize := func(revocations []*models.RevocationClaim) *models.AuthorizationResponse {
+		engine := NewEngine(keys.responder, WithTrustedIssuers(keys.owner.DID), WithRevocationChecker(RevocationLists{{Revocations: revocations}}))
+		response, err := engine.Authorize(context.Background(), req, creds)
+		require.NoError(t, err)
+		return response
+	}
+
+	// Only the delegator's signed revocation is honored
+	revocations, err := signer.NewClaimSigner(keys.agent).RevokeDelegationChain(creds.Delegations, models.RevocationReasonCompromised)
+	require.NoError(t, err)
+	response := authorize(revocations)
+	assert.False(t, response.Authorized)
+	assert.Contains(t, response.Reason, "delegation 0 was revoked by "+keys.agent.DID)
+
+	forged := *revocations[0]
+	forged.RevokerDID = keys.responder.DID
+	forged.Issuer = keys.responder.DID
+	require.NoError(t, signer.NewClaimSigner(keys.responder).SignCredential(&forged))
+	response = authorize([]*models.RevocationClaim{&forged})
+	assert.True(t, response.Authorized, response.Reason)
+}
+
+func TestAuthorizeWithLedger(t *testing.T) {
+	keys := setupKeys(t)
+	expiresAt := time.Now().Add(time.Hour).Unix()
+	engine := NewEngine(keys.responder, WithTrustedIssuers(keys.owner.DID), WithLedger(budget.NewMemoryLedger()))
+
+	creds := credentials(t, keys, "1000", expiresAt)
+	creds.Delegations = &models.DelegationChain{Delegations: []*models.DelegationClaim{
+		delegate(t, keys.agent, keys.subagent, "800", expiresAt),
+	}}
+	agentCreds := &Credentials{Ownership: creds.Ownership, Authorization: creds.Authorization}
+
+	authorize := func(agent *key.AgentKey, creds *Credentials, amount string) *models.AuthorizationResponse {
+		req := models.NewAuthorizationRequest(agent.DID, models.ActionTransfer, models.ScopeETH, keys.responder.DID, "req")
+		req.Amount = amount
+		response, err := engine.Authorize(context.Background(), req, creds)
+		require.NoError(t, err)
+		return response
+	}
+
+	// Each request is within the limits, but together they are not
+	response := authorize(keys.subagent, creds, "600")
+	require.True(t, response.Authorized, response.Reason)
+	assert.Equal(t, "200", response.RemainingBudget)
+
+	response = authorize(keys.subagent, creds, "300")
+	assert.False(t, response.Authorized)
+	assert.Contains(t, response.Reason, "insufficient budget")
+
+	// The agent draws on the same authorization budget as its subagent
+	response = authorize(keys.agent, agentCreds, "400")
+	require.True(t, response.Authorized, response.Reason)
+	assert.Equal(t, "0", response.RemainingBudget)
+
+	response = authorize(keys.subagent, creds, "1")
+	assert.False(t, response.Authorized)
+}
+
+func TestAuthorizeTimeWindow(t *testing.T) {
+	keys := setupKeys(t)
+	claim := models.NewTransferClaim(keys.agent.DID, keys.owner.DID, models.ScopeETH, "1000", 0, "authz-1")
+	claim.Metadata = map[string]interface{}{
+		models.ConstraintTime: map[string]interface{}{"timezone": "America/New_York", "days": []int{1, 2, 3, 4, 5}},
+	}
+	require.NoError(t, signer.NewClaimSigner(keys.owner).SignCredential(claim))
+	req := models.NewAuthorizationRequest(keys.agent.DID, models.ActionTransfer, models.ScopeETH, keys.responder.DID, "req")
+
+	friday := time.Date(2025, 6, 13, 20, 0, 0, 0, time.UTC)
+	response, err := NewEngine(keys.responder, WithTrustedIssuers(keys.owner.DID), WithClock(func() time.Time { return friday })).
+		Authorize(context.Background(), req, &Credentials{Authorization: claim})
+	require.NoError(t, err)
+	assert.True(t, response.Authorized, response.Reason)
+
+	// Saturday 01:00 UTC is still Friday evening in New York, Saturday 14:00 UTC is not
+	for at, authorized := range map[time.Time]bool{friday.Add(5 * time.Hour): true, friday.Add(18 * time.Hour): false} {
+		response, err := NewEngine(keys.responder, WithTrustedIssuers(keys.owner.DID), WithClock(func() time.Time { return at })).
+			Authorize(context.Background(), req, &Credentials{Authorization: claim})
+		require.NoError(t, err)
+		assert.Equal(t, authorized, response.Authorized, at)
+		if !authorized {
+			assert.Contains(t, response.Reason, "Saturday is outside the allowed days")
+		}
+	}
+}
+
+func TestAuthorizeDelegationConstraints(t *testing.T) {
+	keys := setupKeys(t)
+	expiresAt := time.Now().Add(time.Hour).Unix()
+	creds := credentials(t, keys, "1000", expiresAt)
+	creds.Delegations = &models.DelegationChain{Delegations: []*models.DelegationClaim{
+		delegateWith(t, keys.agent, keys.subagent, map[string]interface{}{
+			models.ConstraintCounterparties: map[string]interface{}{"allowed": []string{keys.responder.DID}},
+			models.ConstraintRateLimit:      map[string]interface{}{"max_requests": 1, "window_seconds": 60},
+		}, expiresAt),
+	}}
+	req := models.NewAuthorizationRequest(keys.subagent.DID, models.ActionTransfer, models.ScopeETH, keys.responder.DID, "req")
+
+	// Rate limits cannot be enforced without a counter
+	response, err := NewEngine(keys.responder, WithTrustedIssuers(keys.owner.DID)).Authorize(context.Background(), req, creds)
+	require.NoError(t, err)
+	assert.False(t, response.Authorized)
+	assert.Contains(t, response.Reason, "no rate counter")
+
+	engine := NewEngine(keys.responder, WithTrustedIssuers(keys.owner.DID), WithRateCounter(budget.NewMemoryRateCounter()))
+
+	// Requests denied for any reason give their slot back
+	denied := map[string]func(req *models.AuthorizationRequest){
+		"amount":        func(req *models.AuthorizationRequest) { req.Amount = "5000" },
+		"scope":         func(req *models.AuthorizationRequest) { req.TargetScope = "USD" },
+		"another agent": func(req *models.AuthorizationRequest) { req.AgentDID = keys.owner.DID },
+	}
+	for name, modify := range denied {
+		deniedReq := *req
+		modify(&deniedReq)
+		response, err = engine.Authorize(context.Background(), &deniedReq, creds)
+		require.NoError(t, err)
+		assert.False(t, response.Authorized, name)
+	}
+
+	response, err = engine.Authorize(context.Background(), req, creds)
+	require.NoError(t, err)
+	assert.True(t, response.Authorized, response.Reason)
+
+	response, err = engine.Authorize(context.Background(), req, creds)
+	require.NoError(t, err)
+	assert.False(t, response.Authorized)
+	assert.Contains(t, response.Reason, "rate_limit constraint")
+
+	req.RequesterDID = keys.owner.DID
+	response, err = engine.Authorize(context.Background(), req, creds)
+	require.NoError(t, err)
+	assert.False(t, response.Authorized)
+	assert.Contains(t, response.Reason, "counterparty")
+}
+
+func TestAuthorizeRegion(t *testing.T) {
+	keys := setupKeys(t)
+	expiresAt := time.Now().Add(time.Hour).Unix()
+	creds := credentials(t, keys, "1000", expiresAt)
+	creds.Delegations = &models.DelegationChain{Delegations: []*models.DelegationClaim{
+		delegateWith(t, keys.agent, keys.subagent, map[string]interface{}{
+			models.ConstraintGeo: map[string]interface{}{"allowed_regions": []string{"US"}},
+		}, expiresAt),
+	}}
+	authorize := func(opts ...Option) *models.AuthorizationResponse {
+		// The requester's own claim about its region is not trusted
+		req := models.NewAuthorizationRequest(keys.subagent.DID, models.ActionTransfer, models.ScopeETH, keys.responder.DID, "req")
+		req.Context.CustomData = map[string]interface{}{"region": "US"}
+		response, err := NewEngine(keys.responder, append([]Option{WithTrustedIssuers(keys.owner.DID)}, opts...)...).Authorize(context.Background(), req, creds)
+		require.NoError(t, err)
+		return response
+	}
+	regions := func(region string, err error) Option {
+		return WithRegionResolver(RegionResolverFunc(func(context.Context, *models.AuthorizationRequest) (string, error) {
+			return region, err
+		}))
+	}
+
+	response := authorize()
+	assert.False(t, response.Authorized)
+	assert.Contains(t, response.Reason, "region is unknown")
+
+	response = authorize(regions("US-CA", nil))
+	assert.True(t, response.Authorized, response.Reason)
+
+	response = authorize(regions("FR", nil))
+	assert.False(t, response.Authorized)
+	assert.Contains(t, response.Reason, "region FR is not in the allowed regions")
+
+	response = authorize(regions("", errors.New("no address")))
+	assert.False(t, response.Authorized)
+	assert.Contains(t, response.Reason, "no address")
+}
+
+func TestAuthorizeStatusList(t *testing.T) {
+	keys := setupKeys(t)
+	listURL := "https://example.com/status/1"
+	list, err := status.NewList(listURL, models.StatusPurposeRevocation, 0)
+	require.NoError(t, err)
+
+	// The owner gives the authorization a status entry when issuing it
+	claim := models.NewTransferClaim(keys.agent.DID, keys.owner.DID, models.ScopeETH, "1000", 0, "authz-1")
+	claim.CredentialStatus, err = list.Assign()
+	require.NoError(t, err)
+	require.NoError(t, signer.NewClaimSigner(keys.owner).SignCredential(claim))
+
+	fetcher := status.FetcherFunc(func(_ context.Context, url string) (*models.StatusList2021Credential, error) {
+		credential, err := list.Credential(keys.owner.DID, time.Now(), time.Hour)
+		if err != nil {
+			return nil, err
+		}
+		return credential, signer.NewClaimSigner(keys.owner).SignCredential(credential)
+	})
+	authorize := func() *models.AuthorizationResponse {
+		engine := NewEngine(keys.responder, WithTrustedIssuers(keys.owner.DID), WithStatusChecker(status.NewVerifier(status.WithFetcher(fetcher))))
+		req := models.NewAuthorizationRequest(keys.agent.DID, models.ActionTransfer, models.ScopeETH, keys.responder.DID, "req")
+		response, err := engine.Authorize(context.Background(), req, &Credentials{Authorization: claim})
+		require.NoError(t, err)
+		return response
+	}
+
+	response := authorize()
+	assert.True(t, response.Authorized, response.Reason)
+
+	require.NoError(t, list.Revoke(claim.CredentialStatus))
+	response = authorize()
+	assert.False(t, response.Authorized)
+	assert.Equal(t, "authorization was revoked", response.Reason)
+}
+
+func TestAuthorizeReplay(t *testing.T) {
+	keys := setupKeys(t)
+	creds := credentials(t, keys, "1000", time.Now().Add(time.Hour).Unix())
+	now := time.Now()
+	clock := now
+	engine := NewEngine(keys.responder, WithTrustedIssuers(keys.owner.DID), WithNonceStore(replay.NewMemoryStore(replay.WithClock(func() time.Time { return clock }))), WithClockSkew(time.Minute), WithClock(func() time.Time { return clock }))
+
+	newRequest := func(nonce string, timestamp time.Time) *models.AuthorizationRequest {
+		req := models.NewAuthorizationRequest(keys.agent.DID, models.ActionTransfer, models.ScopeETH, keys.responder.DID, nonce)
+		req.Amount = "10"
+		req.Timestamp = timestamp.Unix()
+		return req
+	}
+
+	// A denied request does not use up its nonce
+	denied := newRequest("req-1", now)
+	denied.Amount = "5000"
+	response, err := engine.Authorize(context.Background(), denied, creds)
+	require.NoError(t, err)
+	assert.False(t, response.Authorized)
+
+	req := newRequest("req-1", now)
+	response, err = engine.Authorize(context.Background(), req, creds)
+	require.NoError(t, err)
+	assert.True(t, response.Authorized, response.Reason)
+
+	response, err = engine.Authorize(context.Background(), req, creds)
+	require.NoError(t, err)
+	assert.False(t, response.Authorized)
+	assert.Contains(t, response.Reason, "already been used")
+
+	// Once the nonce is evicted, the timestamp check still rejects the replay
+	clock = now.Add(2 * time.Minute)
+	response, err = engine.Authorize(context.Background(), req, creds)
+	require.NoError(t, err)
+	assert.False(t, response.Authorized)
+	assert.Contains(t, response.Reason, "clock skew")
+
+	tests := map[string]*models.AuthorizationRequest{
+		"future timestamp": newRequest("req-2", clock.Add(2*time.Minute)),
+		"no nonce":         newRequest("", clock),
+	}
+	for name, req := range tests {
+		t.Run(name, func(t *testing.T) {
+			response, err := engine.Authorize(context.Background(), req, creds)
+			require.NoError(t, err)
+			assert.False(t, response.Authorized)
+		})
+	}
+	response, err = engine.Authorize(context.Background(), newRequest("req-2", clock.Add(30*time.Second)), creds)
+	require.NoError(t, err)
+	assert.True(t, response.Authorized, response.Reason)
+
+	// Nor does a request denied for lack of budget
+	engine = NewEngine(keys.responder, WithTrustedIssuers(keys.owner.DID), WithNonceStore(replay.NewMemoryStore()), WithLedger(budget.NewMemoryLedger()))
+	req = newRequest("req-3", time.Now())
+	req.Amount = "800"
+	response, err = engine.Authorize(context.Background(), req, creds)
+	require.NoError(t, err)
+	require.True(t, response.Authorized, response.Reason)
+
+	req = newRequest("req-4", time.Now())
+	req.Amount = "400"
+	response, err = engine.Authorize(context.Background(), req, creds)
+	require.NoError(t, err)
+	assert.False(t, response.Authorized)
+	assert.Contains(t, response.Reason, "budget")
+
+	req.Amount = "200"
+	response, err = engine.Authorize(context.Background(), req, creds)
+	require.NoError(t, err)
+	assert.True(t, response.Authorized, response.Reason)
+}
@@ -0,0 +1,65 @@
+package authz
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ak68a/agentid-core/pkg/models"
+	"github.com/ak68a/agentid-core/pkg/signer"
+)
+
+// RevocationChecker looks up whether a credential has been revoked
+type RevocationChecker = models.RevocationChecker
+
+// RevocationLists checks credentials against a set of trusted revocation lists.
+// A revocation with an empty credential ID revokes every credential of its agent.
+type RevocationLists []*models.RevocationList
+
+// CheckRevocation returns the first effective revocation of the credential or
+// its agent. An empty credential ID only checks for revocations of the agent.
+func (lists RevocationLists) CheckRevocation(_ context.Context, credentialID, agentDID string) (*models.RevocationClaim, error) {
+	for _, list := range lists {
+		// Agent-wide revocations have no credential ID, so "" would match those of any agent
+		if credentialID != "" {
+			if revocation := list.IsRevoked(credentialID); revocation != nil {
+				return revocation, nil
+			}
+		}
+		for _, revocation := range list.IsAgentRevoked(agentDID) {
+			if revocation.RevokedCredentialID == "" {
+				return revocation, nil
+			}
+		}
+	}
+	return nil, nil
+}
+
+// StatusChecker looks up the bit of a credential's status list entry, such as a *status.Verifier
+type StatusChecker interface {
+	// Status reports whether the entry's bit is set in a list issued by issuerDID
+	Status(ctx context.Context, issuerDID string, entry *models.CredentialStatus) (bool, error)
+}
+
+// CheckStatus looks up the status list entry of a credential, if it has one, in a
+// list issued by the credential's signer, and returns an error naming the
+// credential if it is revoked or suspended
+func CheckStatus(ctx context.Context, checker StatusChecker, credential interface{}, entry *models.CredentialStatus, name string) error {
+	if entry == nil {
+		return nil
+	}
+	issuer, err := signer.SignerDID(credential)
+	if err != nil {
+		return err
+	}
+	set, err := checker.Status(ctx, issuer, entry)
+	if err != nil {
+		return fmt.Errorf("status of %s is unavailable: %w", name, err)
+	}
+	if set {
+		if entry.StatusPurpose == models.StatusPurposeSuspension {
+			return fmt.Errorf("%s is suspended", name)
+		}
+		return fmt.Errorf("%s was revoked", name)
+	}
+	return nil
+}
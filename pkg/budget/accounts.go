@@ -0,0 +1,79 @@
+package budget
+
+import (
+	"fmt"
+	"math/big"
+	"time"
+)
+
+// account is the amount spent against one credential in its current period
+type account struct {
+	PeriodStart int64  `json:"period_start"`
+	Spent       string `json:"spent"`
+}
+
+// accounts maps credential IDs to what has been spent against them
+type accounts map[string]*account
+
+// spent returns the amount spent against a limit in the period containing at
+func (a accounts) spent(limit Limit, at time.Time) (*big.Int, error) {
+	acct, ok := a[limit.CredentialID]
+	if !ok || acct.PeriodStart != limit.Period.Start(at) {
+		return new(big.Int), nil
+	}
+	spent, ok := new(big.Int).SetString(acct.Spent, 10)
+	if !ok {
+		return nil, fmt.Errorf("corrupt ledger entry for %s: %q", limit.CredentialID, acct.Spent)
+	}
+	return spent, nil
+}
+
+// remaining returns the smallest remaining budget across limits, or nil if there are none
+func (a accounts) remaining(limits []Limit, at time.Time) (*big.Int, error) {
+	var least *big.Int
+	for _, limit := range limits {
+		spent, err := a.spent(limit, at)
+		if err != nil {
+			return nil, err
+		}
+		left := new(big.Int).Sub(limit.Max, spent)
+		if left.Sign() < 0 {
+			left.SetInt64(0)
+		}
+		if least == nil || left.Cmp(least) < 0 {
+			least = left
+		}
+	}
+	return least, nil
+}
+
+// spend returns the accounts a spend would update, without changing a, and the
+// remaining budget afterwards. Limits on the same credential are charged once.
+func (a accounts) spend(limits []Limit, amount *big.Int, at time.Time) (accounts, *big.Int, error) {
+	if amount == nil || amount.Sign() < 0 {
+		return nil, nil, fmt.Errorf("invalid spend amount %v", amount)
+	}
+
+	updates := make(accounts)
+	var least *big.Int
+	for _, limit := range limits {
+		if limit.CredentialID == "" || limit.Max == nil {
+			return nil, nil, fmt.Errorf("invalid budget limit %+v", limit)
+		}
+		spent, err := a.spent(limit, at)
+		if err != nil {
+			return nil, nil, err
+		}
+		total := new(big.Int).Add(spent, amount)
+		if total.Cmp(limit.Max) > 0 {
+			return nil, nil, fmt.Errorf("%w: %s has %s of %s left", ErrInsufficientBudget, limit.CredentialID, new(big.Int).Sub(limit.Max, spent), limit.Max)
+		}
+		updates[limit.CredentialID] = &account{PeriodStart: limit.Period.Start(at), Spent: total.String()}
+
+		left := new(big.Int).Sub(limit.Max, total)
+		if least == nil || left.Cmp(least) < 0 {
+			least = left
+		}
+	}
+	return updates, least, nil
+}
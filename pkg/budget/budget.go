@@ -0,0 +1,150 @@
+// Package budget tracks cumulative spending against the amount limits of
+// credentials, so max_amount bounds the total spent rather than each request.
+package budget
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ak68a/agentid-core/pkg/models"
+)
+
+// ErrInsufficientBudget is returned when a spend exceeds the remaining budget of a limit
+var ErrInsufficientBudget = errors.New("insufficient budget")
+
+// Period is how often a budget resets
+type Period string
+
+// Budget periods. Periods start at midnight UTC; weeks start on Monday.
+const (
+	PeriodTotal  Period = "" // Never resets
+	PeriodDaily  Period = "daily"
+	PeriodWeekly Period = "weekly"
+)
+
+// ParsePeriod parses a budget period name
+func ParsePeriod(value string) (Period, error) {
+	switch period := Period(value); period {
+	case PeriodTotal, PeriodDaily, PeriodWeekly:
+		return period, nil
+	default:
+		return "", fmt.Errorf("unknown budget period %q", value)
+	}
+}
+
+// Start returns the Unix time the period containing t started, or 0 for PeriodTotal
+func (p Period) Start(t time.Time) int64 {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	switch p {
+	case PeriodDaily:
+		return day.Unix()
+	case PeriodWeekly:
+		// time.Weekday counts from Sunday; shift so Monday is the first day
+		return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7)).Unix()
+	default:
+		return 0
+	}
+}
+
+// Limit caps the amount that may be spent against a credential in each period
+type Limit struct {
+	CredentialID string
+	Max          *big.Int
+	Period       Period
+}
+
+// Ledger records spends against credential limits. Implementations must be
+// safe for concurrent use.
+type Ledger interface {
+	// Spend records amount against every limit, or against none if any of them
+	// would be exceeded, and returns the smallest remaining budget afterwards
+	Spend(ctx context.Context, limits []Limit, amount *big.Int, at time.Time) (*big.Int, error)
+
+	// Remaining returns the smallest remaining budget across the limits at time at
+	Remaining(ctx context.Context, limits []Limit, at time.Time) (*big.Int, error)
+}
+
+// AgentClaimLimit returns the limit set by an authorization's MaxAmount, or nil if it has none.
+// The period is read from the "budget_period" metadata entry.
+func AgentClaimLimit(claim *models.AgentClaim) (*Limit, error) {
+	if claim.MaxAmount == "" {
+		return nil, nil
+	}
+	max, err := models.ParseAmount(claim.MaxAmount)
+	if err != nil {
+		return nil, fmt.Errorf("authorization %s max amount: %w", claim.Nonce, err)
+	}
+	period, err := periodOf(claim.Metadata)
+	if err != nil {
+		return nil, fmt.Errorf("authorization %s: %w", claim.Nonce, err)
+	}
+	id, err := claim.CredentialID()
+	if err != nil {
+		return nil, err
+	}
+	return &Limit{CredentialID: id, Max: max, Period: period}, nil
+}
+
+// DelegationLimit returns the limit set by a delegation's max_amount constraint, or nil if it has none
+func DelegationLimit(claim *models.DelegationClaim) (*Limit, error) {
+	value, ok := claim.Constraints[models.ConstraintMaxAmount]
+	if !ok {
+		return nil, nil
+	}
+	max, err := models.ParseAmount(value)
+	if err != nil {
+		return nil, fmt.Errorf("delegation %s max amount: %w", claim.Nonce, err)
+	}
+	period, err := periodOf(claim.Constraints)
+	if err != nil {
+		return nil, fmt.Errorf("delegation %s: %w", claim.Nonce, err)
+	}
+	id, err := claim.CredentialID()
+	if err != nil {
+		return nil, err
+	}
+	return &Limit{CredentialID: id, Max: max, Period: period}, nil
+}
+
+// ChainLimits returns the limits of an authorization (which may be nil) and
+// every delegation under it, so a spend at the leaf draws on all of them
+func ChainLimits(claim *models.AgentClaim, chain *models.DelegationChain) ([]Limit, error) {
+	var limits []Limit
+	if claim != nil {
+		limit, err := AgentClaimLimit(claim)
+		if err != nil {
+			return nil, err
+		}
+		if limit != nil {
+			limits = append(limits, *limit)
+		}
+	}
+	if chain != nil {
+		for _, delegation := range chain.Delegations {
+			limit, err := DelegationLimit(delegation)
+			if err != nil {
+				return nil, err
+			}
+			if limit != nil {
+				limits = append(limits, *limit)
+			}
+		}
+	}
+	return limits, nil
+}
+
+func periodOf(m map[string]interface{}) (Period, error) {
+	value, ok := m[models.ConstraintBudgetPeriod]
+	if !ok {
+		return PeriodTotal, nil
+	}
+	name, ok := value.(string)
+	if !ok {
+		return "", fmt.Errorf("budget period must be a string, got %T", value)
+	}
+	return ParsePeriod(name)
+}
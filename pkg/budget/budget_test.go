@@ -0,0 +1,181 @@
+package budget
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ak68a/agentid-core/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ledgers(t *testing.T) map[string]Ledger {
+	fileLedger, err := NewFileLedger(filepath.Join(t.TempDir(), "ledger.json"))
+	require.NoError(t, err)
+	return map[string]Ledger{
+		"memory": NewMemoryLedger(),
+		"file":   fileLedger,
+	}
+}
+
+func TestPeriodStart(t *testing.T) {
+	// Wednesday 2025-06-11 15:04 UTC
+	at := time.Date(2025, 6, 11, 15, 4, 0, 0, time.UTC)
+	assert.Equal(t, int64(0), PeriodTotal.Start(at))
+	assert.Equal(t, time.Date(2025, 6, 11, 0, 0, 0, 0, time.UTC).Unix(), PeriodDaily.Start(at))
+	assert.Equal(t, time.Date(2025, 6, 9, 0, 0, 0, 0, time.UTC).Unix(), PeriodWeekly.Start(at))
+
+	// Sunday belongs to the week that started the Monday before
+	sunday := time.Date(2025, 6, 15, 23, 0, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2025, 6, 9, 0, 0, 0, 0, time.UTC).Unix(), PeriodWeekly.Start(sunday))
+
+	_, err := ParsePeriod("hourly")
+	assert.Error(t, err)
+}
+
+func TestLedgerSpend(t *testing.T) {
+	at := time.Date(2025, 6, 11, 15, 4, 0, 0, time.UTC)
+	ctx := context.Background()
+
+	for name, ledger := range ledgers(t) {
+		t.Run(name, func(t *testing.T) {
+			root := Limit{CredentialID: "root", Max: big.NewInt(1000)}
+			leaf := Limit{CredentialID: "leaf", Max: big.NewInt(600), Period: PeriodDaily}
+			chain := []Limit{root, leaf}
+
+			remaining, err := ledger.Spend(ctx, chain, big.NewInt(500), at)
+			require.NoError(t, err)
+			assert.Equal(t, "100", remaining.String())
+
+			// The leaf's daily budget is exhausted, and nothing is recorded on failure
+			_, err = ledger.Spend(ctx, chain, big.NewInt(200), at)
+			assert.True(t, errors.Is(err, ErrInsufficientBudget))
+			remaining, err = ledger.Remaining(ctx, []Limit{root}, at)
+			require.NoError(t, err)
+			assert.Equal(t, "500", remaining.String())
+
+			// The next day the leaf resets, but the root's total budget does not
+			tomorrow := at.Add(24 * time.Hour)
+			remaining, err = ledger.Spend(ctx, chain, big.NewInt(400), tomorrow)
+			require.NoError(t, err)
+			assert.Equal(t, "100", remaining.String())
+			_, err = ledger.Spend(ctx, chain, big.NewInt(101), tomorrow)
+			assert.True(t, errors.Is(err, ErrInsufficientBudget))
+
+			// A sibling delegation under the same root shares its budget
+			sibling := Limit{CredentialID: "sibling", Max: big.NewInt(1000)}
+			_, err = ledger.Spend(ctx, []Limit{root, sibling}, big.NewInt(101), tomorrow)
+			assert.True(t, errors.Is(err, ErrInsufficientBudget))
+
+			_, err = ledger.Spend(ctx, chain, big.NewInt(-1), at)
+			assert.Error(t, err)
+
+			remaining, err = ledger.Spend(ctx, nil, big.NewInt(1), at)
+			require.NoError(t, err)
+			assert.Nil(t, remaining)
+		})
+	}
+}
+
+func TestLedgerConcurrentSpend(t *testing.T) {
+	at := time.Now()
+	for name, ledger := range ledgers(t) {
+		t.Run(name, func(t *testing.T) {
+			limits := []Limit{{CredentialID: "shared", Max: big.NewInt(50)}}
+
+			var wg sync.WaitGroup
+			var mu sync.Mutex
+			succeeded := 0
+			for i := 0; i < 100; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					if _, err := ledger.Spend(context.Background(), limits, big.NewInt(1), at); err == nil {
+						mu.Lock()
+						succeeded++
+						mu.Unlock()
+					}
+				}()
+			}
+			wg.Wait()
+			assert.Equal(t, 50, succeeded)
+		})
+	}
+}
+
+func TestFileLedgerPersists(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ledger.json")
+	at := time.Now()
+	limits := []Limit{{CredentialID: "n1", Max: big.NewInt(100), Period: PeriodWeekly}}
+
+	ledger, err := NewFileLedger(path)
+	require.NoError(t, err)
+	_, err = ledger.Spend(context.Background(), limits, big.NewInt(30), at)
+	require.NoError(t, err)
+
+	reopened, err := NewFileLedger(path)
+	require.NoError(t, err)
+	remaining, err := reopened.Remaining(context.Background(), limits, at)
+	require.NoError(t, err)
+	assert.Equal(t, "70", remaining.String())
+}
+
+func TestChainLimits(t *testing.T) {
+	claim := models.NewTransferClaim("did:ackid:0xa", "did:ackid:0xo", models.ScopeETH, "1000", 0, "authz-1")
+	claim.Metadata = map[string]interface{}{models.ConstraintBudgetPeriod: "weekly"}
+	chain := &models.DelegationChain{Delegations: []*models.DelegationClaim{
+		{Nonce: "d1", Constraints: map[string]interface{}{models.ConstraintMaxAmount: "500", models.ConstraintBudgetPeriod: "daily"}},
+		{Nonce: "d2", Constraints: map[string]interface{}{}},
+	}}
+
+	// Limits are keyed by credential ID, not by the issuer-chosen nonce
+	claimID, err := claim.CredentialID()
+	require.NoError(t, err)
+	delegationID, err := chain.Delegations[0].CredentialID()
+	require.NoError(t, err)
+
+	limits, err := ChainLimits(claim, chain)
+	require.NoError(t, err)
+	assert.Equal(t, []Limit{
+		{CredentialID: claimID, Max: big.NewInt(1000), Period: PeriodWeekly},
+		{CredentialID: delegationID, Max: big.NewInt(500), Period: PeriodDaily},
+	}, limits)
+
+	chain.Delegations[1].Constraints[models.ConstraintMaxAmount] = "-5"
+	_, err = ChainLimits(claim, chain)
+	assert.Error(t, err)
+}
+
+func TestMemoryRateCounter(t *testing.T) {
+	rates := NewMemoryRateCounter()
+	ctx := context.Background()
+	start := time.Unix(1000, 0)
+
+	for i := 0; i < 3; i++ {
+		ok, err := rates.Take(ctx, "n1", 3, time.Minute, start.Add(time.Duration(i)*time.Second))
+		require.NoError(t, err)
+		assert.True(t, ok)
+	}
+	ok, _ := rates.Take(ctx, "n1", 3, time.Minute, start.Add(30*time.Second))
+	assert.False(t, ok)
+
+	// Other keys have their own window
+	ok, _ = rates.Take(ctx, "n2", 3, time.Minute, start.Add(30*time.Second))
+	assert.True(t, ok)
+
+	// Once the first request leaves the window a slot frees up
+	ok, _ = rates.Take(ctx, "n1", 3, time.Minute, start.Add(time.Minute+500*time.Millisecond))
+	assert.True(t, ok)
+	ok, _ = rates.Take(ctx, "n1", 3, time.Minute, start.Add(time.Minute+500*time.Millisecond))
+	assert.False(t, ok)
+
+	// A released request gives its slot back
+	require.NoError(t, rates.Release(ctx, "n1", start.Add(time.Minute+500*time.Millisecond)))
+	ok, _ = rates.Take(ctx, "n1", 3, time.Minute, start.Add(time.Minute+time.Second))
+	assert.True(t, ok)
+}
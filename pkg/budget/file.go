@@ -0,0 +1,104 @@
+package budget
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// FileLedger is a Ledger persisted to a JSON file. Every spend rewrites the
+// file atomically before it takes effect, so a crash never loses a recorded spend.
+type FileLedger struct {
+	mu       sync.Mutex
+	path     string
+	accounts accounts
+}
+
+var _ Ledger = (*FileLedger)(nil)
+
+// NewFileLedger opens the ledger at path, creating it on the first spend if it does not exist
+func NewFileLedger(path string) (*FileLedger, error) {
+	fl := &FileLedger{path: path, accounts: make(accounts)}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return fl, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ledger: %w", err)
+	}
+	if err := json.Unmarshal(data, &fl.accounts); err != nil {
+		return nil, fmt.Errorf("failed to parse ledger: %w", err)
+	}
+	return fl, nil
+}
+
+// Spend records amount against every limit if none of them would be exceeded
+func (fl *FileLedger) Spend(_ context.Context, limits []Limit, amount *big.Int, at time.Time) (*big.Int, error) {
+	fl.mu.Lock()
+	defer fl.mu.Unlock()
+
+	updates, remaining, err := fl.accounts.spend(limits, amount, at)
+	if err != nil {
+		return nil, err
+	}
+
+	next := make(accounts, len(fl.accounts)+len(updates))
+	for id, acct := range fl.accounts {
+		next[id] = acct
+	}
+	for id, acct := range updates {
+		next[id] = acct
+	}
+	if err := fl.write(next); err != nil {
+		return nil, err
+	}
+	fl.accounts = next
+	return remaining, nil
+}
+
+// Remaining returns the smallest remaining budget across the limits
+func (fl *FileLedger) Remaining(_ context.Context, limits []Limit, at time.Time) (*big.Int, error) {
+	fl.mu.Lock()
+	defer fl.mu.Unlock()
+	return fl.accounts.remaining(limits, at)
+}
+
+// write replaces the ledger file through a temporary file and rename
+func (fl *FileLedger) write(state accounts) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal ledger: %w", err)
+	}
+
+	dir := filepath.Dir(fl.path)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("failed to create ledger directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(dir, ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write ledger: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write ledger: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write ledger: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), fl.path); err != nil {
+		return fmt.Errorf("failed to write ledger: %w", err)
+	}
+	return nil
+}
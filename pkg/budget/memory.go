@@ -0,0 +1,43 @@
+package budget
+
+import (
+	"context"
+	"math/big"
+	"sync"
+	"time"
+)
+
+// MemoryLedger is a Ledger held in memory
+type MemoryLedger struct {
+	mu       sync.Mutex
+	accounts accounts
+}
+
+var _ Ledger = (*MemoryLedger)(nil)
+
+// NewMemoryLedger creates an empty in-memory ledger
+func NewMemoryLedger() *MemoryLedger {
+	return &MemoryLedger{accounts: make(accounts)}
+}
+
+// Spend records amount against every limit if none of them would be exceeded
+func (m *MemoryLedger) Spend(_ context.Context, limits []Limit, amount *big.Int, at time.Time) (*big.Int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	updates, remaining, err := m.accounts.spend(limits, amount, at)
+	if err != nil {
+		return nil, err
+	}
+	for id, acct := range updates {
+		m.accounts[id] = acct
+	}
+	return remaining, nil
+}
+
+// Remaining returns the smallest remaining budget across the limits
+func (m *MemoryLedger) Remaining(_ context.Context, limits []Limit, at time.Time) (*big.Int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.accounts.remaining(limits, at)
+}
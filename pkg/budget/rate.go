@@ -0,0 +1,60 @@
+package budget
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/ak68a/agentid-core/pkg/models"
+)
+
+// MemoryRateCounter is a sliding-window request counter held in memory, used to
+// enforce rate_limit constraints
+type MemoryRateCounter struct {
+	mu       sync.Mutex
+	requests map[string][]time.Time
+}
+
+var _ models.RateCounter = (*MemoryRateCounter)(nil)
+
+// NewMemoryRateCounter creates an empty rate counter
+func NewMemoryRateCounter() *MemoryRateCounter {
+	return &MemoryRateCounter{requests: make(map[string][]time.Time)}
+}
+
+// Take records a request against key if fewer than limit were recorded in the window ending at at
+func (rc *MemoryRateCounter) Take(_ context.Context, key string, limit int, window time.Duration, at time.Time) (bool, error) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	// Forget requests that have left the window
+	cutoff := at.Add(-window)
+	recent := rc.requests[key][:0]
+	for _, t := range rc.requests[key] {
+		if t.After(cutoff) {
+			recent = append(recent, t)
+		}
+	}
+
+	if len(recent) >= limit {
+		rc.requests[key] = recent
+		return false, nil
+	}
+	rc.requests[key] = append(recent, at)
+	return true, nil
+}
+
+// Release forgets one request recorded against key at at
+func (rc *MemoryRateCounter) Release(_ context.Context, key string, at time.Time) error {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	requests := rc.requests[key]
+	for i, t := range requests {
+		if t.Equal(at) {
+			rc.requests[key] = append(requests[:i], requests[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+// Package capability maps the Action and Scope of off-chain claims to the
+// bytes32 capabilities of the AgentRegistry and AgentDelegation contracts.
+//
+// A capability is written action:scope[:qualifier], e.g. "transfer:ETH" or
+// "booking:hotels:paris", and its on-chain ID is the keccak256 of that string.
+// Parts are case-sensitive and may use the models.Wildcard patterns, so
+// "transfer:*" covers "transfer:ETH" off-chain and on-chain alike.
+package capability
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ak68a/agentid-core/pkg/models"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Separator separates the parts of a capability
+const Separator = ":"
+
+// ErrInvalidCapability is returned for capabilities that cannot be encoded
+var ErrInvalidCapability = errors.New("invalid capability")
+
+// Capability is an action on a scope, optionally narrowed by a qualifier
+type Capability struct {
+	Action    string
+	Scope     string
+	Qualifier string // Optional; may itself contain the separator
+}
+
+// ID is the on-chain encoding of a capability
+type ID [32]byte
+
+// String returns the 0x-prefixed hex of the ID
+func (id ID) String() string {
+	return "0x" + hex.EncodeToString(id[:])
+}
+
+// MarshalText encodes the ID as 0x-prefixed hex
+func (id ID) MarshalText() ([]byte, error) {
+	return []byte(id.String()), nil
+}
+
+// UnmarshalText decodes a 0x-prefixed hex ID
+func (id *ID) UnmarshalText(text []byte) error {
+	parsed, err := ParseID(string(text))
+	if err != nil {
+		return err
+	}
+	*id = parsed
+	return nil
+}
+
+// ParseID parses a 0x-prefixed hex ID
+func ParseID(s string) (ID, error) {
+	var id ID
+	raw, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+	if err != nil || len(raw) != len(id) {
+		return id, fmt.Errorf("%w: %q is not a 32-byte hex ID", ErrInvalidCapability, s)
+	}
+	copy(id[:], raw)
+	return id, nil
+}
+
+// New returns the capability to perform action on scope
+func New(action, scope string) (Capability, error) {
+	c := Capability{Action: action, Scope: scope}
+	return c, c.Validate()
+}
+
+// Parse parses action:scope[:qualifier]
+func Parse(s string) (Capability, error) {
+	parts := strings.SplitN(s, Separator, 3)
+	if len(parts) < 2 {
+		return Capability{}, fmt.Errorf("%w: %q is not action:scope[:qualifier]", ErrInvalidCapability, s)
+	}
+	c := Capability{Action: parts[0], Scope: parts[1]}
+	if len(parts) == 3 {
+		c.Qualifier = parts[2]
+		if c.Qualifier == "" {
+			return Capability{}, fmt.Errorf("%w: %q has an empty qualifier", ErrInvalidCapability, s)
+		}
+	}
+	return c, c.Validate()
+}
+
+// FromClaim returns the capability a delegation claim grants
+func FromClaim(claim *models.DelegationClaim) (Capability, error) {
+	return New(claim.Action, claim.Scope)
+}
+
+// Validate checks that the action and scope are set and free of the separator
+func (c Capability) Validate() error {
+	if c.Action == "" || c.Scope == "" {
+		return fmt.Errorf("%w: %q needs an action and a scope", ErrInvalidCapability, c.String())
+	}
+	if strings.Contains(c.Action, Separator) || strings.Contains(c.Scope, Separator) {
+		return fmt.Errorf("%w: the action and scope of %q may not contain %q", ErrInvalidCapability, c.String(), Separator)
+	}
+	return nil
+}
+
+// String returns the canonical action:scope[:qualifier] form
+func (c Capability) String() string {
+	s := c.Action + Separator + c.Scope
+	if c.Qualifier != "" {
+		s += Separator + c.Qualifier
+	}
+	return s
+}
+
+// ID returns the keccak256 of the canonical form
+func (c Capability) ID() ID {
+	return ID(crypto.Keccak256Hash([]byte(c.String())))
+}
+
+// Covers reports whether granting c also grants requested. Each part of c is a
+// models.Wildcard pattern, and a capability without a qualifier covers every
+// qualifier of its action and scope.
+func (c Capability) Covers(requested Capability) bool {
+	if !models.PatternCovers(c.Action, requested.Action) || !models.PatternCovers(c.Scope, requested.Scope) {
+		return false
+	}
+	return c.Qualifier == "" || models.PatternCovers(c.Qualifier, requested.Qualifier)
+}
+
+// IsWildcard reports whether any part of c is a pattern rather than an exact value
+func (c Capability) IsWildcard() bool {
+	for _, part := range []string{c.Action, c.Scope, c.Qualifier} {
+		if strings.HasSuffix(part, models.Wildcard) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,99 @@
+package capability
+
+import (
+	"testing"
+
+	"github.com/ak68a/agentid-core/pkg/models"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input string
+		want  Capability
+	}{
+		{"transfer:ETH", Capability{Action: "transfer", Scope: "ETH"}},
+		{"booking:hotels:paris", Capability{Action: "booking", Scope: "hotels", Qualifier: "paris"}},
+		{"read:api:v1:users", Capability{Action: "read", Scope: "api", Qualifier: "v1:users"}},
+		{"*:*", Capability{Action: "*", Scope: "*"}},
+	}
+	for _, tt := range tests {
+		c, err := Parse(tt.input)
+		require.NoError(t, err, tt.input)
+		assert.Equal(t, tt.want, c)
+		assert.Equal(t, tt.input, c.String())
+	}
+
+	for _, input := range []string{"transfer", "transfer:", ":ETH", "transfer:ETH:", ""} {
+		_, err := Parse(input)
+		assert.ErrorIs(t, err, ErrInvalidCapability, input)
+	}
+	_, err := New("transfer", "ETH:USD")
+	assert.ErrorIs(t, err, ErrInvalidCapability)
+}
+
+func TestID(t *testing.T) {
+	c := Capability{Action: models.ActionTransfer, Scope: models.ScopeETH}
+	// The contracts see the keccak256 of the canonical string
+	assert.Equal(t, ID(crypto.Keccak256Hash([]byte("transfer:ETH"))), c.ID())
+	assert.NotEqual(t, c.ID(), Capability{Action: "transfer", Scope: "eth"}.ID())
+
+	parsed, err := ParseID(c.ID().String())
+	require.NoError(t, err)
+	assert.Equal(t, c.ID(), parsed)
+	_, err = ParseID("0x1234")
+	assert.ErrorIs(t, err, ErrInvalidCapability)
+}
+
+func TestCovers(t *testing.T) {
+	tests := []struct {
+		granted, requested string
+		covers             bool
+	}{
+		{"transfer:ETH", "transfer:ETH", true},
+		{"transfer:ETH", "transfer:ETH:max-100", true},
+		{"transfer:ETH:max-100", "transfer:ETH", false},
+		{"transfer:ETH:max-100", "transfer:ETH:max-200", false},
+		{"transfer:*", "transfer:USD", true},
+		{"*:ETH", "payment:ETH", true},
+		{"booking:hotels/*", "booking:hotels/paris", true},
+		{"booking:hotels/*", "booking:flights", false},
+		{"transfer:ETH", "transfer:*", false},
+		{"transfer:ETH", "payment:ETH", false},
+	}
+	for _, tt := range tests {
+		granted, err := Parse(tt.granted)
+		require.NoError(t, err)
+		requested, err := Parse(tt.requested)
+		require.NoError(t, err)
+		assert.Equal(t, tt.covers, granted.Covers(requested), "%s covers %s", tt.granted, tt.requested)
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	c, ok := Default.Lookup(Capability{Action: "transfer", Scope: "ETH"}.ID())
+	require.True(t, ok)
+	assert.Equal(t, "transfer:ETH", c.String())
+	_, ok = Default.Lookup(Capability{Action: "*", Scope: "hotels"}.ID())
+	assert.True(t, ok)
+
+	registry := NewRegistry()
+	custom := Capability{Action: "provide_fx_quotes", Scope: "EUR", Qualifier: "spot"}
+	_, ok = registry.Lookup(custom.ID())
+	assert.False(t, ok)
+	id := registry.Register(custom)
+	c, ok = registry.Lookup(id)
+	require.True(t, ok)
+	assert.Equal(t, custom, c)
+}
+
+func TestFromClaim(t *testing.T) {
+	claim := &models.DelegationClaim{Action: models.ActionBooking, Scope: models.ScopeHotels}
+	c, err := FromClaim(claim)
+	require.NoError(t, err)
+	assert.Equal(t, "booking:hotels", c.String())
+	assert.False(t, c.IsWildcard())
+	assert.True(t, Capability{Action: "booking", Scope: "hotels/*"}.IsWildcard())
+}
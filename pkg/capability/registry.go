@@ -0,0 +1,63 @@
+package capability
+
+import (
+	"sync"
+
+	"github.com/ak68a/agentid-core/pkg/models"
+)
+
+// Registry remembers the capabilities behind IDs, since keccak cannot be reversed
+type Registry struct {
+	mu           sync.RWMutex
+	capabilities map[ID]Capability
+}
+
+// NewRegistry creates a Registry that knows the given capabilities
+func NewRegistry(capabilities ...Capability) *Registry {
+	r := &Registry{capabilities: make(map[ID]Capability)}
+	for _, c := range capabilities {
+		r.Register(c)
+	}
+	return r
+}
+
+// Register records c and returns its ID
+func (r *Registry) Register(c Capability) ID {
+	id := c.ID()
+	r.mu.Lock()
+	r.capabilities[id] = c
+	r.mu.Unlock()
+	return id
+}
+
+// Lookup returns the capability with the given ID, if it was registered
+func (r *Registry) Lookup(id ID) (Capability, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	c, ok := r.capabilities[id]
+	return c, ok
+}
+
+// Default knows every pairing of the actions and scopes defined in models,
+// including wildcards on either side
+var Default = NewRegistry(wellKnown()...)
+
+func wellKnown() []Capability {
+	actions := []string{
+		models.ActionTransfer, models.ActionBooking, models.ActionQuote, models.ActionPayment,
+		models.ActionMessage, models.ActionRead, models.ActionWrite, models.ActionExecute,
+		models.Wildcard,
+	}
+	scopes := []string{
+		models.ScopeETH, models.ScopeUSD, models.ScopeEUR, models.ScopeFlights,
+		models.ScopeHotels, models.ScopeEmail, models.ScopeDatabase, models.ScopeAPI,
+		models.Wildcard,
+	}
+	var capabilities []Capability
+	for _, action := range actions {
+		for _, scope := range scopes {
+			capabilities = append(capabilities, Capability{Action: action, Scope: scope})
+		}
+	}
+	return capabilities
+}
@@ -0,0 +1,1534 @@
+// Code generated - DO NOT EDIT.
+// This file is a generated binding and any manual changes will be lost.
+
+package bindings
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var (
+	_ = errors.New
+	_ = big.NewInt
+	_ = strings.NewReader
+	_ = ethereum.NotFound
+	_ = bind.Bind
+	_ = common.Big1
+	_ = types.BloomLookup
+	_ = event.NewSubscription
+	_ = abi.ConvertType
+)
+
+// AgentDelegationMetaData contains all meta data concerning the AgentDelegation contract.
+var AgentDelegationMetaData = &bind.MetaData{
+	ABI: "[{\"type\":\"constructor\",\"inputs\":[{\"name\":\"_registry\",\"type\":\"address\",\"internalType\":\"address\"}],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"createDelegation\",\"inputs\":[{\"name\":\"delegate\",\"type\":\"address\",\"internalType\":\"address\"},{\"name\":\"capabilities\",\"type\":\"bytes32[]\",\"internalType\":\"bytes32[]\"},{\"name\":\"validUntil\",\"type\":\"uint256\",\"internalType\":\"uint256\"}],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"delegateList\",\"inputs\":[{\"name\":\"\",\"type\":\"address\",\"internalType\":\"address\"},{\"name\":\"\",\"type\":\"uint256\",\"internalType\":\"uint256\"}],\"outputs\":[{\"name\":\"\",\"type\":\"address\",\"internalType\":\"address\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"delegations\",\"inputs\":[{\"name\":\"\",\"type\":\"address\",\"internalType\":\"address\"},{\"name\":\"\",\"type\":\"address\",\"internalType\":\"address\"}],\"outputs\":[{\"name\":\"delegator\",\"type\":\"address\",\"internalType\":\"address\"},{\"name\":\"delegate\",\"type\":\"address\",\"internalType\":\"address\"},{\"name\":\"validFrom\",\"type\":\"uint256\",\"internalType\":\"uint256\"},{\"name\":\"validUntil\",\"type\":\"uint256\",\"internalType\":\"uint256\"},{\"name\":\"isActive\",\"type\":\"bool\",\"internalType\":\"bool\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"getDelegateList\",\"inputs\":[{\"name\":\"delegator\",\"type\":\"address\",\"internalType\":\"address\"}],\"outputs\":[{\"name\":\"\",\"type\":\"address[]\",\"internalType\":\"address[]\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"getDelegationDetails\",\"inputs\":[{\"name\":\"delegator\",\"type\":\"address\",\"internalType\":\"address\"},{\"name\":\"delegate\",\"type\":\"address\",\"internalType\":\"address\"}],\"outputs\":[{\"name\":\"capabilities\",\"type\":\"bytes32[]\",\"internalType\":\"bytes32[]\"},{\"name\":\"validFrom\",\"type\":\"uint256\",\"internalType\":\"uint256\"},{\"name\":\"validUntil\",\"type\":\"uint256\",\"internalType\":\"uint256\"},{\"name\":\"isActive\",\"type\":\"bool\",\"internalType\":\"bool\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"hasDelegatedCapability\",\"inputs\":[{\"name\":\"delegator\",\"type\":\"address\",\"internalType\":\"address\"},{\"name\":\"delegate\",\"type\":\"address\",\"internalType\":\"address\"},{\"name\":\"capability\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"outputs\":[{\"name\":\"\",\"type\":\"bool\",\"internalType\":\"bool\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"owner\",\"inputs\":[],\"outputs\":[{\"name\":\"\",\"type\":\"address\",\"internalType\":\"address\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"pause\",\"inputs\":[],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"paused\",\"inputs\":[],\"outputs\":[{\"name\":\"\",\"type\":\"bool\",\"internalType\":\"bool\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"registry\",\"inputs\":[],\"outputs\":[{\"name\":\"\",\"type\":\"address\",\"internalType\":\"contractAgentRegistry\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"renounceOwnership\",\"inputs\":[],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"revokeDelegation\",\"inputs\":[{\"name\":\"delegate\",\"type\":\"address\",\"internalType\":\"address\"},{\"name\":\"reason\",\"type\":\"string\",\"internalType\":\"string\"}],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"setRegistry\",\"inputs\":[{\"name\":\"_registry\",\"type\":\"address\",\"internalType\":\"address\"}],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"transferOwnership\",\"inputs\":[{\"name\":\"newOwner\",\"type\":\"address\",\"internalType\":\"address\"}],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"unpause\",\"inputs\":[],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"event\",\"name\":\"DelegationCreated\",\"inputs\":[{\"name\":\"delegator\",\"type\":\"address\",\"indexed\":true,\"internalType\":\"address\"},{\"name\":\"delegate\",\"type\":\"address\",\"indexed\":true,\"internalType\":\"address\"},{\"name\":\"capabilities\",\"type\":\"bytes32[]\",\"indexed\":false,\"internalType\":\"bytes32[]\"},{\"name\":\"validUntil\",\"type\":\"uint256\",\"indexed\":false,\"internalType\":\"uint256\"}],\"anonymous\":false},{\"type\":\"event\",\"name\":\"DelegationExpired\",\"inputs\":[{\"name\":\"delegator\",\"type\":\"address\",\"indexed\":true,\"internalType\":\"address\"},{\"name\":\"delegate\",\"type\":\"address\",\"indexed\":true,\"internalType\":\"address\"}],\"anonymous\":false},{\"type\":\"event\",\"name\":\"DelegationRevoked\",\"inputs\":[{\"name\":\"delegator\",\"type\":\"address\",\"indexed\":true,\"internalType\":\"address\"},{\"name\":\"delegate\",\"type\":\"address\",\"indexed\":true,\"internalType\":\"address\"},{\"name\":\"reason\",\"type\":\"string\",\"indexed\":false,\"internalType\":\"string\"}],\"anonymous\":false},{\"type\":\"event\",\"name\":\"OwnershipTransferred\",\"inputs\":[{\"name\":\"previousOwner\",\"type\":\"address\",\"indexed\":true,\"internalType\":\"address\"},{\"name\":\"newOwner\",\"type\":\"address\",\"indexed\":true,\"internalType\":\"address\"}],\"anonymous\":false},{\"type\":\"event\",\"name\":\"Paused\",\"inputs\":[{\"name\":\"account\",\"type\":\"address\",\"indexed\":false,\"internalType\":\"address\"}],\"anonymous\":false},{\"type\":\"event\",\"name\":\"Unpaused\",\"inputs\":[{\"name\":\"account\",\"type\":\"address\",\"indexed\":false,\"internalType\":\"address\"}],\"anonymous\":false},{\"type\":\"error\",\"name\":\"EnforcedPause\",\"inputs\":[]},{\"type\":\"error\",\"name\":\"ExpectedPause\",\"inputs\":[]},{\"type\":\"error\",\"name\":\"OwnableInvalidOwner\",\"inputs\":[{\"name\":\"owner\",\"type\":\"address\",\"internalType\":\"address\"}]},{\"type\":\"error\",\"name\":\"OwnableUnauthorizedAccount\",\"inputs\":[{\"name\":\"account\",\"type\":\"address\",\"internalType\":\"address\"}]}]",
+	Bin: "0x608060405234801561000f575f5ffd5b506040516125b13803806125b183398181016040528101906100319190610217565b335f73ffffffffffffffffffffffffffffffffffffffff168173ffffffffffffffffffffffffffffffffffffffff16036100a2575f6040517f1e4fbdf70000000000000000000000000000000000000000000000000000000081526004016100999190610251565b60405180910390fd5b6100b1816100f860201b60201c565b508060015f6101000a81548173ffffffffffffffffffffffffffffffffffffffff021916908373ffffffffffffffffffffffffffffffffffffffff1602179055505061026a565b5f5f5f9054906101000a900473ffffffffffffffffffffffffffffffffffffffff169050815f5f6101000a81548173ffffffffffffffffffffffffffffffffffffffff021916908373ffffffffffffffffffffffffffffffffffffffff1602179055508173ffffffffffffffffffffffffffffffffffffffff168173ffffffffffffffffffffffffffffffffffffffff167f8be0079c531659141344cd1fd0a4f28419497f9722a3daafe3b4186f6b6457e060405160405180910390a35050565b5f5ffd5b5f73ffffffffffffffffffffffffffffffffffffffff82169050919050565b5f6101e6826101bd565b9050919050565b6101f6816101dc565b8114610200575f5ffd5b50565b5f81519050610211816101ed565b92915050565b5f6020828403121561022c5761022b6101b9565b5b5f61023984828501610203565b91505092915050565b61024b816101dc565b82525050565b5f6020820190506102645f830184610242565b92915050565b61233a806102775f395ff3fe608060405234801561000f575f5ffd5b50600436106100f3575f3560e01c80638da5cb5b11610095578063c64814dd11610064578063c64814dd14610223578063ed0a58b314610257578063f2fde38b14610287578063f6f24bb9146102a3576100f3565b80638da5cb5b1461019d578063969fecd4146101bb578063a91ee0dc146101eb578063b2b687e514610207576100f3565b8063715018a6116100d1578063715018a61461013b5780637b103999146101455780637e360d52146101635780638456cb5914610193576100f3565b80633f4ba83a146100f75780635c975abb14610101578063658e566a1461011f575b5f5ffd5b6100ff6102d6565b005b6101096102e8565b604051610116919061172e565b60405180910390f35b610139600480360381019061013491906118ee565b6102fd565b005b61014361079e565b005b61014d6107b1565b60405161015a91906119a3565b60405180910390f35b61017d600480360381019061017891906119ef565b6107d6565b60405161018a9190611a3c565b60405180910390f35b61019b61081e565b005b6101a5610830565b6040516101b29190611a3c565b60405180910390f35b6101d560048036038101906101d09190611a55565b610857565b6040516101e29190611b37565b60405180910390f35b61020560048036038101906102009190611a55565b61091f565b005b610221600480360381019061021c9190611bb4565b61096a565b005b61023d60048036038101906102389190611c25565b6110f9565b60405161024e959493929190611c72565b60405180910390f35b610271600480360381019061026c9190611cf6565b611180565b60405161027e919061172e565b60405180910390f35b6102a1600480360381019061029c9190611a55565b611296565b005b6102bd60048036038101906102b89190611c25565b61131a565b6040516102cd9493929190611dfd565b60405180910390f35b6102de61141b565b6102e66114a2565b565b5f5f60149054906101000a900460ff16905090565b60025f3373ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff1681526020019081526020015f205f8373ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff1681526020019081526020015f206005015f9054906101000a900460ff166103c4576040517f08c379a00000000000000000000000000000000000000000000000000000000081526004016103bb90611ea1565b60405180910390fd5b5f60025f3373ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff1681526020019081526020015f205f8473ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff1681526020019081526020015f206005015f6101000a81548160ff0219169083151502179055505f5f90505b60035f3373ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff1681526020019081526020015f2080549050811015610734578273ffffffffffffffffffffffffffffffffffffffff1660035f3373ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff1681526020019081526020015f20828154811061050957610508611ebf565b5b905f5260205f20015f9054906101000a900473ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff16036107275760035f3373ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff1681526020019081526020015f20600160035f3373ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff1681526020019081526020015f20805490506105d69190611f19565b815481106105e7576105e6611ebf565b5b905f5260205f20015f9054906101000a900473ffffffffffffffffffffffffffffffffffffffff1660035f3373ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff1681526020019081526020015f20828154811061065e5761065d611ebf565b5b905f5260205f20015f6101000a81548173ffffffffffffffffffffffffffffffffffffffff021916908373ffffffffffffffffffffffffffffffffffffffff16021790555060035f3373ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff1681526020019081526020015f208054806106f0576106ef611f4c565b5b600190038181905f5260205f20015f6101000a81549073ffffffffffffffffffffffffffffffffffffffff02191690559055610734565b808060010191505061045b565b508173ffffffffffffffffffffffffffffffffffffffff163373ffffffffffffffffffffffffffffffffffffffff167f7b9de2ea25e2bcf2150702bf2d070e8245d542228ef81748a5c86603ec13c034836040516107929190611fc9565b60405180910390a35050565b6107a661141b565b6107af5f611503565b565b60015f9054906101000a900473ffffffffffffffffffffffffffffffffffffffff1681565b6003602052815f5260405f2081815481106107ef575f80fd5b905f5260205f20015f915091509054906101000a900473ffffffffffffffffffffffffffffffffffffffff1681565b61082661141b565b61082e6115c4565b565b5f5f5f9054906101000a900473ffffffffffffffffffffffffffffffffffffffff16905090565b606060035f8373ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff1681526020019081526020015f2080548060200260200160405190810160405280929190818152602001828054801561091357602002820191905f5260205f20905b815f9054906101000a900473ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff16815260200190600101908083116108ca575b50505050509050919050565b61092761141b565b8060015f6101000a81548173ffffffffffffffffffffffffffffffffffffffff021916908373ffffffffffffffffffffffffffffffffffffffff16021790555050565b5f600381111561097d5761097c611fe9565b5b60015f9054906101000a900473ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff16636b5dae34336040518263ffffffff1660e01b81526004016109d79190611a3c565b602060405180830381865afa1580156109f2573d5f5f3e3d5ffd5b505050506040513d601f19601f82011682018060405250810190610a169190612039565b6003811115610a2857610a27611fe9565b5b03610a68576040517f08c379a0000000000000000000000000000000000000000000000000000000008152600401610a5f906120ae565b60405180910390fd5b610a70611626565b5f73ffffffffffffffffffffffffffffffffffffffff168473ffffffffffffffffffffffffffffffffffffffff1603610ade576040517f08c379a0000000000000000000000000000000000000000000000000000000008152600401610ad590612116565b60405180910390fd5b428111610b20576040517f08c379a0000000000000000000000000000000000000000000000000000000008152600401610b179061217e565b60405180910390fd5b5f6003811115610b3357610b32611fe9565b5b60015f9054906101000a900473ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff16636b5dae34866040518263ffffffff1660e01b8152600401610b8d9190611a3c565b602060405180830381865afa158015610ba8573d5f5f3e3d5ffd5b505050506040513d601f19601f82011682018060405250810190610bcc9190612039565b6003811115610bde57610bdd611fe9565b5b03610c1e576040517f08c379a0000000000000000000000000000000000000000000000000000000008152600401610c15906121e6565b60405180910390fd5b60025f3373ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff1681526020019081526020015f205f8573ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff1681526020019081526020015f206005015f9054906101000a900460ff1615610ce6576040517f08c379a0000000000000000000000000000000000000000000000000000000008152600401610cdd9061224e565b60405180910390fd5b6040518060c001604052803373ffffffffffffffffffffffffffffffffffffffff1681526020018573ffffffffffffffffffffffffffffffffffffffff1681526020018484808060200260200160405190810160405280939291908181526020018383602002808284375f81840152601f19601f8201169050808301925050505050505081526020014281526020018281526020016001151581525060025f3373ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff1681526020019081526020015f205f8673ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff1681526020019081526020015f205f820151815f015f6101000a81548173ffffffffffffffffffffffffffffffffffffffff021916908373ffffffffffffffffffffffffffffffffffffffff1602179055506020820151816001015f6101000a81548173ffffffffffffffffffffffffffffffffffffffff021916908373ffffffffffffffffffffffffffffffffffffffff1602179055506040820151816002019080519060200190610ea09291906116ae565b50606082015181600301556080820151816004015560a0820151816005015f6101000a81548160ff0219169083151502179055509050505f5f90505f5f90505b60035f3373ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff1681526020019081526020015f2080549050811015610fe7578573ffffffffffffffffffffffffffffffffffffffff1660035f3373ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff1681526020019081526020015f208281548110610f8e57610f8d611ebf565b5b905f5260205f20015f9054906101000a900473ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff1603610fda5760019150610fe7565b8080600101915050610ee0565b50806110895760035f3373ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff1681526020019081526020015f2085908060018154018082558091505060019003905f5260205f20015f9091909190916101000a81548173ffffffffffffffffffffffffffffffffffffffff021916908373ffffffffffffffffffffffffffffffffffffffff1602179055505b8473ffffffffffffffffffffffffffffffffffffffff163373ffffffffffffffffffffffffffffffffffffffff167ff6daba9a137fa5026dbda1ca430e44b5968c8b6f4e6050e2de1695d54d8ccc748686866040516110ea939291906122d4565b60405180910390a35050505050565b6002602052815f5260405f20602052805f5260405f205f9150915050805f015f9054906101000a900473ffffffffffffffffffffffffffffffffffffffff1690806001015f9054906101000a900473ffffffffffffffffffffffffffffffffffffffff1690806003015490806004015490806005015f9054906101000a900460ff16905085565b5f5f60025f8673ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff1681526020019081526020015f205f8573ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff1681526020019081526020015f209050806005015f9054906101000a900460ff16158061121c5750806003015442105b8061122a5750806004015442115b15611238575f91505061128f565b5f5f90505b8160020180549050811015611289578382600201828154811061126357611262611ebf565b5b905f5260205f2001540361127c5760019250505061128f565b808060010191505061123d565b505f9150505b9392505050565b61129e61141b565b5f73ffffffffffffffffffffffffffffffffffffffff168173ffffffffffffffffffffffffffffffffffffffff160361130e575f6040517f1e4fbdf70000000000000000000000000000000000000000000000000000000081526004016113059190611a3c565b60405180910390fd5b61131781611503565b50565b60605f5f5f5f60025f8873ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff1681526020019081526020015f205f8773ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff1681526020019081526020015f2090508060020181600301548260040154836005015f9054906101000a900460ff168380548060200260200160405190810160405280929190818152602001828054801561140257602002820191905f5260205f20905b8154815260200190600101908083116113ee575b5050505050935094509450945094505092959194509250565b611423611667565b73ffffffffffffffffffffffffffffffffffffffff16611441610830565b73ffffffffffffffffffffffffffffffffffffffff16146114a057611464611667565b6040517f118cdaa70000000000000000000000000000000000000000000000000000000081526004016114979190611a3c565b60405180910390fd5b565b6114aa61166e565b5f5f60146101000a81548160ff0219169083151502179055507f5db9ee0a495bf2e6ff9c91a7834c1ba4fdd244a5e8aa4e537bd38aeae4b073aa6114ec611667565b6040516114f99190611a3c565b60405180910390a1565b5f5f5f9054906101000a900473ffffffffffffffffffffffffffffffffffffffff169050815f5f6101000a81548173ffffffffffffffffffffffffffffffffffffffff021916908373ffffffffffffffffffffffffffffffffffffffff1602179055508173ffffffffffffffffffffffffffffffffffffffff168173ffffffffffffffffffffffffffffffffffffffff167f8be0079c531659141344cd1fd0a4f28419497f9722a3daafe3b4186f6b6457e060405160405180910390a35050565b6115cc611626565b60015f60146101000a81548160ff0219169083151502179055507f62e78cea01bee320cd4e420270b5ea74000d11b0c9f74754ebdbfc544b05a25861160f611667565b60405161161c9190611a3c565b60405180910390a1565b61162e6102e8565b15611665576040517fd93c066500000000000000000000000000000000000000000000000000000000815260040160405180910390fd5b565b5f33905090565b6116766102e8565b6116ac576040517f8dfc202b00000000000000000000000000000000000000000000000000000000815260040160405180910390fd5b565b828054828255905f5260205f209081019282156116e8579160200282015b828111156116e75782518255916020019190600101906116cc565b5b5090506116f591906116f9565b5090565b5b80821115611710575f815f9055506001016116fa565b5090565b5f8115159050919050565b61172881611714565b82525050565b5f6020820190506117415f83018461171f565b92915050565b5f604051905090565b5f5ffd5b5f5ffd5b5f73ffffffffffffffffffffffffffffffffffffffff82169050919050565b5f61178182611758565b9050919050565b61179181611777565b811461179b575f5ffd5b50565b5f813590506117ac81611788565b92915050565b5f5ffd5b5f5ffd5b5f601f19601f8301169050919050565b7f4e487b71000000000000000000000000000000000000000000000000000000005f52604160045260245ffd5b611800826117ba565b810181811067ffffffffffffffff8211171561181f5761181e6117ca565b5b80604052505050565b5f611831611747565b905061183d82826117f7565b919050565b5f67ffffffffffffffff82111561185c5761185b6117ca565b5b611865826117ba565b9050602081019050919050565b828183375f83830152505050565b5f61189261188d84611842565b611828565b9050828152602081018484840111156118ae576118ad6117b6565b5b6118b9848285611872565b509392505050565b5f82601f8301126118d5576118d46117b2565b5b81356118e5848260208601611880565b91505092915050565b5f5f6040838503121561190457611903611750565b5b5f6119118582860161179e565b925050602083013567ffffffffffffffff81111561193257611931611754565b5b61193e858286016118c1565b9150509250929050565b5f819050919050565b5f61196b61196661196184611758565b611948565b611758565b9050919050565b5f61197c82611951565b9050919050565b5f61198d82611972565b9050919050565b61199d81611983565b82525050565b5f6020820190506119b65f830184611994565b92915050565b5f819050919050565b6119ce816119bc565b81146119d8575f5ffd5b50565b5f813590506119e9816119c5565b92915050565b5f5f60408385031215611a0557611a04611750565b5b5f611a128582860161179e565b9250506020611a23858286016119db565b9150509250929050565b611a3681611777565b82525050565b5f602082019050611a4f5f830184611a2d565b92915050565b5f60208284031215611a6a57611a69611750565b5b5f611a778482850161179e565b91505092915050565b5f81519050919050565b5f82825260208201905092915050565b5f819050602082019050919050565b611ab281611777565b82525050565b5f611ac38383611aa9565b60208301905092915050565b5f602082019050919050565b5f611ae582611a80565b611aef8185611a8a565b9350611afa83611a9a565b805f5b83811015611b2a578151611b118882611ab8565b9750611b1c83611acf565b925050600181019050611afd565b5085935050505092915050565b5f6020820190508181035f830152611b4f8184611adb565b905092915050565b5f5ffd5b5f5ffd5b5f5f83601f840112611b7457611b736117b2565b5b8235905067ffffffffffffffff811115611b9157611b90611b57565b5b602083019150836020820283011115611bad57611bac611b5b565b5b9250929050565b5f5f5f5f60608587031215611bcc57611bcb611750565b5b5f611bd98782880161179e565b945050602085013567ffffffffffffffff811115611bfa57611bf9611754565b5b611c0687828801611b5f565b93509350506040611c19878288016119db565b91505092959194509250565b5f5f60408385031215611c3b57611c3a611750565b5b5f611c488582860161179e565b9250506020611c598582860161179e565b9150509250929050565b611c6c816119bc565b82525050565b5f60a082019050611c855f830188611a2d565b611c926020830187611a2d565b611c9f6040830186611c63565b611cac6060830185611c63565b611cb9608083018461171f565b9695505050505050565b5f819050919050565b611cd581611cc3565b8114611cdf575f5ffd5b50565b5f81359050611cf081611ccc565b92915050565b5f5f5f60608486031215611d0d57611d0c611750565b5b5f611d1a8682870161179e565b9350506020611d2b8682870161179e565b9250506040611d3c86828701611ce2565b9150509250925092565b5f81519050919050565b5f82825260208201905092915050565b5f819050602082019050919050565b611d7881611cc3565b82525050565b5f611d898383611d6f565b60208301905092915050565b5f602082019050919050565b5f611dab82611d46565b611db58185611d50565b9350611dc083611d60565b805f5b83811015611df0578151611dd78882611d7e565b9750611de283611d95565b925050600181019050611dc3565b5085935050505092915050565b5f6080820190508181035f830152611e158187611da1565b9050611e246020830186611c63565b611e316040830185611c63565b611e3e606083018461171f565b95945050505050565b5f82825260208201905092915050565b7f4e6f206163746976652064656c65676174696f6e0000000000000000000000005f82015250565b5f611e8b601483611e47565b9150611e9682611e57565b602082019050919050565b5f6020820190508181035f830152611eb881611e7f565b9050919050565b7f4e487b71000000000000000000000000000000000000000000000000000000005f52603260045260245ffd5b7f4e487b71000000000000000000000000000000000000000000000000000000005f52601160045260245ffd5b5f611f23826119bc565b9150611f2e836119bc565b9250828203905081811115611f4657611f45611eec565b5b92915050565b7f4e487b71000000000000000000000000000000000000000000000000000000005f52603160045260245ffd5b5f81519050919050565b8281835e5f83830152505050565b5f611f9b82611f79565b611fa58185611e47565b9350611fb5818560208601611f83565b611fbe816117ba565b840191505092915050565b5f6020820190508181035f830152611fe18184611f91565b905092915050565b7f4e487b71000000000000000000000000000000000000000000000000000000005f52602160045260245ffd5b60048110612022575f5ffd5b50565b5f8151905061203381612016565b92915050565b5f6020828403121561204e5761204d611750565b5b5f61205b84828501612025565b91505092915050565b7f4e6f7420612072656769737465726564206167656e74000000000000000000005f82015250565b5f612098601683611e47565b91506120a382612064565b602082019050919050565b5f6020820190508181035f8301526120c58161208c565b9050919050565b7f496e76616c69642064656c6567617465000000000000000000000000000000005f82015250565b5f612100601083611e47565b915061210b826120cc565b602082019050919050565b5f6020820190508181035f83015261212d816120f4565b9050919050565b7f496e76616c69642076616c696469747920706572696f640000000000000000005f82015250565b5f612168601783611e47565b915061217382612134565b602082019050919050565b5f6020820190508181035f8301526121958161215c565b9050919050565b7f44656c6567617465206e6f7420726567697374657265640000000000000000005f82015250565b5f6121d0601783611e47565b91506121db8261219c565b602082019050919050565b5f6020820190508181035f8301526121fd816121c4565b9050919050565b7f44656c65676174696f6e20616c726561647920657869737473000000000000005f82015250565b5f612238601983611e47565b915061224382612204565b602082019050919050565b5f6020820190508181035f8301526122658161222c565b9050919050565b5f5ffd5b82818337505050565b5f6122848385611d50565b93507f07ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff8311156122b7576122b661226c565b5b6020830292506122c8838584612270565b82840190509392505050565b5f6040820190508181035f8301526122ed818587612279565b90506122fc6020830184611c63565b94935050505056fea26469706673582212205e174bb0640cb5f9ed28952443ec1e6ab251b4123bab9a76127ccf06b5b69ffc64736f6c634300081e0033",
+}
+
+// AgentDelegationABI is the input ABI used to generate the binding from.
+// Deprecated: Use AgentDelegationMetaData.ABI instead.
+var AgentDelegationABI = AgentDelegationMetaData.ABI
+
+// AgentDelegationBin is the compiled bytecode used for deploying new contracts.
+// Deprecated: Use AgentDelegationMetaData.Bin instead.
+var AgentDelegationBin = AgentDelegationMetaData.Bin
+
+// DeployAgentDelegation deploys a new Ethereum contract, binding an instance of AgentDelegation to it.
+func DeployAgentDelegation(auth *bind.TransactOpts, backend bind.ContractBackend, _registry common.Address) (common.Address, *types.Transaction, *AgentDelegation, error) {
+	parsed, err := AgentDelegationMetaData.GetAbi()
+	if err != nil {
+		return common.Address{}, nil, nil, err
+	}
+	if parsed == nil {
+		return common.Address{}, nil, nil, errors.New("GetABI returned nil")
+	}
+
+	address, tx, contract, err := bind.DeployContract(auth, *parsed, common.FromHex(AgentDelegationBin), backend, _registry)
+	if err != nil {
+		return common.Address{}, nil, nil, err
+	}
+	return address, tx, &AgentDelegation{AgentDelegationCaller: AgentDelegationCaller{contract: contract}, AgentDelegationTransactor: AgentDelegationTransactor{contract: contract}, AgentDelegationFilterer: AgentDelegationFilterer{contract: contract}}, nil
+}
+
+// AgentDelegation is an auto generated Go binding around an Ethereum contract.
+type AgentDelegation struct {
+	AgentDelegationCaller     // Read-only binding to the contract
+	AgentDelegationTransactor // Write-only binding to the contract
+	AgentDelegationFilterer   // Log filterer for contract events
+}
+
+// AgentDelegationCaller is an auto generated read-only Go binding around an Ethereum contract.
+type AgentDelegationCaller struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// AgentDelegationTransactor is an auto generated write-only Go binding around an Ethereum contract.
+type AgentDelegationTransactor struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// AgentDelegationFilterer is an auto generated log filtering Go binding around an Ethereum contract events.
+type AgentDelegationFilterer struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// AgentDelegationSession is an auto generated Go binding around an Ethereum contract,
+// with pre-set call and transact options.
+type AgentDelegationSession struct {
+	Contract     *AgentDelegation  // Generic contract binding to set the session for
+	CallOpts     bind.CallOpts     // Call options to use throughout this session
+	TransactOpts bind.TransactOpts // Transaction auth options to use throughout this session
+}
+
+// AgentDelegationCallerSession is an auto generated read-only Go binding around an Ethereum contract,
+// with pre-set call options.
+type AgentDelegationCallerSession struct {
+	Contract *AgentDelegationCaller // Generic contract caller binding to set the session for
+	CallOpts bind.CallOpts          // Call options to use throughout this session
+}
+
+// AgentDelegationTransactorSession is an auto generated write-only Go binding around an Ethereum contract,
+// with pre-set transact options.
+type AgentDelegationTransactorSession struct {
+	Contract     *AgentDelegationTransactor // Generic contract transactor binding to set the session for
+	TransactOpts bind.TransactOpts          // Transaction auth options to use throughout this session
+}
+
+// AgentDelegationRaw is an auto generated low-level Go binding around an Ethereum contract.
+type AgentDelegationRaw struct {
+	Contract *AgentDelegation // Generic contract binding to access the raw methods on
+}
+
+// AgentDelegationCallerRaw is an auto generated low-level read-only Go binding around an Ethereum contract.
+type AgentDelegationCallerRaw struct {
+	Contract *AgentDelegationCaller // Generic read-only contract binding to access the raw methods on
+}
+
+// AgentDelegationTransactorRaw is an auto generated low-level write-only Go binding around an Ethereum contract.
+type AgentDelegationTransactorRaw struct {
+	Contract *AgentDelegationTransactor // Generic write-only contract binding to access the raw methods on
+}
+
+// NewAgentDelegation creates a new instance of AgentDelegation, bound to a specific deployed contract.
+func NewAgentDelegation(address common.Address, backend bind.ContractBackend) (*AgentDelegation, error) {
+	contract, err := bindAgentDelegation(address, backend, backend, backend)
+	if err != nil {
+		return nil, err
+	}
+	return &AgentDelegation{AgentDelegationCaller: AgentDelegationCaller{contract: contract}, AgentDelegationTransactor: AgentDelegationTransactor{contract: contract}, AgentDelegationFilterer: AgentDelegationFilterer{contract: contract}}, nil
+}
+
+// NewAgentDelegationCaller creates a new read-only instance of AgentDelegation, bound to a specific deployed contract.
+func NewAgentDelegationCaller(address common.Address, caller bind.ContractCaller) (*AgentDelegationCaller, error) {
+	contract, err := bindAgentDelegation(address, caller, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &AgentDelegationCaller{contract: contract}, nil
+}
+
+// NewAgentDelegationTransactor creates a new write-only instance of AgentDelegation, bound to a specific deployed contract.
+func NewAgentDelegationTransactor(address common.Address, transactor bind.ContractTransactor) (*AgentDelegationTransactor, error) {
+	contract, err := bindAgentDelegation(address, nil, transactor, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &AgentDelegationTransactor{contract: contract}, nil
+}
+
+// NewAgentDelegationFilterer creates a new log filterer instance of AgentDelegation, bound to a specific deployed contract.
+func NewAgentDelegationFilterer(address common.Address, filterer bind.ContractFilterer) (*AgentDelegationFilterer, error) {
+	contract, err := bindAgentDelegation(address, nil, nil, filterer)
+	if err != nil {
+		return nil, err
+	}
+	return &AgentDelegationFilterer{contract: contract}, nil
+}
+
+// bindAgentDelegation binds a generic wrapper to an already deployed contract.
+func bindAgentDelegation(address common.Address, caller bind.ContractCaller, transactor bind.ContractTransactor, filterer bind.ContractFilterer) (*bind.BoundContract, error) {
+	parsed, err := AgentDelegationMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	return bind.NewBoundContract(address, *parsed, caller, transactor, filterer), nil
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_AgentDelegation *AgentDelegationRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _AgentDelegation.Contract.AgentDelegationCaller.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_AgentDelegation *AgentDelegationRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _AgentDelegation.Contract.AgentDelegationTransactor.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_AgentDelegation *AgentDelegationRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _AgentDelegation.Contract.AgentDelegationTransactor.contract.Transact(opts, method, params...)
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_AgentDelegation *AgentDelegationCallerRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _AgentDelegation.Contract.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_AgentDelegation *AgentDelegationTransactorRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _AgentDelegation.Contract.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_AgentDelegation *AgentDelegationTransactorRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _AgentDelegation.Contract.contract.Transact(opts, method, params...)
+}
+
+// DelegateList is a free data retrieval call binding the contract method 0x7e360d52.
+//
+// Solidity: function delegateList(address , uint256 ) view returns(address)
+func (_AgentDelegation *AgentDelegationCaller) DelegateList(opts *bind.CallOpts, arg0 common.Address, arg1 *big.Int) (common.Address, error) {
+	var out []interface{}
+	err := _AgentDelegation.contract.Call(opts, &out, "delegateList", arg0, arg1)
+
+	if err != nil {
+		return *new(common.Address), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(common.Address)).(*common.Address)
+
+	return out0, err
+
+}
+
+// DelegateList is a free data retrieval call binding the contract method 0x7e360d52.
+//
+// Solidity: function delegateList(address , uint256 ) view returns(address)
+func (_AgentDelegation *AgentDelegationSession) DelegateList(arg0 common.Address, arg1 *big.Int) (common.Address, error) {
+	return _AgentDelegation.Contract.DelegateList(&_AgentDelegation.CallOpts, arg0, arg1)
+}
+
+// DelegateList is a free data retrieval call binding the contract method 0x7e360d52.
+//
+// Solidity: function delegateList(address , uint256 ) view returns(address)
+func (_AgentDelegation *AgentDelegationCallerSession) DelegateList(arg0 common.Address, arg1 *big.Int) (common.Address, error) {
+	return _AgentDelegation.Contract.DelegateList(&_AgentDelegation.CallOpts, arg0, arg1)
+}
+
+// Delegations is a free data retrieval call binding the contract method 0xc64814dd.
+//
+// Solidity: function delegations(address , address ) view returns(address delegator, address delegate, uint256 validFrom, uint256 validUntil, bool isActive)
+func (_AgentDelegation *AgentDelegationCaller) Delegations(opts *bind.CallOpts, arg0 common.Address, arg1 common.Address) (struct {
+	Delegator  common.Address
+	Delegate   common.Address
+	ValidFrom  *big.Int
+	ValidUntil *big.Int
+	IsActive   bool
+}, error) {
+	var out []interface{}
+	err := _AgentDelegation.contract.Call(opts, &out, "delegations", arg0, arg1)
+
+	outstruct := new(struct {
+		Delegator  common.Address
+		Delegate   common.Address
+		ValidFrom  *big.Int
+		ValidUntil *big.Int
+		IsActive   bool
+	})
+	if err != nil {
+		return *outstruct, err
+	}
+
+	outstruct.Delegator = *abi.ConvertType(out[0], new(common.Address)).(*common.Address)
+	outstruct.Delegate = *abi.ConvertType(out[1], new(common.Address)).(*common.Address)
+	outstruct.ValidFrom = *abi.ConvertType(out[2], new(*big.Int)).(**big.Int)
+	outstruct.ValidUntil = *abi.ConvertType(out[3], new(*big.Int)).(**big.Int)
+	outstruct.IsActive = *abi.ConvertType(out[4], new(bool)).(*bool)
+
+	return *outstruct, err
+
+}
+
+// Delegations is a free data retrieval call binding the contract method 0xc64814dd.
+//
+// Solidity: function delegations(address , address ) view returns(address delegator, address delegate, uint256 validFrom, uint256 validUntil, bool isActive)
+func (_AgentDelegation *AgentDelegationSession) Delegations(arg0 common.Address, arg1 common.Address) (struct {
+	Delegator  common.Address
+	Delegate   common.Address
+	ValidFrom  *big.Int
+	ValidUntil *big.Int
+	IsActive   bool
+}, error) {
+	return _AgentDelegation.Contract.Delegations(&_AgentDelegation.CallOpts, arg0, arg1)
+}
+
+// Delegations is a free data retrieval call binding the contract method 0xc64814dd.
+//
+// Solidity: function delegations(address , address ) view returns(address delegator, address delegate, uint256 validFrom, uint256 validUntil, bool isActive)
+func (_AgentDelegation *AgentDelegationCallerSession) Delegations(arg0 common.Address, arg1 common.Address) (struct {
+	Delegator  common.Address
+	Delegate   common.Address
+	ValidFrom  *big.Int
+	ValidUntil *big.Int
+	IsActive   bool
+}, error) {
+	return _AgentDelegation.Contract.Delegations(&_AgentDelegation.CallOpts, arg0, arg1)
+}
+
+// GetDelegateList is a free data retrieval call binding the contract method 0x969fecd4.
+//
+// Solidity: function getDelegateList(address delegator) view returns(address[])
+func (_AgentDelegation *AgentDelegationCaller) GetDelegateList(opts *bind.CallOpts, delegator common.Address) ([]common.Address, error) {
+	var out []interface{}
+	err := _AgentDelegation.contract.Call(opts, &out, "getDelegateList", delegator)
+
+	if err != nil {
+		return *new([]common.Address), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new([]common.Address)).(*[]common.Address)
+
+	return out0, err
+
+}
+
+// GetDelegateList is a free data retrieval call binding the contract method 0x969fecd4.
+//
+// Solidity: function getDelegateList(address delegator) view returns(address[])
+func (_AgentDelegation *AgentDelegationSession) GetDelegateList(delegator common.Address) ([]common.Address, error) {
+	return _AgentDelegation.Contract.GetDelegateList(&_AgentDelegation.CallOpts, delegator)
+}
+
+// GetDelegateList is a free data retrieval call binding the contract method 0x969fecd4.
+//
+// Solidity: function getDelegateList(address delegator) view returns(address[])
+func (_AgentDelegation *AgentDelegationCallerSession) GetDelegateList(delegator common.Address) ([]common.Address, error) {
+	return _AgentDelegation.Contract.GetDelegateList(&_AgentDelegation.CallOpts, delegator)
+}
+
+// GetDelegationDetails is a free data retrieval call binding the contract method 0xf6f24bb9.
+//
+// Solidity: function getDelegationDetails(address delegator, address delegate) view returns(bytes32[] capabilities, uint256 validFrom, uint256 validUntil, bool isActive)
+func (_AgentDelegation *AgentDelegationCaller) GetDelegationDetails(opts *bind.CallOpts, delegator common.Address, delegate common.Address) (struct {
+	Capabilities [][32]byte
+	ValidFrom    *big.Int
+	ValidUntil   *big.Int
+	IsActive     bool
+}, error) {
+	var out []interface{}
+	err := _AgentDelegation.contract.Call(opts, &out, "getDelegationDetails", delegator, delegate)
+
+	outstruct := new(struct {
+		Capabilities [][32]byte
+		ValidFrom    *big.Int
+		ValidUntil   *big.Int
+		IsActive     bool
+	})
+	if err != nil {
+		return *outstruct, err
+	}
+
+	outstruct.Capabilities = *abi.ConvertType(out[0], new([][32]byte)).(*[][32]byte)
+	outstruct.ValidFrom = *abi.ConvertType(out[1], new(*big.Int)).(**big.Int)
+	outstruct.ValidUntil = *abi.ConvertType(out[2], new(*big.Int)).(**big.Int)
+	outstruct.IsActive = *abi.ConvertType(out[3], new(bool)).(*bool)
+
+	return *outstruct, err
+
+}
+
+// GetDelegationDetails is a free data retrieval call binding the contract method 0xf6f24bb9.
+//
+// Solidity: function getDelegationDetails(address delegator, address delegate) view returns(bytes32[] capabilities, uint256 validFrom, uint256 validUntil, bool isActive)
+func (_AgentDelegation *AgentDelegationSession) GetDelegationDetails(delegator common.Address, delegate common.Address) (struct {
+	Capabilities [][32]byte
+	ValidFrom    *big.Int
+	ValidUntil   *big.Int
+	IsActive     bool
+}, error) {
+	return _AgentDelegation.Contract.GetDelegationDetails(&_AgentDelegation.CallOpts, delegator, delegate)
+}
+
+// GetDelegationDetails is a free data retrieval call binding the contract method 0xf6f24bb9.
+//
+// Solidity: function getDelegationDetails(address delegator, address delegate) view returns(bytes32[] capabilities, uint256 validFrom, uint256 validUntil, bool isActive)
+func (_AgentDelegation *AgentDelegationCallerSession) GetDelegationDetails(delegator common.Address, delegate common.Address) (struct {
+	Capabilities [][32]byte
+	ValidFrom    *big.Int
+	ValidUntil   *big.Int
+	IsActive     bool
+}, error) {
+	return _AgentDelegation.Contract.GetDelegationDetails(&_AgentDelegation.CallOpts, delegator, delegate)
+}
+
+// HasDelegatedCapability is a free data retrieval call binding the contract method 0xed0a58b3.
+//
+// Solidity: function hasDelegatedCapability(address delegator, address delegate, bytes32 capability) view returns(bool)
+func (_AgentDelegation *AgentDelegationCaller) HasDelegatedCapability(opts *bind.CallOpts, delegator common.Address, delegate common.Address, capability [32]byte) (bool, error) {
+	var out []interface{}
+	err := _AgentDelegation.contract.Call(opts, &out, "hasDelegatedCapability", delegator, delegate, capability)
+
+	if err != nil {
+		return *new(bool), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(bool)).(*bool)
+
+	return out0, err
+
+}
+
+// HasDelegatedCapability is a free data retrieval call binding the contract method 0xed0a58b3.
+//
+// Solidity: function hasDelegatedCapability(address delegator, address delegate, bytes32 capability) view returns(bool)
+func (_AgentDelegation *AgentDelegationSession) HasDelegatedCapability(delegator common.Address, delegate common.Address, capability [32]byte) (bool, error) {
+	return _AgentDelegation.Contract.HasDelegatedCapability(&_AgentDelegation.CallOpts, delegator, delegate, capability)
+}
+
+// HasDelegatedCapability is a free data retrieval call binding the contract method 0xed0a58b3.
+//
+// Solidity: function hasDelegatedCapability(address delegator, address delegate, bytes32 capability) view returns(bool)
+func (_AgentDelegation *AgentDelegationCallerSession) HasDelegatedCapability(delegator common.Address, delegate common.Address, capability [32]byte) (bool, error) {
+	return _AgentDelegation.Contract.HasDelegatedCapability(&_AgentDelegation.CallOpts, delegator, delegate, capability)
+}
+
+// Owner is a free data retrieval call binding the contract method 0x8da5cb5b.
+//
+// Solidity: function owner() view returns(address)
+func (_AgentDelegation *AgentDelegationCaller) Owner(opts *bind.CallOpts) (common.Address, error) {
+	var out []interface{}
+	err := _AgentDelegation.contract.Call(opts, &out, "owner")
+
+	if err != nil {
+		return *new(common.Address), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(common.Address)).(*common.Address)
+
+	return out0, err
+
+}
+
+// Owner is a free data retrieval call binding the contract method 0x8da5cb5b.
+//
+// Solidity: function owner() view returns(address)
+func (_AgentDelegation *AgentDelegationSession) Owner() (common.Address, error) {
+	return _AgentDelegation.Contract.Owner(&_AgentDelegation.CallOpts)
+}
+
+// Owner is a free data retrieval call binding the contract method 0x8da5cb5b.
+//
+// Solidity: function owner() view returns(address)
+func (_AgentDelegation *AgentDelegationCallerSession) Owner() (common.Address, error) {
+	return _AgentDelegation.Contract.Owner(&_AgentDelegation.CallOpts)
+}
+
+// Paused is a free data retrieval call binding the contract method 0x5c975abb.
+//
+// Solidity: function paused() view returns(bool)
+func (_AgentDelegation *AgentDelegationCaller) Paused(opts *bind.CallOpts) (bool, error) {
+	var out []interface{}
+	err := _AgentDelegation.contract.Call(opts, &out, "paused")
+
+	if err != nil {
+		return *new(bool), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(bool)).(*bool)
+
+	return out0, err
+
+}
+
+// Paused is a free data retrieval call binding the contract method 0x5c975abb.
+//
+// Solidity: function paused() view returns(bool)
+func (_AgentDelegation *AgentDelegationSession) Paused() (bool, error) {
+	return _AgentDelegation.Contract.Paused(&_AgentDelegation.CallOpts)
+}
+
+// Paused is a free data retrieval call binding the contract method 0x5c975abb.
+//
+// Solidity: function paused() view returns(bool)
+func (_AgentDelegation *AgentDelegationCallerSession) Paused() (bool, error) {
+	return _AgentDelegation.Contract.Paused(&_AgentDelegation.CallOpts)
+}
+
+// Registry is a free data retrieval call binding the contract method 0x7b103999.
+//
+// Solidity: function registry() view returns(address)
+func (_AgentDelegation *AgentDelegationCaller) Registry(opts *bind.CallOpts) (common.Address, error) {
+	var out []interface{}
+	err := _AgentDelegation.contract.Call(opts, &out, "registry")
+
+	if err != nil {
+		return *new(common.Address), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(common.Address)).(*common.Address)
+
+	return out0, err
+
+}
+
+// Registry is a free data retrieval call binding the contract method 0x7b103999.
+//
+// Solidity: function registry() view returns(address)
+func (_AgentDelegation *AgentDelegationSession) Registry() (common.Address, error) {
+	return _AgentDelegation.Contract.Registry(&_AgentDelegation.CallOpts)
+}
+
+// Registry is a free data retrieval call binding the contract method 0x7b103999.
+//
+// Solidity: function registry() view returns(address)
+func (_AgentDelegation *AgentDelegationCallerSession) Registry() (common.Address, error) {
+	return _AgentDelegation.Contract.Registry(&_AgentDelegation.CallOpts)
+}
+
+// CreateDelegation is a paid mutator transaction binding the contract method 0xb2b687e5.
+//
+// Solidity: function createDelegation(address delegate, bytes32[] capabilities, uint256 validUntil) returns()
+func (_AgentDelegation *AgentDelegationTransactor) CreateDelegation(opts *bind.TransactOpts, delegate common.Address, capabilities [][32]byte, validUntil *big.Int) (*types.Transaction, error) {
+	return _AgentDelegation.contract.Transact(opts, "createDelegation", delegate, capabilities, validUntil)
+}
+
+// CreateDelegation is a paid mutator transaction binding the contract method 0xb2b687e5.
+//
+// Solidity: function createDelegation(address delegate, bytes32[] capabilities, uint256 validUntil) returns()
+func (_AgentDelegation *AgentDelegationSession) CreateDelegation(delegate common.Address, capabilities [][32]byte, validUntil *big.Int) (*types.Transaction, error) {
+	return _AgentDelegation.Contract.CreateDelegation(&_AgentDelegation.TransactOpts, delegate, capabilities, validUntil)
+}
+
+// CreateDelegation is a paid mutator transaction binding the contract method 0xb2b687e5.
+//
+// Solidity: function createDelegation(address delegate, bytes32[] capabilities, uint256 validUntil) returns()
+func (_AgentDelegation *AgentDelegationTransactorSession) CreateDelegation(delegate common.Address, capabilities [][32]byte, validUntil *big.Int) (*types.Transaction, error) {
+	return _AgentDelegation.Contract.CreateDelegation(&_AgentDelegation.TransactOpts, delegate, capabilities, validUntil)
+}
+
+// Pause is a paid mutator transaction binding the contract method 0x8456cb59.
+//
+// Solidity: function pause() returns()
+func (_AgentDelegation *AgentDelegationTransactor) Pause(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _AgentDelegation.contract.Transact(opts, "pause")
+}
+
+// Pause is a paid mutator transaction binding the contract method 0x8456cb59.
+//
+// Solidity: function pause() returns()
+func (_AgentDelegation *AgentDelegationSession) Pause() (*types.Transaction, error) {
+	return _AgentDelegation.Contract.Pause(&_AgentDelegation.TransactOpts)
+}
+
+// Pause is a paid mutator transaction binding the contract method 0x8456cb59.
+//
+// Solidity: function pause() returns()
+func (_AgentDelegation *AgentDelegationTransactorSession) Pause() (*types.Transaction, error) {
+	return _AgentDelegation.Contract.Pause(&_AgentDelegation.TransactOpts)
+}
+
+// RenounceOwnership is a paid mutator transaction binding the contract method 0x715018a6.
+//
+// Solidity: function renounceOwnership() returns()
+func (_AgentDelegation *AgentDelegationTransactor) RenounceOwnership(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _AgentDelegation.contract.Transact(opts, "renounceOwnership")
+}
+
+// RenounceOwnership is a paid mutator transaction binding the contract method 0x715018a6.
+//
+// Solidity: function renounceOwnership() returns()
+func (_AgentDelegation *AgentDelegationSession) RenounceOwnership() (*types.Transaction, error) {
+	return _AgentDelegation.Contract.RenounceOwnership(&_AgentDelegation.TransactOpts)
+}
+
+// RenounceOwnership is a paid mutator transaction binding the contract method 0x715018a6.
+//
+// Solidity: function renounceOwnership() returns()
+func (_AgentDelegation *AgentDelegationTransactorSession) RenounceOwnership() (*types.Transaction, error) {
+	return _AgentDelegation.Contract.RenounceOwnership(&_AgentDelegation.TransactOpts)
+}
+
+// RevokeDelegation is a paid mutator transaction binding the contract method 0x658e566a.
+//
+// Solidity: function revokeDelegation(address delegate, string reason) returns()
+func (_AgentDelegation *AgentDelegationTransactor) RevokeDelegation(opts *bind.TransactOpts, delegate common.Address, reason string) (*types.Transaction, error) {
+	return _AgentDelegation.contract.Transact(opts, "revokeDelegation", delegate, reason)
+}
+
+// RevokeDelegation is a paid mutator transaction binding the contract method 0x658e566a.
+//
+// Solidity: function revokeDelegation(address delegate, string reason) returns()
+func (_AgentDelegation *AgentDelegationSession) RevokeDelegation(delegate common.Address, reason string) (*types.Transaction, error) {
+	return _AgentDelegation.Contract.RevokeDelegation(&_AgentDelegation.TransactOpts, delegate, reason)
+}
+
+// RevokeDelegation is a paid mutator transaction binding the contract method 0x658e566a.
+//
+// Solidity: function revokeDelegation(address delegate, string reason) returns()
+func (_AgentDelegation *AgentDelegationTransactorSession) RevokeDelegation(delegate common.Address, reason string) (*types.Transaction, error) {
+	return _AgentDelegation.Contract.RevokeDelegation(&_AgentDelegation.TransactOpts, delegate, reason)
+}
+
+// SetRegistry is a paid mutator transaction binding the contract method 0xa91ee0dc.
+//
+// Solidity: function setRegistry(address _registry) returns()
+func (_AgentDelegation *AgentDelegationTransactor) SetRegistry(opts *bind.TransactOpts, _registry common.Address) (*types.Transaction, error) {
+	return _AgentDelegation.contract.Transact(opts, "setRegistry", _registry)
+}
+
+// SetRegistry is a paid mutator transaction binding the contract method 0xa91ee0dc.
+//
+// Solidity: function setRegistry(address _registry) returns()
+func (_AgentDelegation *AgentDelegationSession) SetRegistry(_registry common.Address) (*types.Transaction, error) {
+	return _AgentDelegation.Contract.SetRegistry(&_AgentDelegation.TransactOpts, _registry)
+}
+
+// SetRegistry is a paid mutator transaction binding the contract method 0xa91ee0dc.
+//
+// Solidity: function setRegistry(address _registry) returns()
+func (_AgentDelegation *AgentDelegationTransactorSession) SetRegistry(_registry common.Address) (*types.Transaction, error) {
+	return _AgentDelegation.Contract.SetRegistry(&_AgentDelegation.TransactOpts, _registry)
+}
+
+// TransferOwnership is a paid mutator transaction binding the contract method 0xf2fde38b.
+//
+// Solidity: function transferOwnership(address newOwner) returns()
+func (_AgentDelegation *AgentDelegationTransactor) TransferOwnership(opts *bind.TransactOpts, newOwner common.Address) (*types.Transaction, error) {
+	return _AgentDelegation.contract.Transact(opts, "transferOwnership", newOwner)
+}
+
+// TransferOwnership is a paid mutator transaction binding the contract method 0xf2fde38b.
+//
+// Solidity: function transferOwnership(address newOwner) returns()
+func (_AgentDelegation *AgentDelegationSession) TransferOwnership(newOwner common.Address) (*types.Transaction, error) {
+	return _AgentDelegation.Contract.TransferOwnership(&_AgentDelegation.TransactOpts, newOwner)
+}
+
+// TransferOwnership is a paid mutator transaction binding the contract method 0xf2fde38b.
+//
+// Solidity: function transferOwnership(address newOwner) returns()
+func (_AgentDelegation *AgentDelegationTransactorSession) TransferOwnership(newOwner common.Address) (*types.Transaction, error) {
+	return _AgentDelegation.Contract.TransferOwnership(&_AgentDelegation.TransactOpts, newOwner)
+}
+
+// Unpause is a paid mutator transaction binding the contract method 0x3f4ba83a.
+//
+// Solidity: function unpause() returns()
+func (_AgentDelegation *AgentDelegationTransactor) Unpause(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _AgentDelegation.contract.Transact(opts, "unpause")
+}
+
+// Unpause is a paid mutator transaction binding the contract method 0x3f4ba83a.
+//
+// Solidity: function unpause() returns()
+func (_AgentDelegation *AgentDelegationSession) Unpause() (*types.Transaction, error) {
+	return _AgentDelegation.Contract.Unpause(&_AgentDelegation.TransactOpts)
+}
+
+// Unpause is a paid mutator transaction binding the contract method 0x3f4ba83a.
+//
+// Solidity: function unpause() returns()
+func (_AgentDelegation *AgentDelegationTransactorSession) Unpause() (*types.Transaction, error) {
+	return _AgentDelegation.Contract.Unpause(&_AgentDelegation.TransactOpts)
+}
+
+// AgentDelegationDelegationCreatedIterator is returned from FilterDelegationCreated and is used to iterate over the raw logs and unpacked data for DelegationCreated events raised by the AgentDelegation contract.
+type AgentDelegationDelegationCreatedIterator struct {
+	Event *AgentDelegationDelegationCreated // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *AgentDelegationDelegationCreatedIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(AgentDelegationDelegationCreated)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(AgentDelegationDelegationCreated)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *AgentDelegationDelegationCreatedIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *AgentDelegationDelegationCreatedIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// AgentDelegationDelegationCreated represents a DelegationCreated event raised by the AgentDelegation contract.
+type AgentDelegationDelegationCreated struct {
+	Delegator    common.Address
+	Delegate     common.Address
+	Capabilities [][32]byte
+	ValidUntil   *big.Int
+	Raw          types.Log // Blockchain specific contextual infos
+}
+
+// FilterDelegationCreated is a free log retrieval operation binding the contract event 0xf6daba9a137fa5026dbda1ca430e44b5968c8b6f4e6050e2de1695d54d8ccc74.
+//
+// Solidity: event DelegationCreated(address indexed delegator, address indexed delegate, bytes32[] capabilities, uint256 validUntil)
+func (_AgentDelegation *AgentDelegationFilterer) FilterDelegationCreated(opts *bind.FilterOpts, delegator []common.Address, delegate []common.Address) (*AgentDelegationDelegationCreatedIterator, error) {
+
+	var delegatorRule []interface{}
+	for _, delegatorItem := range delegator {
+		delegatorRule = append(delegatorRule, delegatorItem)
+	}
+	var delegateRule []interface{}
+	for _, delegateItem := range delegate {
+		delegateRule = append(delegateRule, delegateItem)
+	}
+
+	logs, sub, err := _AgentDelegation.contract.FilterLogs(opts, "DelegationCreated", delegatorRule, delegateRule)
+	if err != nil {
+		return nil, err
+	}
+	return &AgentDelegationDelegationCreatedIterator{contract: _AgentDelegation.contract, event: "DelegationCreated", logs: logs, sub: sub}, nil
+}
+
+// WatchDelegationCreated is a free log subscription operation binding the contract event 0xf6daba9a137fa5026dbda1ca430e44b5968c8b6f4e6050e2de1695d54d8ccc74.
+//
+// Solidity: event DelegationCreated(address indexed delegator, address indexed delegate, bytes32[] capabilities, uint256 validUntil)
+func (_AgentDelegation *AgentDelegationFilterer) WatchDelegationCreated(opts *bind.WatchOpts, sink chan<- *AgentDelegationDelegationCreated, delegator []common.Address, delegate []common.Address) (event.Subscription, error) {
+
+	var delegatorRule []interface{}
+	for _, delegatorItem := range delegator {
+		delegatorRule = append(delegatorRule, delegatorItem)
+	}
+	var delegateRule []interface{}
+	for _, delegateItem := range delegate {
+		delegateRule = append(delegateRule, delegateItem)
+	}
+
+	logs, sub, err := _AgentDelegation.contract.WatchLogs(opts, "DelegationCreated", delegatorRule, delegateRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(AgentDelegationDelegationCreated)
+				if err := _AgentDelegation.contract.UnpackLog(event, "DelegationCreated", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseDelegationCreated is a log parse operation binding the contract event 0xf6daba9a137fa5026dbda1ca430e44b5968c8b6f4e6050e2de1695d54d8ccc74.
+//
+// Solidity: event DelegationCreated(address indexed delegator, address indexed delegate, bytes32[] capabilities, uint256 validUntil)
+func (_AgentDelegation *AgentDelegationFilterer) ParseDelegationCreated(log types.Log) (*AgentDelegationDelegationCreated, error) {
+	event := new(AgentDelegationDelegationCreated)
+	if err := _AgentDelegation.contract.UnpackLog(event, "DelegationCreated", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// AgentDelegationDelegationExpiredIterator is returned from FilterDelegationExpired and is used to iterate over the raw logs and unpacked data for DelegationExpired events raised by the AgentDelegation contract.
+type AgentDelegationDelegationExpiredIterator struct {
+	Event *AgentDelegationDelegationExpired // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *AgentDelegationDelegationExpiredIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(AgentDelegationDelegationExpired)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(AgentDelegationDelegationExpired)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *AgentDelegationDelegationExpiredIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *AgentDelegationDelegationExpiredIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// AgentDelegationDelegationExpired represents a DelegationExpired event raised by the AgentDelegation contract.
+type AgentDelegationDelegationExpired struct {
+	Delegator common.Address
+	Delegate  common.Address
+	Raw       types.Log // Blockchain specific contextual infos
+}
+
+// FilterDelegationExpired is a free log retrieval operation binding the contract event 0x1ffa9acf866f5bf0dcb9ff06545a0b242ef4c0cb13c62ea80add1451988e918d.
+//
+// Solidity: event DelegationExpired(address indexed delegator, address indexed delegate)
+func (_AgentDelegation *AgentDelegationFilterer) FilterDelegationExpired(opts *bind.FilterOpts, delegator []common.Address, delegate []common.Address) (*AgentDelegationDelegationExpiredIterator, error) {
+
+	var delegatorRule []interface{}
+	for _, delegatorItem := range delegator {
+		delegatorRule = append(delegatorRule, delegatorItem)
+	}
+	var delegateRule []interface{}
+	for _, delegateItem := range delegate {
+		delegateRule = append(delegateRule, delegateItem)
+	}
+
+	logs, sub, err := _AgentDelegation.contract.FilterLogs(opts, "DelegationExpired", delegatorRule, delegateRule)
+	if err != nil {
+		return nil, err
+	}
+	return &AgentDelegationDelegationExpiredIterator{contract: _AgentDelegation.contract, event: "DelegationExpired", logs: logs, sub: sub}, nil
+}
+
+// WatchDelegationExpired is a free log subscription operation binding the contract event 0x1ffa9acf866f5bf0dcb9ff06545a0b242ef4c0cb13c62ea80add1451988e918d.
+//
+// Solidity: event DelegationExpired(address indexed delegator, address indexed delegate)
+func (_AgentDelegation *AgentDelegationFilterer) WatchDelegationExpired(opts *bind.WatchOpts, sink chan<- *AgentDelegationDelegationExpired, delegator []common.Address, delegate []common.Address) (event.Subscription, error) {
+
+	var delegatorRule []interface{}
+	for _, delegatorItem := range delegator {
+		delegatorRule = append(delegatorRule, delegatorItem)
+	}
+	var delegateRule []interface{}
+	for _, delegateItem := range delegate {
+		delegateRule = append(delegateRule, delegateItem)
+	}
+
+	logs, sub, err := _AgentDelegation.contract.WatchLogs(opts, "DelegationExpired", delegatorRule, delegateRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(AgentDelegationDelegationExpired)
+				if err := _AgentDelegation.contract.UnpackLog(event, "DelegationExpired", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseDelegationExpired is a log parse operation binding the contract event 0x1ffa9acf866f5bf0dcb9ff06545a0b242ef4c0cb13c62ea80add1451988e918d.
+//
+// Solidity: event DelegationExpired(address indexed delegator, address indexed delegate)
+func (_AgentDelegation *AgentDelegationFilterer) ParseDelegationExpired(log types.Log) (*AgentDelegationDelegationExpired, error) {
+	event := new(AgentDelegationDelegationExpired)
+	if err := _AgentDelegation.contract.UnpackLog(event, "DelegationExpired", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// AgentDelegationDelegationRevokedIterator is returned from FilterDelegationRevoked and is used to iterate over the raw logs and unpacked data for DelegationRevoked events raised by the AgentDelegation contract.
+type AgentDelegationDelegationRevokedIterator struct {
+	Event *AgentDelegationDelegationRevoked // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *AgentDelegationDelegationRevokedIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(AgentDelegationDelegationRevoked)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(AgentDelegationDelegationRevoked)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *AgentDelegationDelegationRevokedIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *AgentDelegationDelegationRevokedIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// AgentDelegationDelegationRevoked represents a DelegationRevoked event raised by the AgentDelegation contract.
+type AgentDelegationDelegationRevoked struct {
+	Delegator common.Address
+	Delegate  common.Address
+	Reason    string
+	Raw       types.Log // Blockchain specific contextual infos
+}
+
+// FilterDelegationRevoked is a free log retrieval operation binding the contract event 0x7b9de2ea25e2bcf2150702bf2d070e8245d542228ef81748a5c86603ec13c034.
+//
+// Solidity: event DelegationRevoked(address indexed delegator, address indexed delegate, string reason)
+func (_AgentDelegation *AgentDelegationFilterer) FilterDelegationRevoked(opts *bind.FilterOpts, delegator []common.Address, delegate []common.Address) (*AgentDelegationDelegationRevokedIterator, error) {
+
+	var delegatorRule []interface{}
+	for _, delegatorItem := range delegator {
+		delegatorRule = append(delegatorRule, delegatorItem)
+	}
+	var delegateRule []interface{}
+	for _, delegateItem := range delegate {
+		delegateRule = append(delegateRule, delegateItem)
+	}
+
+	logs, sub, err := _AgentDelegation.contract.FilterLogs(opts, "DelegationRevoked", delegatorRule, delegateRule)
+	if err != nil {
+		return nil, err
+	}
+	return &AgentDelegationDelegationRevokedIterator{contract: _AgentDelegation.contract, event: "DelegationRevoked", logs: logs, sub: sub}, nil
+}
+
+// WatchDelegationRevoked is a free log subscription operation binding the contract event 0x7b9de2ea25e2bcf2150702bf2d070e8245d542228ef81748a5c86603ec13c034.
+//
+// Solidity: event DelegationRevoked(address indexed delegator, address indexed delegate, string reason)
+func (_AgentDelegation *AgentDelegationFilterer) WatchDelegationRevoked(opts *bind.WatchOpts, sink chan<- *AgentDelegationDelegationRevoked, delegator []common.Address, delegate []common.Address) (event.Subscription, error) {
+
+	var delegatorRule []interface{}
+	for _, delegatorItem := range delegator {
+		delegatorRule = append(delegatorRule, delegatorItem)
+	}
+	var delegateRule []interface{}
+	for _, delegateItem := range delegate {
+		delegateRule = append(delegateRule, delegateItem)
+	}
+
+	logs, sub, err := _AgentDelegation.contract.WatchLogs(opts, "DelegationRevoked", delegatorRule, delegateRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(AgentDelegationDelegationRevoked)
+				if err := _AgentDelegation.contract.UnpackLog(event, "DelegationRevoked", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseDelegationRevoked is a log parse operation binding the contract event 0x7b9de2ea25e2bcf2150702bf2d070e8245d542228ef81748a5c86603ec13c034.
+//
+// Solidity: event DelegationRevoked(address indexed delegator, address indexed delegate, string reason)
+func (_AgentDelegation *AgentDelegationFilterer) ParseDelegationRevoked(log types.Log) (*AgentDelegationDelegationRevoked, error) {
+	event := new(AgentDelegationDelegationRevoked)
+	if err := _AgentDelegation.contract.UnpackLog(event, "DelegationRevoked", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// AgentDelegationOwnershipTransferredIterator is returned from FilterOwnershipTransferred and is used to iterate over the raw logs and unpacked data for OwnershipTransferred events raised by the AgentDelegation contract.
+type AgentDelegationOwnershipTransferredIterator struct {
+	Event *AgentDelegationOwnershipTransferred // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *AgentDelegationOwnershipTransferredIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(AgentDelegationOwnershipTransferred)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(AgentDelegationOwnershipTransferred)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *AgentDelegationOwnershipTransferredIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *AgentDelegationOwnershipTransferredIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// AgentDelegationOwnershipTransferred represents a OwnershipTransferred event raised by the AgentDelegation contract.
+type AgentDelegationOwnershipTransferred struct {
+	PreviousOwner common.Address
+	NewOwner      common.Address
+	Raw           types.Log // Blockchain specific contextual infos
+}
+
+// FilterOwnershipTransferred is a free log retrieval operation binding the contract event 0x8be0079c531659141344cd1fd0a4f28419497f9722a3daafe3b4186f6b6457e0.
+//
+// Solidity: event OwnershipTransferred(address indexed previousOwner, address indexed newOwner)
+func (_AgentDelegation *AgentDelegationFilterer) FilterOwnershipTransferred(opts *bind.FilterOpts, previousOwner []common.Address, newOwner []common.Address) (*AgentDelegationOwnershipTransferredIterator, error) {
+
+	var previousOwnerRule []interface{}
+	for _, previousOwnerItem := range previousOwner {
+		previousOwnerRule = append(previousOwnerRule, previousOwnerItem)
+	}
+	var newOwnerRule []interface{}
+	for _, newOwnerItem := range newOwner {
+		newOwnerRule = append(newOwnerRule, newOwnerItem)
+	}
+
+	logs, sub, err := _AgentDelegation.contract.FilterLogs(opts, "OwnershipTransferred", previousOwnerRule, newOwnerRule)
+	if err != nil {
+		return nil, err
+	}
+	return &AgentDelegationOwnershipTransferredIterator{contract: _AgentDelegation.contract, event: "OwnershipTransferred", logs: logs, sub: sub}, nil
+}
+
+// WatchOwnershipTransferred is a free log subscription operation binding the contract event 0x8be0079c531659141344cd1fd0a4f28419497f9722a3daafe3b4186f6b6457e0.
+//
+// Solidity: event OwnershipTransferred(address indexed previousOwner, address indexed newOwner)
+func (_AgentDelegation *AgentDelegationFilterer) WatchOwnershipTransferred(opts *bind.WatchOpts, sink chan<- *AgentDelegationOwnershipTransferred, previousOwner []common.Address, newOwner []common.Address) (event.Subscription, error) {
+
+	var previousOwnerRule []interface{}
+	for _, previousOwnerItem := range previousOwner {
+		previousOwnerRule = append(previousOwnerRule, previousOwnerItem)
+	}
+	var newOwnerRule []interface{}
+	for _, newOwnerItem := range newOwner {
+		newOwnerRule = append(newOwnerRule, newOwnerItem)
+	}
+
+	logs, sub, err := _AgentDelegation.contract.WatchLogs(opts, "OwnershipTransferred", previousOwnerRule, newOwnerRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(AgentDelegationOwnershipTransferred)
+				if err := _AgentDelegation.contract.UnpackLog(event, "OwnershipTransferred", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseOwnershipTransferred is a log parse operation binding the contract event 0x8be0079c531659141344cd1fd0a4f28419497f9722a3daafe3b4186f6b6457e0.
+//
+// Solidity: event OwnershipTransferred(address indexed previousOwner, address indexed newOwner)
+func (_AgentDelegation *AgentDelegationFilterer) ParseOwnershipTransferred(log types.Log) (*AgentDelegationOwnershipTransferred, error) {
+	event := new(AgentDelegationOwnershipTransferred)
+	if err := _AgentDelegation.contract.UnpackLog(event, "OwnershipTransferred", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// AgentDelegationPausedIterator is returned from FilterPaused and is used to iterate over the raw logs and unpacked data for Paused events raised by the AgentDelegation contract.
+type AgentDelegationPausedIterator struct {
+	Event *AgentDelegationPaused // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *AgentDelegationPausedIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(AgentDelegationPaused)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(AgentDelegationPaused)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *AgentDelegationPausedIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *AgentDelegationPausedIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// AgentDelegationPaused represents a Paused event raised by the AgentDelegation contract.
+type AgentDelegationPaused struct {
+	Account common.Address
+	Raw     types.Log // Blockchain specific contextual infos
+}
+
+// FilterPaused is a free log retrieval operation binding the contract event 0x62e78cea01bee320cd4e420270b5ea74000d11b0c9f74754ebdbfc544b05a258.
+//
+// Solidity: event Paused(address account)
+func (_AgentDelegation *AgentDelegationFilterer) FilterPaused(opts *bind.FilterOpts) (*AgentDelegationPausedIterator, error) {
+
+	logs, sub, err := _AgentDelegation.contract.FilterLogs(opts, "Paused")
+	if err != nil {
+		return nil, err
+	}
+	return &AgentDelegationPausedIterator{contract: _AgentDelegation.contract, event: "Paused", logs: logs, sub: sub}, nil
+}
+
+// WatchPaused is a free log subscription operation binding the contract event 0x62e78cea01bee320cd4e420270b5ea74000d11b0c9f74754ebdbfc544b05a258.
+//
+// Solidity: event Paused(address account)
+func (_AgentDelegation *AgentDelegationFilterer) WatchPaused(opts *bind.WatchOpts, sink chan<- *AgentDelegationPaused) (event.Subscription, error) {
+
+	logs, sub, err := _AgentDelegation.contract.WatchLogs(opts, "Paused")
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(AgentDelegationPaused)
+				if err := _AgentDelegation.contract.UnpackLog(event, "Paused", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParsePaused is a log parse operation binding the contract event 0x62e78cea01bee320cd4e420270b5ea74000d11b0c9f74754ebdbfc544b05a258.
+//
+// Solidity: event Paused(address account)
+func (_AgentDelegation *AgentDelegationFilterer) ParsePaused(log types.Log) (*AgentDelegationPaused, error) {
+	event := new(AgentDelegationPaused)
+	if err := _AgentDelegation.contract.UnpackLog(event, "Paused", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// AgentDelegationUnpausedIterator is returned from FilterUnpaused and is used to iterate over the raw logs and unpacked data for Unpaused events raised by the AgentDelegation contract.
+type AgentDelegationUnpausedIterator struct {
+	Event *AgentDelegationUnpaused // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *AgentDelegationUnpausedIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(AgentDelegationUnpaused)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(AgentDelegationUnpaused)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *AgentDelegationUnpausedIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *AgentDelegationUnpausedIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// AgentDelegationUnpaused represents a Unpaused event raised by the AgentDelegation contract.
+type AgentDelegationUnpaused struct {
+	Account common.Address
+	Raw     types.Log // Blockchain specific contextual infos
+}
+
+// FilterUnpaused is a free log retrieval operation binding the contract event 0x5db9ee0a495bf2e6ff9c91a7834c1ba4fdd244a5e8aa4e537bd38aeae4b073aa.
+//
+// Solidity: event Unpaused(address account)
+func (_AgentDelegation *AgentDelegationFilterer) FilterUnpaused(opts *bind.FilterOpts) (*AgentDelegationUnpausedIterator, error) {
+
+	logs, sub, err := _AgentDelegation.contract.FilterLogs(opts, "Unpaused")
+	if err != nil {
+		return nil, err
+	}
+	return &AgentDelegationUnpausedIterator{contract: _AgentDelegation.contract, event: "Unpaused", logs: logs, sub: sub}, nil
+}
+
+// WatchUnpaused is a free log subscription operation binding the contract event 0x5db9ee0a495bf2e6ff9c91a7834c1ba4fdd244a5e8aa4e537bd38aeae4b073aa.
+//
+// Solidity: event Unpaused(address account)
+func (_AgentDelegation *AgentDelegationFilterer) WatchUnpaused(opts *bind.WatchOpts, sink chan<- *AgentDelegationUnpaused) (event.Subscription, error) {
+
+	logs, sub, err := _AgentDelegation.contract.WatchLogs(opts, "Unpaused")
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(AgentDelegationUnpaused)
+				if err := _AgentDelegation.contract.UnpackLog(event, "Unpaused", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseUnpaused is a log parse operation binding the contract event 0x5db9ee0a495bf2e6ff9c91a7834c1ba4fdd244a5e8aa4e537bd38aeae4b073aa.
+//
+// Solidity: event Unpaused(address account)
+func (_AgentDelegation *AgentDelegationFilterer) ParseUnpaused(log types.Log) (*AgentDelegationUnpaused, error) {
+	event := new(AgentDelegationUnpaused)
+	if err := _AgentDelegation.contract.UnpackLog(event, "Unpaused", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
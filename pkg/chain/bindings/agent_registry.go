@@ -0,0 +1,1790 @@
+// Code generated - DO NOT EDIT.
+// This file is a generated binding and any manual changes will be lost.
+
+package bindings
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var (
+	_ = errors.New
+	_ = big.NewInt
+	_ = strings.NewReader
+	_ = ethereum.NotFound
+	_ = bind.Bind
+	_ = common.Big1
+	_ = types.BloomLookup
+	_ = event.NewSubscription
+	_ = abi.ConvertType
+)
+
+// AgentRegistryMetaData contains all meta data concerning the AgentRegistry contract.
+var AgentRegistryMetaData = &bind.MetaData{
+	ABI: "[{\"type\":\"constructor\",\"inputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"addVerifier\",\"inputs\":[{\"name\":\"verifier\",\"type\":\"address\",\"internalType\":\"address\"}],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"agents\",\"inputs\":[{\"name\":\"\",\"type\":\"address\",\"internalType\":\"address\"}],\"outputs\":[{\"name\":\"agentAddress\",\"type\":\"address\",\"internalType\":\"address\"},{\"name\":\"did\",\"type\":\"string\",\"internalType\":\"string\"},{\"name\":\"trustLevel\",\"type\":\"uint8\",\"internalType\":\"enumAgentRegistry.TrustLevel\"},{\"name\":\"registeredAt\",\"type\":\"uint256\",\"internalType\":\"uint256\"},{\"name\":\"lastVerifiedAt\",\"type\":\"uint256\",\"internalType\":\"uint256\"},{\"name\":\"isActive\",\"type\":\"bool\",\"internalType\":\"bool\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"didToAddress\",\"inputs\":[{\"name\":\"\",\"type\":\"string\",\"internalType\":\"string\"}],\"outputs\":[{\"name\":\"\",\"type\":\"address\",\"internalType\":\"address\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"getAgentTrustLevel\",\"inputs\":[{\"name\":\"agent\",\"type\":\"address\",\"internalType\":\"address\"}],\"outputs\":[{\"name\":\"\",\"type\":\"uint8\",\"internalType\":\"enumAgentRegistry.TrustLevel\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"hasCapability\",\"inputs\":[{\"name\":\"agent\",\"type\":\"address\",\"internalType\":\"address\"},{\"name\":\"capability\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"outputs\":[{\"name\":\"\",\"type\":\"bool\",\"internalType\":\"bool\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"owner\",\"inputs\":[],\"outputs\":[{\"name\":\"\",\"type\":\"address\",\"internalType\":\"address\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"pause\",\"inputs\":[],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"paused\",\"inputs\":[],\"outputs\":[{\"name\":\"\",\"type\":\"bool\",\"internalType\":\"bool\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"registerAgent\",\"inputs\":[{\"name\":\"did\",\"type\":\"string\",\"internalType\":\"string\"}],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"removeVerifier\",\"inputs\":[{\"name\":\"verifier\",\"type\":\"address\",\"internalType\":\"address\"}],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"renounceOwnership\",\"inputs\":[],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"revokeAgent\",\"inputs\":[{\"name\":\"agent\",\"type\":\"address\",\"internalType\":\"address\"},{\"name\":\"reason\",\"type\":\"string\",\"internalType\":\"string\"}],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"transferOwnership\",\"inputs\":[{\"name\":\"newOwner\",\"type\":\"address\",\"internalType\":\"address\"}],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"unpause\",\"inputs\":[],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"verifiers\",\"inputs\":[{\"name\":\"\",\"type\":\"address\",\"internalType\":\"address\"}],\"outputs\":[{\"name\":\"\",\"type\":\"bool\",\"internalType\":\"bool\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"verifyAgent\",\"inputs\":[{\"name\":\"agent\",\"type\":\"address\",\"internalType\":\"address\"},{\"name\":\"newTrustLevel\",\"type\":\"uint8\",\"internalType\":\"enumAgentRegistry.TrustLevel\"},{\"name\":\"capabilities\",\"type\":\"bytes32[]\",\"internalType\":\"bytes32[]\"}],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"event\",\"name\":\"AgentRegistered\",\"inputs\":[{\"name\":\"agent\",\"type\":\"address\",\"indexed\":true,\"internalType\":\"address\"},{\"name\":\"did\",\"type\":\"string\",\"indexed\":false,\"internalType\":\"string\"},{\"name\":\"trustLevel\",\"type\":\"uint8\",\"indexed\":false,\"internalType\":\"enumAgentRegistry.TrustLevel\"}],\"anonymous\":false},{\"type\":\"event\",\"name\":\"AgentRevoked\",\"inputs\":[{\"name\":\"agent\",\"type\":\"address\",\"indexed\":true,\"internalType\":\"address\"},{\"name\":\"reason\",\"type\":\"string\",\"indexed\":false,\"internalType\":\"string\"}],\"anonymous\":false},{\"type\":\"event\",\"name\":\"AgentVerified\",\"inputs\":[{\"name\":\"agent\",\"type\":\"address\",\"indexed\":true,\"internalType\":\"address\"},{\"name\":\"newTrustLevel\",\"type\":\"uint8\",\"indexed\":false,\"internalType\":\"enumAgentRegistry.TrustLevel\"}],\"anonymous\":false},{\"type\":\"event\",\"name\":\"CapabilityAdded\",\"inputs\":[{\"name\":\"agent\",\"type\":\"address\",\"indexed\":true,\"internalType\":\"address\"},{\"name\":\"capability\",\"type\":\"bytes32\",\"indexed\":false,\"internalType\":\"bytes32\"}],\"anonymous\":false},{\"type\":\"event\",\"name\":\"CapabilityRemoved\",\"inputs\":[{\"name\":\"agent\",\"type\":\"address\",\"indexed\":true,\"internalType\":\"address\"},{\"name\":\"capability\",\"type\":\"bytes32\",\"indexed\":false,\"internalType\":\"bytes32\"}],\"anonymous\":false},{\"type\":\"event\",\"name\":\"OwnershipTransferred\",\"inputs\":[{\"name\":\"previousOwner\",\"type\":\"address\",\"indexed\":true,\"internalType\":\"address\"},{\"name\":\"newOwner\",\"type\":\"address\",\"indexed\":true,\"internalType\":\"address\"}],\"anonymous\":false},{\"type\":\"event\",\"name\":\"Paused\",\"inputs\":[{\"name\":\"account\",\"type\":\"address\",\"indexed\":false,\"internalType\":\"address\"}],\"anonymous\":false},{\"type\":\"event\",\"name\":\"Unpaused\",\"inputs\":[{\"name\":\"account\",\"type\":\"address\",\"indexed\":false,\"internalType\":\"address\"}],\"anonymous\":false},{\"type\":\"error\",\"name\":\"EnforcedPause\",\"inputs\":[]},{\"type\":\"error\",\"name\":\"ExpectedPause\",\"inputs\":[]},{\"type\":\"error\",\"name\":\"OwnableInvalidOwner\",\"inputs\":[{\"name\":\"owner\",\"type\":\"address\",\"internalType\":\"address\"}]},{\"type\":\"error\",\"name\":\"OwnableUnauthorizedAccount\",\"inputs\":[{\"name\":\"account\",\"type\":\"address\",\"internalType\":\"address\"}]}]",
+	Bin: "0x608060405234801561000f575f5ffd5b50335f73ffffffffffffffffffffffffffffffffffffffff168173ffffffffffffffffffffffffffffffffffffffff1603610081575f6040517f1e4fbdf70000000000000000000000000000000000000000000000000000000081526004016100789190610196565b60405180910390fd5b6100908161009660201b60201c565b506101af565b5f5f5f9054906101000a900473ffffffffffffffffffffffffffffffffffffffff169050815f5f6101000a81548173ffffffffffffffffffffffffffffffffffffffff021916908373ffffffffffffffffffffffffffffffffffffffff1602179055508173ffffffffffffffffffffffffffffffffffffffff168173ffffffffffffffffffffffffffffffffffffffff167f8be0079c531659141344cd1fd0a4f28419497f9722a3daafe3b4186f6b6457e060405160405180910390a35050565b5f73ffffffffffffffffffffffffffffffffffffffff82169050919050565b5f61018082610157565b9050919050565b61019081610176565b82525050565b5f6020820190506101a95f830184610187565b92915050565b611f6f806101bc5f395ff3fe608060405234801561000f575f5ffd5b50600436106100fe575f3560e01c8063715018a611610095578063bf6af90c11610064578063bf6af90c14610270578063ca2dfd0a1461028c578063f2fde38b146102a8578063fd66091e146102c4576100fe565b8063715018a6146102225780638456cb591461022c5780638da5cb5b146102365780639000b3d614610254576100fe565b80635ecea824116100d15780635ecea824146101625780636b5dae34146101925780636ba1f4a4146101c25780636c824487146101f2576100fe565b806321251d91146101025780632d2a95851461011e5780633f4ba83a1461013a5780635c975abb14610144575b5f5ffd5b61011c600480360381019061011791906113c9565b6102f9565b005b61013860048036038101906101339190611572565b6106cc565b005b6101426109eb565b005b61014c6109fd565b60405161015991906115d3565b60405180910390f35b61017c6004803603810190610177919061161f565b610a12565b60405161018991906115d3565b60405180910390f35b6101ac60048036038101906101a7919061165d565b610acc565b6040516101b991906116fb565b60405180910390f35b6101dc60048036038101906101d79190611572565b610b21565b6040516101e99190611723565b60405180910390f35b61020c6004803603810190610207919061165d565b610b69565b60405161021991906115d3565b60405180910390f35b61022a610b86565b005b610234610b99565b005b61023e610bab565b60405161024b9190611723565b60405180910390f35b61026e6004803603810190610269919061165d565b610bd2565b005b61028a6004803603810190610285919061173c565b610c32565b005b6102a660048036038101906102a1919061165d565b610e6f565b005b6102c260048036038101906102bd919061165d565b610ece565b005b6102de60048036038101906102d9919061165d565b610f52565b6040516102f09695949392919061180e565b60405180910390f35b60035f3373ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff1681526020019081526020015f205f9054906101000a900460ff16610382576040517f08c379a0000000000000000000000000000000000000000000000000000000008152600401610379906118be565b60405180910390fd5b61038a611047565b60015f8573ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff1681526020019081526020015f206005015f9054906101000a900460ff16610416576040517f08c379a000000000000000000000000000000000000000000000000000000000815260040161040d90611926565b60405180910390fd5b60015f8573ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff1681526020019081526020015f206002015f9054906101000a900460ff16600381111561047557610474611688565b5b83600381111561048857610487611688565b5b116104c8576040517f08c379a00000000000000000000000000000000000000000000000000000000081526004016104bf9061198e565b60405180910390fd5b8260015f8673ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff1681526020019081526020015f206002015f6101000a81548160ff0219169083600381111561052a57610529611688565b5b02179055504260015f8673ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff1681526020019081526020015f20600401819055505f5f90505b82829050811015610677576001805f8773ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff1681526020019081526020015f206006015f8585858181106105d9576105d86119ac565b5b9050602002013581526020019081526020015f205f6101000a81548160ff0219169083151502179055508473ffffffffffffffffffffffffffffffffffffffff167f1eb5a0b9725a98314d6c9206c9e05bd837230cc22d009ff823d4ffb3cc94592084848481811061064e5761064d6119ac565b5b9050602002013560405161066291906119e8565b60405180910390a28080600101915050610579565b508373ffffffffffffffffffffffffffffffffffffffff167feb3401046ed50fde9f1632c720ef16f263c0fe66eb2fda955c333836806992d7846040516106be91906116fb565b60405180910390a250505050565b6106d4611047565b5f815111610717576040517f08c379a000000000000000000000000000000000000000000000000000000000815260040161070e90611a4b565b60405180910390fd5b5f73ffffffffffffffffffffffffffffffffffffffff1660028260405161073e9190611aa3565b90815260200160405180910390205f9054906101000a900473ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff16146107c2576040517f08c379a00000000000000000000000000000000000000000000000000000000081526004016107b990611b03565b60405180910390fd5b60015f3373ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff1681526020019081526020015f206005015f9054906101000a900460ff161561084f576040517f08c379a000000000000000000000000000000000000000000000000000000000815260040161084690611b6b565b60405180910390fd5b5f60015f3373ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff1681526020019081526020015f20905033815f015f6101000a81548173ffffffffffffffffffffffffffffffffffffffff021916908373ffffffffffffffffffffffffffffffffffffffff160217905550818160010190816108e19190611d86565b505f816002015f6101000a81548160ff0219169083600381111561090857610907611688565b5b02179055504281600301819055504281600401819055506001816005015f6101000a81548160ff0219169083151502179055503360028360405161094c9190611aa3565b90815260200160405180910390205f6101000a81548173ffffffffffffffffffffffffffffffffffffffff021916908373ffffffffffffffffffffffffffffffffffffffff1602179055503373ffffffffffffffffffffffffffffffffffffffff167f043b4bd510c310dfeac11db30f86f7186f983ad895bfad174b5f2a770834b932835f6040516109df929190611e55565b60405180910390a25050565b6109f3611088565b6109fb61110f565b565b5f5f60149054906101000a900460ff16905090565b5f60015f8473ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff1681526020019081526020015f206005015f9054906101000a900460ff168015610ac4575060015f8473ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff1681526020019081526020015f206006015f8381526020019081526020015f205f9054906101000a900460ff165b905092915050565b5f60015f8373ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff1681526020019081526020015f206002015f9054906101000a900460ff169050919050565b6002818051602081018201805184825260208301602085012081835280955050505050505f915054906101000a900473ffffffffffffffffffffffffffffffffffffffff1681565b6003602052805f5260405f205f915054906101000a900460ff1681565b610b8e611088565b610b975f611170565b565b610ba1611088565b610ba9611231565b565b5f5f5f9054906101000a900473ffffffffffffffffffffffffffffffffffffffff16905090565b610bda611088565b600160035f8373ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff1681526020019081526020015f205f6101000a81548160ff02191690831515021790555050565b60035f3373ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff1681526020019081526020015f205f9054906101000a900460ff16610cbb576040517f08c379a0000000000000000000000000000000000000000000000000000000008152600401610cb2906118be565b60405180910390fd5b60015f8373ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff1681526020019081526020015f206005015f9054906101000a900460ff16610d47576040517f08c379a0000000000000000000000000000000000000000000000000000000008152600401610d3e90611926565b60405180910390fd5b5f60015f8473ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff1681526020019081526020015f206005015f6101000a81548160ff021916908315150217905550600260015f8473ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff1681526020019081526020015f20600101604051610ded9190611f03565b90815260200160405180910390205f6101000a81549073ffffffffffffffffffffffffffffffffffffffff02191690558173ffffffffffffffffffffffffffffffffffffffff167f403d7e1a9c42fe215347ba00d9d34b4bf8ef8ef3b5b723900c8ab9576e4b7b2c82604051610e639190611f19565b60405180910390a25050565b610e77611088565b5f60035f8373ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff1681526020019081526020015f205f6101000a81548160ff02191690831515021790555050565b610ed6611088565b5f73ffffffffffffffffffffffffffffffffffffffff168173ffffffffffffffffffffffffffffffffffffffff1603610f46575f6040517f1e4fbdf7000000000000000000000000000000000000000000000000000000008152600401610f3d9190611723565b60405180910390fd5b610f4f81611170565b50565b6001602052805f5260405f205f91509050805f015f9054906101000a900473ffffffffffffffffffffffffffffffffffffffff1690806001018054610f9690611bb6565b80601f0160208091040260200160405190810160405280929190818152602001828054610fc290611bb6565b801561100d5780601f10610fe45761010080835404028352916020019161100d565b820191905f5260205f20905b815481529060010190602001808311610ff057829003601f168201915b505050505090806002015f9054906101000a900460ff1690806003015490806004015490806005015f9054906101000a900460ff16905086565b61104f6109fd565b15611086576040517fd93c066500000000000000000000000000000000000000000000000000000000815260040160405180910390fd5b565b611090611293565b73ffffffffffffffffffffffffffffffffffffffff166110ae610bab565b73ffffffffffffffffffffffffffffffffffffffff161461110d576110d1611293565b6040517f118cdaa70000000000000000000000000000000000000000000000000000000081526004016111049190611723565b60405180910390fd5b565b61111761129a565b5f5f60146101000a81548160ff0219169083151502179055507f5db9ee0a495bf2e6ff9c91a7834c1ba4fdd244a5e8aa4e537bd38aeae4b073aa611159611293565b6040516111669190611723565b60405180910390a1565b5f5f5f9054906101000a900473ffffffffffffffffffffffffffffffffffffffff169050815f5f6101000a81548173ffffffffffffffffffffffffffffffffffffffff021916908373ffffffffffffffffffffffffffffffffffffffff1602179055508173ffffffffffffffffffffffffffffffffffffffff168173ffffffffffffffffffffffffffffffffffffffff167f8be0079c531659141344cd1fd0a4f28419497f9722a3daafe3b4186f6b6457e060405160405180910390a35050565b611239611047565b60015f60146101000a81548160ff0219169083151502179055507f62e78cea01bee320cd4e420270b5ea74000d11b0c9f74754ebdbfc544b05a25861127c611293565b6040516112899190611723565b60405180910390a1565b5f33905090565b6112a26109fd565b6112d8576040517f8dfc202b00000000000000000000000000000000000000000000000000000000815260040160405180910390fd5b565b5f604051905090565b5f5ffd5b5f5ffd5b5f73ffffffffffffffffffffffffffffffffffffffff82169050919050565b5f611314826112eb565b9050919050565b6113248161130a565b811461132e575f5ffd5b50565b5f8135905061133f8161131b565b92915050565b60048110611351575f5ffd5b50565b5f8135905061136281611345565b92915050565b5f5ffd5b5f5ffd5b5f5ffd5b5f5f83601f84011261138957611388611368565b5b8235905067ffffffffffffffff8111156113a6576113a561136c565b5b6020830191508360208202830111156113c2576113c1611370565b5b9250929050565b5f5f5f5f606085870312156113e1576113e06112e3565b5b5f6113ee87828801611331565b94505060206113ff87828801611354565b935050604085013567ffffffffffffffff8111156114205761141f6112e7565b5b61142c87828801611374565b925092505092959194509250565b5f5ffd5b5f601f19601f8301169050919050565b7f4e487b71000000000000000000000000000000000000000000000000000000005f52604160045260245ffd5b6114848261143e565b810181811067ffffffffffffffff821117156114a3576114a261144e565b5b80604052505050565b5f6114b56112da565b90506114c1828261147b565b919050565b5f67ffffffffffffffff8211156114e0576114df61144e565b5b6114e98261143e565b9050602081019050919050565b828183375f83830152505050565b5f611516611511846114c6565b6114ac565b9050828152602081018484840111156115325761153161143a565b5b61153d8482856114f6565b509392505050565b5f82601f83011261155957611558611368565b5b8135611569848260208601611504565b91505092915050565b5f60208284031215611587576115866112e3565b5b5f82013567ffffffffffffffff8111156115a4576115a36112e7565b5b6115b084828501611545565b91505092915050565b5f8115159050919050565b6115cd816115b9565b82525050565b5f6020820190506115e65f8301846115c4565b92915050565b5f819050919050565b6115fe816115ec565b8114611608575f5ffd5b50565b5f81359050611619816115f5565b92915050565b5f5f60408385031215611635576116346112e3565b5b5f61164285828601611331565b92505060206116538582860161160b565b9150509250929050565b5f60208284031215611672576116716112e3565b5b5f61167f84828501611331565b91505092915050565b7f4e487b71000000000000000000000000000000000000000000000000000000005f52602160045260245ffd5b600481106116c6576116c5611688565b5b50565b5f8190506116d6826116b5565b919050565b5f6116e5826116c9565b9050919050565b6116f5816116db565b82525050565b5f60208201905061170e5f8301846116ec565b92915050565b61171d8161130a565b82525050565b5f6020820190506117365f830184611714565b92915050565b5f5f60408385031215611752576117516112e3565b5b5f61175f85828601611331565b925050602083013567ffffffffffffffff8111156117805761177f6112e7565b5b61178c85828601611545565b9150509250929050565b5f81519050919050565b5f82825260208201905092915050565b8281835e5f83830152505050565b5f6117c882611796565b6117d281856117a0565b93506117e28185602086016117b0565b6117eb8161143e565b840191505092915050565b5f819050919050565b611808816117f6565b82525050565b5f60c0820190506118215f830189611714565b818103602083015261183381886117be565b905061184260408301876116ec565b61184f60608301866117ff565b61185c60808301856117ff565b61186960a08301846115c4565b979650505050505050565b7f4e6f7420612076657269666965720000000000000000000000000000000000005f82015250565b5f6118a8600e836117a0565b91506118b382611874565b602082019050919050565b5f6020820190508181035f8301526118d58161189c565b9050919050565b7f4167656e74206e6f7420726567697374657265640000000000000000000000005f82015250565b5f6119106014836117a0565b915061191b826118dc565b602082019050919050565b5f6020820190508181035f83015261193d81611904565b9050919050565b7f496e76616c6964207472757374206c6576656c207570677261646500000000005f82015250565b5f611978601b836117a0565b915061198382611944565b602082019050919050565b5f6020820190508181035f8301526119a58161196c565b9050919050565b7f4e487b71000000000000000000000000000000000000000000000000000000005f52603260045260245ffd5b6119e2816115ec565b82525050565b5f6020820190506119fb5f8301846119d9565b92915050565b7f496e76616c6964204449440000000000000000000000000000000000000000005f82015250565b5f611a35600b836117a0565b9150611a4082611a01565b602082019050919050565b5f6020820190508181035f830152611a6281611a29565b9050919050565b5f81905092915050565b5f611a7d82611796565b611a878185611a69565b9350611a978185602086016117b0565b80840191505092915050565b5f611aae8284611a73565b915081905092915050565b7f44494420616c72656164792072656769737465726564000000000000000000005f82015250565b5f611aed6016836117a0565b9150611af882611ab9565b602082019050919050565b5f6020820190508181035f830152611b1a81611ae1565b9050919050565b7f4167656e7420616c7265616479207265676973746572656400000000000000005f82015250565b5f611b556018836117a0565b9150611b6082611b21565b602082019050919050565b5f6020820190508181035f830152611b8281611b49565b9050919050565b7f4e487b71000000000000000000000000000000000000000000000000000000005f52602260045260245ffd5b5f6002820490506001821680611bcd57607f821691505b602082108103611be057611bdf611b89565b5b50919050565b5f819050815f5260205f209050919050565b5f6020601f8301049050919050565b5f82821b905092915050565b5f60088302611c427fffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff82611c07565b611c4c8683611c07565b95508019841693508086168417925050509392505050565b5f819050919050565b5f611c87611c82611c7d846117f6565b611c64565b6117f6565b9050919050565b5f819050919050565b611ca083611c6d565b611cb4611cac82611c8e565b848454611c13565b825550505050565b5f5f905090565b611ccb611cbc565b611cd6818484611c97565b505050565b5b81811015611cf957611cee5f82611cc3565b600181019050611cdc565b5050565b601f821115611d3e57611d0f81611be6565b611d1884611bf8565b81016020851015611d27578190505b611d3b611d3385611bf8565b830182611cdb565b50505b505050565b5f82821c905092915050565b5f611d5e5f1984600802611d43565b1980831691505092915050565b5f611d768383611d4f565b9150826002028217905092915050565b611d8f82611796565b67ffffffffffffffff811115611da857611da761144e565b5b611db28254611bb6565b611dbd828285611cfd565b5f60209050601f831160018114611dee575f8415611ddc578287015190505b611de68582611d6b565b865550611e4d565b601f198416611dfc86611be6565b5f5b82811015611e2357848901518255600182019150602085019450602081019050611dfe565b86831015611e405784890151611e3c601f891682611d4f565b8355505b6001600288020188555050505b505050505050565b5f6040820190508181035f830152611e6d81856117be565b9050611e7c60208301846116ec565b9392505050565b5f8154611e8f81611bb6565b611e998186611a69565b9450600182165f8114611eb35760018114611ec857611efa565b60ff1983168652811515820286019350611efa565b611ed185611be6565b5f5b83811015611ef257815481890152600182019150602081019050611ed3565b838801955050505b50505092915050565b5f611f0e8284611e83565b915081905092915050565b5f6020820190508181035f830152611f3181846117be565b90509291505056fea264697066735822122054bb8513adbae149de7e414078b69569731dce3eff9e8592a04e69bf639694fb64736f6c634300081e0033",
+}
+
+// AgentRegistryABI is the input ABI used to generate the binding from.
+// Deprecated: Use AgentRegistryMetaData.ABI instead.
+var AgentRegistryABI = AgentRegistryMetaData.ABI
+
+// AgentRegistryBin is the compiled bytecode used for deploying new contracts.
+// Deprecated: Use AgentRegistryMetaData.Bin instead.
+var AgentRegistryBin = AgentRegistryMetaData.Bin
+
+// DeployAgentRegistry deploys a new Ethereum contract, binding an instance of AgentRegistry to it.
+func DeployAgentRegistry(auth *bind.TransactOpts, backend bind.ContractBackend) (common.Address, *types.Transaction, *AgentRegistry, error) {
+	parsed, err := AgentRegistryMetaData.GetAbi()
+	if err != nil {
+		return common.Address{}, nil, nil, err
+	}
+	if parsed == nil {
+		return common.Address{}, nil, nil, errors.New("GetABI returned nil")
+	}
+
+	address, tx, contract, err := bind.DeployContract(auth, *parsed, common.FromHex(AgentRegistryBin), backend)
+	if err != nil {
+		return common.Address{}, nil, nil, err
+	}
+	return address, tx, &AgentRegistry{AgentRegistryCaller: AgentRegistryCaller{contract: contract}, AgentRegistryTransactor: AgentRegistryTransactor{contract: contract}, AgentRegistryFilterer: AgentRegistryFilterer{contract: contract}}, nil
+}
+
+// AgentRegistry is an auto generated Go binding around an Ethereum contract.
+type AgentRegistry struct {
+	AgentRegistryCaller     // Read-only binding to the contract
+	AgentRegistryTransactor // Write-only binding to the contract
+	AgentRegistryFilterer   // Log filterer for contract events
+}
+
+// AgentRegistryCaller is an auto generated read-only Go binding around an Ethereum contract.
+type AgentRegistryCaller struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// AgentRegistryTransactor is an auto generated write-only Go binding around an Ethereum contract.
+type AgentRegistryTransactor struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// AgentRegistryFilterer is an auto generated log filtering Go binding around an Ethereum contract events.
+type AgentRegistryFilterer struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// AgentRegistrySession is an auto generated Go binding around an Ethereum contract,
+// with pre-set call and transact options.
+type AgentRegistrySession struct {
+	Contract     *AgentRegistry    // Generic contract binding to set the session for
+	CallOpts     bind.CallOpts     // Call options to use throughout this session
+	TransactOpts bind.TransactOpts // Transaction auth options to use throughout this session
+}
+
+// AgentRegistryCallerSession is an auto generated read-only Go binding around an Ethereum contract,
+// with pre-set call options.
+type AgentRegistryCallerSession struct {
+	Contract *AgentRegistryCaller // Generic contract caller binding to set the session for
+	CallOpts bind.CallOpts        // Call options to use throughout this session
+}
+
+// AgentRegistryTransactorSession is an auto generated write-only Go binding around an Ethereum contract,
+// with pre-set transact options.
+type AgentRegistryTransactorSession struct {
+	Contract     *AgentRegistryTransactor // Generic contract transactor binding to set the session for
+	TransactOpts bind.TransactOpts        // Transaction auth options to use throughout this session
+}
+
+// AgentRegistryRaw is an auto generated low-level Go binding around an Ethereum contract.
+type AgentRegistryRaw struct {
+	Contract *AgentRegistry // Generic contract binding to access the raw methods on
+}
+
+// AgentRegistryCallerRaw is an auto generated low-level read-only Go binding around an Ethereum contract.
+type AgentRegistryCallerRaw struct {
+	Contract *AgentRegistryCaller // Generic read-only contract binding to access the raw methods on
+}
+
+// AgentRegistryTransactorRaw is an auto generated low-level write-only Go binding around an Ethereum contract.
+type AgentRegistryTransactorRaw struct {
+	Contract *AgentRegistryTransactor // Generic write-only contract binding to access the raw methods on
+}
+
+// NewAgentRegistry creates a new instance of AgentRegistry, bound to a specific deployed contract.
+func NewAgentRegistry(address common.Address, backend bind.ContractBackend) (*AgentRegistry, error) {
+	contract, err := bindAgentRegistry(address, backend, backend, backend)
+	if err != nil {
+		return nil, err
+	}
+	return &AgentRegistry{AgentRegistryCaller: AgentRegistryCaller{contract: contract}, AgentRegistryTransactor: AgentRegistryTransactor{contract: contract}, AgentRegistryFilterer: AgentRegistryFilterer{contract: contract}}, nil
+}
+
+// NewAgentRegistryCaller creates a new read-only instance of AgentRegistry, bound to a specific deployed contract.
+func NewAgentRegistryCaller(address common.Address, caller bind.ContractCaller) (*AgentRegistryCaller, error) {
+	contract, err := bindAgentRegistry(address, caller, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &AgentRegistryCaller{contract: contract}, nil
+}
+
+// NewAgentRegistryTransactor creates a new write-only instance of AgentRegistry, bound to a specific deployed contract.
+func NewAgentRegistryTransactor(address common.Address, transactor bind.ContractTransactor) (*AgentRegistryTransactor, error) {
+	contract, err := bindAgentRegistry(address, nil, transactor, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &AgentRegistryTransactor{contract: contract}, nil
+}
+
+// NewAgentRegistryFilterer creates a new log filterer instance of AgentRegistry, bound to a specific deployed contract.
+func NewAgentRegistryFilterer(address common.Address, filterer bind.ContractFilterer) (*AgentRegistryFilterer, error) {
+	contract, err := bindAgentRegistry(address, nil, nil, filterer)
+	if err != nil {
+		return nil, err
+	}
+	return &AgentRegistryFilterer{contract: contract}, nil
+}
+
+// bindAgentRegistry binds a generic wrapper to an already deployed contract.
+func bindAgentRegistry(address common.Address, caller bind.ContractCaller, transactor bind.ContractTransactor, filterer bind.ContractFilterer) (*bind.BoundContract, error) {
+	parsed, err := AgentRegistryMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	return bind.NewBoundContract(address, *parsed, caller, transactor, filterer), nil
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_AgentRegistry *AgentRegistryRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _AgentRegistry.Contract.AgentRegistryCaller.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_AgentRegistry *AgentRegistryRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _AgentRegistry.Contract.AgentRegistryTransactor.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_AgentRegistry *AgentRegistryRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _AgentRegistry.Contract.AgentRegistryTransactor.contract.Transact(opts, method, params...)
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_AgentRegistry *AgentRegistryCallerRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _AgentRegistry.Contract.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_AgentRegistry *AgentRegistryTransactorRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _AgentRegistry.Contract.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_AgentRegistry *AgentRegistryTransactorRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _AgentRegistry.Contract.contract.Transact(opts, method, params...)
+}
+
+// Agents is a free data retrieval call binding the contract method 0xfd66091e.
+//
+// Solidity: function agents(address ) view returns(address agentAddress, string did, uint8 trustLevel, uint256 registeredAt, uint256 lastVerifiedAt, bool isActive)
+func (_AgentRegistry *AgentRegistryCaller) Agents(opts *bind.CallOpts, arg0 common.Address) (struct {
+	AgentAddress   common.Address
+	Did            string
+	TrustLevel     uint8
+	RegisteredAt   *big.Int
+	LastVerifiedAt *big.Int
+	IsActive       bool
+}, error) {
+	var out []interface{}
+	err := _AgentRegistry.contract.Call(opts, &out, "agents", arg0)
+
+	outstruct := new(struct {
+		AgentAddress   common.Address
+		Did            string
+		TrustLevel     uint8
+		RegisteredAt   *big.Int
+		LastVerifiedAt *big.Int
+		IsActive       bool
+	})
+	if err != nil {
+		return *outstruct, err
+	}
+
+	outstruct.AgentAddress = *abi.ConvertType(out[0], new(common.Address)).(*common.Address)
+	outstruct.Did = *abi.ConvertType(out[1], new(string)).(*string)
+	outstruct.TrustLevel = *abi.ConvertType(out[2], new(uint8)).(*uint8)
+	outstruct.RegisteredAt = *abi.ConvertType(out[3], new(*big.Int)).(**big.Int)
+	outstruct.LastVerifiedAt = *abi.ConvertType(out[4], new(*big.Int)).(**big.Int)
+	outstruct.IsActive = *abi.ConvertType(out[5], new(bool)).(*bool)
+
+	return *outstruct, err
+
+}
+
+// Agents is a free data retrieval call binding the contract method 0xfd66091e.
+//
+// Solidity: function agents(address ) view returns(address agentAddress, string did, uint8 trustLevel, uint256 registeredAt, uint256 lastVerifiedAt, bool isActive)
+func (_AgentRegistry *AgentRegistrySession) Agents(arg0 common.Address) (struct {
+	AgentAddress   common.Address
+	Did            string
+	TrustLevel     uint8
+	RegisteredAt   *big.Int
+	LastVerifiedAt *big.Int
+	IsActive       bool
+}, error) {
+	return _AgentRegistry.Contract.Agents(&_AgentRegistry.CallOpts, arg0)
+}
+
+// Agents is a free data retrieval call binding the contract method 0xfd66091e.
+//
+// Solidity: function agents(address ) view returns(address agentAddress, string did, uint8 trustLevel, uint256 registeredAt, uint256 lastVerifiedAt, bool isActive)
+func (_AgentRegistry *AgentRegistryCallerSession) Agents(arg0 common.Address) (struct {
+	AgentAddress   common.Address
+	Did            string
+	TrustLevel     uint8
+	RegisteredAt   *big.Int
+	LastVerifiedAt *big.Int
+	IsActive       bool
+}, error) {
+	return _AgentRegistry.Contract.Agents(&_AgentRegistry.CallOpts, arg0)
+}
+
+// DidToAddress is a free data retrieval call binding the contract method 0x6ba1f4a4.
+//
+// Solidity: function didToAddress(string ) view returns(address)
+func (_AgentRegistry *AgentRegistryCaller) DidToAddress(opts *bind.CallOpts, arg0 string) (common.Address, error) {
+	var out []interface{}
+	err := _AgentRegistry.contract.Call(opts, &out, "didToAddress", arg0)
+
+	if err != nil {
+		return *new(common.Address), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(common.Address)).(*common.Address)
+
+	return out0, err
+
+}
+
+// DidToAddress is a free data retrieval call binding the contract method 0x6ba1f4a4.
+//
+// Solidity: function didToAddress(string ) view returns(address)
+func (_AgentRegistry *AgentRegistrySession) DidToAddress(arg0 string) (common.Address, error) {
+	return _AgentRegistry.Contract.DidToAddress(&_AgentRegistry.CallOpts, arg0)
+}
+
+// DidToAddress is a free data retrieval call binding the contract method 0x6ba1f4a4.
+//
+// Solidity: function didToAddress(string ) view returns(address)
+func (_AgentRegistry *AgentRegistryCallerSession) DidToAddress(arg0 string) (common.Address, error) {
+	return _AgentRegistry.Contract.DidToAddress(&_AgentRegistry.CallOpts, arg0)
+}
+
+// GetAgentTrustLevel is a free data retrieval call binding the contract method 0x6b5dae34.
+//
+// Solidity: function getAgentTrustLevel(address agent) view returns(uint8)
+func (_AgentRegistry *AgentRegistryCaller) GetAgentTrustLevel(opts *bind.CallOpts, agent common.Address) (uint8, error) {
+	var out []interface{}
+	err := _AgentRegistry.contract.Call(opts, &out, "getAgentTrustLevel", agent)
+
+	if err != nil {
+		return *new(uint8), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(uint8)).(*uint8)
+
+	return out0, err
+
+}
+
+// GetAgentTrustLevel is a free data retrieval call binding the contract method 0x6b5dae34.
+//
+// Solidity: function getAgentTrustLevel(address agent) view returns(uint8)
+func (_AgentRegistry *AgentRegistrySession) GetAgentTrustLevel(agent common.Address) (uint8, error) {
+	return _AgentRegistry.Contract.GetAgentTrustLevel(&_AgentRegistry.CallOpts, agent)
+}
+
+// GetAgentTrustLevel is a free data retrieval call binding the contract method 0x6b5dae34.
+//
+// Solidity: function getAgentTrustLevel(address agent) view returns(uint8)
+func (_AgentRegistry *AgentRegistryCallerSession) GetAgentTrustLevel(agent common.Address) (uint8, error) {
+	return _AgentRegistry.Contract.GetAgentTrustLevel(&_AgentRegistry.CallOpts, agent)
+}
+
+// HasCapability is a free data retrieval call binding the contract method 0x5ecea824.
+//
+// Solidity: function hasCapability(address agent, bytes32 capability) view returns(bool)
+func (_AgentRegistry *AgentRegistryCaller) HasCapability(opts *bind.CallOpts, agent common.Address, capability [32]byte) (bool, error) {
+	var out []interface{}
+	err := _AgentRegistry.contract.Call(opts, &out, "hasCapability", agent, capability)
+
+	if err != nil {
+		return *new(bool), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(bool)).(*bool)
+
+	return out0, err
+
+}
+
+// HasCapability is a free data retrieval call binding the contract method 0x5ecea824.
+//
+// Solidity: function hasCapability(address agent, bytes32 capability) view returns(bool)
+func (_AgentRegistry *AgentRegistrySession) HasCapability(agent common.Address, capability [32]byte) (bool, error) {
+	return _AgentRegistry.Contract.HasCapability(&_AgentRegistry.CallOpts, agent, capability)
+}
+
+// HasCapability is a free data retrieval call binding the contract method 0x5ecea824.
+//
+// Solidity: function hasCapability(address agent, bytes32 capability) view returns(bool)
+func (_AgentRegistry *AgentRegistryCallerSession) HasCapability(agent common.Address, capability [32]byte) (bool, error) {
+	return _AgentRegistry.Contract.HasCapability(&_AgentRegistry.CallOpts, agent, capability)
+}
+
+// Owner is a free data retrieval call binding the contract method 0x8da5cb5b.
+//
+// Solidity: function owner() view returns(address)
+func (_AgentRegistry *AgentRegistryCaller) Owner(opts *bind.CallOpts) (common.Address, error) {
+	var out []interface{}
+	err := _AgentRegistry.contract.Call(opts, &out, "owner")
+
+	if err != nil {
+		return *new(common.Address), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(common.Address)).(*common.Address)
+
+	return out0, err
+
+}
+
+// Owner is a free data retrieval call binding the contract method 0x8da5cb5b.
+//
+// Solidity: function owner() view returns(address)
+func (_AgentRegistry *AgentRegistrySession) Owner() (common.Address, error) {
+	return _AgentRegistry.Contract.Owner(&_AgentRegistry.CallOpts)
+}
+
+// Owner is a free data retrieval call binding the contract method 0x8da5cb5b.
+//
+// Solidity: function owner() view returns(address)
+func (_AgentRegistry *AgentRegistryCallerSession) Owner() (common.Address, error) {
+	return _AgentRegistry.Contract.Owner(&_AgentRegistry.CallOpts)
+}
+
+// Paused is a free data retrieval call binding the contract method 0x5c975abb.
+//
+// Solidity: function paused() view returns(bool)
+func (_AgentRegistry *AgentRegistryCaller) Paused(opts *bind.CallOpts) (bool, error) {
+	var out []interface{}
+	err := _AgentRegistry.contract.Call(opts, &out, "paused")
+
+	if err != nil {
+		return *new(bool), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(bool)).(*bool)
+
+	return out0, err
+
+}
+
+// Paused is a free data retrieval call binding the contract method 0x5c975abb.
+//
+// Solidity: function paused() view returns(bool)
+func (_AgentRegistry *AgentRegistrySession) Paused() (bool, error) {
+	return _AgentRegistry.Contract.Paused(&_AgentRegistry.CallOpts)
+}
+
+// Paused is a free data retrieval call binding the contract method 0x5c975abb.
+//
+// Solidity: function paused() view returns(bool)
+func (_AgentRegistry *AgentRegistryCallerSession) Paused() (bool, error) {
+	return _AgentRegistry.Contract.Paused(&_AgentRegistry.CallOpts)
+}
+
+// Verifiers is a free data retrieval call binding the contract method 0x6c824487.
+//
+// Solidity: function verifiers(address ) view returns(bool)
+func (_AgentRegistry *AgentRegistryCaller) Verifiers(opts *bind.CallOpts, arg0 common.Address) (bool, error) {
+	var out []interface{}
+	err := _AgentRegistry.contract.Call(opts, &out, "verifiers", arg0)
+
+	if err != nil {
+		return *new(bool), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(bool)).(*bool)
+
+	return out0, err
+
+}
+
+// Verifiers is a free data retrieval call binding the contract method 0x6c824487.
+//
+// Solidity: function verifiers(address ) view returns(bool)
+func (_AgentRegistry *AgentRegistrySession) Verifiers(arg0 common.Address) (bool, error) {
+	return _AgentRegistry.Contract.Verifiers(&_AgentRegistry.CallOpts, arg0)
+}
+
+// Verifiers is a free data retrieval call binding the contract method 0x6c824487.
+//
+// Solidity: function verifiers(address ) view returns(bool)
+func (_AgentRegistry *AgentRegistryCallerSession) Verifiers(arg0 common.Address) (bool, error) {
+	return _AgentRegistry.Contract.Verifiers(&_AgentRegistry.CallOpts, arg0)
+}
+
+// AddVerifier is a paid mutator transaction binding the contract method 0x9000b3d6.
+//
+// Solidity: function addVerifier(address verifier) returns()
+func (_AgentRegistry *AgentRegistryTransactor) AddVerifier(opts *bind.TransactOpts, verifier common.Address) (*types.Transaction, error) {
+	return _AgentRegistry.contract.Transact(opts, "addVerifier", verifier)
+}
+
+// AddVerifier is a paid mutator transaction binding the contract method 0x9000b3d6.
+//
+// Solidity: function addVerifier(address verifier) returns()
+func (_AgentRegistry *AgentRegistrySession) AddVerifier(verifier common.Address) (*types.Transaction, error) {
+	return _AgentRegistry.Contract.AddVerifier(&_AgentRegistry.TransactOpts, verifier)
+}
+
+// AddVerifier is a paid mutator transaction binding the contract method 0x9000b3d6.
+//
+// Solidity: function addVerifier(address verifier) returns()
+func (_AgentRegistry *AgentRegistryTransactorSession) AddVerifier(verifier common.Address) (*types.Transaction, error) {
+	return _AgentRegistry.Contract.AddVerifier(&_AgentRegistry.TransactOpts, verifier)
+}
+
+// Pause is a paid mutator transaction binding the contract method 0x8456cb59.
+//
+// Solidity: function pause() returns()
+func (_AgentRegistry *AgentRegistryTransactor) Pause(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _AgentRegistry.contract.Transact(opts, "pause")
+}
+
+// Pause is a paid mutator transaction binding the contract method 0x8456cb59.
+//
+// Solidity: function pause() returns()
+func (_AgentRegistry *AgentRegistrySession) Pause() (*types.Transaction, error) {
+	return _AgentRegistry.Contract.Pause(&_AgentRegistry.TransactOpts)
+}
+
+// Pause is a paid mutator transaction binding the contract method 0x8456cb59.
+//
+// Solidity: function pause() returns()
+func (_AgentRegistry *AgentRegistryTransactorSession) Pause() (*types.Transaction, error) {
+	return _AgentRegistry.Contract.Pause(&_AgentRegistry.TransactOpts)
+}
+
+// RegisterAgent is a paid mutator transaction binding the contract method 0x2d2a9585.
+//
+// Solidity: function registerAgent(string did) returns()
+func (_AgentRegistry *AgentRegistryTransactor) RegisterAgent(opts *bind.TransactOpts, did string) (*types.Transaction, error) {
+	return _AgentRegistry.contract.Transact(opts, "registerAgent", did)
+}
+
+// RegisterAgent is a paid mutator transaction binding the contract method 0x2d2a9585.
+//
+// Solidity: function registerAgent(string did) returns()
+func (_AgentRegistry *AgentRegistrySession) RegisterAgent(did string) (*types.Transaction, error) {
+	return _AgentRegistry.Contract.RegisterAgent(&_AgentRegistry.TransactOpts, did)
+}
+
+// RegisterAgent is a paid mutator transaction binding the contract method 0x2d2a9585.
+//
+// Solidity: function registerAgent(string did) returns()
+func (_AgentRegistry *AgentRegistryTransactorSession) RegisterAgent(did string) (*types.Transaction, error) {
+	return _AgentRegistry.Contract.RegisterAgent(&_AgentRegistry.TransactOpts, did)
+}
+
+// RemoveVerifier is a paid mutator transaction binding the contract method 0xca2dfd0a.
+//
+// Solidity: function removeVerifier(address verifier) returns()
+func (_AgentRegistry *AgentRegistryTransactor) RemoveVerifier(opts *bind.TransactOpts, verifier common.Address) (*types.Transaction, error) {
+	return _AgentRegistry.contract.Transact(opts, "removeVerifier", verifier)
+}
+
+// RemoveVerifier is a paid mutator transaction binding the contract method 0xca2dfd0a.
+//
+// Solidity: function removeVerifier(address verifier) returns()
+func (_AgentRegistry *AgentRegistrySession) RemoveVerifier(verifier common.Address) (*types.Transaction, error) {
+	return _AgentRegistry.Contract.RemoveVerifier(&_AgentRegistry.TransactOpts, verifier)
+}
+
+// RemoveVerifier is a paid mutator transaction binding the contract method 0xca2dfd0a.
+//
+// Solidity: function removeVerifier(address verifier) returns()
+func (_AgentRegistry *AgentRegistryTransactorSession) RemoveVerifier(verifier common.Address) (*types.Transaction, error) {
+	return _AgentRegistry.Contract.RemoveVerifier(&_AgentRegistry.TransactOpts, verifier)
+}
+
+// RenounceOwnership is a paid mutator transaction binding the contract method 0x715018a6.
+//
+// Solidity: function renounceOwnership() returns()
+func (_AgentRegistry *AgentRegistryTransactor) RenounceOwnership(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _AgentRegistry.contract.Transact(opts, "renounceOwnership")
+}
+
+// RenounceOwnership is a paid mutator transaction binding the contract method 0x715018a6.
+//
+// Solidity: function renounceOwnership() returns()
+func (_AgentRegistry *AgentRegistrySession) RenounceOwnership() (*types.Transaction, error) {
+	return _AgentRegistry.Contract.RenounceOwnership(&_AgentRegistry.TransactOpts)
+}
+
+// RenounceOwnership is a paid mutator transaction binding the contract method 0x715018a6.
+//
+// Solidity: function renounceOwnership() returns()
+func (_AgentRegistry *AgentRegistryTransactorSession) RenounceOwnership() (*types.Transaction, error) {
+	return _AgentRegistry.Contract.RenounceOwnership(&_AgentRegistry.TransactOpts)
+}
+
+// RevokeAgent is a paid mutator transaction binding the contract method 0xbf6af90c.
+//
+// Solidity: function revokeAgent(address agent, string reason) returns()
+func (_AgentRegistry *AgentRegistryTransactor) RevokeAgent(opts *bind.TransactOpts, agent common.Address, reason string) (*types.Transaction, error) {
+	return _AgentRegistry.contract.Transact(opts, "revokeAgent", agent, reason)
+}
+
+// RevokeAgent is a paid mutator transaction binding the contract method 0xbf6af90c.
+//
+// Solidity: function revokeAgent(address agent, string reason) returns()
+func (_AgentRegistry *AgentRegistrySession) RevokeAgent(agent common.Address, reason string) (*types.Transaction, error) {
+	return _AgentRegistry.Contract.RevokeAgent(&_AgentRegistry.TransactOpts, agent, reason)
+}
+
+// RevokeAgent is a paid mutator transaction binding the contract method 0xbf6af90c.
+//
+// Solidity: function revokeAgent(address agent, string reason) returns()
+func (_AgentRegistry *AgentRegistryTransactorSession) RevokeAgent(agent common.Address, reason string) (*types.Transaction, error) {
+	return _AgentRegistry.Contract.RevokeAgent(&_AgentRegistry.TransactOpts, agent, reason)
+}
+
+// TransferOwnership is a paid mutator transaction binding the contract method 0xf2fde38b.
+//
+// Solidity: function transferOwnership(address newOwner) returns()
+func (_AgentRegistry *AgentRegistryTransactor) TransferOwnership(opts *bind.TransactOpts, newOwner common.Address) (*types.Transaction, error) {
+	return _AgentRegistry.contract.Transact(opts, "transferOwnership", newOwner)
+}
+
+// TransferOwnership is a paid mutator transaction binding the contract method 0xf2fde38b.
+//
+// Solidity: function transferOwnership(address newOwner) returns()
+func (_AgentRegistry *AgentRegistrySession) TransferOwnership(newOwner common.Address) (*types.Transaction, error) {
+	return _AgentRegistry.Contract.TransferOwnership(&_AgentRegistry.TransactOpts, newOwner)
+}
+
+// TransferOwnership is a paid mutator transaction binding the contract method 0xf2fde38b.
+//
+// Solidity: function transferOwnership(address newOwner) returns()
+func (_AgentRegistry *AgentRegistryTransactorSession) TransferOwnership(newOwner common.Address) (*types.Transaction, error) {
+	return _AgentRegistry.Contract.TransferOwnership(&_AgentRegistry.TransactOpts, newOwner)
+}
+
+// Unpause is a paid mutator transaction binding the contract method 0x3f4ba83a.
+//
+// Solidity: function unpause() returns()
+func (_AgentRegistry *AgentRegistryTransactor) Unpause(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _AgentRegistry.contract.Transact(opts, "unpause")
+}
+
+// Unpause is a paid mutator transaction binding the contract method 0x3f4ba83a.
+//
+// Solidity: function unpause() returns()
+func (_AgentRegistry *AgentRegistrySession) Unpause() (*types.Transaction, error) {
+	return _AgentRegistry.Contract.Unpause(&_AgentRegistry.TransactOpts)
+}
+
+// Unpause is a paid mutator transaction binding the contract method 0x3f4ba83a.
+//
+// Solidity: function unpause() returns()
+func (_AgentRegistry *AgentRegistryTransactorSession) Unpause() (*types.Transaction, error) {
+	return _AgentRegistry.Contract.Unpause(&_AgentRegistry.TransactOpts)
+}
+
+// VerifyAgent is a paid mutator transaction binding the contract method 0x21251d91.
+//
+// Solidity: function verifyAgent(address agent, uint8 newTrustLevel, bytes32[] capabilities) returns()
+func (_AgentRegistry *AgentRegistryTransactor) VerifyAgent(opts *bind.TransactOpts, agent common.Address, newTrustLevel uint8, capabilities [][32]byte) (*types.Transaction, error) {
+	return _AgentRegistry.contract.Transact(opts, "verifyAgent", agent, newTrustLevel, capabilities)
+}
+
+// VerifyAgent is a paid mutator transaction binding the contract method 0x21251d91.
+//
+// Solidity: function verifyAgent(address agent, uint8 newTrustLevel, bytes32[] capabilities) returns()
+func (_AgentRegistry *AgentRegistrySession) VerifyAgent(agent common.Address, newTrustLevel uint8, capabilities [][32]byte) (*types.Transaction, error) {
+	return _AgentRegistry.Contract.VerifyAgent(&_AgentRegistry.TransactOpts, agent, newTrustLevel, capabilities)
+}
+
+// VerifyAgent is a paid mutator transaction binding the contract method 0x21251d91.
+//
+// Solidity: function verifyAgent(address agent, uint8 newTrustLevel, bytes32[] capabilities) returns()
+func (_AgentRegistry *AgentRegistryTransactorSession) VerifyAgent(agent common.Address, newTrustLevel uint8, capabilities [][32]byte) (*types.Transaction, error) {
+	return _AgentRegistry.Contract.VerifyAgent(&_AgentRegistry.TransactOpts, agent, newTrustLevel, capabilities)
+}
+
+// AgentRegistryAgentRegisteredIterator is returned from FilterAgentRegistered and is used to iterate over the raw logs and unpacked data for AgentRegistered events raised by the AgentRegistry contract.
+type AgentRegistryAgentRegisteredIterator struct {
+	Event *AgentRegistryAgentRegistered // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *AgentRegistryAgentRegisteredIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(AgentRegistryAgentRegistered)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(AgentRegistryAgentRegistered)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *AgentRegistryAgentRegisteredIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *AgentRegistryAgentRegisteredIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// AgentRegistryAgentRegistered represents a AgentRegistered event raised by the AgentRegistry contract.
+type AgentRegistryAgentRegistered struct {
+	Agent      common.Address
+	Did        string
+	TrustLevel uint8
+	Raw        types.Log // Blockchain specific contextual infos
+}
+
+// FilterAgentRegistered is a free log retrieval operation binding the contract event 0x043b4bd510c310dfeac11db30f86f7186f983ad895bfad174b5f2a770834b932.
+//
+// Solidity: event AgentRegistered(address indexed agent, string did, uint8 trustLevel)
+func (_AgentRegistry *AgentRegistryFilterer) FilterAgentRegistered(opts *bind.FilterOpts, agent []common.Address) (*AgentRegistryAgentRegisteredIterator, error) {
+
+	var agentRule []interface{}
+	for _, agentItem := range agent {
+		agentRule = append(agentRule, agentItem)
+	}
+
+	logs, sub, err := _AgentRegistry.contract.FilterLogs(opts, "AgentRegistered", agentRule)
+	if err != nil {
+		return nil, err
+	}
+	return &AgentRegistryAgentRegisteredIterator{contract: _AgentRegistry.contract, event: "AgentRegistered", logs: logs, sub: sub}, nil
+}
+
+// WatchAgentRegistered is a free log subscription operation binding the contract event 0x043b4bd510c310dfeac11db30f86f7186f983ad895bfad174b5f2a770834b932.
+//
+// Solidity: event AgentRegistered(address indexed agent, string did, uint8 trustLevel)
+func (_AgentRegistry *AgentRegistryFilterer) WatchAgentRegistered(opts *bind.WatchOpts, sink chan<- *AgentRegistryAgentRegistered, agent []common.Address) (event.Subscription, error) {
+
+	var agentRule []interface{}
+	for _, agentItem := range agent {
+		agentRule = append(agentRule, agentItem)
+	}
+
+	logs, sub, err := _AgentRegistry.contract.WatchLogs(opts, "AgentRegistered", agentRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(AgentRegistryAgentRegistered)
+				if err := _AgentRegistry.contract.UnpackLog(event, "AgentRegistered", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseAgentRegistered is a log parse operation binding the contract event 0x043b4bd510c310dfeac11db30f86f7186f983ad895bfad174b5f2a770834b932.
+//
+// Solidity: event AgentRegistered(address indexed agent, string did, uint8 trustLevel)
+func (_AgentRegistry *AgentRegistryFilterer) ParseAgentRegistered(log types.Log) (*AgentRegistryAgentRegistered, error) {
+	event := new(AgentRegistryAgentRegistered)
+	if err := _AgentRegistry.contract.UnpackLog(event, "AgentRegistered", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// AgentRegistryAgentRevokedIterator is returned from FilterAgentRevoked and is used to iterate over the raw logs and unpacked data for AgentRevoked events raised by the AgentRegistry contract.
+type AgentRegistryAgentRevokedIterator struct {
+	Event *AgentRegistryAgentRevoked // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *AgentRegistryAgentRevokedIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(AgentRegistryAgentRevoked)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(AgentRegistryAgentRevoked)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *AgentRegistryAgentRevokedIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *AgentRegistryAgentRevokedIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// AgentRegistryAgentRevoked represents a AgentRevoked event raised by the AgentRegistry contract.
+type AgentRegistryAgentRevoked struct {
+	Agent  common.Address
+	Reason string
+	Raw    types.Log // Blockchain specific contextual infos
+}
+
+// FilterAgentRevoked is a free log retrieval operation binding the contract event 0x403d7e1a9c42fe215347ba00d9d34b4bf8ef8ef3b5b723900c8ab9576e4b7b2c.
+//
+// Solidity: event AgentRevoked(address indexed agent, string reason)
+func (_AgentRegistry *AgentRegistryFilterer) FilterAgentRevoked(opts *bind.FilterOpts, agent []common.Address) (*AgentRegistryAgentRevokedIterator, error) {
+
+	var agentRule []interface{}
+	for _, agentItem := range agent {
+		agentRule = append(agentRule, agentItem)
+	}
+
+	logs, sub, err := _AgentRegistry.contract.FilterLogs(opts, "AgentRevoked", agentRule)
+	if err != nil {
+		return nil, err
+	}
+	return &AgentRegistryAgentRevokedIterator{contract: _AgentRegistry.contract, event: "AgentRevoked", logs: logs, sub: sub}, nil
+}
+
+// WatchAgentRevoked is a free log subscription operation binding the contract event 0x403d7e1a9c42fe215347ba00d9d34b4bf8ef8ef3b5b723900c8ab9576e4b7b2c.
+//
+// Solidity: event AgentRevoked(address indexed agent, string reason)
+func (_AgentRegistry *AgentRegistryFilterer) WatchAgentRevoked(opts *bind.WatchOpts, sink chan<- *AgentRegistryAgentRevoked, agent []common.Address) (event.Subscription, error) {
+
+	var agentRule []interface{}
+	for _, agentItem := range agent {
+		agentRule = append(agentRule, agentItem)
+	}
+
+	logs, sub, err := _AgentRegistry.contract.WatchLogs(opts, "AgentRevoked", agentRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(AgentRegistryAgentRevoked)
+				if err := _AgentRegistry.contract.UnpackLog(event, "AgentRevoked", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseAgentRevoked is a log parse operation binding the contract event 0x403d7e1a9c42fe215347ba00d9d34b4bf8ef8ef3b5b723900c8ab9576e4b7b2c.
+//
+// Solidity: event AgentRevoked(address indexed agent, string reason)
+func (_AgentRegistry *AgentRegistryFilterer) ParseAgentRevoked(log types.Log) (*AgentRegistryAgentRevoked, error) {
+	event := new(AgentRegistryAgentRevoked)
+	if err := _AgentRegistry.contract.UnpackLog(event, "AgentRevoked", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// AgentRegistryAgentVerifiedIterator is returned from FilterAgentVerified and is used to iterate over the raw logs and unpacked data for AgentVerified events raised by the AgentRegistry contract.
+type AgentRegistryAgentVerifiedIterator struct {
+	Event *AgentRegistryAgentVerified // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *AgentRegistryAgentVerifiedIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(AgentRegistryAgentVerified)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(AgentRegistryAgentVerified)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *AgentRegistryAgentVerifiedIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *AgentRegistryAgentVerifiedIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// AgentRegistryAgentVerified represents a AgentVerified event raised by the AgentRegistry contract.
+type AgentRegistryAgentVerified struct {
+	Agent         common.Address
+	NewTrustLevel uint8
+	Raw           types.Log // Blockchain specific contextual infos
+}
+
+// FilterAgentVerified is a free log retrieval operation binding the contract event 0xeb3401046ed50fde9f1632c720ef16f263c0fe66eb2fda955c333836806992d7.
+//
+// Solidity: event AgentVerified(address indexed agent, uint8 newTrustLevel)
+func (_AgentRegistry *AgentRegistryFilterer) FilterAgentVerified(opts *bind.FilterOpts, agent []common.Address) (*AgentRegistryAgentVerifiedIterator, error) {
+
+	var agentRule []interface{}
+	for _, agentItem := range agent {
+		agentRule = append(agentRule, agentItem)
+	}
+
+	logs, sub, err := _AgentRegistry.contract.FilterLogs(opts, "AgentVerified", agentRule)
+	if err != nil {
+		return nil, err
+	}
+	return &AgentRegistryAgentVerifiedIterator{contract: _AgentRegistry.contract, event: "AgentVerified", logs: logs, sub: sub}, nil
+}
+
+// WatchAgentVerified is a free log subscription operation binding the contract event 0xeb3401046ed50fde9f1632c720ef16f263c0fe66eb2fda955c333836806992d7.
+//
+// Solidity: event AgentVerified(address indexed agent, uint8 newTrustLevel)
+func (_AgentRegistry *AgentRegistryFilterer) WatchAgentVerified(opts *bind.WatchOpts, sink chan<- *AgentRegistryAgentVerified, agent []common.Address) (event.Subscription, error) {
+
+	var agentRule []interface{}
+	for _, agentItem := range agent {
+		agentRule = append(agentRule, agentItem)
+	}
+
+	logs, sub, err := _AgentRegistry.contract.WatchLogs(opts, "AgentVerified", agentRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(AgentRegistryAgentVerified)
+				if err := _AgentRegistry.contract.UnpackLog(event, "AgentVerified", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseAgentVerified is a log parse operation binding the contract event 0xeb3401046ed50fde9f1632c720ef16f263c0fe66eb2fda955c333836806992d7.
+//
+// Solidity: event AgentVerified(address indexed agent, uint8 newTrustLevel)
+func (_AgentRegistry *AgentRegistryFilterer) ParseAgentVerified(log types.Log) (*AgentRegistryAgentVerified, error) {
+	event := new(AgentRegistryAgentVerified)
+	if err := _AgentRegistry.contract.UnpackLog(event, "AgentVerified", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// AgentRegistryCapabilityAddedIterator is returned from FilterCapabilityAdded and is used to iterate over the raw logs and unpacked data for CapabilityAdded events raised by the AgentRegistry contract.
+type AgentRegistryCapabilityAddedIterator struct {
+	Event *AgentRegistryCapabilityAdded // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *AgentRegistryCapabilityAddedIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(AgentRegistryCapabilityAdded)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(AgentRegistryCapabilityAdded)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *AgentRegistryCapabilityAddedIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *AgentRegistryCapabilityAddedIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// AgentRegistryCapabilityAdded represents a CapabilityAdded event raised by the AgentRegistry contract.
+type AgentRegistryCapabilityAdded struct {
+	Agent      common.Address
+	Capability [32]byte
+	Raw        types.Log // Blockchain specific contextual infos
+}
+
+// FilterCapabilityAdded is a free log retrieval operation binding the contract event 0x1eb5a0b9725a98314d6c9206c9e05bd837230cc22d009ff823d4ffb3cc945920.
+//
+// Solidity: event CapabilityAdded(address indexed agent, bytes32 capability)
+func (_AgentRegistry *AgentRegistryFilterer) FilterCapabilityAdded(opts *bind.FilterOpts, agent []common.Address) (*AgentRegistryCapabilityAddedIterator, error) {
+
+	var agentRule []interface{}
+	for _, agentItem := range agent {
+		agentRule = append(agentRule, agentItem)
+	}
+
+	logs, sub, err := _AgentRegistry.contract.FilterLogs(opts, "CapabilityAdded", agentRule)
+	if err != nil {
+		return nil, err
+	}
+	return &AgentRegistryCapabilityAddedIterator{contract: _AgentRegistry.contract, event: "CapabilityAdded", logs: logs, sub: sub}, nil
+}
+
+// WatchCapabilityAdded is a free log subscription operation binding the contract event 0x1eb5a0b9725a98314d6c9206c9e05bd837230cc22d009ff823d4ffb3cc945920.
+//
+// Solidity: event CapabilityAdded(address indexed agent, bytes32 capability)
+func (_AgentRegistry *AgentRegistryFilterer) WatchCapabilityAdded(opts *bind.WatchOpts, sink chan<- *AgentRegistryCapabilityAdded, agent []common.Address) (event.Subscription, error) {
+
+	var agentRule []interface{}
+	for _, agentItem := range agent {
+		agentRule = append(agentRule, agentItem)
+	}
+
+	logs, sub, err := _AgentRegistry.contract.WatchLogs(opts, "CapabilityAdded", agentRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(AgentRegistryCapabilityAdded)
+				if err := _AgentRegistry.contract.UnpackLog(event, "CapabilityAdded", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseCapabilityAdded is a log parse operation binding the contract event 0x1eb5a0b9725a98314d6c9206c9e05bd837230cc22d009ff823d4ffb3cc945920.
+//
+// Solidity: event CapabilityAdded(address indexed agent, bytes32 capability)
+func (_AgentRegistry *AgentRegistryFilterer) ParseCapabilityAdded(log types.Log) (*AgentRegistryCapabilityAdded, error) {
+	event := new(AgentRegistryCapabilityAdded)
+	if err := _AgentRegistry.contract.UnpackLog(event, "CapabilityAdded", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// AgentRegistryCapabilityRemovedIterator is returned from FilterCapabilityRemoved and is used to iterate over the raw logs and unpacked data for CapabilityRemoved events raised by the AgentRegistry contract.
+type AgentRegistryCapabilityRemovedIterator struct {
+	Event *AgentRegistryCapabilityRemoved // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *AgentRegistryCapabilityRemovedIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(AgentRegistryCapabilityRemoved)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(AgentRegistryCapabilityRemoved)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *AgentRegistryCapabilityRemovedIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *AgentRegistryCapabilityRemovedIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// AgentRegistryCapabilityRemoved represents a CapabilityRemoved event raised by the AgentRegistry contract.
+type AgentRegistryCapabilityRemoved struct {
+	Agent      common.Address
+	Capability [32]byte
+	Raw        types.Log // Blockchain specific contextual infos
+}
+
+// FilterCapabilityRemoved is a free log retrieval operation binding the contract event 0xc724b29248dabd8ebef0b5c90e325e40c6f0917ad63625a5de8f3cf17c3b973e.
+//
+// Solidity: event CapabilityRemoved(address indexed agent, bytes32 capability)
+func (_AgentRegistry *AgentRegistryFilterer) FilterCapabilityRemoved(opts *bind.FilterOpts, agent []common.Address) (*AgentRegistryCapabilityRemovedIterator, error) {
+
+	var agentRule []interface{}
+	for _, agentItem := range agent {
+		agentRule = append(agentRule, agentItem)
+	}
+
+	logs, sub, err := _AgentRegistry.contract.FilterLogs(opts, "CapabilityRemoved", agentRule)
+	if err != nil {
+		return nil, err
+	}
+	return &AgentRegistryCapabilityRemovedIterator{contract: _AgentRegistry.contract, event: "CapabilityRemoved", logs: logs, sub: sub}, nil
+}
+
+// WatchCapabilityRemoved is a free log subscription operation binding the contract event 0xc724b29248dabd8ebef0b5c90e325e40c6f0917ad63625a5de8f3cf17c3b973e.
+//
+// Solidity: event CapabilityRemoved(address indexed agent, bytes32 capability)
+func (_AgentRegistry *AgentRegistryFilterer) WatchCapabilityRemoved(opts *bind.WatchOpts, sink chan<- *AgentRegistryCapabilityRemoved, agent []common.Address) (event.Subscription, error) {
+
+	var agentRule []interface{}
+	for _, agentItem := range agent {
+		agentRule = append(agentRule, agentItem)
+	}
+
+	logs, sub, err := _AgentRegistry.contract.WatchLogs(opts, "CapabilityRemoved", agentRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(AgentRegistryCapabilityRemoved)
+				if err := _AgentRegistry.contract.UnpackLog(event, "CapabilityRemoved", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseCapabilityRemoved is a log parse operation binding the contract event 0xc724b29248dabd8ebef0b5c90e325e40c6f0917ad63625a5de8f3cf17c3b973e.
+//
+// Solidity: event CapabilityRemoved(address indexed agent, bytes32 capability)
+func (_AgentRegistry *AgentRegistryFilterer) ParseCapabilityRemoved(log types.Log) (*AgentRegistryCapabilityRemoved, error) {
+	event := new(AgentRegistryCapabilityRemoved)
+	if err := _AgentRegistry.contract.UnpackLog(event, "CapabilityRemoved", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// AgentRegistryOwnershipTransferredIterator is returned from FilterOwnershipTransferred and is used to iterate over the raw logs and unpacked data for OwnershipTransferred events raised by the AgentRegistry contract.
+type AgentRegistryOwnershipTransferredIterator struct {
+	Event *AgentRegistryOwnershipTransferred // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *AgentRegistryOwnershipTransferredIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(AgentRegistryOwnershipTransferred)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(AgentRegistryOwnershipTransferred)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *AgentRegistryOwnershipTransferredIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *AgentRegistryOwnershipTransferredIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// AgentRegistryOwnershipTransferred represents a OwnershipTransferred event raised by the AgentRegistry contract.
+type AgentRegistryOwnershipTransferred struct {
+	PreviousOwner common.Address
+	NewOwner      common.Address
+	Raw           types.Log // Blockchain specific contextual infos
+}
+
+// FilterOwnershipTransferred is a free log retrieval operation binding the contract event 0x8be0079c531659141344cd1fd0a4f28419497f9722a3daafe3b4186f6b6457e0.
+//
+// Solidity: event OwnershipTransferred(address indexed previousOwner, address indexed newOwner)
+func (_AgentRegistry *AgentRegistryFilterer) FilterOwnershipTransferred(opts *bind.FilterOpts, previousOwner []common.Address, newOwner []common.Address) (*AgentRegistryOwnershipTransferredIterator, error) {
+
+	var previousOwnerRule []interface{}
+	for _, previousOwnerItem := range previousOwner {
+		previousOwnerRule = append(previousOwnerRule, previousOwnerItem)
+	}
+	var newOwnerRule []interface{}
+	for _, newOwnerItem := range newOwner {
+		newOwnerRule = append(newOwnerRule, newOwnerItem)
+	}
+
+	logs, sub, err := _AgentRegistry.contract.FilterLogs(opts, "OwnershipTransferred", previousOwnerRule, newOwnerRule)
+	if err != nil {
+		return nil, err
+	}
+	return &AgentRegistryOwnershipTransferredIterator{contract: _AgentRegistry.contract, event: "OwnershipTransferred", logs: logs, sub: sub}, nil
+}
+
+// WatchOwnershipTransferred is a free log subscription operation binding the contract event 0x8be0079c531659141344cd1fd0a4f28419497f9722a3daafe3b4186f6b6457e0.
+//
+// Solidity: event OwnershipTransferred(address indexed previousOwner, address indexed newOwner)
+func (_AgentRegistry *AgentRegistryFilterer) WatchOwnershipTransferred(opts *bind.WatchOpts, sink chan<- *AgentRegistryOwnershipTransferred, previousOwner []common.Address, newOwner []common.Address) (event.Subscription, error) {
+
+	var previousOwnerRule []interface{}
+	for _, previousOwnerItem := range previousOwner {
+		previousOwnerRule = append(previousOwnerRule, previousOwnerItem)
+	}
+	var newOwnerRule []interface{}
+	for _, newOwnerItem := range newOwner {
+		newOwnerRule = append(newOwnerRule, newOwnerItem)
+	}
+
+	logs, sub, err := _AgentRegistry.contract.WatchLogs(opts, "OwnershipTransferred", previousOwnerRule, newOwnerRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(AgentRegistryOwnershipTransferred)
+				if err := _AgentRegistry.contract.UnpackLog(event, "OwnershipTransferred", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseOwnershipTransferred is a log parse operation binding the contract event 0x8be0079c531659141344cd1fd0a4f28419497f9722a3daafe3b4186f6b6457e0.
+//
+// Solidity: event OwnershipTransferred(address indexed previousOwner, address indexed newOwner)
+func (_AgentRegistry *AgentRegistryFilterer) ParseOwnershipTransferred(log types.Log) (*AgentRegistryOwnershipTransferred, error) {
+	event := new(AgentRegistryOwnershipTransferred)
+	if err := _AgentRegistry.contract.UnpackLog(event, "OwnershipTransferred", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// AgentRegistryPausedIterator is returned from FilterPaused and is used to iterate over the raw logs and unpacked data for Paused events raised by the AgentRegistry contract.
+type AgentRegistryPausedIterator struct {
+	Event *AgentRegistryPaused // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *AgentRegistryPausedIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(AgentRegistryPaused)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(AgentRegistryPaused)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *AgentRegistryPausedIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *AgentRegistryPausedIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// AgentRegistryPaused represents a Paused event raised by the AgentRegistry contract.
+type AgentRegistryPaused struct {
+	Account common.Address
+	Raw     types.Log // Blockchain specific contextual infos
+}
+
+// FilterPaused is a free log retrieval operation binding the contract event 0x62e78cea01bee320cd4e420270b5ea74000d11b0c9f74754ebdbfc544b05a258.
+//
+// Solidity: event Paused(address account)
+func (_AgentRegistry *AgentRegistryFilterer) FilterPaused(opts *bind.FilterOpts) (*AgentRegistryPausedIterator, error) {
+
+	logs, sub, err := _AgentRegistry.contract.FilterLogs(opts, "Paused")
+	if err != nil {
+		return nil, err
+	}
+	return &AgentRegistryPausedIterator{contract: _AgentRegistry.contract, event: "Paused", logs: logs, sub: sub}, nil
+}
+
+// WatchPaused is a free log subscription operation binding the contract event 0x62e78cea01bee320cd4e420270b5ea74000d11b0c9f74754ebdbfc544b05a258.
+//
+// Solidity: event Paused(address account)
+func (_AgentRegistry *AgentRegistryFilterer) WatchPaused(opts *bind.WatchOpts, sink chan<- *AgentRegistryPaused) (event.Subscription, error) {
+
+	logs, sub, err := _AgentRegistry.contract.WatchLogs(opts, "Paused")
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(AgentRegistryPaused)
+				if err := _AgentRegistry.contract.UnpackLog(event, "Paused", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParsePaused is a log parse operation binding the contract event 0x62e78cea01bee320cd4e420270b5ea74000d11b0c9f74754ebdbfc544b05a258.
+//
+// Solidity: event Paused(address account)
+func (_AgentRegistry *AgentRegistryFilterer) ParsePaused(log types.Log) (*AgentRegistryPaused, error) {
+	event := new(AgentRegistryPaused)
+	if err := _AgentRegistry.contract.UnpackLog(event, "Paused", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// AgentRegistryUnpausedIterator is returned from FilterUnpaused and is used to iterate over the raw logs and unpacked data for Unpaused events raised by the AgentRegistry contract.
+type AgentRegistryUnpausedIterator struct {
+	Event *AgentRegistryUnpaused // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *AgentRegistryUnpausedIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(AgentRegistryUnpaused)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(AgentRegistryUnpaused)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *AgentRegistryUnpausedIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *AgentRegistryUnpausedIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// AgentRegistryUnpaused represents a Unpaused event raised by the AgentRegistry contract.
+type AgentRegistryUnpaused struct {
+	Account common.Address
+	Raw     types.Log // Blockchain specific contextual infos
+}
+
+// FilterUnpaused is a free log retrieval operation binding the contract event 0x5db9ee0a495bf2e6ff9c91a7834c1ba4fdd244a5e8aa4e537bd38aeae4b073aa.
+//
+// Solidity: event Unpaused(address account)
+func (_AgentRegistry *AgentRegistryFilterer) FilterUnpaused(opts *bind.FilterOpts) (*AgentRegistryUnpausedIterator, error) {
+
+	logs, sub, err := _AgentRegistry.contract.FilterLogs(opts, "Unpaused")
+	if err != nil {
+		return nil, err
+	}
+	return &AgentRegistryUnpausedIterator{contract: _AgentRegistry.contract, event: "Unpaused", logs: logs, sub: sub}, nil
+}
+
+// WatchUnpaused is a free log subscription operation binding the contract event 0x5db9ee0a495bf2e6ff9c91a7834c1ba4fdd244a5e8aa4e537bd38aeae4b073aa.
+//
+// Solidity: event Unpaused(address account)
+func (_AgentRegistry *AgentRegistryFilterer) WatchUnpaused(opts *bind.WatchOpts, sink chan<- *AgentRegistryUnpaused) (event.Subscription, error) {
+
+	logs, sub, err := _AgentRegistry.contract.WatchLogs(opts, "Unpaused")
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(AgentRegistryUnpaused)
+				if err := _AgentRegistry.contract.UnpackLog(event, "Unpaused", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseUnpaused is a log parse operation binding the contract event 0x5db9ee0a495bf2e6ff9c91a7834c1ba4fdd244a5e8aa4e537bd38aeae4b073aa.
+//
+// Solidity: event Unpaused(address account)
+func (_AgentRegistry *AgentRegistryFilterer) ParseUnpaused(log types.Log) (*AgentRegistryUnpaused, error) {
+	event := new(AgentRegistryUnpaused)
+	if err := _AgentRegistry.contract.UnpackLog(event, "Unpaused", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
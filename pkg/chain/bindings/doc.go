@@ -0,0 +1,9 @@
+// Package bindings contains the abigen bindings of the AgentRegistry and
+// AgentDelegation contracts. Run `forge build` in contracts/ and then
+// `go generate` in this directory to regenerate them after a contract changes.
+package bindings
+
+//go:generate sh -c "jq -c .abi ../../../contracts/out/AgentRegistry.sol/AgentRegistry.json > /tmp/AgentRegistry.abi && jq -r .bytecode.object ../../../contracts/out/AgentRegistry.sol/AgentRegistry.json > /tmp/AgentRegistry.bin"
+//go:generate go run github.com/ethereum/go-ethereum/cmd/abigen --abi /tmp/AgentRegistry.abi --bin /tmp/AgentRegistry.bin --pkg bindings --type AgentRegistry --out agent_registry.go
+//go:generate sh -c "jq -c .abi ../../../contracts/out/AgentDelegation.sol/AgentDelegation.json > /tmp/AgentDelegation.abi && jq -r .bytecode.object ../../../contracts/out/AgentDelegation.sol/AgentDelegation.json > /tmp/AgentDelegation.bin"
+//go:generate go run github.com/ethereum/go-ethereum/cmd/abigen --abi /tmp/AgentDelegation.abi --bin /tmp/AgentDelegation.bin --pkg bindings --type AgentDelegation --out agent_delegation.go
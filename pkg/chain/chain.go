@@ -0,0 +1,237 @@
+// Package chain registers agents and manages delegations through the
+// AgentRegistry and AgentDelegation contracts. Transactions are signed by a
+// key.Signer, so the same agent keys, keystores and HSMs used for credentials
+// can act on-chain.
+package chain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ak68a/agentid-core/pkg/capability"
+	"github.com/ak68a/agentid-core/pkg/chain/bindings"
+	"github.com/ak68a/agentid-core/pkg/key"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+var (
+	// ErrReadOnly is returned when a transaction is sent through a Client without a transactor
+	ErrReadOnly = errors.New("chain client has no transactor")
+	// ErrTransactionFailed is returned when a mined transaction reverted
+	ErrTransactionFailed = errors.New("transaction failed")
+)
+
+// TrustLevel mirrors the AgentRegistry.TrustLevel enum
+type TrustLevel uint8
+
+const (
+	Unverified TrustLevel = iota
+	Verified
+	Certified
+	CrossChain
+)
+
+var trustLevelNames = []string{"Unverified", "Verified", "Certified", "CrossChain"}
+
+// String returns the name the contract gives the level
+func (l TrustLevel) String() string {
+	if int(l) < len(trustLevelNames) {
+		return trustLevelNames[l]
+	}
+	return fmt.Sprintf("TrustLevel(%d)", uint8(l))
+}
+
+// ParseTrustLevel parses a level name, ignoring case
+func ParseTrustLevel(s string) (TrustLevel, error) {
+	for i, name := range trustLevelNames {
+		if strings.EqualFold(s, name) {
+			return TrustLevel(i), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown trust level %q", s)
+}
+
+// Backend is what a Client needs from an Ethereum node; *ethclient.Client and
+// the simulated backend's client both satisfy it
+type Backend interface {
+	bind.ContractBackend
+	bind.DeployBackend
+}
+
+// Addresses locates the deployed contracts
+type Addresses struct {
+	Registry   common.Address `json:"registry"`
+	Delegation common.Address `json:"delegation"`
+}
+
+// Client calls the AgentRegistry and AgentDelegation contracts
+type Client struct {
+	backend      Backend
+	addresses    Addresses
+	registry     *bindings.AgentRegistry
+	delegation   *bindings.AgentDelegation
+	signer       key.Signer
+	chainID      *big.Int
+	capabilities *capability.Registry
+	block        *big.Int // Block reads are made at, nil for the latest
+}
+
+// Option configures a Client
+type Option func(*Client)
+
+// WithTransactor lets the client send transactions signed by signer for the
+// chain with the given ID. Without it the client is read-only.
+func WithTransactor(signer key.Signer, chainID *big.Int) Option {
+	return func(c *Client) {
+		c.signer = signer
+		c.chainID = chainID
+	}
+}
+
+// WithCapabilityRegistry sets the registry used to decode on-chain capability
+// IDs. It defaults to capability.Default; capabilities the client writes are
+// added to it.
+func WithCapabilityRegistry(registry *capability.Registry) Option {
+	return func(c *Client) {
+		c.capabilities = registry
+	}
+}
+
+// gasMargin is the percentage added to gas estimates. Nodes estimate against
+// the latest block, so a transaction that stores block.timestamp, such as
+// verifyAgent right after registerAgent, can look cheaper than it will be.
+const gasMargin = 20
+
+// estimator raises the gas estimates of a backend by gasMargin
+type estimator struct {
+	Backend
+}
+
+func (e estimator) EstimateGas(ctx context.Context, call ethereum.CallMsg) (uint64, error) {
+	gas, err := e.Backend.EstimateGas(ctx, call)
+	return gas + gas*gasMargin/100, err
+}
+
+// NewClient creates a Client for the contracts at addresses
+func NewClient(backend Backend, addresses Addresses, opts ...Option) (*Client, error) {
+	backend = estimator{backend}
+	registry, err := bindings.NewAgentRegistry(addresses.Registry, backend)
+	if err != nil {
+		return nil, fmt.Errorf("failed to bind AgentRegistry: %w", err)
+	}
+	delegation, err := bindings.NewAgentDelegation(addresses.Delegation, backend)
+	if err != nil {
+		return nil, fmt.Errorf("failed to bind AgentDelegation: %w", err)
+	}
+	c := &Client{
+		backend:      backend,
+		addresses:    addresses,
+		registry:     registry,
+		delegation:   delegation,
+		capabilities: capability.Default,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c, nil
+}
+
+// Addresses returns the addresses of the contracts the client calls
+func (c *Client) Addresses() Addresses {
+	return c.addresses
+}
+
+// Backend returns the backend the client reads the chain through
+func (c *Client) Backend() Backend {
+	return c.backend
+}
+
+// At returns a copy of the client whose reads are made against the state at
+// the given block. A nil number reads the latest state.
+func (c *Client) At(number *big.Int) *Client {
+	copied := *c
+	copied.block = number
+	return &copied
+}
+
+// Wait waits for tx to be mined and returns its receipt, or ErrTransactionFailed if it reverted
+func (c *Client) Wait(ctx context.Context, tx *types.Transaction) (*types.Receipt, error) {
+	return wait(ctx, c.backend, tx)
+}
+
+func wait(ctx context.Context, backend bind.DeployBackend, tx *types.Transaction) (*types.Receipt, error) {
+	receipt, err := bind.WaitMined(ctx, backend, tx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to wait for transaction %s: %w", tx.Hash().Hex(), err)
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return receipt, fmt.Errorf("%w: %s", ErrTransactionFailed, tx.Hash().Hex())
+	}
+	return receipt, nil
+}
+
+// encode registers capabilities and returns their IDs in the form the bindings take
+func (c *Client) encode(capabilities []capability.Capability) ([][32]byte, error) {
+	ids := make([][32]byte, len(capabilities))
+	for i, granted := range capabilities {
+		if err := granted.Validate(); err != nil {
+			return nil, err
+		}
+		ids[i] = c.capabilities.Register(granted)
+	}
+	return ids, nil
+}
+
+func (c *Client) callOpts(ctx context.Context) *bind.CallOpts {
+	return &bind.CallOpts{Context: ctx, BlockNumber: c.block}
+}
+
+func (c *Client) transactOpts(ctx context.Context) (*bind.TransactOpts, error) {
+	if c.signer == nil {
+		return nil, ErrReadOnly
+	}
+	return NewTransactor(ctx, c.signer, c.chainID), nil
+}
+
+// NewTransactor returns transaction options that sign with signer for the
+// chain with the given ID
+func NewTransactor(ctx context.Context, signer key.Signer, chainID *big.Int) *bind.TransactOpts {
+	from := common.HexToAddress(signer.GetAddress())
+	txSigner := types.LatestSignerForChainID(chainID)
+	return &bind.TransactOpts{
+		From:    from,
+		Context: ctx,
+		Signer: func(address common.Address, tx *types.Transaction) (*types.Transaction, error) {
+			if address != from {
+				return nil, bind.ErrNotAuthorized
+			}
+			signature, err := signer.SignHash(ctx, txSigner.Hash(tx).Bytes())
+			if err != nil {
+				return nil, fmt.Errorf("failed to sign transaction: %w", err)
+			}
+			return tx.WithSignature(txSigner, signature)
+		},
+	}
+}
+
+// Deploy deploys an AgentRegistry and an AgentDelegation bound to it, owned by
+// signer. The contracts can be used once both returned transactions are mined.
+func Deploy(ctx context.Context, backend Backend, signer key.Signer, chainID *big.Int) (Addresses, []*types.Transaction, error) {
+	opts := NewTransactor(ctx, signer, chainID)
+	backend = estimator{backend}
+	registry, registryTx, _, err := bindings.DeployAgentRegistry(opts, backend)
+	if err != nil {
+		return Addresses{}, nil, fmt.Errorf("failed to deploy AgentRegistry: %w", err)
+	}
+	delegation, delegationTx, _, err := bindings.DeployAgentDelegation(opts, backend, registry)
+	if err != nil {
+		return Addresses{}, nil, fmt.Errorf("failed to deploy AgentDelegation: %w", err)
+	}
+	return Addresses{Registry: registry, Delegation: delegation}, []*types.Transaction{registryTx, delegationTx}, nil
+}
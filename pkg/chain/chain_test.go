@@ -0,0 +1,203 @@
+package chain_test
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ak68a/agentid-core/pkg/capability"
+	"github.com/ak68a/agentid-core/pkg/chain"
+	"github.com/ak68a/agentid-core/pkg/chain/chaintest"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	transferETH = capability.Capability{Action: "transfer", Scope: "ETH"}
+	bookFlights = capability.Capability{Action: "booking", Scope: "flights"}
+)
+
+func TestRegistry(t *testing.T) {
+	ctx := context.Background()
+	agent := chaintest.NewKey(t)
+	tc := chaintest.New(t, agent)
+	owner := tc.Owner
+	client := tc.Client(agent)
+
+	tc.Send(client.RegisterAgent(ctx, agent.DID))
+	registered, err := client.GetAgent(ctx, chaintest.Address(agent))
+	require.NoError(t, err)
+	assert.Equal(t, agent.DID, registered.DID)
+	assert.Equal(t, chain.Unverified, registered.TrustLevel)
+	assert.True(t, registered.Active)
+	assert.False(t, registered.RegisteredAt.IsZero())
+
+	found, err := client.GetAgentAddress(ctx, agent.DID)
+	require.NoError(t, err)
+	assert.Equal(t, chaintest.Address(agent), found)
+
+	// The DID and the address can only be registered once
+	_, err = client.RegisterAgent(ctx, agent.DID+"-2")
+	assert.ErrorContains(t, err, "Agent already registered")
+
+	// Only verifiers can verify
+	_, err = client.VerifyAgent(ctx, chaintest.Address(agent), chain.Verified, nil)
+	assert.ErrorContains(t, err, "Not a verifier")
+
+	tc.Send(owner.VerifyAgent(ctx, chaintest.Address(agent), chain.Certified, []capability.Capability{transferETH}))
+	level, err := client.GetAgentTrustLevel(ctx, chaintest.Address(agent))
+	require.NoError(t, err)
+	assert.Equal(t, chain.Certified, level)
+	ok, err := client.HasCapability(ctx, chaintest.Address(agent), transferETH)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	ok, err = client.HasCapability(ctx, chaintest.Address(agent), bookFlights)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	// Trust levels only go up
+	_, err = owner.VerifyAgent(ctx, chaintest.Address(agent), chain.Verified, nil)
+	assert.ErrorContains(t, err, "Invalid trust level upgrade")
+
+	before, err := tc.Backend.Client().BlockNumber(ctx)
+	require.NoError(t, err)
+	tc.Send(owner.RevokeAgent(ctx, chaintest.Address(agent), "compromised"))
+	revoked, err := client.GetAgent(ctx, chaintest.Address(agent))
+	require.NoError(t, err)
+	assert.False(t, revoked.Active)
+	ok, err = client.HasCapability(ctx, chaintest.Address(agent), transferETH)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	// Reads can be made against earlier state
+	earlier, err := client.At(new(big.Int).SetUint64(before)).GetAgent(ctx, chaintest.Address(agent))
+	require.NoError(t, err)
+	assert.True(t, earlier.Active)
+}
+
+func TestDelegation(t *testing.T) {
+	ctx := context.Background()
+	alice, bob := chaintest.NewKey(t), chaintest.NewKey(t)
+	tc := chaintest.New(t, alice, bob)
+	owner := tc.Owner
+	aliceClient := tc.Client(alice)
+	bobClient := tc.Client(bob)
+
+	tc.Send(aliceClient.RegisterAgent(ctx, alice.DID))
+	tc.Send(bobClient.RegisterAgent(ctx, bob.DID))
+
+	// Unverified agents cannot delegate
+	validUntil := time.Now().Add(time.Hour)
+	_, err := aliceClient.CreateDelegation(ctx, chaintest.Address(bob), []capability.Capability{transferETH}, validUntil)
+	assert.ErrorContains(t, err, "Not a registered agent")
+
+	tc.Send(owner.VerifyAgent(ctx, chaintest.Address(alice), chain.Verified, nil))
+	tc.Send(owner.VerifyAgent(ctx, chaintest.Address(bob), chain.Verified, nil))
+	tc.Send(aliceClient.CreateDelegation(ctx, chaintest.Address(bob), []capability.Capability{transferETH}, validUntil))
+
+	ok, err := bobClient.HasDelegatedCapability(ctx, chaintest.Address(alice), chaintest.Address(bob), transferETH)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	ok, err = bobClient.HasDelegatedCapability(ctx, chaintest.Address(alice), chaintest.Address(bob), bookFlights)
+	require.NoError(t, err)
+	assert.False(t, ok)
+	ok, err = bobClient.HasDelegatedCapability(ctx, chaintest.Address(bob), chaintest.Address(alice), transferETH)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	details, err := bobClient.GetDelegationDetails(ctx, chaintest.Address(alice), chaintest.Address(bob))
+	require.NoError(t, err)
+	assert.True(t, details.Active)
+	assert.Equal(t, []capability.ID{transferETH.ID()}, details.Capabilities)
+	assert.Equal(t, []capability.Capability{transferETH}, details.Granted)
+	assert.Equal(t, validUntil.Unix(), details.ValidUntil.Unix())
+	delegates, err := bobClient.GetDelegates(ctx, chaintest.Address(alice))
+	require.NoError(t, err)
+	assert.Equal(t, []common.Address{chaintest.Address(bob)}, delegates)
+
+	// Only the delegator can revoke
+	_, err = bobClient.RevokeDelegation(ctx, chaintest.Address(alice), "not mine")
+	assert.ErrorContains(t, err, "No active delegation")
+	tc.Send(aliceClient.RevokeDelegation(ctx, chaintest.Address(bob), "done"))
+	ok, err = bobClient.HasDelegatedCapability(ctx, chaintest.Address(alice), chaintest.Address(bob), transferETH)
+	require.NoError(t, err)
+	assert.False(t, ok)
+	details, err = bobClient.GetDelegationDetails(ctx, chaintest.Address(alice), chaintest.Address(bob))
+	require.NoError(t, err)
+	assert.False(t, details.Active)
+}
+
+func TestDelegatedWildcard(t *testing.T) {
+	ctx := context.Background()
+	alice, bob := chaintest.NewKey(t), chaintest.NewKey(t)
+	tc := chaintest.New(t, alice, bob)
+	owner := tc.Owner
+	aliceClient := tc.Client(alice)
+	// Bob's client has never seen the capabilities Alice delegates
+	bobClient, err := chain.NewClient(tc.Backend.Client(), tc.Addresses, chain.WithCapabilityRegistry(capability.NewRegistry()))
+	require.NoError(t, err)
+
+	tc.Send(aliceClient.RegisterAgent(ctx, alice.DID))
+	tc.Send(tc.Client(bob).RegisterAgent(ctx, bob.DID))
+	// Verifying in the block after the registration costs more than estimated
+	// against the registration's block
+	tc.Send(owner.VerifyAgent(ctx, chaintest.Address(bob), chain.Verified, nil))
+	tc.Send(owner.VerifyAgent(ctx, chaintest.Address(alice), chain.Verified, nil))
+
+	hotels := capability.Capability{Action: "booking", Scope: "hotels/*"}
+	tc.Send(aliceClient.CreateDelegation(ctx, chaintest.Address(bob), []capability.Capability{{Action: "transfer", Scope: "*"}, hotels}, time.Now().Add(time.Hour)))
+
+	tests := []struct {
+		want    capability.Capability
+		granted bool
+	}{
+		{transferETH, true},
+		{capability.Capability{Action: "transfer", Scope: "USD", Qualifier: "max-100"}, true},
+		{capability.Capability{Action: "booking", Scope: "hotels/paris"}, true},
+		{bookFlights, false},
+	}
+	for _, tt := range tests {
+		ok, err := aliceClient.HasDelegatedCapability(ctx, chaintest.Address(alice), chaintest.Address(bob), tt.want)
+		require.NoError(t, err)
+		assert.Equal(t, tt.granted, ok, tt.want.String())
+	}
+
+	// Wildcards are only expanded when their IDs can be decoded
+	ok, err := bobClient.HasDelegatedCapability(ctx, chaintest.Address(alice), chaintest.Address(bob), transferETH)
+	require.NoError(t, err)
+	assert.False(t, ok)
+	ok, err = bobClient.HasDelegatedCapability(ctx, chaintest.Address(alice), chaintest.Address(bob), hotels)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	_, err = aliceClient.CreateDelegation(ctx, chaintest.Address(bob), []capability.Capability{{Action: "transfer"}}, time.Now().Add(time.Hour))
+	assert.ErrorIs(t, err, capability.ErrInvalidCapability)
+}
+
+func TestReadOnlyClient(t *testing.T) {
+	tc := chaintest.New(t)
+	client, err := chain.NewClient(tc.Backend.Client(), tc.Addresses)
+	require.NoError(t, err)
+
+	_, err = client.RegisterAgent(context.Background(), "did:ackid:0x1")
+	assert.ErrorIs(t, err, chain.ErrReadOnly)
+	level, err := client.GetAgentTrustLevel(context.Background(), chaintest.Address(tc.OwnerKey))
+	require.NoError(t, err)
+	assert.Equal(t, chain.Unverified, level)
+}
+
+func TestTrustLevel(t *testing.T) {
+	for _, level := range []chain.TrustLevel{chain.Unverified, chain.Verified, chain.Certified, chain.CrossChain} {
+		parsed, err := chain.ParseTrustLevel(level.String())
+		require.NoError(t, err)
+		assert.Equal(t, level, parsed)
+	}
+	parsed, err := chain.ParseTrustLevel("certified")
+	require.NoError(t, err)
+	assert.Equal(t, chain.Certified, parsed)
+	_, err = chain.ParseTrustLevel("trusted")
+	assert.Error(t, err)
+	assert.Equal(t, "TrustLevel(9)", chain.TrustLevel(9).String())
+}
@@ -0,0 +1,96 @@
+// Package chaintest provides a simulated chain with the AgentID contracts
+// deployed, for testing code that talks to them.
+package chaintest
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ak68a/agentid-core/pkg/capability"
+	"github.com/ak68a/agentid-core/pkg/chain"
+	"github.com/ak68a/agentid-core/pkg/key"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient/simulated"
+	"github.com/stretchr/testify/require"
+)
+
+// Chain is a simulated chain with the contracts deployed by an owner that is
+// also a verifier
+type Chain struct {
+	Backend   *simulated.Backend
+	ChainID   *big.Int
+	Addresses chain.Addresses
+	OwnerKey  *key.AgentKey // Key that deployed the contracts
+	Owner     *chain.Client // Client transacting as the owner
+
+	t *testing.T
+}
+
+// NewKey generates an agent key
+func NewKey(t *testing.T) *key.AgentKey {
+	k, err := key.GenerateAgentKey()
+	require.NoError(t, err)
+	return k
+}
+
+// Address returns the Ethereum address of a key
+func Address(k key.Signer) common.Address {
+	return common.HexToAddress(k.GetAddress())
+}
+
+// New starts a simulated chain, closed when the test ends, and deploys the
+// contracts on it. The funded keys get a balance to pay for transactions.
+func New(t *testing.T, funded ...*key.AgentKey) *Chain {
+	ctx := context.Background()
+	owner := NewKey(t)
+	balance, _ := new(big.Int).SetString("100000000000000000000", 10)
+	alloc := types.GenesisAlloc{Address(owner): {Balance: balance}}
+	for _, k := range funded {
+		alloc[Address(k)] = types.Account{Balance: balance}
+	}
+	backend := simulated.NewBackend(alloc)
+	t.Cleanup(func() { backend.Close() })
+
+	chainID, err := backend.Client().ChainID(ctx)
+	require.NoError(t, err)
+	addresses, txs, err := chain.Deploy(ctx, backend.Client(), owner, chainID)
+	require.NoError(t, err)
+	c := &Chain{Backend: backend, ChainID: chainID, Addresses: addresses, OwnerKey: owner, t: t}
+	c.Mine(txs...)
+	c.Owner = c.Client(owner)
+	c.Send(c.Owner.AddVerifier(ctx, Address(owner)))
+	return c
+}
+
+// Client returns a client transacting as signer
+func (c *Chain) Client(signer key.Signer) *chain.Client {
+	client, err := chain.NewClient(c.Backend.Client(), c.Addresses, chain.WithTransactor(signer, c.ChainID))
+	require.NoError(c.t, err)
+	return client
+}
+
+// Mine commits a block and requires txs to have succeeded in it
+func (c *Chain) Mine(txs ...*types.Transaction) {
+	c.Backend.Commit()
+	for _, tx := range txs {
+		receipt, err := bind.WaitMined(context.Background(), c.Backend.Client(), tx)
+		require.NoError(c.t, err)
+		require.Equal(c.t, types.ReceiptStatusSuccessful, receipt.Status)
+	}
+}
+
+// Send requires a transaction to have been sent and mines it
+func (c *Chain) Send(tx *types.Transaction, err error) {
+	require.NoError(c.t, err)
+	c.Mine(tx)
+}
+
+// Register registers an agent and has the owner verify it at level with the capabilities
+func (c *Chain) Register(agent *key.AgentKey, level chain.TrustLevel, capabilities ...capability.Capability) {
+	ctx := context.Background()
+	c.Send(c.Client(agent).RegisterAgent(ctx, agent.DID))
+	c.Send(c.Owner.VerifyAgent(ctx, Address(agent), level, capabilities))
+}
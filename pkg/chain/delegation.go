@@ -0,0 +1,115 @@
+package chain
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ak68a/agentid-core/pkg/capability"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Delegation is the on-chain delegation from one agent to another
+type Delegation struct {
+	Capabilities []capability.ID
+	// Granted holds the capabilities whose IDs are known to the client's registry
+	Granted    []capability.Capability
+	ValidFrom  time.Time
+	ValidUntil time.Time
+	Active     bool
+}
+
+// CreateDelegation delegates capabilities from the transactor to delegate
+// until validUntil. Both agents must be verified in the registry.
+func (c *Client) CreateDelegation(ctx context.Context, delegate common.Address, capabilities []capability.Capability, validUntil time.Time) (*types.Transaction, error) {
+	opts, err := c.transactOpts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ids, err := c.encode(capabilities)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := c.delegation.CreateDelegation(opts, delegate, ids, big.NewInt(validUntil.Unix()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to delegate to %s: %w", delegate.Hex(), err)
+	}
+	return tx, nil
+}
+
+// RevokeDelegation revokes the transactor's delegation to delegate
+func (c *Client) RevokeDelegation(ctx context.Context, delegate common.Address, reason string) (*types.Transaction, error) {
+	opts, err := c.transactOpts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := c.delegation.RevokeDelegation(opts, delegate, reason)
+	if err != nil {
+		return nil, fmt.Errorf("failed to revoke delegation to %s: %w", delegate.Hex(), err)
+	}
+	return tx, nil
+}
+
+// HasDelegatedCapability reports whether delegator's delegation to delegate
+// is active, within its validity period and covers want. A delegated wildcard
+// such as "transfer:*" covers want if it is known to the client's registry,
+// the same as it would in an off-chain claim.
+func (c *Client) HasDelegatedCapability(ctx context.Context, delegator, delegate common.Address, want capability.Capability) (bool, error) {
+	ok, err := c.hasDelegatedID(ctx, delegator, delegate, want.ID())
+	if ok || err != nil {
+		return ok, err
+	}
+
+	details, err := c.GetDelegationDetails(ctx, delegator, delegate)
+	if err != nil || !details.Active {
+		return false, err
+	}
+	for _, granted := range details.Granted {
+		if granted != want && granted.Covers(want) {
+			// The contract still decides whether the delegation is within its validity period
+			return c.hasDelegatedID(ctx, delegator, delegate, granted.ID())
+		}
+	}
+	return false, nil
+}
+
+func (c *Client) hasDelegatedID(ctx context.Context, delegator, delegate common.Address, id capability.ID) (bool, error) {
+	ok, err := c.delegation.HasDelegatedCapability(c.callOpts(ctx), delegator, delegate, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to check delegation from %s to %s: %w", delegator.Hex(), delegate.Hex(), err)
+	}
+	return ok, nil
+}
+
+// GetDelegationDetails returns delegator's delegation to delegate. If there
+// is none, the result is inactive and has no capabilities.
+func (c *Client) GetDelegationDetails(ctx context.Context, delegator, delegate common.Address) (*Delegation, error) {
+	details, err := c.delegation.GetDelegationDetails(c.callOpts(ctx), delegator, delegate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get delegation from %s to %s: %w", delegator.Hex(), delegate.Hex(), err)
+	}
+	delegation := &Delegation{
+		ValidFrom:  time.Unix(details.ValidFrom.Int64(), 0),
+		ValidUntil: time.Unix(details.ValidUntil.Int64(), 0),
+		Active:     details.IsActive,
+	}
+	for _, raw := range details.Capabilities {
+		id := capability.ID(raw)
+		delegation.Capabilities = append(delegation.Capabilities, id)
+		if granted, ok := c.capabilities.Lookup(id); ok {
+			delegation.Granted = append(delegation.Granted, granted)
+		}
+	}
+	return delegation, nil
+}
+
+// GetDelegates returns the agents delegator has active delegations to
+func (c *Client) GetDelegates(ctx context.Context, delegator common.Address) ([]common.Address, error) {
+	delegates, err := c.delegation.GetDelegateList(c.callOpts(ctx), delegator)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list delegates of %s: %w", delegator.Hex(), err)
+	}
+	return delegates, nil
+}
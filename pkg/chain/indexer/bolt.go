@@ -0,0 +1,146 @@
+package indexer
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	eventsBucket = []byte("events") // block || log index -> JSON event
+	metaBucket   = []byte("meta")   // "cursor" -> JSON cursor
+	cursorKey    = []byte("cursor")
+)
+
+// BoltStore is a Store persisted to a bbolt database, so an indexer resumes
+// from its cursor after a restart
+type BoltStore struct {
+	db *bolt.DB
+}
+
+var _ Store = (*BoltStore)(nil)
+
+// NewBoltStore opens the index database at path, creating it if it does not exist
+func NewBoltStore(path string) (*BoltStore, error) {
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open index database: %w", err)
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, name := range [][]byte{eventsBucket, metaBucket} {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to initialize index database: %w", err)
+	}
+	return &BoltStore{db: db}, nil
+}
+
+// Cursor returns the last indexed block
+func (b *BoltStore) Cursor(_ context.Context) (*Cursor, error) {
+	var cursor *Cursor
+	err := b.db.View(func(tx *bolt.Tx) error {
+		value := tx.Bucket(metaBucket).Get(cursorKey)
+		if value == nil {
+			return nil
+		}
+		cursor = new(Cursor)
+		return json.Unmarshal(value, cursor)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read index cursor: %w", err)
+	}
+	return cursor, nil
+}
+
+// Append adds events and moves the cursor in one transaction
+func (b *BoltStore) Append(_ context.Context, events []Event, to Cursor) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(eventsBucket)
+		for _, event := range events {
+			value, err := json.Marshal(event)
+			if err != nil {
+				return fmt.Errorf("failed to encode event: %w", err)
+			}
+			if err := bucket.Put(eventKey(event.Block, event.LogIndex), value); err != nil {
+				return fmt.Errorf("failed to store event: %w", err)
+			}
+		}
+		return putCursor(tx, &to)
+	})
+}
+
+// Rewind drops the events after to and moves the cursor back in one transaction
+func (b *BoltStore) Rewind(_ context.Context, to *Cursor) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(eventsBucket)
+		c := bucket.Cursor()
+		k, _ := c.First()
+		if to != nil {
+			k, _ = c.Seek(eventKey(to.Block+1, 0))
+		}
+		// Deleting while iterating skips keys, so collect them first
+		var dropped [][]byte
+		for ; k != nil; k, _ = c.Next() {
+			dropped = append(dropped, k)
+		}
+		for _, k := range dropped {
+			if err := bucket.Delete(k); err != nil {
+				return fmt.Errorf("failed to drop event: %w", err)
+			}
+		}
+		return putCursor(tx, to)
+	})
+}
+
+// Events returns the stored events in key order, which is chain order
+func (b *BoltStore) Events(_ context.Context) ([]Event, error) {
+	var events []Event
+	err := b.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(eventsBucket).ForEach(func(_, value []byte) error {
+			var event Event
+			if err := json.Unmarshal(value, &event); err != nil {
+				return err
+			}
+			events = append(events, event)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read events: %w", err)
+	}
+	return events, nil
+}
+
+// Close closes the database
+func (b *BoltStore) Close() error {
+	return b.db.Close()
+}
+
+func putCursor(tx *bolt.Tx, cursor *Cursor) error {
+	meta := tx.Bucket(metaBucket)
+	if cursor == nil {
+		return meta.Delete(cursorKey)
+	}
+	value, err := json.Marshal(cursor)
+	if err != nil {
+		return err
+	}
+	return meta.Put(cursorKey, value)
+}
+
+func eventKey(block uint64, logIndex uint) []byte {
+	k := make([]byte, 12)
+	binary.BigEndian.PutUint64(k, block)
+	binary.BigEndian.PutUint32(k[8:], uint32(logIndex))
+	return k
+}
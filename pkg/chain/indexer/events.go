@@ -0,0 +1,160 @@
+package indexer
+
+import (
+	"fmt"
+
+	"github.com/ak68a/agentid-core/pkg/capability"
+	"github.com/ak68a/agentid-core/pkg/chain"
+	"github.com/ak68a/agentid-core/pkg/chain/bindings"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// EventKind is the name of an indexed contract event
+type EventKind string
+
+const (
+	AgentRegistered   EventKind = "AgentRegistered"
+	AgentVerified     EventKind = "AgentVerified"
+	AgentRevoked      EventKind = "AgentRevoked"
+	CapabilityAdded   EventKind = "CapabilityAdded"
+	CapabilityRemoved EventKind = "CapabilityRemoved"
+	DelegationCreated EventKind = "DelegationCreated"
+	DelegationRevoked EventKind = "DelegationRevoked"
+)
+
+// Event is an indexed contract event. Only the fields of its kind are set.
+type Event struct {
+	Kind      EventKind   `json:"kind"`
+	Block     uint64      `json:"block"`
+	BlockHash common.Hash `json:"block_hash"`
+	TxHash    common.Hash `json:"tx_hash"`
+	LogIndex  uint        `json:"log_index"`
+	Time      int64       `json:"time"` // Timestamp of the block
+
+	Agent        common.Address   `json:"agent"` // The agent, or the delegator of delegation events
+	Delegate     common.Address   `json:"delegate,omitempty"`
+	DID          string           `json:"did,omitempty"`
+	TrustLevel   chain.TrustLevel `json:"trust_level,omitempty"`
+	Capabilities []capability.ID  `json:"capabilities,omitempty"`
+	ValidUntil   int64            `json:"valid_until,omitempty"`
+	Reason       string           `json:"reason,omitempty"`
+}
+
+// parser decodes the logs of the registry and delegation contracts
+type parser struct {
+	parsers map[common.Address]map[common.Hash]func(types.Log) (*Event, error)
+}
+
+func newParser(addresses chain.Addresses) (*parser, error) {
+	registryABI, err := bindings.AgentRegistryMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	delegationABI, err := bindings.AgentDelegationMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	registry, err := bindings.NewAgentRegistryFilterer(addresses.Registry, nil)
+	if err != nil {
+		return nil, err
+	}
+	delegation, err := bindings.NewAgentDelegationFilterer(addresses.Delegation, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	registryEvents := map[common.Hash]func(types.Log) (*Event, error){
+		registryABI.Events[string(AgentRegistered)].ID: func(log types.Log) (*Event, error) {
+			e, err := registry.ParseAgentRegistered(log)
+			if err != nil {
+				return nil, err
+			}
+			return &Event{Kind: AgentRegistered, Agent: e.Agent, DID: e.Did, TrustLevel: chain.TrustLevel(e.TrustLevel)}, nil
+		},
+		registryABI.Events[string(AgentVerified)].ID: func(log types.Log) (*Event, error) {
+			e, err := registry.ParseAgentVerified(log)
+			if err != nil {
+				return nil, err
+			}
+			return &Event{Kind: AgentVerified, Agent: e.Agent, TrustLevel: chain.TrustLevel(e.NewTrustLevel)}, nil
+		},
+		registryABI.Events[string(AgentRevoked)].ID: func(log types.Log) (*Event, error) {
+			e, err := registry.ParseAgentRevoked(log)
+			if err != nil {
+				return nil, err
+			}
+			return &Event{Kind: AgentRevoked, Agent: e.Agent, Reason: e.Reason}, nil
+		},
+		registryABI.Events[string(CapabilityAdded)].ID: func(log types.Log) (*Event, error) {
+			e, err := registry.ParseCapabilityAdded(log)
+			if err != nil {
+				return nil, err
+			}
+			return &Event{Kind: CapabilityAdded, Agent: e.Agent, Capabilities: []capability.ID{e.Capability}}, nil
+		},
+		registryABI.Events[string(CapabilityRemoved)].ID: func(log types.Log) (*Event, error) {
+			e, err := registry.ParseCapabilityRemoved(log)
+			if err != nil {
+				return nil, err
+			}
+			return &Event{Kind: CapabilityRemoved, Agent: e.Agent, Capabilities: []capability.ID{e.Capability}}, nil
+		},
+	}
+	delegationEvents := map[common.Hash]func(types.Log) (*Event, error){
+		delegationABI.Events[string(DelegationCreated)].ID: func(log types.Log) (*Event, error) {
+			e, err := delegation.ParseDelegationCreated(log)
+			if err != nil {
+				return nil, err
+			}
+			event := &Event{Kind: DelegationCreated, Agent: e.Delegator, Delegate: e.Delegate, ValidUntil: e.ValidUntil.Int64()}
+			for _, id := range e.Capabilities {
+				event.Capabilities = append(event.Capabilities, id)
+			}
+			return event, nil
+		},
+		delegationABI.Events[string(DelegationRevoked)].ID: func(log types.Log) (*Event, error) {
+			e, err := delegation.ParseDelegationRevoked(log)
+			if err != nil {
+				return nil, err
+			}
+			return &Event{Kind: DelegationRevoked, Agent: e.Delegator, Delegate: e.Delegate, Reason: e.Reason}, nil
+		},
+	}
+
+	return &parser{parsers: map[common.Address]map[common.Hash]func(types.Log) (*Event, error){
+		addresses.Registry:   registryEvents,
+		addresses.Delegation: delegationEvents,
+	}}, nil
+}
+
+// topics returns the event signatures to filter logs by
+func (p *parser) topics() []common.Hash {
+	var topics []common.Hash
+	for _, events := range p.parsers {
+		for topic := range events {
+			topics = append(topics, topic)
+		}
+	}
+	return topics
+}
+
+// parse decodes a log, or returns nil if it is not an indexed event
+func (p *parser) parse(log types.Log) (*Event, error) {
+	if len(log.Topics) == 0 {
+		return nil, nil
+	}
+	parse, ok := p.parsers[log.Address][log.Topics[0]]
+	if !ok {
+		return nil, nil
+	}
+	event, err := parse(log)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode log %d of block %d: %w", log.Index, log.BlockNumber, err)
+	}
+	event.Block = log.BlockNumber
+	event.BlockHash = log.BlockHash
+	event.TxHash = log.TxHash
+	event.LogIndex = log.Index
+	return event, nil
+}
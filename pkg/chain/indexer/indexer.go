@@ -0,0 +1,461 @@
+// Package indexer follows the events of the AgentRegistry and AgentDelegation
+// contracts into a local store. It only indexes blocks with enough
+// confirmations, rewinds when a reorganization reaches past them, resumes from
+// the stored cursor, and can publish on-chain agent revocations to an
+// off-chain revocation list.
+package indexer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ak68a/agentid-core/pkg/capability"
+	"github.com/ak68a/agentid-core/pkg/chain"
+	"github.com/ak68a/agentid-core/pkg/did"
+	"github.com/ak68a/agentid-core/pkg/models"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+const (
+	// DefaultConfirmations is how many blocks must follow a block before it is indexed
+	DefaultConfirmations = 12
+	// DefaultBatchSize is how many blocks are fetched per log query
+	DefaultBatchSize = 1000
+)
+
+// ErrReorg is returned when the chain reorganized while a batch was indexed.
+// Nothing from the batch is stored, and the next Sync starts over from the cursor.
+var ErrReorg = errors.New("chain reorganized while indexing")
+
+// Backend is what an Indexer needs from an Ethereum node; *ethclient.Client
+// and the simulated backend's client both satisfy it
+type Backend interface {
+	ethereum.LogFilterer
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+	ChainID(ctx context.Context) (*big.Int, error)
+}
+
+// Publisher publishes revocations to an off-chain revocation list, such as a *revocation.Publisher
+type Publisher interface {
+	Publish(ctx context.Context, revocations []*models.RevocationClaim, at time.Time) (*models.RevocationDelta, error)
+}
+
+// Indexer follows the registry and delegation contracts
+type Indexer struct {
+	backend       Backend
+	addresses     chain.Addresses
+	store         Store
+	parser        *parser
+	confirmations uint64
+	startBlock    uint64
+	batchSize     uint64
+	publisher     Publisher
+	capabilities  *capability.Registry
+	now           func() time.Time
+
+	syncMu  sync.Mutex // serializes Sync
+	chainID *big.Int
+
+	mu    sync.RWMutex
+	state *state
+}
+
+// Option configures an Indexer
+type Option func(*Indexer)
+
+// WithConfirmations sets how many blocks must follow a block before it is indexed
+func WithConfirmations(confirmations uint64) Option {
+	return func(ix *Indexer) {
+		ix.confirmations = confirmations
+	}
+}
+
+// WithStartBlock sets the block indexing starts from when the store is empty,
+// typically the block the contracts were deployed in
+func WithStartBlock(block uint64) Option {
+	return func(ix *Indexer) {
+		ix.startBlock = block
+	}
+}
+
+// WithBatchSize sets how many blocks are fetched per log query
+func WithBatchSize(blocks uint64) Option {
+	return func(ix *Indexer) {
+		if blocks > 0 {
+			ix.batchSize = blocks
+		}
+	}
+}
+
+// WithRevocationPublisher publishes every indexed AgentRevoked event as a
+// revocation of all of the agent's credentials, so verifiers syncing the
+// publisher's list also reject agents revoked on-chain. Published revocations
+// cannot be withdrawn, so a reorganization deeper than the confirmation depth
+// that drops an AgentRevoked still leaves the agent revoked off-chain.
+func WithRevocationPublisher(publisher Publisher) Option {
+	return func(ix *Indexer) {
+		ix.publisher = publisher
+	}
+}
+
+// WithCapabilityRegistry sets the registry used to decode delegated
+// capabilities. It defaults to capability.Default.
+func WithCapabilityRegistry(registry *capability.Registry) Option {
+	return func(ix *Indexer) {
+		ix.capabilities = registry
+	}
+}
+
+// WithClock sets the time source used to date published revocations
+func WithClock(now func() time.Time) Option {
+	return func(ix *Indexer) {
+		ix.now = now
+	}
+}
+
+// New creates an Indexer for the contracts at addresses that stores events in store
+func New(backend Backend, addresses chain.Addresses, store Store, opts ...Option) (*Indexer, error) {
+	ix := &Indexer{
+		backend:       backend,
+		addresses:     addresses,
+		store:         store,
+		confirmations: DefaultConfirmations,
+		batchSize:     DefaultBatchSize,
+		capabilities:  capability.Default,
+		now:           time.Now,
+	}
+	for _, opt := range opts {
+		opt(ix)
+	}
+	parser, err := newParser(addresses)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load contract events: %w", err)
+	}
+	ix.parser = parser
+	return ix, nil
+}
+
+// Sync indexes every confirmed block after the cursor. The first call loads
+// the events already in the store; queries answer from what has been loaded.
+func (ix *Indexer) Sync(ctx context.Context) error {
+	ix.syncMu.Lock()
+	defer ix.syncMu.Unlock()
+
+	if ix.loadedState() == nil {
+		if err := ix.load(ctx); err != nil {
+			return err
+		}
+	}
+
+	head, err := ix.backend.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to get the chain head: %w", err)
+	}
+	if head.Number.Uint64() < ix.confirmations {
+		return nil
+	}
+	target := head.Number.Uint64() - ix.confirmations
+
+	cursor, err := ix.store.Cursor(ctx)
+	if err != nil {
+		return err
+	}
+	if cursor != nil {
+		hash, err := ix.canonicalHash(ctx, cursor.Block)
+		if err != nil {
+			return err
+		}
+		if hash != cursor.Hash {
+			if cursor, err = ix.rewind(ctx, cursor); err != nil {
+				return err
+			}
+		}
+	}
+
+	from := ix.startBlock
+	if cursor != nil {
+		from = cursor.Block + 1
+	}
+	for from <= target {
+		to := min(from+ix.batchSize-1, target)
+		if err := ix.index(ctx, from, to); err != nil {
+			return err
+		}
+		from = to + 1
+	}
+	return nil
+}
+
+// Run syncs every interval until ctx is done. Failures are retried on the
+// next tick; call Sync directly to observe them.
+func (ix *Indexer) Run(ctx context.Context, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		ix.Sync(ctx)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// index stores the events of blocks from to to and advances the cursor to to
+func (ix *Indexer) index(ctx context.Context, from, to uint64) error {
+	logs, err := ix.backend.FilterLogs(ctx, ethereum.FilterQuery{
+		FromBlock: new(big.Int).SetUint64(from),
+		ToBlock:   new(big.Int).SetUint64(to),
+		Addresses: []common.Address{ix.addresses.Registry, ix.addresses.Delegation},
+		Topics:    [][]common.Hash{ix.parser.topics()},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get logs of blocks %d to %d: %w", from, to, err)
+	}
+
+	headers := make(map[uint64]*types.Header)
+	var events []Event
+	for _, log := range logs {
+		if log.Removed {
+			continue
+		}
+		event, err := ix.parser.parse(log)
+		if err != nil {
+			return err
+		}
+		if event == nil {
+			continue
+		}
+		header, ok := headers[log.BlockNumber]
+		if !ok {
+			if header, err = ix.header(ctx, log.BlockNumber); err != nil {
+				return err
+			}
+			headers[log.BlockNumber] = header
+		}
+		if header.Hash() != log.BlockHash {
+			return fmt.Errorf("%w: block %d changed", ErrReorg, log.BlockNumber)
+		}
+		event.Time = int64(header.Time)
+		events = append(events, *event)
+	}
+
+	last, err := ix.header(ctx, to)
+	if err != nil {
+		return err
+	}
+	// Publish before storing: if storing fails the batch is indexed again and
+	// republished, which is harmless, while the reverse could lose a revocation
+	if err := ix.publish(ctx, events); err != nil {
+		return err
+	}
+	if err := ix.store.Append(ctx, events, Cursor{Block: to, Hash: last.Hash()}); err != nil {
+		return fmt.Errorf("failed to store events: %w", err)
+	}
+
+	ix.mu.Lock()
+	ix.state.apply(events)
+	ix.mu.Unlock()
+	return nil
+}
+
+// rewind drops the events of blocks that are no longer canonical. Blocks are
+// chained by hash, so the newest stored block that is still canonical proves
+// every event before it is too.
+func (ix *Indexer) rewind(ctx context.Context, cursor *Cursor) (*Cursor, error) {
+	events, err := ix.store.Events(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var to *Cursor
+	for i := len(events) - 1; i >= 0 && to == nil; i-- {
+		event := events[i]
+		if event.Block > cursor.Block || (i+1 < len(events) && events[i+1].Block == event.Block) {
+			continue
+		}
+		hash, err := ix.canonicalHash(ctx, event.Block)
+		if err != nil {
+			return nil, err
+		}
+		if hash == event.BlockHash {
+			to = &Cursor{Block: event.Block, Hash: hash}
+		}
+	}
+
+	if err := ix.store.Rewind(ctx, to); err != nil {
+		return nil, fmt.Errorf("failed to rewind the index: %w", err)
+	}
+	return to, ix.load(ctx)
+}
+
+// load rebuilds the state from the stored events
+func (ix *Indexer) load(ctx context.Context) error {
+	events, err := ix.store.Events(ctx)
+	if err != nil {
+		return err
+	}
+	state := newState(ix.capabilities, events)
+	ix.mu.Lock()
+	ix.state = state
+	ix.mu.Unlock()
+	return nil
+}
+
+func (ix *Indexer) loadedState() *state {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+	return ix.state
+}
+
+// publish sends the agent revocations among events to the publisher
+func (ix *Indexer) publish(ctx context.Context, events []Event) error {
+	if ix.publisher == nil {
+		return nil
+	}
+
+	// Agents registered earlier in the same batch are not in the state yet
+	dids := make(map[common.Address]string)
+	var revoked []Event
+	for _, event := range events {
+		switch event.Kind {
+		case AgentRegistered:
+			dids[event.Agent] = event.DID
+		case AgentRevoked:
+			revoked = append(revoked, event)
+		}
+	}
+	if len(revoked) == 0 {
+		return nil
+	}
+
+	if ix.chainID == nil {
+		chainID, err := ix.backend.ChainID(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get the chain ID: %w", err)
+		}
+		ix.chainID = chainID
+	}
+	revoker := did.PkhDID(ix.chainID.Int64(), ix.addresses.Registry)
+	at := ix.now()
+
+	var revocations []*models.RevocationClaim
+	for _, event := range revoked {
+		agentDID, ok := dids[event.Agent]
+		if !ok {
+			if agent, found := ix.Agent(event.Agent); found {
+				agentDID, ok = agent.DID, true
+			}
+		}
+		if !ok || agentDID == "" {
+			// Registered before the start block; there is no DID to revoke
+			continue
+		}
+		// Block timestamps can run ahead of the local clock, but the revocation
+		// is already final on-chain
+		revokedAt := min(event.Time, at.Unix())
+		revocations = append(revocations, &models.RevocationClaim{
+			RevokedAgentDID: agentDID,
+			RevokerDID:      revoker,
+			Reason:          event.Reason,
+			RevokedAt:       revokedAt,
+			Nonce:           fmt.Sprintf("%s:%d", event.TxHash.Hex(), event.LogIndex),
+			Metadata: map[string]interface{}{
+				"chain_id":      ix.chainID.String(),
+				"block":         event.Block,
+				"block_time":    event.Time,
+				"tx_hash":       event.TxHash.Hex(),
+				"agent_address": event.Agent.Hex(),
+			},
+			Type:    models.RevocationCredentialType,
+			Issuer:  revoker,
+			Subject: agentDID,
+		})
+	}
+	if len(revocations) == 0 {
+		return nil
+	}
+	if _, err := ix.publisher.Publish(ctx, revocations, at); err != nil {
+		return fmt.Errorf("failed to publish on-chain revocations: %w", err)
+	}
+	return nil
+}
+
+func (ix *Indexer) header(ctx context.Context, number uint64) (*types.Header, error) {
+	header, err := ix.backend.HeaderByNumber(ctx, new(big.Int).SetUint64(number))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get block %d: %w", number, err)
+	}
+	return header, nil
+}
+
+// canonicalHash returns the hash of the canonical block with the given
+// number, or the zero hash if the chain is no longer that long
+func (ix *Indexer) canonicalHash(ctx context.Context, number uint64) (common.Hash, error) {
+	header, err := ix.backend.HeaderByNumber(ctx, new(big.Int).SetUint64(number))
+	if errors.Is(err, ethereum.NotFound) {
+		return common.Hash{}, nil
+	}
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("failed to get block %d: %w", number, err)
+	}
+	return header.Hash(), nil
+}
+
+// Agent returns the indexed registry entry of an agent
+func (ix *Indexer) Agent(address common.Address) (*Agent, bool) {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+	if ix.state == nil {
+		return nil, false
+	}
+	agent, ok := ix.state.agents[address]
+	if !ok {
+		return nil, false
+	}
+	return copyAgent(agent), true
+}
+
+// AgentByDID returns the indexed registry entry of the agent that last registered a DID
+func (ix *Indexer) AgentByDID(agentDID string) (*Agent, bool) {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+	if ix.state == nil {
+		return nil, false
+	}
+	address, ok := ix.state.dids[agentDID]
+	if !ok {
+		return nil, false
+	}
+	return copyAgent(ix.state.agents[address]), true
+}
+
+// Delegation returns the indexed delegation from delegator to delegate
+func (ix *Indexer) Delegation(delegator, delegate common.Address) (*Delegation, bool) {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+	if ix.state == nil {
+		return nil, false
+	}
+	delegation, ok := ix.state.delegations[delegationKey{delegator, delegate}]
+	if !ok {
+		return nil, false
+	}
+	return copyDelegation(delegation), true
+}
+
+// Delegations returns every indexed delegation of delegator, including
+// revoked ones, ordered by delegate
+func (ix *Indexer) Delegations(delegator common.Address) []*Delegation {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+	if ix.state == nil {
+		return nil
+	}
+	return ix.state.delegationsFrom(delegator)
+}
@@ -0,0 +1,230 @@
+package indexer
+
+import (
+	"context"
+	"math/big"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ak68a/agentid-core/pkg/capability"
+	"github.com/ak68a/agentid-core/pkg/chain"
+	"github.com/ak68a/agentid-core/pkg/chain/chaintest"
+	"github.com/ak68a/agentid-core/pkg/key"
+	"github.com/ak68a/agentid-core/pkg/models"
+	"github.com/ak68a/agentid-core/pkg/revocation"
+	"github.com/ak68a/agentid-core/pkg/signer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var transferETH = capability.Capability{Action: "transfer", Scope: "ETH"}
+
+// newIndexer returns an indexer of the chain
+func newIndexer(t *testing.T, tc *chaintest.Chain, store Store, opts ...Option) *Indexer {
+	ix, err := New(tc.Backend.Client(), tc.Addresses, store, opts...)
+	require.NoError(t, err)
+	return ix
+}
+
+func TestIndexer(t *testing.T) {
+	ctx := context.Background()
+	alice, bob := chaintest.NewKey(t), chaintest.NewKey(t)
+	tc := chaintest.New(t, alice, bob)
+	tc.Register(alice, chain.Certified, transferETH)
+	tc.Register(bob, chain.Certified, transferETH)
+
+	publisher := revocation.NewPublisher(chaintest.NewKey(t), "onchain")
+	ix := newIndexer(t, tc, NewMemoryStore(), WithConfirmations(2), WithBatchSize(3), WithRevocationPublisher(publisher))
+	require.NoError(t, ix.Sync(ctx))
+
+	indexed, ok := ix.AgentByDID(alice.DID)
+	require.True(t, ok)
+	assert.Equal(t, chaintest.Address(alice), indexed.Address)
+	assert.Equal(t, chain.Certified, indexed.TrustLevel)
+	assert.True(t, indexed.Active)
+	assert.Equal(t, []capability.ID{transferETH.ID()}, indexed.Capabilities)
+
+	hotels := capability.Capability{Action: "booking", Scope: "hotels"}
+	validUntil := time.Now().Add(time.Hour)
+	tc.Send(tc.Client(alice).CreateDelegation(ctx, chaintest.Address(bob), []capability.Capability{hotels}, validUntil))
+
+	// The delegation is only indexed once it is confirmed
+	require.NoError(t, ix.Sync(ctx))
+	_, ok = ix.Delegation(chaintest.Address(alice), chaintest.Address(bob))
+	assert.False(t, ok)
+	tc.Backend.Commit()
+	tc.Backend.Commit()
+	require.NoError(t, ix.Sync(ctx))
+	delegation, ok := ix.Delegation(chaintest.Address(alice), chaintest.Address(bob))
+	require.True(t, ok)
+	assert.True(t, delegation.Active)
+	assert.Equal(t, []capability.Capability{hotels}, delegation.Granted)
+	assert.Equal(t, validUntil.Unix(), delegation.ValidUntil.Unix())
+
+	tc.Send(tc.Client(alice).RevokeDelegation(ctx, chaintest.Address(bob), "trip cancelled"))
+	tc.Send(tc.Owner.RevokeAgent(ctx, chaintest.Address(alice), "compromised"))
+	tc.Backend.Commit()
+	tc.Backend.Commit()
+	require.NoError(t, ix.Sync(ctx))
+
+	delegations := ix.Delegations(chaintest.Address(alice))
+	require.Len(t, delegations, 1)
+	assert.False(t, delegations[0].Active)
+	assert.Equal(t, "trip cancelled", delegations[0].RevocationReason)
+	indexed, ok = ix.Agent(chaintest.Address(alice))
+	require.True(t, ok)
+	assert.False(t, indexed.Active)
+	assert.Equal(t, "compromised", indexed.RevocationReason)
+
+	// The on-chain revocation reaches verifiers that sync the off-chain list
+	syncer := revocation.NewSyncer(publisher, publisher.Feed().Snapshot.IssuerDID, "onchain")
+	require.NoError(t, syncer.Sync(ctx))
+	revoked, err := syncer.CheckRevocation(ctx, "any-credential", alice.DID)
+	require.NoError(t, err)
+	require.NotNil(t, revoked)
+	assert.Equal(t, "compromised", revoked.Reason)
+	assert.Contains(t, revoked.RevokerDID, tc.Addresses.Registry.Hex())
+	revoked, err = syncer.CheckRevocation(ctx, "any-credential", bob.DID)
+	require.NoError(t, err)
+	assert.Nil(t, revoked)
+
+	// Delegations from the revoked agent no longer verify
+	now := time.Now().Unix()
+	claim := &models.DelegationClaim{
+		DelegatorDID: alice.DID,
+		DelegateDID:  bob.DID,
+		Action:       "booking",
+		Scope:        "hotels",
+		IssuedAt:     now,
+		ExpiresAt:    now + 3600,
+		Nonce:        "delegation-1",
+		MaxDepth:     1,
+	}
+	require.NoError(t, signer.NewClaimSigner(alice).SignDelegationClaim(claim))
+	claims := &models.DelegationChain{Delegations: []*models.DelegationClaim{claim}}
+	_, err = signer.NewClaimSigner(nil, signer.WithRevocationChecker(syncer)).VerifyDelegationChain(claims)
+	assert.ErrorContains(t, err, "delegator "+alice.DID+" was revoked")
+	valid, err := signer.NewClaimSigner(nil).VerifyDelegationChain(claims)
+	require.NoError(t, err)
+	assert.True(t, valid)
+
+	// Syncing again publishes nothing new
+	sequence := publisher.Feed().Snapshot.Sequence
+	tc.Backend.Commit()
+	require.NoError(t, ix.Sync(ctx))
+	assert.Equal(t, sequence, publisher.Feed().Snapshot.Sequence)
+}
+
+func TestIndexerResume(t *testing.T) {
+	ctx := context.Background()
+	alice, bob := chaintest.NewKey(t), chaintest.NewKey(t)
+	tc := chaintest.New(t, alice, bob)
+	tc.Register(alice, chain.Verified, transferETH)
+
+	path := filepath.Join(t.TempDir(), "index.db")
+	store, err := NewBoltStore(path)
+	require.NoError(t, err)
+	require.NoError(t, newIndexer(t, tc, store, WithConfirmations(0)).Sync(ctx))
+	events, err := store.Events(ctx)
+	require.NoError(t, err)
+	require.NoError(t, store.Close())
+
+	tc.Register(bob, chain.Verified, transferETH)
+
+	store, err = NewBoltStore(path)
+	require.NoError(t, err)
+	defer store.Close()
+	ix := newIndexer(t, tc, store, WithConfirmations(0))
+	require.NoError(t, ix.Sync(ctx))
+
+	// Events before the cursor are loaded, not indexed again
+	resumed, err := store.Events(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, events, resumed[:len(events)])
+	assert.Len(t, resumed, 2*len(events))
+	for _, agent := range []*key.AgentKey{alice, bob} {
+		indexed, ok := ix.AgentByDID(agent.DID)
+		require.True(t, ok)
+		assert.Equal(t, chain.Verified, indexed.TrustLevel)
+	}
+
+	head, err := tc.Backend.Client().HeaderByNumber(ctx, nil)
+	require.NoError(t, err)
+	cursor, err := store.Cursor(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, &Cursor{Block: head.Number.Uint64(), Hash: head.Hash()}, cursor)
+}
+
+func TestIndexerReorg(t *testing.T) {
+	ctx := context.Background()
+	alice := chaintest.NewKey(t)
+	tc := chaintest.New(t, alice)
+	ix := newIndexer(t, tc, NewMemoryStore(), WithConfirmations(0))
+
+	fork, err := tc.Backend.Client().HeaderByNumber(ctx, nil)
+	require.NoError(t, err)
+	tc.Send(tc.Client(alice).RegisterAgent(ctx, alice.DID))
+	require.NoError(t, ix.Sync(ctx))
+	orphaned, ok := ix.AgentByDID(alice.DID)
+	require.True(t, ok)
+	assert.True(t, orphaned.Active)
+
+	// Replace the block with the registration by a longer chain
+	require.NoError(t, tc.Backend.Fork(fork.Hash()))
+	tc.Backend.Rollback()
+	for i := 0; i < 3; i++ {
+		tc.Backend.Commit()
+	}
+	require.NoError(t, ix.Sync(ctx))
+
+	// Whether or not the registration was mined again, the index agrees with the new chain
+	onChain, err := tc.Owner.GetAgent(ctx, chaintest.Address(alice))
+	require.NoError(t, err)
+	indexed, ok := ix.Agent(chaintest.Address(alice))
+	assert.Equal(t, onChain.Active, ok && indexed.Active)
+	events, err := ix.store.Events(ctx)
+	require.NoError(t, err)
+	for _, event := range events {
+		header, err := tc.Backend.Client().HeaderByNumber(ctx, new(big.Int).SetUint64(event.Block))
+		require.NoError(t, err)
+		assert.Equal(t, header.Hash(), event.BlockHash, "event %s of block %d", event.Kind, event.Block)
+	}
+}
+
+func TestStoreRewind(t *testing.T) {
+	ctx := context.Background()
+	for name, store := range map[string]Store{"memory": NewMemoryStore(), "bolt": newBoltStore(t)} {
+		t.Run(name, func(t *testing.T) {
+			events := []Event{
+				{Kind: AgentRegistered, Block: 1, DID: "a"},
+				{Kind: AgentVerified, Block: 1, LogIndex: 1},
+				{Kind: AgentRegistered, Block: 3, DID: "b"},
+				{Kind: AgentRegistered, Block: 4, DID: "c"},
+			}
+			require.NoError(t, store.Append(ctx, events, Cursor{Block: 5}))
+			require.NoError(t, store.Rewind(ctx, &Cursor{Block: 3}))
+			stored, err := store.Events(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, events[:3], stored)
+			cursor, err := store.Cursor(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, &Cursor{Block: 3}, cursor)
+
+			require.NoError(t, store.Rewind(ctx, nil))
+			stored, err = store.Events(ctx)
+			require.NoError(t, err)
+			assert.Empty(t, stored)
+			cursor, err = store.Cursor(ctx)
+			require.NoError(t, err)
+			assert.Nil(t, cursor)
+		})
+	}
+}
+
+func newBoltStore(t *testing.T) *BoltStore {
+	store, err := NewBoltStore(filepath.Join(t.TempDir(), "index.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { store.Close() })
+	return store
+}
@@ -0,0 +1,162 @@
+package indexer
+
+import (
+	"bytes"
+	"sort"
+	"time"
+
+	"github.com/ak68a/agentid-core/pkg/capability"
+	"github.com/ak68a/agentid-core/pkg/chain"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Agent is an agent's registry entry as rebuilt from events
+type Agent struct {
+	chain.Agent
+	// Capabilities are the capabilities granted by verifiers, in the order they were added
+	Capabilities     []capability.ID
+	RevokedAt        time.Time // Zero unless the agent was revoked
+	RevocationReason string
+}
+
+// Delegation is a delegation as rebuilt from events
+type Delegation struct {
+	Delegator common.Address
+	Delegate  common.Address
+	chain.Delegation
+	RevokedAt        time.Time // Zero unless the delegation was revoked
+	RevocationReason string
+}
+
+type delegationKey struct {
+	delegator, delegate common.Address
+}
+
+// state is the registry and delegation state built by applying events in chain order
+type state struct {
+	capabilities *capability.Registry
+	agents       map[common.Address]*Agent
+	dids         map[string]common.Address
+	delegations  map[delegationKey]*Delegation
+}
+
+func newState(capabilities *capability.Registry, events []Event) *state {
+	s := &state{
+		capabilities: capabilities,
+		agents:       make(map[common.Address]*Agent),
+		dids:         make(map[string]common.Address),
+		delegations:  make(map[delegationKey]*Delegation),
+	}
+	s.apply(events)
+	return s
+}
+
+// apply updates the state the way the contracts did when they emitted events
+func (s *state) apply(events []Event) {
+	for _, e := range events {
+		at := time.Unix(e.Time, 0)
+		switch e.Kind {
+		case AgentRegistered:
+			agent := s.agent(e.Agent)
+			// Re-registering a revoked agent keeps the capabilities it was granted
+			agent.DID = e.DID
+			agent.TrustLevel = e.TrustLevel
+			agent.RegisteredAt = at
+			agent.LastVerifiedAt = at
+			agent.Active = true
+			agent.RevokedAt = time.Time{}
+			agent.RevocationReason = ""
+			s.dids[e.DID] = e.Agent
+		case AgentVerified:
+			agent := s.agent(e.Agent)
+			agent.TrustLevel = e.TrustLevel
+			agent.LastVerifiedAt = at
+		case AgentRevoked:
+			agent := s.agent(e.Agent)
+			agent.Active = false
+			agent.RevokedAt = at
+			agent.RevocationReason = e.Reason
+		case CapabilityAdded:
+			agent := s.agent(e.Agent)
+			for _, id := range e.Capabilities {
+				if !containsID(agent.Capabilities, id) {
+					agent.Capabilities = append(agent.Capabilities, id)
+				}
+			}
+		case CapabilityRemoved:
+			agent := s.agent(e.Agent)
+			kept := agent.Capabilities[:0]
+			for _, id := range agent.Capabilities {
+				if !containsID(e.Capabilities, id) {
+					kept = append(kept, id)
+				}
+			}
+			agent.Capabilities = kept
+		case DelegationCreated:
+			delegation := &Delegation{Delegator: e.Agent, Delegate: e.Delegate}
+			delegation.Capabilities = append([]capability.ID(nil), e.Capabilities...)
+			for _, id := range e.Capabilities {
+				if granted, ok := s.capabilities.Lookup(id); ok {
+					delegation.Granted = append(delegation.Granted, granted)
+				}
+			}
+			delegation.ValidFrom = at
+			delegation.ValidUntil = time.Unix(e.ValidUntil, 0)
+			delegation.Active = true
+			s.delegations[delegationKey{e.Agent, e.Delegate}] = delegation
+		case DelegationRevoked:
+			if delegation, ok := s.delegations[delegationKey{e.Agent, e.Delegate}]; ok {
+				delegation.Active = false
+				delegation.RevokedAt = at
+				delegation.RevocationReason = e.Reason
+			}
+		}
+	}
+}
+
+func (s *state) agent(address common.Address) *Agent {
+	agent, ok := s.agents[address]
+	if !ok {
+		agent = &Agent{}
+		agent.Address = address
+		s.agents[address] = agent
+	}
+	return agent
+}
+
+// copyAgent returns a copy that does not share slices with the state
+func copyAgent(agent *Agent) *Agent {
+	copied := *agent
+	copied.Capabilities = append([]capability.ID(nil), agent.Capabilities...)
+	return &copied
+}
+
+func copyDelegation(delegation *Delegation) *Delegation {
+	copied := *delegation
+	copied.Capabilities = append([]capability.ID(nil), delegation.Capabilities...)
+	copied.Granted = append([]capability.Capability(nil), delegation.Granted...)
+	return &copied
+}
+
+// delegationsFrom returns the delegations of delegator, ordered by delegate
+func (s *state) delegationsFrom(delegator common.Address) []*Delegation {
+	var delegations []*Delegation
+	for key, delegation := range s.delegations {
+		if key.delegator == delegator {
+			delegations = append(delegations, copyDelegation(delegation))
+		}
+	}
+	sort.Slice(delegations, func(i, j int) bool {
+		return bytes.Compare(delegations[i].Delegate[:], delegations[j].Delegate[:]) < 0
+	})
+	return delegations
+}
+
+func containsID(ids []capability.ID, id capability.ID) bool {
+	for _, candidate := range ids {
+		if candidate == id {
+			return true
+		}
+	}
+	return false
+}
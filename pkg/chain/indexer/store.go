@@ -0,0 +1,84 @@
+package indexer
+
+import (
+	"context"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Cursor is the last block an indexer has processed
+type Cursor struct {
+	Block uint64      `json:"block"`
+	Hash  common.Hash `json:"hash"`
+}
+
+// Store persists indexed events and the cursor they were indexed up to
+type Store interface {
+	// Cursor returns the last indexed block, or nil before the first
+	Cursor(ctx context.Context) (*Cursor, error)
+	// Append adds the events of the blocks after the cursor up to to, and
+	// moves the cursor to to, atomically
+	Append(ctx context.Context, events []Event, to Cursor) error
+	// Rewind drops the events after to.Block and moves the cursor back to it.
+	// A nil cursor drops everything.
+	Rewind(ctx context.Context, to *Cursor) error
+	// Events returns every stored event in chain order
+	Events(ctx context.Context) ([]Event, error)
+}
+
+// MemoryStore is a Store that keeps everything in memory
+type MemoryStore struct {
+	mu     sync.RWMutex
+	cursor *Cursor
+	events []Event
+}
+
+var _ Store = (*MemoryStore)(nil)
+
+// NewMemoryStore creates an empty MemoryStore
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{}
+}
+
+// Cursor returns the last indexed block
+func (m *MemoryStore) Cursor(_ context.Context) (*Cursor, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.cursor == nil {
+		return nil, nil
+	}
+	cursor := *m.cursor
+	return &cursor, nil
+}
+
+// Append adds events and moves the cursor
+func (m *MemoryStore) Append(_ context.Context, events []Event, to Cursor) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.events = append(m.events, events...)
+	m.cursor = &to
+	return nil
+}
+
+// Rewind drops the events after to and moves the cursor back
+func (m *MemoryStore) Rewind(_ context.Context, to *Cursor) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	kept := m.events[:0]
+	for _, event := range m.events {
+		if to != nil && event.Block <= to.Block {
+			kept = append(kept, event)
+		}
+	}
+	m.events = kept
+	m.cursor = to
+	return nil
+}
+
+// Events returns the stored events
+func (m *MemoryStore) Events(_ context.Context) ([]Event, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return append([]Event(nil), m.events...), nil
+}
@@ -0,0 +1,144 @@
+package chain
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ak68a/agentid-core/pkg/capability"
+	"github.com/ak68a/agentid-core/pkg/key"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Agent is an agent's entry in the AgentRegistry
+type Agent struct {
+	Address        common.Address
+	DID            string
+	TrustLevel     TrustLevel
+	RegisteredAt   time.Time
+	LastVerifiedAt time.Time
+	Active         bool
+}
+
+// RegisterAgent registers the transactor's address as the agent with the given DID
+func (c *Client) RegisterAgent(ctx context.Context, did string) (*types.Transaction, error) {
+	opts, err := c.transactOpts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := c.registry.RegisterAgent(opts, did)
+	if err != nil {
+		return nil, fmt.Errorf("failed to register agent %s: %w", did, err)
+	}
+	return tx, nil
+}
+
+// VerifyAgent raises an agent's trust level and grants it capabilities. The
+// transactor must be a verifier of the registry and the level must be higher
+// than the agent's current one.
+func (c *Client) VerifyAgent(ctx context.Context, agent common.Address, level TrustLevel, capabilities []capability.Capability) (*types.Transaction, error) {
+	opts, err := c.transactOpts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ids, err := c.encode(capabilities)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := c.registry.VerifyAgent(opts, agent, uint8(level), ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify agent %s: %w", agent.Hex(), err)
+	}
+	return tx, nil
+}
+
+// RevokeAgent deactivates an agent. The transactor must be a verifier of the registry.
+func (c *Client) RevokeAgent(ctx context.Context, agent common.Address, reason string) (*types.Transaction, error) {
+	opts, err := c.transactOpts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := c.registry.RevokeAgent(opts, agent, reason)
+	if err != nil {
+		return nil, fmt.Errorf("failed to revoke agent %s: %w", agent.Hex(), err)
+	}
+	return tx, nil
+}
+
+// AddVerifier allows verifier to verify and revoke agents. The transactor must own the registry.
+func (c *Client) AddVerifier(ctx context.Context, verifier common.Address) (*types.Transaction, error) {
+	opts, err := c.transactOpts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := c.registry.AddVerifier(opts, verifier)
+	if err != nil {
+		return nil, fmt.Errorf("failed to add verifier %s: %w", verifier.Hex(), err)
+	}
+	return tx, nil
+}
+
+// GetAgent returns an agent's registry entry. Unknown addresses return an
+// inactive entry with an empty DID.
+func (c *Client) GetAgent(ctx context.Context, agent common.Address) (*Agent, error) {
+	entry, err := c.registry.Agents(c.callOpts(ctx), agent)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get agent %s: %w", agent.Hex(), err)
+	}
+	return &Agent{
+		Address:        agent,
+		DID:            entry.Did,
+		TrustLevel:     TrustLevel(entry.TrustLevel),
+		RegisteredAt:   time.Unix(entry.RegisteredAt.Int64(), 0),
+		LastVerifiedAt: time.Unix(entry.LastVerifiedAt.Int64(), 0),
+		Active:         entry.IsActive,
+	}, nil
+}
+
+// GetAgentAddress returns the address registered for a DID, or the zero address if there is none
+func (c *Client) GetAgentAddress(ctx context.Context, did string) (common.Address, error) {
+	address, err := c.registry.DidToAddress(c.callOpts(ctx), did)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("failed to look up agent %s: %w", did, err)
+	}
+	return address, nil
+}
+
+// FindAgent returns the registry entry of the agent with a DID. did:ackid
+// agents are looked up by the address in their DID, so they are still found
+// once revoked; others are found through the registry's index of DIDs, which
+// forgets revoked agents. Agents that are not found return an inactive entry
+// with an empty DID.
+func (c *Client) FindAgent(ctx context.Context, did string) (*Agent, error) {
+	address, err := key.ExtractAddressFromDID(did)
+	if err != nil {
+		if address, err = c.GetAgentAddress(ctx, did); err != nil {
+			return nil, err
+		}
+		if address == (common.Address{}) {
+			return &Agent{}, nil
+		}
+	}
+	return c.GetAgent(ctx, address)
+}
+
+// GetAgentTrustLevel returns an agent's trust level
+func (c *Client) GetAgentTrustLevel(ctx context.Context, agent common.Address) (TrustLevel, error) {
+	level, err := c.registry.GetAgentTrustLevel(c.callOpts(ctx), agent)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get trust level of %s: %w", agent.Hex(), err)
+	}
+	return TrustLevel(level), nil
+}
+
+// HasCapability reports whether an active agent was granted exactly this
+// capability by a verifier. The registry cannot list an agent's capabilities,
+// so wildcard grants are not expanded.
+func (c *Client) HasCapability(ctx context.Context, agent common.Address, want capability.Capability) (bool, error) {
+	ok, err := c.registry.HasCapability(c.callOpts(ctx), agent, want.ID())
+	if err != nil {
+		return false, fmt.Errorf("failed to check capability of %s: %w", agent.Hex(), err)
+	}
+	return ok, nil
+}
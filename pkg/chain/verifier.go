@@ -0,0 +1,219 @@
+package chain
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ak68a/agentid-core/pkg/capability"
+	"github.com/ak68a/agentid-core/pkg/models"
+	"github.com/ak68a/agentid-core/pkg/signer"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Layer is one of the checks a Verifier makes
+type Layer string
+
+const (
+	// LayerOffChain checks the claim's signature, expiry and off-chain revocations
+	LayerOffChain Layer = "off-chain"
+	// LayerRegistry checks that both agents are active in AgentRegistry with enough trust
+	LayerRegistry Layer = "registry"
+	// LayerDelegation checks the AgentDelegation grant of a capability mirrored on-chain
+	LayerDelegation Layer = "delegation"
+)
+
+// LayerResult is the outcome of one layer
+type LayerResult struct {
+	Layer    Layer  `json:"layer"`
+	Accepted bool   `json:"accepted"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+// VerificationResult is the outcome of verifying a claim against both worlds.
+// Layers lists the layers that ran, in order; the last one rejected the claim
+// if it is not valid.
+type VerificationResult struct {
+	Valid      bool          `json:"valid"`
+	RejectedBy Layer         `json:"rejected_by,omitempty"`
+	Reason     string        `json:"reason,omitempty"`
+	Block      uint64        `json:"block"`
+	Layers     []LayerResult `json:"layers"`
+}
+
+func (r *VerificationResult) accept(layer Layer, reason string) {
+	r.Layers = append(r.Layers, LayerResult{Layer: layer, Accepted: true, Reason: reason})
+}
+
+func (r *VerificationResult) reject(layer Layer, reason string) *VerificationResult {
+	r.Layers = append(r.Layers, LayerResult{Layer: layer, Reason: reason})
+	r.RejectedBy = layer
+	r.Reason = reason
+	return r
+}
+
+// Verifier verifies off-chain delegation claims and cross-checks them against
+// the registry and delegation contracts, so an agent revoked on-chain can no
+// longer use the claims it signed
+type Verifier struct {
+	client        *Client
+	claims        *signer.ClaimSigner
+	minTrustLevel TrustLevel
+	now           func() time.Time
+}
+
+// VerifierOption configures a Verifier
+type VerifierOption func(*Verifier)
+
+// WithClaimVerifier sets the ClaimSigner that verifies claims off-chain, e.g.
+// one with a DID resolver and a revocation checker
+func WithClaimVerifier(claims *signer.ClaimSigner) VerifierOption {
+	return func(v *Verifier) {
+		v.claims = claims
+	}
+}
+
+// WithMinTrustLevel sets the trust level both agents of a claim must have. It defaults to Verified.
+func WithMinTrustLevel(level TrustLevel) VerifierOption {
+	return func(v *Verifier) {
+		v.minTrustLevel = level
+	}
+}
+
+// WithVerifierClock sets the time source used for the expiry check
+func WithVerifierClock(now func() time.Time) VerifierOption {
+	return func(v *Verifier) {
+		v.now = now
+	}
+}
+
+// NewVerifier creates a Verifier that reads the contracts through client
+func NewVerifier(client *Client, opts ...VerifierOption) *Verifier {
+	v := &Verifier{
+		client:        client,
+		claims:        signer.NewClaimSigner(nil),
+		minTrustLevel: Verified,
+		now:           time.Now,
+	}
+	for _, opt := range opts {
+		opt(v)
+	}
+	return v
+}
+
+// VerifyDelegation verifies a delegation claim off-chain, then checks at the
+// given block, or the latest if block is nil, that its delegator and delegate
+// are active in the registry with the minimum trust level. If the delegator
+// also granted the claim's capability on-chain, the on-chain delegation must
+// still hold it. Rejections are reported in the result; errors are only
+// returned when the chain cannot be read.
+func (v *Verifier) VerifyDelegation(ctx context.Context, claim *models.DelegationClaim, block *big.Int) (*VerificationResult, error) {
+	if block == nil {
+		head, err := v.client.backend.HeaderByNumber(ctx, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get the latest block: %w", err)
+		}
+		block = head.Number
+	}
+	result := &VerificationResult{Block: block.Uint64()}
+	// Every read is made at the same block so the layers agree with each other
+	at := v.client.At(block)
+
+	if reason := v.verifyOffChain(ctx, claim); reason != "" {
+		return result.reject(LayerOffChain, reason), nil
+	}
+	result.accept(LayerOffChain, "")
+
+	delegator, reason, err := v.checkAgent(ctx, at, "delegator", claim.DelegatorDID)
+	if err != nil || reason != "" {
+		return result.reject(LayerRegistry, reason), err
+	}
+	delegate, reason, err := v.checkAgent(ctx, at, "delegate", claim.DelegateDID)
+	if err != nil || reason != "" {
+		return result.reject(LayerRegistry, reason), err
+	}
+	result.accept(LayerRegistry, "")
+
+	granted, err := capability.FromClaim(claim)
+	if err != nil {
+		// Such a capability cannot be encoded, so it cannot be mirrored either
+		result.Valid = true
+		return result, nil
+	}
+	mirrored, err := at.mirrors(ctx, delegator, delegate, granted)
+	if err != nil {
+		return nil, err
+	}
+	if !mirrored {
+		result.Valid = true
+		return result, nil
+	}
+	ok, err := at.HasDelegatedCapability(ctx, delegator, delegate, granted)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return result.reject(LayerDelegation, fmt.Sprintf("on-chain delegation of %s is revoked or expired", granted)), nil
+	}
+	result.accept(LayerDelegation, "")
+	result.Valid = true
+	return result, nil
+}
+
+// verifyOffChain returns why a claim fails off-chain verification, or "" if it passes
+func (v *Verifier) verifyOffChain(ctx context.Context, claim *models.DelegationClaim) string {
+	if claim.IsExpiredAt(v.now()) {
+		return "claim has expired"
+	}
+	// A chain of one checks the signature and, with a revocation checker, revocations
+	valid, err := v.claims.VerifyDelegationChainContext(ctx, &models.DelegationChain{Delegations: []*models.DelegationClaim{claim}})
+	if err != nil {
+		return err.Error()
+	}
+	if !valid {
+		return "invalid signature"
+	}
+	return ""
+}
+
+// checkAgent returns the address of an agent and why it fails the registry
+// check, or "" if it passes
+func (v *Verifier) checkAgent(ctx context.Context, at *Client, role, agentDID string) (common.Address, string, error) {
+	agent, err := at.FindAgent(ctx, agentDID)
+	if err != nil {
+		return common.Address{}, "", err
+	}
+	address := agent.Address
+	switch {
+	case agent.DID != agentDID && agent.DID != "":
+		return address, fmt.Sprintf("%s address %s is registered as %s", role, address.Hex(), agent.DID), nil
+	case !agent.Active && agent.DID != "":
+		return address, fmt.Sprintf("%s %s is revoked", role, agentDID), nil
+	case !agent.Active:
+		return address, fmt.Sprintf("%s %s is not registered", role, agentDID), nil
+	case agent.TrustLevel < v.minTrustLevel:
+		return address, fmt.Sprintf("%s %s is %s, below %s", role, agentDID, agent.TrustLevel, v.minTrustLevel), nil
+	}
+	return address, "", nil
+}
+
+// mirrors reports whether the on-chain delegation from delegator to delegate
+// lists want or a capability covering it, active or not
+func (c *Client) mirrors(ctx context.Context, delegator, delegate common.Address, want capability.Capability) (bool, error) {
+	details, err := c.GetDelegationDetails(ctx, delegator, delegate)
+	if err != nil {
+		return false, err
+	}
+	for _, id := range details.Capabilities {
+		if id == want.ID() {
+			return true, nil
+		}
+	}
+	for _, granted := range details.Granted {
+		if granted.Covers(want) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,152 @@
+package chain_test
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ak68a/agentid-core/pkg/capability"
+	"github.com/ak68a/agentid-core/pkg/chain"
+	"github.com/ak68a/agentid-core/pkg/chain/chaintest"
+	"github.com/ak68a/agentid-core/pkg/key"
+	"github.com/ak68a/agentid-core/pkg/models"
+	"github.com/ak68a/agentid-core/pkg/signer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// delegationClaim returns a claim from delegator to delegate signed by the delegator
+func delegationClaim(t *testing.T, delegator, delegate *key.AgentKey, granted capability.Capability) *models.DelegationClaim {
+	now := time.Now().Unix()
+	claim := &models.DelegationClaim{
+		DelegatorDID: delegator.DID,
+		DelegateDID:  delegate.DID,
+		Action:       granted.Action,
+		Scope:        granted.Scope,
+		IssuedAt:     now,
+		ExpiresAt:    now + 3600,
+		Nonce:        "nonce-1",
+		MaxDepth:     1,
+		Type:         []string{"VerifiableCredential", "DelegationCredential"},
+		Context:      []string{"https://www.w3.org/2018/credentials/v1"},
+		Issuer:       delegator.DID,
+		Subject:      delegate.DID,
+	}
+	require.NoError(t, signer.NewClaimSigner(delegator).SignDelegationClaim(claim))
+	return claim
+}
+
+func layers(result *chain.VerificationResult) []chain.Layer {
+	var names []chain.Layer
+	for _, layer := range result.Layers {
+		names = append(names, layer.Layer)
+	}
+	return names
+}
+
+func TestVerifyDelegation(t *testing.T) {
+	ctx := context.Background()
+	alice, bob := chaintest.NewKey(t), chaintest.NewKey(t)
+	tc := chaintest.New(t, alice, bob)
+	owner := tc.Owner
+	aliceClient := tc.Client(alice)
+	verifier := chain.NewVerifier(owner)
+
+	claim := delegationClaim(t, alice, bob, transferETH)
+
+	// Neither agent is registered yet
+	result, err := verifier.VerifyDelegation(ctx, claim, nil)
+	require.NoError(t, err)
+	assert.False(t, result.Valid)
+	assert.Equal(t, chain.LayerRegistry, result.RejectedBy)
+	assert.Contains(t, result.Reason, "not registered")
+
+	tc.Send(aliceClient.RegisterAgent(ctx, alice.DID))
+	tc.Send(tc.Client(bob).RegisterAgent(ctx, bob.DID))
+	tc.Send(owner.VerifyAgent(ctx, chaintest.Address(bob), chain.Verified, nil))
+	tc.Send(owner.VerifyAgent(ctx, chaintest.Address(alice), chain.Certified, nil))
+
+	// The capability is not mirrored on-chain, so the registry decides
+	result, err = verifier.VerifyDelegation(ctx, claim, nil)
+	require.NoError(t, err)
+	assert.True(t, result.Valid)
+	assert.Equal(t, []chain.Layer{chain.LayerOffChain, chain.LayerRegistry}, layers(result))
+
+	result, err = chain.NewVerifier(owner, chain.WithMinTrustLevel(chain.Certified)).VerifyDelegation(ctx, claim, nil)
+	require.NoError(t, err)
+	assert.False(t, result.Valid)
+	assert.Equal(t, chain.LayerRegistry, result.RejectedBy)
+	assert.Contains(t, result.Reason, "delegate "+bob.DID+" is Verified, below Certified")
+
+	// A wildcard granted on-chain mirrors the claim's capability
+	tc.Send(aliceClient.CreateDelegation(ctx, chaintest.Address(bob), []capability.Capability{{Action: "transfer", Scope: "*"}}, time.Now().Add(time.Hour)))
+	result, err = verifier.VerifyDelegation(ctx, claim, nil)
+	require.NoError(t, err)
+	assert.True(t, result.Valid)
+	assert.Equal(t, []chain.Layer{chain.LayerOffChain, chain.LayerRegistry, chain.LayerDelegation}, layers(result))
+
+	// A capability the on-chain delegation does not list is left to the registry
+	result, err = verifier.VerifyDelegation(ctx, delegationClaim(t, alice, bob, bookFlights), nil)
+	require.NoError(t, err)
+	assert.True(t, result.Valid)
+	assert.Equal(t, []chain.Layer{chain.LayerOffChain, chain.LayerRegistry}, layers(result))
+
+	before, err := tc.Backend.Client().BlockNumber(ctx)
+	require.NoError(t, err)
+	tc.Send(aliceClient.RevokeDelegation(ctx, chaintest.Address(bob), "done"))
+	result, err = verifier.VerifyDelegation(ctx, claim, nil)
+	require.NoError(t, err)
+	assert.False(t, result.Valid)
+	assert.Equal(t, chain.LayerDelegation, result.RejectedBy)
+
+	tc.Send(owner.RevokeAgent(ctx, chaintest.Address(alice), "compromised"))
+	result, err = verifier.VerifyDelegation(ctx, claim, nil)
+	require.NoError(t, err)
+	assert.False(t, result.Valid)
+	assert.Equal(t, chain.LayerRegistry, result.RejectedBy)
+	assert.Equal(t, "delegator "+alice.DID+" is revoked", result.Reason)
+
+	// Verification at an earlier block sees the state of that block
+	result, err = verifier.VerifyDelegation(ctx, claim, new(big.Int).SetUint64(before))
+	require.NoError(t, err)
+	assert.True(t, result.Valid)
+	assert.Equal(t, before, result.Block)
+}
+
+func TestVerifyDelegationOffChain(t *testing.T) {
+	ctx := context.Background()
+	alice, bob := chaintest.NewKey(t), chaintest.NewKey(t)
+	tc := chaintest.New(t)
+	verifier := chain.NewVerifier(tc.Owner)
+
+	tests := []struct {
+		name   string
+		modify func(claim *models.DelegationClaim)
+		opts   []chain.VerifierOption
+	}{
+		{"tampered", func(claim *models.DelegationClaim) { claim.Scope = "USD" }, nil},
+		{"signed by another agent", func(claim *models.DelegationClaim) {
+			*claim = *delegationClaim(t, bob, bob, transferETH)
+			claim.DelegatorDID = alice.DID
+		}, nil},
+		{"expired", nil, []chain.VerifierOption{chain.WithVerifierClock(func() time.Time { return time.Now().Add(2 * time.Hour) })}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claim := delegationClaim(t, alice, bob, transferETH)
+			if tt.modify != nil {
+				tt.modify(claim)
+			}
+			v := verifier
+			if tt.opts != nil {
+				v = chain.NewVerifier(tc.Owner, tt.opts...)
+			}
+			result, err := v.VerifyDelegation(ctx, claim, nil)
+			require.NoError(t, err)
+			assert.False(t, result.Valid)
+			assert.Equal(t, chain.LayerOffChain, result.RejectedBy)
+			assert.Equal(t, []chain.Layer{chain.LayerOffChain}, layers(result))
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package did
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// maxCacheEntries bounds the number of documents a Cache holds
+const maxCacheEntries = 1024
+
+// Cache remembers the documents a resolver returns for a time, so that a DID
+// resolved repeatedly is fetched once. Failed resolutions are not cached.
+type Cache struct {
+	resolver Resolver
+	ttl      time.Duration
+	now      func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+}
+
+type cacheEntry struct {
+	doc     *Document
+	expires time.Time
+}
+
+// NewCache returns a Cache in front of resolver keeping documents for ttl
+func NewCache(resolver Resolver, ttl time.Duration) *Cache {
+	return &Cache{
+		resolver: resolver,
+		ttl:      ttl,
+		now:      time.Now,
+		entries:  make(map[string]cacheEntry),
+	}
+}
+
+// Resolve returns the cached document of a DID, resolving it if it is missing or expired
+func (c *Cache) Resolve(ctx context.Context, did string) (*Document, error) {
+	now := c.now()
+	c.mu.Lock()
+	entry, ok := c.entries[did]
+	c.mu.Unlock()
+	if ok && now.Before(entry.expires) {
+		return entry.doc, nil
+	}
+
+	doc, err := c.resolver.Resolve(ctx, did)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.entries) >= maxCacheEntries {
+		for key, entry := range c.entries {
+			if !now.Before(entry.expires) {
+				delete(c.entries, key)
+			}
+		}
+	}
+	// When full of live entries, serve the document without caching it
+	if len(c.entries) < maxCacheEntries {
+		c.entries[did] = cacheEntry{doc: doc, expires: now.Add(c.ttl)}
+	}
+	return doc, nil
+}
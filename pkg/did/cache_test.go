@@ -0,0 +1,48 @@
+package did
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCache(t *testing.T) {
+	calls := 0
+	fail := false
+	resolver := ResolverFunc(func(_ context.Context, did string) (*Document, error) {
+		calls++
+		if fail {
+			return nil, errors.New("unreachable")
+		}
+		return &Document{ID: did}, nil
+	})
+	now := time.Unix(1700000000, 0)
+	cache := NewCache(resolver, time.Minute)
+	cache.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	for i := 0; i < 3; i++ {
+		doc, err := cache.Resolve(ctx, "did:web:example.com")
+		require.NoError(t, err)
+		assert.Equal(t, "did:web:example.com", doc.ID)
+	}
+	assert.Equal(t, 1, calls, "a cached document is not resolved again")
+
+	// Failures are not cached
+	fail = true
+	_, err := cache.Resolve(ctx, "did:web:other.com")
+	assert.Error(t, err)
+	_, err = cache.Resolve(ctx, "did:web:other.com")
+	assert.Error(t, err)
+	assert.Equal(t, 3, calls)
+
+	// Expired documents are resolved again
+	now = now.Add(time.Minute)
+	_, err = cache.Resolve(ctx, "did:web:example.com")
+	assert.Error(t, err)
+	assert.Equal(t, 4, calls)
+}
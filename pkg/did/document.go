@@ -0,0 +1,286 @@
+package did
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/mr-tron/base58"
+)
+
+// DID Core contexts
+const (
+	DIDContext          = "https://www.w3.org/ns/did/v1"
+	SecpRecoveryContext = "https://w3id.org/security/suites/secp256k1recovery-2020/v2"
+	MultikeyContext     = "https://w3id.org/security/multikey/v1"
+)
+
+// Verification method types understood by this package
+const (
+	EcdsaSecp256k1RecoveryMethod2020  = "EcdsaSecp256k1RecoveryMethod2020"
+	EcdsaSecp256k1VerificationKey2019 = "EcdsaSecp256k1VerificationKey2019"
+	Multikey                          = "Multikey"
+	JsonWebKey2020                    = "JsonWebKey2020"
+)
+
+// Document is a W3C DID document
+type Document struct {
+	Context              StringOrSlice              `json:"@context,omitempty"`
+	ID                   string                     `json:"id"`
+	Controller           StringOrSlice              `json:"controller,omitempty"`
+	AlsoKnownAs          []string                   `json:"alsoKnownAs,omitempty"`
+	VerificationMethod   []VerificationMethod       `json:"verificationMethod,omitempty"`
+	Authentication       []VerificationRelationship `json:"authentication,omitempty"`
+	AssertionMethod      []VerificationRelationship `json:"assertionMethod,omitempty"`
+	KeyAgreement         []VerificationRelationship `json:"keyAgreement,omitempty"`
+	CapabilityInvocation []VerificationRelationship `json:"capabilityInvocation,omitempty"`
+	CapabilityDelegation []VerificationRelationship `json:"capabilityDelegation,omitempty"`
+	Service              []Service                  `json:"service,omitempty"`
+}
+
+// VerificationMethod is a public key or blockchain account that can verify proofs for a DID
+type VerificationMethod struct {
+	ID                  string `json:"id"`
+	Type                string `json:"type"`
+	Controller          string `json:"controller"`
+	PublicKeyHex        string `json:"publicKeyHex,omitempty"`
+	PublicKeyMultibase  string `json:"publicKeyMultibase,omitempty"`
+	PublicKeyJwk        *JWK   `json:"publicKeyJwk,omitempty"`
+	BlockchainAccountID string `json:"blockchainAccountId,omitempty"` // CAIP-10, e.g. eip155:1:0x...
+}
+
+// JWK is the subset of a JSON Web Key needed for secp256k1 public keys
+type JWK struct {
+	Kty string `json:"kty"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// Service is a service endpoint advertised by a DID document
+type Service struct {
+	ID              string      `json:"id"`
+	Type            string      `json:"type"`
+	ServiceEndpoint interface{} `json:"serviceEndpoint"`
+}
+
+// VerificationRelationship is an entry of a verification relationship such as
+// assertionMethod: either a reference to a method by ID or an embedded method
+type VerificationRelationship struct {
+	Reference string
+	Embedded  *VerificationMethod
+}
+
+// MarshalJSON encodes a reference as a string and an embedded method as an object
+func (r VerificationRelationship) MarshalJSON() ([]byte, error) {
+	if r.Embedded != nil {
+		return json.Marshal(r.Embedded)
+	}
+	return json.Marshal(r.Reference)
+}
+
+// UnmarshalJSON accepts either a method ID string or an embedded method object
+func (r *VerificationRelationship) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '"' {
+		return json.Unmarshal(data, &r.Reference)
+	}
+	r.Embedded = &VerificationMethod{}
+	return json.Unmarshal(data, r.Embedded)
+}
+
+// StringOrSlice is a JSON-LD property that may be a single string or an array
+type StringOrSlice []string
+
+// UnmarshalJSON accepts a string or an array of strings
+func (s *StringOrSlice) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '"' {
+		var value string
+		if err := json.Unmarshal(data, &value); err != nil {
+			return err
+		}
+		*s = StringOrSlice{value}
+		return nil
+	}
+	return json.Unmarshal(data, (*[]string)(s))
+}
+
+// Relationship returns the entries of a verification relationship by proof purpose name
+func (d *Document) Relationship(purpose string) ([]VerificationRelationship, error) {
+	switch purpose {
+	case "authentication":
+		return d.Authentication, nil
+	case "assertionMethod":
+		return d.AssertionMethod, nil
+	case "keyAgreement":
+		return d.KeyAgreement, nil
+	case "capabilityInvocation":
+		return d.CapabilityInvocation, nil
+	case "capabilityDelegation":
+		return d.CapabilityDelegation, nil
+	default:
+		return nil, fmt.Errorf("unknown verification relationship %q", purpose)
+	}
+}
+
+// VerificationMethods returns the methods the document authorizes for purpose,
+// resolving references against its verificationMethod list
+func (d *Document) VerificationMethods(purpose string) ([]*VerificationMethod, error) {
+	relationship, err := d.Relationship(purpose)
+	if err != nil {
+		return nil, err
+	}
+
+	methods := make([]*VerificationMethod, 0, len(relationship))
+	for _, entry := range relationship {
+		if entry.Embedded != nil {
+			methods = append(methods, entry.Embedded)
+			continue
+		}
+		vm := d.verificationMethod(entry.Reference)
+		if vm == nil {
+			return nil, fmt.Errorf("verification method %s is referenced but not defined", entry.Reference)
+		}
+		methods = append(methods, vm)
+	}
+	return methods, nil
+}
+
+// FindVerificationMethod returns the method with the given ID if the document
+// authorizes it for purpose. IDs may be absolute or relative (#key-1).
+func (d *Document) FindVerificationMethod(id, purpose string) (*VerificationMethod, error) {
+	methods, err := d.VerificationMethods(purpose)
+	if err != nil {
+		return nil, err
+	}
+	for _, vm := range methods {
+		if d.absoluteID(vm.ID) == d.absoluteID(id) {
+			return vm, nil
+		}
+	}
+	return nil, fmt.Errorf("verification method %s is not authorized for %s by %s", id, purpose, d.ID)
+}
+
+// verificationMethod looks up a method defined in the verificationMethod list
+func (d *Document) verificationMethod(id string) *VerificationMethod {
+	for i := range d.VerificationMethod {
+		if d.absoluteID(d.VerificationMethod[i].ID) == d.absoluteID(id) {
+			return &d.VerificationMethod[i]
+		}
+	}
+	return nil
+}
+
+// absoluteID resolves a relative DID URL (#fragment) against the document ID
+func (d *Document) absoluteID(id string) string {
+	if strings.HasPrefix(id, "#") {
+		return d.ID + id
+	}
+	return id
+}
+
+// Address returns the Ethereum address a verification method authorizes,
+// derived from its blockchain account or public key
+func (vm *VerificationMethod) Address() (common.Address, error) {
+	if vm.BlockchainAccountID != "" {
+		return parseEIP155Account(vm.BlockchainAccountID)
+	}
+
+	switch {
+	case vm.PublicKeyHex != "":
+		raw, err := hex.DecodeString(strings.TrimPrefix(vm.PublicKeyHex, "0x"))
+		if err != nil {
+			return common.Address{}, fmt.Errorf("invalid publicKeyHex: %w", err)
+		}
+		return addressFromPublicKey(raw)
+	case vm.PublicKeyMultibase != "":
+		raw, err := decodeMultikey(vm.PublicKeyMultibase)
+		if err != nil {
+			return common.Address{}, err
+		}
+		return addressFromPublicKey(raw)
+	case vm.PublicKeyJwk != nil:
+		return vm.PublicKeyJwk.address()
+	default:
+		return common.Address{}, fmt.Errorf("verification method %s has no secp256k1 key material", vm.ID)
+	}
+}
+
+// address decodes a secp256k1 JWK into an Ethereum address
+func (jwk *JWK) address() (common.Address, error) {
+	if jwk.Kty != "EC" || jwk.Crv != "secp256k1" {
+		return common.Address{}, fmt.Errorf("unsupported JWK %s/%s, expected EC/secp256k1", jwk.Kty, jwk.Crv)
+	}
+	x, err := base64.RawURLEncoding.DecodeString(jwk.X)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("invalid JWK x: %w", err)
+	}
+	y, err := base64.RawURLEncoding.DecodeString(jwk.Y)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("invalid JWK y: %w", err)
+	}
+	// RFC 7518 coordinates are the full field size, which also keeps them from overflowing the key
+	if len(x) != 32 || len(y) != 32 {
+		return common.Address{}, fmt.Errorf("invalid JWK: coordinates must be 32 bytes, got %d and %d", len(x), len(y))
+	}
+
+	raw := make([]byte, 65)
+	raw[0] = 0x04
+	copy(raw[1:33], x)
+	copy(raw[33:], y)
+	return addressFromPublicKey(raw)
+}
+
+// addressFromPublicKey accepts a compressed (33-byte) or uncompressed (65-byte) secp256k1 key
+func addressFromPublicKey(raw []byte) (common.Address, error) {
+	if len(raw) == 33 {
+		pub, err := crypto.DecompressPubkey(raw)
+		if err != nil {
+			return common.Address{}, fmt.Errorf("invalid compressed public key: %w", err)
+		}
+		return crypto.PubkeyToAddress(*pub), nil
+	}
+	pub, err := crypto.UnmarshalPubkey(raw)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("invalid public key: %w", err)
+	}
+	return crypto.PubkeyToAddress(*pub), nil
+}
+
+// parseEIP155Account parses a CAIP-10 account ID such as eip155:1:0xab...
+func parseEIP155Account(account string) (common.Address, error) {
+	parts := strings.Split(account, ":")
+	if len(parts) != 3 || parts[0] != "eip155" {
+		return common.Address{}, fmt.Errorf("unsupported blockchain account %s", account)
+	}
+	if !common.IsHexAddress(parts[2]) {
+		return common.Address{}, fmt.Errorf("invalid Ethereum address in account %s", account)
+	}
+	return common.HexToAddress(parts[2]), nil
+}
+
+// secp256k1PubMulticodec is the varint-encoded multicodec prefix 0xe7 for secp256k1 public keys
+var secp256k1PubMulticodec = []byte{0xe7, 0x01}
+
+// decodeMultikey decodes a base58btc multibase secp256k1 public key (z prefix)
+func decodeMultikey(value string) ([]byte, error) {
+	if !strings.HasPrefix(value, "z") {
+		return nil, fmt.Errorf("unsupported multibase encoding in %s", value)
+	}
+	data, err := base58.Decode(value[1:])
+	if err != nil {
+		return nil, fmt.Errorf("invalid base58 key: %w", err)
+	}
+	if len(data) < 2 || data[0] != secp256k1PubMulticodec[0] || data[1] != secp256k1PubMulticodec[1] {
+		return nil, fmt.Errorf("unsupported key type in %s, only secp256k1 is supported", value)
+	}
+	return data[2:], nil
+}
+
+// encodeMultikey encodes a compressed secp256k1 public key as base58btc multibase
+func encodeMultikey(compressed []byte) string {
+	return "z" + base58.Encode(append(append([]byte{}, secp256k1PubMulticodec...), compressed...))
+}
@@ -0,0 +1,98 @@
+package did
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ak68a/agentid-core/pkg/key"
+	"github.com/ak68a/agentid-core/pkg/models"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// resolveAckid builds the document of a did:ackid:0x{address} DID. The DID is
+// its own key: a single recovery method for the address at #key-1.
+func resolveAckid(_ context.Context, did string) (*Document, error) {
+	address, err := key.ExtractAddressFromDID(did)
+	if err != nil {
+		return nil, err
+	}
+	return recoveryDocument(did, did+"#key-1", fmt.Sprintf("eip155:1:%s", address.Hex())), nil
+}
+
+// resolvePkh builds the document of a did:pkh:eip155:{chainId}:0x{address} DID
+func resolvePkh(_ context.Context, did string) (*Document, error) {
+	account := strings.TrimPrefix(did, models.DIDMethodPkh)
+	parts := strings.Split(account, ":")
+	if len(parts) != 3 || parts[0] != "eip155" {
+		return nil, fmt.Errorf("only eip155 accounts are supported: %s", did)
+	}
+	if _, ok := new(big.Int).SetString(parts[1], 10); !ok {
+		return nil, fmt.Errorf("invalid chain ID in %s", did)
+	}
+	if _, err := parseEIP155Account(account); err != nil {
+		return nil, err
+	}
+	return recoveryDocument(did, did+"#blockchainAccountId", account), nil
+}
+
+// resolveKey builds the document of a did:key DID for a secp256k1 public key
+func resolveKey(_ context.Context, did string) (*Document, error) {
+	multibase := strings.TrimPrefix(did, models.DIDMethodKey)
+	raw, err := decodeMultikey(multibase)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := crypto.DecompressPubkey(raw); err != nil {
+		return nil, fmt.Errorf("invalid secp256k1 key in %s: %w", did, err)
+	}
+
+	vm := VerificationMethod{
+		ID:                 did + "#" + multibase,
+		Type:               Multikey,
+		Controller:         did,
+		PublicKeyMultibase: multibase,
+	}
+	return &Document{
+		Context:              StringOrSlice{DIDContext, MultikeyContext},
+		ID:                   did,
+		VerificationMethod:   []VerificationMethod{vm},
+		Authentication:       []VerificationRelationship{{Reference: vm.ID}},
+		AssertionMethod:      []VerificationRelationship{{Reference: vm.ID}},
+		CapabilityInvocation: []VerificationRelationship{{Reference: vm.ID}},
+		CapabilityDelegation: []VerificationRelationship{{Reference: vm.ID}},
+	}, nil
+}
+
+// recoveryDocument returns a document whose only method is an Ethereum account
+// that signatures are recovered against
+func recoveryDocument(did, methodID, account string) *Document {
+	vm := VerificationMethod{
+		ID:                  methodID,
+		Type:                EcdsaSecp256k1RecoveryMethod2020,
+		Controller:          did,
+		BlockchainAccountID: account,
+	}
+	return &Document{
+		Context:              StringOrSlice{DIDContext, SecpRecoveryContext},
+		ID:                   did,
+		VerificationMethod:   []VerificationMethod{vm},
+		Authentication:       []VerificationRelationship{{Reference: vm.ID}},
+		AssertionMethod:      []VerificationRelationship{{Reference: vm.ID}},
+		CapabilityInvocation: []VerificationRelationship{{Reference: vm.ID}},
+		CapabilityDelegation: []VerificationRelationship{{Reference: vm.ID}},
+	}
+}
+
+// KeyDID returns the did:key DID of a secp256k1 public key
+func KeyDID(pub *ecdsa.PublicKey) string {
+	return models.DIDMethodKey + encodeMultikey(crypto.CompressPubkey(pub))
+}
+
+// PkhDID returns the did:pkh DID of an Ethereum account on a chain
+func PkhDID(chainID int64, address common.Address) string {
+	return fmt.Sprintf("%seip155:%d:%s", models.DIDMethodPkh, chainID, address.Hex())
+}
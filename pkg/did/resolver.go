@@ -0,0 +1,110 @@
+// Package did resolves DIDs to W3C DID documents and finds the verification
+// methods (Ethereum addresses) that may sign on a DID's behalf.
+//
+// Supported methods are did:ackid, did:key (secp256k1), did:web and did:pkh (eip155).
+package did
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Resolver resolves a DID to its DID document
+type Resolver interface {
+	Resolve(ctx context.Context, did string) (*Document, error)
+}
+
+// ResolverFunc adapts a function to the Resolver interface
+type ResolverFunc func(ctx context.Context, did string) (*Document, error)
+
+// Resolve calls f(ctx, did)
+func (f ResolverFunc) Resolve(ctx context.Context, did string) (*Document, error) {
+	return f(ctx, did)
+}
+
+// Registry dispatches resolution to a driver per DID method
+type Registry struct {
+	drivers map[string]Resolver
+}
+
+// Option configures the default Registry
+type Option func(*options)
+
+type options struct {
+	httpClient *http.Client
+}
+
+// WithHTTPClient sets the client used by the did:web driver. The default client
+// only connects to public addresses; a client set here is used as is.
+func WithHTTPClient(client *http.Client) Option {
+	return func(o *options) {
+		o.httpClient = client
+	}
+}
+
+// NewResolver returns a Registry with drivers for did:ackid, did:key, did:web and did:pkh
+func NewResolver(opts ...Option) *Registry {
+	o := &options{httpClient: defaultHTTPClient}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	r := &Registry{drivers: make(map[string]Resolver)}
+	r.Register("ackid", ResolverFunc(resolveAckid))
+	r.Register("key", ResolverFunc(resolveKey))
+	r.Register("web", &WebResolver{Client: o.httpClient})
+	r.Register("pkh", ResolverFunc(resolvePkh))
+	return r
+}
+
+// Register adds or replaces the driver for a DID method (e.g. "web")
+func (r *Registry) Register(method string, driver Resolver) {
+	r.drivers[method] = driver
+}
+
+// Resolve resolves a DID with the driver registered for its method
+func (r *Registry) Resolve(ctx context.Context, did string) (*Document, error) {
+	method, _, err := Parse(did)
+	if err != nil {
+		return nil, err
+	}
+	driver, ok := r.drivers[method]
+	if !ok {
+		return nil, fmt.Errorf("unsupported DID method %q", method)
+	}
+
+	doc, err := driver.Resolve(ctx, did)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve %s: %w", did, err)
+	}
+	if doc.ID != did {
+		return nil, fmt.Errorf("resolved document ID %s does not match %s", doc.ID, did)
+	}
+	return doc, nil
+}
+
+// Parse splits a DID into its method and method-specific identifier
+func Parse(did string) (method, id string, err error) {
+	parts := strings.SplitN(did, ":", 3)
+	if len(parts) != 3 || parts[0] != "did" || parts[1] == "" || parts[2] == "" {
+		return "", "", fmt.Errorf("invalid DID: %s", did)
+	}
+	return parts[1], parts[2], nil
+}
+
+// ResolveVerificationMethod resolves a DID URL such as did:ackid:0x...#key-1 and
+// returns the verification method if its DID document authorizes it for purpose
+func ResolveVerificationMethod(ctx context.Context, r Resolver, didURL, purpose string) (*VerificationMethod, error) {
+	did, fragment, found := strings.Cut(didURL, "#")
+	if !found || fragment == "" {
+		return nil, fmt.Errorf("verification method %s has no fragment", didURL)
+	}
+
+	doc, err := r.Resolve(ctx, did)
+	if err != nil {
+		return nil, err
+	}
+	return doc.FindVerificationMethod(didURL, purpose)
+}
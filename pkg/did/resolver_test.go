@@ -0,0 +1,150 @@
+package did
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/ak68a/agentid-core/pkg/key"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveAckid(t *testing.T) {
+	agentKey, err := key.GenerateAgentKey()
+	require.NoError(t, err)
+
+	vm, err := ResolveVerificationMethod(context.Background(), NewResolver(), agentKey.DID+"#key-1", "assertionMethod")
+	require.NoError(t, err)
+	assert.Equal(t, EcdsaSecp256k1RecoveryMethod2020, vm.Type)
+
+	address, err := vm.Address()
+	require.NoError(t, err)
+	assert.Equal(t, agentKey.Address, address)
+
+	_, err = ResolveVerificationMethod(context.Background(), NewResolver(), agentKey.DID+"#key-2", "assertionMethod")
+	assert.Error(t, err)
+	_, err = NewResolver().Resolve(context.Background(), "did:ackid:not-an-address")
+	assert.Error(t, err)
+}
+
+func TestResolveKey(t *testing.T) {
+	agentKey, err := key.GenerateAgentKey()
+	require.NoError(t, err)
+
+	keyDID := KeyDID(agentKey.PublicKey)
+	assert.True(t, strings.HasPrefix(keyDID, "did:key:zQ3s"), "secp256k1 did:key should start with zQ3s: %s", keyDID)
+
+	doc, err := NewResolver().Resolve(context.Background(), keyDID)
+	require.NoError(t, err)
+	methods, err := doc.VerificationMethods("capabilityDelegation")
+	require.NoError(t, err)
+	require.Len(t, methods, 1)
+	assert.Equal(t, Multikey, methods[0].Type)
+
+	address, err := methods[0].Address()
+	require.NoError(t, err)
+	assert.Equal(t, agentKey.Address, address)
+
+	// Ed25519 (multicodec 0xed) keys cannot produce Ethereum signatures
+	_, err = NewResolver().Resolve(context.Background(), "did:key:z6MkhaXgBZDvotDkL5257faiztiGiC2QtKLGpbnnEGta2doK")
+	assert.Error(t, err)
+}
+
+func TestResolvePkh(t *testing.T) {
+	agentKey, err := key.GenerateAgentKey()
+	require.NoError(t, err)
+
+	pkhDID := PkhDID(137, agentKey.Address)
+	assert.Equal(t, "did:pkh:eip155:137:"+agentKey.Address.Hex(), pkhDID)
+
+	vm, err := ResolveVerificationMethod(context.Background(), NewResolver(), pkhDID+"#blockchainAccountId", "authentication")
+	require.NoError(t, err)
+	address, err := vm.Address()
+	require.NoError(t, err)
+	assert.Equal(t, agentKey.Address, address)
+
+	for _, bad := range []string{
+		"did:pkh:solana:4sGjMW1sUnHzSxGspuhpqLDx6wiyjNtZ:CKg5d12Jhpej1JqtmxLJgaFqqeYjxgPqToJ4LBdvG9Ev",
+		"did:pkh:eip155:x:" + agentKey.Address.Hex(),
+		"did:pkh:eip155:1:0x1234",
+	} {
+		_, err := NewResolver().Resolve(context.Background(), bad)
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	_, err := NewResolver().Resolve(context.Background(), "did:example:123")
+	assert.ErrorContains(t, err, "unsupported DID method")
+
+	_, err = NewResolver().Resolve(context.Background(), "not-a-did")
+	assert.Error(t, err)
+
+	// Custom drivers can be registered, but must return the requested document
+	registry := NewResolver()
+	registry.Register("example", ResolverFunc(func(_ context.Context, did string) (*Document, error) {
+		return &Document{ID: "did:example:other"}, nil
+	}))
+	_, err = registry.Resolve(context.Background(), "did:example:123")
+	assert.ErrorContains(t, err, "does not match")
+}
+
+func TestDocumentJSON(t *testing.T) {
+	agentKey, err := key.GenerateAgentKey()
+	require.NoError(t, err)
+
+	// A document with a single-string context, a relative reference and an embedded method
+	data := `{
+		"@context": "https://www.w3.org/ns/did/v1",
+		"id": "did:web:example.com",
+		"verificationMethod": [{
+			"id": "#owner",
+			"type": "EcdsaSecp256k1RecoveryMethod2020",
+			"controller": "did:web:example.com",
+			"blockchainAccountId": "eip155:1:` + agentKey.Address.Hex() + `"
+		}],
+		"assertionMethod": [
+			"#owner",
+			{
+				"id": "did:web:example.com#jwk",
+				"type": "JsonWebKey2020",
+				"controller": "did:web:example.com",
+				"publicKeyJwk": {"kty": "EC", "crv": "secp256k1", "x": "` + jwkCoordinate(agentKey.PublicKey.X) + `", "y": "` + jwkCoordinate(agentKey.PublicKey.Y) + `"}
+			}
+		],
+		"authentication": ["#missing"]
+	}`
+
+	var doc Document
+	require.NoError(t, json.Unmarshal([]byte(data), &doc))
+	assert.Equal(t, StringOrSlice{DIDContext}, doc.Context)
+
+	for _, id := range []string{"did:web:example.com#owner", "#owner", "did:web:example.com#jwk"} {
+		vm, err := doc.FindVerificationMethod(id, "assertionMethod")
+		require.NoError(t, err, id)
+		address, err := vm.Address()
+		require.NoError(t, err, id)
+		assert.Equal(t, agentKey.Address, address, id)
+	}
+
+	// Defined methods are only usable for the relationships that list them
+	_, err = doc.FindVerificationMethod("#owner", "capabilityInvocation")
+	assert.Error(t, err)
+	_, err = doc.VerificationMethods("authentication")
+	assert.ErrorContains(t, err, "not defined")
+
+	// Oversized coordinates are rejected rather than overflowing the key
+	oversized := &JWK{Kty: "EC", Crv: "secp256k1", X: base64.RawURLEncoding.EncodeToString(make([]byte, 33)), Y: jwkCoordinate(agentKey.PublicKey.Y)}
+	_, err = (&VerificationMethod{ID: "#big", PublicKeyJwk: oversized}).Address()
+	assert.ErrorContains(t, err, "must be 32 bytes")
+
+	// References and embedded methods survive a round trip
+	encoded, err := json.Marshal(&doc)
+	require.NoError(t, err)
+	var decoded Document
+	require.NoError(t, json.Unmarshal(encoded, &decoded))
+	assert.Equal(t, doc, decoded)
+}
@@ -0,0 +1,119 @@
+package did
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/ak68a/agentid-core/pkg/models"
+)
+
+// maxDocumentSize bounds the size of a fetched did:web document
+const maxDocumentSize = 1 << 20
+
+// defaultHTTPClient fetches did:web documents from public addresses only, so
+// that resolving a DID chosen by a caller cannot reach the resolver's own
+// network. The address is checked after DNS resolution, on every connection.
+var defaultHTTPClient = &http.Client{
+	Timeout: 10 * time.Second,
+	Transport: &http.Transport{
+		DialContext: (&net.Dialer{
+			Timeout: 5 * time.Second,
+			Control: dialPublic,
+		}).DialContext,
+		TLSHandshakeTimeout: 5 * time.Second,
+		MaxIdleConns:        16,
+		IdleConnTimeout:     90 * time.Second,
+	},
+}
+
+// dialPublic refuses connections to loopback, private, link-local and other
+// non-public addresses
+func dialPublic(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || !isPublic(ip) {
+		return fmt.Errorf("refusing to connect to non-public address %s", host)
+	}
+	return nil
+}
+
+func isPublic(ip net.IP) bool {
+	return !(ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast())
+}
+
+// WebResolver resolves did:web DIDs by fetching did.json over HTTPS
+type WebResolver struct {
+	Client *http.Client // defaults to a client with a 10s timeout that only connects to public addresses
+}
+
+// Resolve fetches and decodes the DID document of a did:web DID
+func (w *WebResolver) Resolve(ctx context.Context, did string) (*Document, error) {
+	documentURL, err := WebDocumentURL(did)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, documentURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/did+json, application/json")
+
+	client := w.Client
+	if client == nil {
+		client = defaultHTTPClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", documentURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch %s: %s", documentURL, resp.Status)
+	}
+
+	var doc Document
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxDocumentSize)).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("invalid DID document at %s: %w", documentURL, err)
+	}
+	return &doc, nil
+}
+
+// WebDocumentURL maps a did:web DID to the HTTPS URL of its document:
+// did:web:example.com -> https://example.com/.well-known/did.json and
+// did:web:example.com:agents:a1 -> https://example.com/agents/a1/did.json
+func WebDocumentURL(did string) (string, error) {
+	if !strings.HasPrefix(did, models.DIDMethodWeb) {
+		return "", fmt.Errorf("not a did:web DID: %s", did)
+	}
+
+	segments := strings.Split(strings.TrimPrefix(did, models.DIDMethodWeb), ":")
+	for i, segment := range segments {
+		// The host may carry a percent-encoded port, e.g. localhost%3A8443
+		decoded, err := url.PathUnescape(segment)
+		if err != nil || decoded == "" || decoded == "." || decoded == ".." || strings.ContainsAny(decoded, "/?#@\\") {
+			return "", fmt.Errorf("invalid did:web segment %q in %s", segment, did)
+		}
+		segments[i] = decoded
+	}
+
+	host := segments[0]
+	if len(segments) == 1 {
+		return "https://" + host + "/.well-known/did.json", nil
+	}
+	return "https://" + host + "/" + strings.Join(segments[1:], "/") + "/did.json", nil
+}
@@ -0,0 +1,105 @@
+package did
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ak68a/agentid-core/pkg/key"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebDocumentURL(t *testing.T) {
+	tests := map[string]string{
+		"did:web:w3c-ccg.github.io":                    "https://w3c-ccg.github.io/.well-known/did.json",
+		"did:web:w3c-ccg.github.io:user:alice":         "https://w3c-ccg.github.io/user/alice/did.json",
+		"did:web:example.com%3A3000:user:alice":        "https://example.com:3000/user/alice/did.json",
+		"did:web:acme-corp.com:agents:booking%2Dagent": "https://acme-corp.com/agents/booking-agent/did.json",
+	}
+	for did, want := range tests {
+		got, err := WebDocumentURL(did)
+		require.NoError(t, err, did)
+		assert.Equal(t, want, got, did)
+	}
+
+	for _, bad := range []string{
+		"did:key:z123",
+		"did:web:",
+		"did:web:example.com::alice",
+		"did:web:example.com:..:admin",
+		"did:web:example.com:a%2Fb",
+		"did:web:user%40evil.com",
+	} {
+		_, err := WebDocumentURL(bad)
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestWebResolver(t *testing.T) {
+	ownerKey, err := key.GenerateAgentKey()
+	require.NoError(t, err)
+
+	documents := make(map[string]*Document)
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		doc, ok := documents[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/did+json")
+		json.NewEncoder(w).Encode(doc)
+	}))
+	defer server.Close()
+
+	host := strings.ReplaceAll(strings.TrimPrefix(server.URL, "https://"), ":", "%3A")
+	ownerDID := "did:web:" + host
+	documents["/.well-known/did.json"] = &Document{
+		Context: StringOrSlice{DIDContext},
+		ID:      ownerDID,
+		VerificationMethod: []VerificationMethod{{
+			ID:           ownerDID + "#owner",
+			Type:         EcdsaSecp256k1VerificationKey2019,
+			Controller:   ownerDID,
+			PublicKeyHex: hex.EncodeToString(crypto.CompressPubkey(ownerKey.PublicKey)),
+		}},
+		AssertionMethod: []VerificationRelationship{{Reference: "#owner"}},
+	}
+	// A document served at the wrong path claims another DID
+	documents["/agents/impostor/did.json"] = documents["/.well-known/did.json"]
+
+	resolver := NewResolver(WithHTTPClient(server.Client()))
+
+	vm, err := ResolveVerificationMethod(context.Background(), resolver, ownerDID+"#owner", "assertionMethod")
+	require.NoError(t, err)
+	address, err := vm.Address()
+	require.NoError(t, err)
+	assert.Equal(t, ownerKey.Address, address)
+
+	_, err = resolver.Resolve(context.Background(), ownerDID+":agents:impostor")
+	assert.ErrorContains(t, err, "does not match")
+
+	_, err = resolver.Resolve(context.Background(), ownerDID+":agents:missing")
+	assert.ErrorContains(t, err, "404")
+
+	// The default client does not connect to loopback or private addresses
+	_, err = NewResolver().Resolve(context.Background(), ownerDID)
+	assert.ErrorContains(t, err, "non-public address 127.0.0.1")
+	for _, address := range []string{"10.0.0.1:443", "192.168.1.1:443", "169.254.169.254:80", "[::1]:443", "0.0.0.0:443"} {
+		assert.Error(t, dialPublic("tcp", address, nil), address)
+	}
+	assert.NoError(t, dialPublic("tcp", "93.184.215.14:443", nil))
+}
+
+// jwkCoordinate encodes a curve coordinate as unpadded base64url
+// jwkCoordinate encodes a coordinate padded to 32 bytes, as RFC 7518 requires
+func jwkCoordinate(n *big.Int) string {
+	return base64.RawURLEncoding.EncodeToString(n.FillBytes(make([]byte, 32)))
+}
@@ -0,0 +1,315 @@
+// Package httpsig signs and verifies HTTP requests with RFC 9421 HTTP Message
+// Signatures made by an agent's secp256k1 key, so a server knows which DID sent
+// each request. Bodies are bound to the signature through an RFC 9530
+// Content-Digest, and an agent's credentials can travel in a signed header.
+package httpsig
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const (
+	// Algorithm is the alg parameter of signatures made by this package: ECDSA
+	// over secp256k1 of the SHA-256 of the signature base. The signature is
+	// [R || S || V] so verifiers can recover the signing address.
+	Algorithm = "ecdsa-secp256k1-sha256"
+
+	// Label is the name signatures are stored under in the Signature and Signature-Input headers
+	Label = "agentid"
+
+	// CredentialHeader carries the base64url-encoded JSON of an agent's authz.Credentials
+	CredentialHeader = "AgentID-Credential"
+)
+
+var (
+	// ErrNoSignature is returned when a request carries no signature from this package
+	ErrNoSignature = errors.New("request is not signed")
+	// ErrInvalidSignature is returned when a signature does not verify or does not cover enough of the request
+	ErrInvalidSignature = errors.New("invalid request signature")
+)
+
+// requiredComponents must be covered by every signature
+var requiredComponents = []string{"@method", "@authority", "@path", "@query"}
+
+// signatureParams are the parameters of a signature, in the order they are serialized
+type signatureParams struct {
+	components []string
+	created    int64
+	expires    int64
+	keyID      string
+	alg        string
+	nonce      string
+	raw        string // Serialization the parameters were parsed from, if any
+}
+
+// String serializes the parameters as an RFC 8941 inner list. Parsed parameters
+// keep the sender's serialization, which is what the sender signed.
+func (p *signatureParams) String() string {
+	if p.raw != "" {
+		return p.raw
+	}
+	var b strings.Builder
+	b.WriteByte('(')
+	for i, component := range p.components {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteString(quote(component))
+	}
+	b.WriteByte(')')
+	fmt.Fprintf(&b, ";created=%d", p.created)
+	if p.expires != 0 {
+		fmt.Fprintf(&b, ";expires=%d", p.expires)
+	}
+	fmt.Fprintf(&b, ";keyid=%s;alg=%s", quote(p.keyID), quote(p.alg))
+	if p.nonce != "" {
+		fmt.Fprintf(&b, ";nonce=%s", quote(p.nonce))
+	}
+	return b.String()
+}
+
+func (p *signatureParams) covers(component string) bool {
+	for _, c := range p.components {
+		if c == component {
+			return true
+		}
+	}
+	return false
+}
+
+// signatureBase builds the RFC 9421 signature base of a request
+func signatureBase(r *http.Request, params *signatureParams) ([]byte, error) {
+	var b strings.Builder
+	seen := make(map[string]bool)
+	for _, component := range params.components {
+		if seen[component] {
+			return nil, fmt.Errorf("component %s is covered twice", component)
+		}
+		seen[component] = true
+
+		value, err := componentValue(r, component)
+		if err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(&b, "%s: %s\n", quote(component), value)
+	}
+	fmt.Fprintf(&b, "%s: %s", quote("@signature-params"), params.String())
+	return []byte(b.String()), nil
+}
+
+// componentValue returns the value of a derived component or header field
+func componentValue(r *http.Request, component string) (string, error) {
+	switch component {
+	case "@method":
+		return strings.ToUpper(r.Method), nil
+	case "@authority":
+		host := r.Host
+		if host == "" && r.URL != nil {
+			host = r.URL.Host
+		}
+		return strings.ToLower(host), nil
+	case "@path":
+		if path := r.URL.EscapedPath(); path != "" {
+			return path, nil
+		}
+		return "/", nil
+	case "@query":
+		return "?" + r.URL.RawQuery, nil
+	}
+	if strings.HasPrefix(component, "@") {
+		return "", fmt.Errorf("unsupported derived component %s", component)
+	}
+	if component != strings.ToLower(component) {
+		return "", fmt.Errorf("header component %s is not lowercase", component)
+	}
+
+	values := r.Header.Values(component)
+	if len(values) == 0 {
+		return "", fmt.Errorf("covered header %s is missing", component)
+	}
+	for i, value := range values {
+		values[i] = strings.TrimSpace(value)
+	}
+	return strings.Join(values, ", "), nil
+}
+
+// contentDigest returns the RFC 9530 Content-Digest of a body
+func contentDigest(body []byte) string {
+	sum := sha256.Sum256(body)
+	return "sha-256=:" + base64.StdEncoding.EncodeToString(sum[:]) + ":"
+}
+
+// quote serializes an RFC 8941 string
+func quote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// dictionaryMember returns the raw value of a member of an RFC 8941 dictionary
+// header, or "" if there is no such member
+func dictionaryMember(header, name string) string {
+	for _, member := range splitDictionary(header) {
+		key, value, _ := strings.Cut(member, "=")
+		if strings.TrimSpace(key) == name {
+			return strings.TrimSpace(value)
+		}
+	}
+	return ""
+}
+
+// splitDictionary splits a dictionary at the commas outside strings and inner lists
+func splitDictionary(header string) []string {
+	var (
+		members []string
+		start   int
+		depth   int
+		quoted  bool
+	)
+	for i := 0; i < len(header); i++ {
+		switch c := header[i]; {
+		case quoted && c == '\\':
+			i++
+		case c == '"':
+			quoted = !quoted
+		case quoted:
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		case c == ',' && depth == 0:
+			members = append(members, header[start:i])
+			start = i + 1
+		}
+	}
+	return append(members, header[start:])
+}
+
+// parseSignatureParams parses a Signature-Input member value
+func parseSignatureParams(raw string) (*signatureParams, error) {
+	p := &parser{s: raw}
+	params := &signatureParams{raw: raw}
+
+	if !p.consume('(') {
+		return nil, fmt.Errorf("signature input is not an inner list")
+	}
+	for {
+		p.skipSpaces()
+		if p.consume(')') {
+			break
+		}
+		component, err := p.string()
+		if err != nil {
+			return nil, err
+		}
+		params.components = append(params.components, component)
+	}
+
+	for p.consume(';') {
+		name := p.key()
+		if !p.consume('=') {
+			return nil, fmt.Errorf("signature parameter %s has no value", name)
+		}
+		var err error
+		switch name {
+		case "created":
+			params.created, err = p.integer()
+		case "expires":
+			params.expires, err = p.integer()
+		case "keyid":
+			params.keyID, err = p.string()
+		case "alg":
+			params.alg, err = p.string()
+		case "nonce":
+			params.nonce, err = p.string()
+		default:
+			return nil, fmt.Errorf("unsupported signature parameter %s", name)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("signature parameter %s: %w", name, err)
+		}
+	}
+	if !p.done() {
+		return nil, fmt.Errorf("unexpected %q in signature input", p.s[p.i:])
+	}
+	return params, nil
+}
+
+// parser reads the RFC 8941 items used in signature inputs
+type parser struct {
+	s string
+	i int
+}
+
+func (p *parser) done() bool {
+	return p.i >= len(p.s)
+}
+
+func (p *parser) consume(c byte) bool {
+	if p.i < len(p.s) && p.s[p.i] == c {
+		p.i++
+		return true
+	}
+	return false
+}
+
+func (p *parser) skipSpaces() {
+	for p.i < len(p.s) && p.s[p.i] == ' ' {
+		p.i++
+	}
+}
+
+func (p *parser) key() string {
+	start := p.i
+	for p.i < len(p.s) && (p.s[p.i] >= 'a' && p.s[p.i] <= 'z' || p.s[p.i] >= '0' && p.s[p.i] <= '9' || strings.IndexByte("_-.*", p.s[p.i]) >= 0) {
+		p.i++
+	}
+	return p.s[start:p.i]
+}
+
+func (p *parser) string() (string, error) {
+	if !p.consume('"') {
+		return "", fmt.Errorf("expected a string")
+	}
+	var b strings.Builder
+	for p.i < len(p.s) {
+		c := p.s[p.i]
+		p.i++
+		switch c {
+		case '"':
+			return b.String(), nil
+		case '\\':
+			if p.i >= len(p.s) || (p.s[p.i] != '"' && p.s[p.i] != '\\') {
+				return "", fmt.Errorf("invalid escape in string")
+			}
+			b.WriteByte(p.s[p.i])
+			p.i++
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return "", fmt.Errorf("unterminated string")
+}
+
+func (p *parser) integer() (int64, error) {
+	start := p.i
+	if p.i < len(p.s) && p.s[p.i] == '-' {
+		p.i++
+	}
+	for p.i < len(p.s) && p.s[p.i] >= '0' && p.s[p.i] <= '9' {
+		p.i++
+	}
+	return strconv.ParseInt(p.s[start:p.i], 10, 64)
+}
+
+// parseByteSequence decodes an RFC 8941 byte sequence, :base64:
+func parseByteSequence(raw string) ([]byte, error) {
+	if len(raw) < 2 || raw[0] != ':' || raw[len(raw)-1] != ':' {
+		return nil, fmt.Errorf("not a byte sequence")
+	}
+	return base64.StdEncoding.DecodeString(raw[1 : len(raw)-1])
+}
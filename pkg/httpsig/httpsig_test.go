@@ -0,0 +1,231 @@
+package httpsig
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ak68a/agentid-core/pkg/authz"
+	"github.com/ak68a/agentid-core/pkg/key"
+	"github.com/ak68a/agentid-core/pkg/models"
+	"github.com/ak68a/agentid-core/pkg/replay"
+	"github.com/ak68a/agentid-core/pkg/signer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newKey(t *testing.T) *key.AgentKey {
+	k, err := key.GenerateAgentKey()
+	require.NoError(t, err)
+	return k
+}
+
+// echo answers with the verified agent's DID and the request body
+var echo = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	agent, ok := AgentFromContext(r.Context())
+	if !ok {
+		http.Error(w, "no agent", http.StatusInternalServerError)
+		return
+	}
+	body, _ := io.ReadAll(r.Body)
+	fmt.Fprintf(w, "%s %s", agent.DID, body)
+})
+
+func get(t *testing.T, client *http.Client, req *http.Request) (int, string) {
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp.StatusCode, string(body)
+}
+
+func TestTransportAndAuthenticate(t *testing.T) {
+	agent := newKey(t)
+	server := httptest.NewServer(NewVerifier().Authenticate(echo))
+	defer server.Close()
+	client := &http.Client{Transport: NewTransport(server.Client().Transport, agent)}
+
+	req, err := http.NewRequest(http.MethodPost, server.URL+"/orders?id=7", strings.NewReader(`{"amount":"10"}`))
+	require.NoError(t, err)
+	status, body := get(t, client, req)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, agent.DID+` {"amount":"10"}`, body)
+
+	req, err = http.NewRequest(http.MethodGet, server.URL+"/orders", nil)
+	require.NoError(t, err)
+	status, body = get(t, client, req)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, agent.DID+" ", body)
+
+	// The caller's request is left untouched
+	assert.Empty(t, req.Header.Get("Signature"))
+
+	req, err = http.NewRequest(http.MethodGet, server.URL+"/orders", nil)
+	require.NoError(t, err)
+	status, _ = get(t, server.Client(), req)
+	assert.Equal(t, http.StatusUnauthorized, status)
+}
+
+func TestVerifyRejectsTampering(t *testing.T) {
+	agent := newKey(t)
+	other := newKey(t)
+	server := httptest.NewServer(NewVerifier().Authenticate(echo))
+	defer server.Close()
+
+	sign := func(t *testing.T, opts ...SignOption) *http.Request {
+		req, err := http.NewRequest(http.MethodPost, server.URL+"/orders?id=7", strings.NewReader("pay 10"))
+		require.NoError(t, err)
+		require.NoError(t, SignRequest(context.Background(), req, agent, opts...))
+		return req
+	}
+
+	tests := []struct {
+		name   string
+		modify func(req *http.Request)
+		opts   []SignOption
+	}{
+		{"path", func(req *http.Request) { req.URL.Path = "/refunds" }, nil},
+		{"query", func(req *http.Request) { req.URL.RawQuery = "id=8" }, nil},
+		{"method", func(req *http.Request) { req.Method = http.MethodPut }, nil},
+		{"body", func(req *http.Request) { req.Body = io.NopCloser(strings.NewReader("pay 99")); req.ContentLength = 6 }, nil},
+		{"key of another agent", nil, []SignOption{WithKeyID(other.DID + "#key-1")}},
+		{"expired", nil, []SignOption{WithSignClock(func() time.Time { return time.Now().Add(-time.Hour) })}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := sign(t, tt.opts...)
+			if tt.modify != nil {
+				tt.modify(req)
+			}
+			status, body := get(t, server.Client(), req)
+			assert.Equal(t, http.StatusUnauthorized, status, body)
+			assert.Contains(t, body, "invalid request signature")
+		})
+	}
+
+	status, _ := get(t, server.Client(), sign(t))
+	assert.Equal(t, http.StatusOK, status)
+}
+
+func TestVerifyMaxBodySize(t *testing.T) {
+	agent := newKey(t)
+	server := httptest.NewServer(NewVerifier(WithMaxBodySize(8)).Authenticate(echo))
+	defer server.Close()
+	client := &http.Client{Transport: NewTransport(server.Client().Transport, agent)}
+
+	req, err := http.NewRequest(http.MethodPost, server.URL+"/orders", strings.NewReader("pay 10"))
+	require.NoError(t, err)
+	status, _ := get(t, client, req)
+	assert.Equal(t, http.StatusOK, status)
+
+	req, err = http.NewRequest(http.MethodPost, server.URL+"/orders", strings.NewReader("pay 1000000"))
+	require.NoError(t, err)
+	status, body := get(t, client, req)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, status)
+	assert.Contains(t, body, "exceeds 8 bytes")
+
+	// Verify enforces the limit without the middleware too
+	req, err = http.NewRequest(http.MethodPost, server.URL+"/orders", strings.NewReader("pay 1000000"))
+	require.NoError(t, err)
+	require.NoError(t, SignRequest(context.Background(), req, agent))
+	_, err = NewVerifier(WithMaxBodySize(8)).Verify(req)
+	var tooLarge *http.MaxBytesError
+	assert.ErrorAs(t, err, &tooLarge)
+}
+
+func TestVerifyReplay(t *testing.T) {
+	agent := newKey(t)
+	server := httptest.NewServer(NewVerifier(WithNonceStore(replay.NewMemoryStore())).Authenticate(echo))
+	defer server.Close()
+
+	req, err := http.NewRequest(http.MethodGet, server.URL+"/orders", nil)
+	require.NoError(t, err)
+	require.NoError(t, SignRequest(context.Background(), req, agent))
+
+	status, _ := get(t, server.Client(), req)
+	assert.Equal(t, http.StatusOK, status)
+	status, body := get(t, server.Client(), req)
+	assert.Equal(t, http.StatusUnauthorized, status)
+	assert.Contains(t, body, "already been used")
+}
+
+func TestRequire(t *testing.T) {
+	owner, agent, responder := newKey(t), newKey(t), newKey(t)
+	claim := models.NewTransferClaim(agent.DID, owner.DID, models.ScopeETH, "1000", time.Now().Add(time.Hour).Unix(), "claim-1")
+	require.NoError(t, signer.NewClaimSigner(owner).SignCredential(claim))
+	creds := &authz.Credentials{Authorization: claim}
+
+	verifier := NewVerifier()
+	engine := authz.NewEngine(responder, authz.WithTrustedIssuers(owner.DID))
+	var decision *models.AuthorizationResponse
+	mux := http.NewServeMux()
+	mux.Handle("/transfer", verifier.Require(engine, models.ActionTransfer, models.ScopeETH)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		agent, _ := AgentFromContext(r.Context())
+		decision = agent.Authorization
+	})))
+	mux.Handle("/booking", verifier.Require(engine, models.ActionBooking, "flights")(echo))
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	withCreds := &http.Client{Transport: NewTransport(server.Client().Transport, agent, WithCredentials(creds))}
+	withoutCreds := &http.Client{Transport: NewTransport(server.Client().Transport, agent)}
+	// An agent presenting a credential issued to someone else
+	impostor := &http.Client{Transport: NewTransport(server.Client().Transport, newKey(t), WithCredentials(creds))}
+
+	tests := []struct {
+		name   string
+		client *http.Client
+		path   string
+		status int
+	}{
+		{"granted", withCreds, "/transfer", http.StatusOK},
+		{"action not granted", withCreds, "/booking", http.StatusForbidden},
+		{"no credentials", withoutCreds, "/transfer", http.StatusForbidden},
+		{"credential of another agent", impostor, "/transfer", http.StatusForbidden},
+		{"unsigned", server.Client(), "/transfer", http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodPost, server.URL+tt.path, nil)
+			require.NoError(t, err)
+			status, body := get(t, tt.client, req)
+			assert.Equal(t, tt.status, status, body)
+		})
+	}
+	require.NotNil(t, decision)
+	assert.True(t, decision.Authorized)
+	assert.Equal(t, responder.DID, decision.ResponderDID)
+}
+
+func TestSignatureParams(t *testing.T) {
+	params := &signatureParams{
+		components: []string{"@method", "@path", "content-digest"},
+		created:    1700000000,
+		expires:    1700000300,
+		keyID:      `did:ackid:0xabc#key-1`,
+		alg:        Algorithm,
+		nonce:      `a"b\c`,
+	}
+	serialized := params.String()
+	assert.Equal(t, `("@method" "@path" "content-digest");created=1700000000;expires=1700000300;keyid="did:ackid:0xabc#key-1";alg="ecdsa-secp256k1-sha256";nonce="a\"b\\c"`, serialized)
+
+	header := `other=("@method");created=1;keyid="x, y";alg="z", ` + Label + `=` + serialized
+	parsed, err := parseSignatureParams(dictionaryMember(header, Label))
+	require.NoError(t, err)
+	assert.Equal(t, params.components, parsed.components)
+	assert.Equal(t, params.created, parsed.created)
+	assert.Equal(t, params.expires, parsed.expires)
+	assert.Equal(t, params.keyID, parsed.keyID)
+	assert.Equal(t, params.nonce, parsed.nonce)
+
+	for _, input := range []string{`"@method"`, `("@method";created=1`, `("@method");created=x`, `("@method");unknown=1`} {
+		_, err := parseSignatureParams(input)
+		assert.Error(t, err, input)
+	}
+}
@@ -0,0 +1,83 @@
+package httpsig
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/ak68a/agentid-core/pkg/authz"
+	"github.com/ak68a/agentid-core/pkg/models"
+)
+
+// Authenticate returns middleware that rejects requests without a valid
+// signature with 401 Unauthorized, and bodies over the maximum size with 413
+// Request Entity Too Large, and passes the signing agent to next in the
+// request context (see AgentFromContext)
+func (v *Verifier) Authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Body != nil {
+			r.Body = http.MaxBytesReader(w, r.Body, v.maxBodySize)
+		}
+		agent, err := v.Verify(r)
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			writeError(w, http.StatusRequestEntityTooLarge, fmt.Errorf("request body exceeds %d bytes", tooLarge.Limit))
+			return
+		}
+		if err != nil {
+			writeError(w, http.StatusUnauthorized, err)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(WithAgent(r.Context(), agent)))
+	})
+}
+
+// Require returns middleware that, on top of Authenticate, has engine decide
+// whether the credentials in the request's CredentialHeader grant the signing
+// agent action on scope. Requests without credentials, or whose credentials
+// fall short, are rejected with 403 Forbidden; the decision is passed to next
+// as the agent's Authorization.
+func (v *Verifier) Require(engine *authz.Engine, action, scope string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return v.Authenticate(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			agent, _ := AgentFromContext(r.Context())
+			creds, err := credentials(r)
+			if err != nil {
+				writeError(w, http.StatusBadRequest, err)
+				return
+			}
+			if creds == nil {
+				writeError(w, http.StatusForbidden, fmt.Errorf("no %s header", CredentialHeader))
+				return
+			}
+
+			req := &models.AuthorizationRequest{
+				AgentDID:     agent.DID,
+				TargetAction: action,
+				TargetScope:  scope,
+				RequesterDID: agent.DID,
+				Timestamp:    agent.Created.Unix(),
+				Nonce:        agent.Nonce,
+				RequestID:    r.Header.Get("X-Request-ID"),
+			}
+			response, err := engine.Authorize(r.Context(), req, creds)
+			if err != nil {
+				writeError(w, http.StatusInternalServerError, err)
+				return
+			}
+			if !response.Authorized {
+				writeError(w, http.StatusForbidden, errors.New(response.Reason))
+				return
+			}
+			agent.Authorization = response
+			next.ServeHTTP(w, r)
+		}))
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+}
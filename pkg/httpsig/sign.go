@@ -0,0 +1,161 @@
+package httpsig
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/ak68a/agentid-core/pkg/authz"
+	"github.com/ak68a/agentid-core/pkg/key"
+)
+
+// DefaultSignatureTTL is how long a signature stays valid unless WithSignatureTTL is given
+const DefaultSignatureTTL = 5 * time.Minute
+
+// SignOption configures how requests are signed
+type SignOption func(*signOptions)
+
+type signOptions struct {
+	keyID       string
+	credentials *authz.Credentials
+	ttl         time.Duration
+	now         func() time.Time
+}
+
+// WithKeyID sets the verification method named in signatures. It defaults to
+// the signer's DID with the #key-1 fragment, the method of did:ackid documents.
+func WithKeyID(keyID string) SignOption {
+	return func(o *signOptions) {
+		o.keyID = keyID
+	}
+}
+
+// WithCredentials attaches the agent's credentials to every request in the CredentialHeader
+func WithCredentials(credentials *authz.Credentials) SignOption {
+	return func(o *signOptions) {
+		o.credentials = credentials
+	}
+}
+
+// WithSignatureTTL sets how long after its creation a signature expires
+func WithSignatureTTL(ttl time.Duration) SignOption {
+	return func(o *signOptions) {
+		o.ttl = ttl
+	}
+}
+
+// WithSignClock sets the time source used for the created and expires parameters
+func WithSignClock(now func() time.Time) SignOption {
+	return func(o *signOptions) {
+		o.now = now
+	}
+}
+
+func newSignOptions(signer key.Signer, opts []SignOption) *signOptions {
+	o := &signOptions{keyID: signer.GetDID() + "#key-1", ttl: DefaultSignatureTTL, now: time.Now}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// SignRequest signs r in place. The signature covers the method, authority,
+// path and query, the Content-Digest of the body if there is one, and the
+// credential header if credentials are attached.
+func SignRequest(ctx context.Context, r *http.Request, signer key.Signer, opts ...SignOption) error {
+	return signRequest(ctx, r, signer, newSignOptions(signer, opts))
+}
+
+func signRequest(ctx context.Context, r *http.Request, signer key.Signer, o *signOptions) error {
+	components := append([]string(nil), requiredComponents...)
+
+	if r.Body != nil && r.Body != http.NoBody {
+		body, err := io.ReadAll(r.Body)
+		r.Body.Close()
+		if err != nil {
+			return fmt.Errorf("failed to read request body: %w", err)
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		r.GetBody = func() (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(body)), nil
+		}
+		r.Header.Set("Content-Digest", contentDigest(body))
+		components = append(components, "content-digest")
+	}
+
+	if o.credentials != nil {
+		data, err := json.Marshal(o.credentials)
+		if err != nil {
+			return fmt.Errorf("failed to encode credentials: %w", err)
+		}
+		r.Header.Set(CredentialHeader, base64.RawURLEncoding.EncodeToString(data))
+		components = append(components, "agentid-credential")
+	}
+
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	created := o.now()
+	params := &signatureParams{
+		components: components,
+		created:    created.Unix(),
+		keyID:      o.keyID,
+		alg:        Algorithm,
+		nonce:      hex.EncodeToString(nonce),
+	}
+	if o.ttl > 0 {
+		params.expires = created.Add(o.ttl).Unix()
+	}
+
+	base, err := signatureBase(r, params)
+	if err != nil {
+		return err
+	}
+	hash := sha256.Sum256(base)
+	signature, err := signer.SignHash(ctx, hash[:])
+	if err != nil {
+		return fmt.Errorf("failed to sign request: %w", err)
+	}
+
+	r.Header.Set("Signature-Input", Label+"="+params.String())
+	r.Header.Set("Signature", Label+"=:"+base64.StdEncoding.EncodeToString(signature)+":")
+	return nil
+}
+
+// Transport is an http.RoundTripper that signs every request with an agent's key
+type Transport struct {
+	base   http.RoundTripper
+	signer key.Signer
+	opts   *signOptions
+}
+
+// NewTransport creates a Transport that signs requests before passing them to
+// base, or to http.DefaultTransport if base is nil
+func NewTransport(base http.RoundTripper, signer key.Signer, opts ...SignOption) *Transport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &Transport{base: base, signer: signer, opts: newSignOptions(signer, opts)}
+}
+
+// RoundTrip signs a copy of the request and sends it
+func (t *Transport) RoundTrip(r *http.Request) (*http.Response, error) {
+	// A RoundTripper must not modify the caller's request
+	signed := r.Clone(r.Context())
+	if err := signRequest(r.Context(), signed, t.signer, t.opts); err != nil {
+		if r.Body != nil {
+			r.Body.Close()
+		}
+		return nil, err
+	}
+	return t.base.RoundTrip(signed)
+}
@@ -0,0 +1,261 @@
+package httpsig
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ak68a/agentid-core/pkg/authz"
+	"github.com/ak68a/agentid-core/pkg/did"
+	"github.com/ak68a/agentid-core/pkg/models"
+	"github.com/ak68a/agentid-core/pkg/replay"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// DefaultMaxAge is how far a signature's creation time may be from the
+// verifier's clock unless WithMaxAge is given
+const DefaultMaxAge = 5 * time.Minute
+
+// DefaultMaxBodySize is the largest request body read to check its digest
+// unless WithMaxBodySize is given
+const DefaultMaxBodySize = 1 << 20
+
+// Agent is the verified sender of a request
+type Agent struct {
+	DID     string    // DID that signed the request
+	KeyID   string    // Verification method the request was signed with
+	Created time.Time // When the request was signed
+	Nonce   string    // Nonce of the signature
+
+	// Authorization is the signed decision on the agent's credentials, set by
+	// Verifier.Require
+	Authorization *models.AuthorizationResponse
+}
+
+// Verifier checks the signatures of inbound requests
+type Verifier struct {
+	resolver    did.Resolver
+	maxAge      time.Duration
+	maxBodySize int64
+	nonces      replay.NonceStore
+	now         func() time.Time
+}
+
+// Option configures a Verifier
+type Option func(*Verifier)
+
+// WithResolver sets the DID resolver used to find the keys allowed to sign for a DID
+func WithResolver(resolver did.Resolver) Option {
+	return func(v *Verifier) {
+		v.resolver = resolver
+	}
+}
+
+// WithMaxAge sets how far a signature's creation time may be from the verifier's clock
+func WithMaxAge(maxAge time.Duration) Option {
+	return func(v *Verifier) {
+		v.maxAge = maxAge
+	}
+}
+
+// WithMaxBodySize sets the largest request body accepted, in bytes. The whole
+// body is held in memory to check its digest.
+func WithMaxBodySize(size int64) Option {
+	return func(v *Verifier) {
+		v.maxBodySize = size
+	}
+}
+
+// WithNonceStore makes signatures single-use by recording their nonces, per
+// key, for as long as they would otherwise be accepted
+func WithNonceStore(store replay.NonceStore) Option {
+	return func(v *Verifier) {
+		v.nonces = store
+	}
+}
+
+// WithClock sets the time source used for the creation and expiry checks
+func WithClock(now func() time.Time) Option {
+	return func(v *Verifier) {
+		v.now = now
+	}
+}
+
+// NewVerifier creates a Verifier
+func NewVerifier(opts ...Option) *Verifier {
+	v := &Verifier{
+		resolver:    did.NewResolver(),
+		maxAge:      DefaultMaxAge,
+		maxBodySize: DefaultMaxBodySize,
+		now:         time.Now,
+	}
+	for _, opt := range opts {
+		opt(v)
+	}
+	return v
+}
+
+// Verify checks the request's signature and returns the agent that made it.
+// The body, if any, is read to check its Content-Digest and then restored.
+func (v *Verifier) Verify(r *http.Request) (*Agent, error) {
+	input := dictionaryMember(r.Header.Get("Signature-Input"), Label)
+	rawSignature := dictionaryMember(r.Header.Get("Signature"), Label)
+	if input == "" || rawSignature == "" {
+		return nil, ErrNoSignature
+	}
+	params, err := parseSignatureParams(input)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+	if err := v.checkParams(r, params); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+	if err := v.checkDigest(r, params); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidSignature, err)
+	}
+
+	signature, err := parseByteSequence(rawSignature)
+	if err != nil || len(signature) != 65 {
+		return nil, fmt.Errorf("%w: malformed signature", ErrInvalidSignature)
+	}
+	base, err := signatureBase(r, params)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+	hash := sha256.Sum256(base)
+	pub, err := crypto.SigToPub(hash[:], signature)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+
+	vm, err := did.ResolveVerificationMethod(r.Context(), v.resolver, params.keyID, string(models.Authentication))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+	address, err := vm.Address()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+	if address != crypto.PubkeyToAddress(*pub) {
+		return nil, fmt.Errorf("%w: not signed by %s", ErrInvalidSignature, params.keyID)
+	}
+
+	created := time.Unix(params.created, 0)
+	if v.nonces != nil {
+		// Scoped by key, so the nonce can still be recorded per agent by an authz.Engine
+		if err := v.nonces.Record(r.Context(), params.keyID, params.nonce, v.retainUntil(params)); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+		}
+	}
+
+	agentDID, _, _ := strings.Cut(params.keyID, "#")
+	return &Agent{DID: agentDID, KeyID: params.keyID, Created: created, Nonce: params.nonce}, nil
+}
+
+// checkParams checks the algorithm, freshness and coverage of a signature
+func (v *Verifier) checkParams(r *http.Request, params *signatureParams) error {
+	if params.alg != Algorithm {
+		return fmt.Errorf("unsupported algorithm %q", params.alg)
+	}
+	if params.keyID == "" {
+		return fmt.Errorf("signature has no keyid")
+	}
+	if v.nonces != nil && params.nonce == "" {
+		return fmt.Errorf("signature has no nonce")
+	}
+
+	now := v.now()
+	if err := replay.CheckTimestamp(params.created, now, v.maxAge); err != nil {
+		return fmt.Errorf("created %w", err)
+	}
+	if params.expires != 0 && now.Unix() > params.expires {
+		return fmt.Errorf("signature expired at %d", params.expires)
+	}
+
+	for _, component := range requiredComponents {
+		if !params.covers(component) {
+			return fmt.Errorf("signature does not cover %s", component)
+		}
+	}
+	if r.Header.Get(CredentialHeader) != "" && !params.covers("agentid-credential") {
+		return fmt.Errorf("signature does not cover the credential header")
+	}
+	return nil
+}
+
+// retainUntil returns how long a signature's nonce must be kept: until the
+// signature expires or leaves the maximum age window, whichever is first
+func (v *Verifier) retainUntil(params *signatureParams) time.Time {
+	until := time.Unix(params.created, 0).Add(v.maxAge)
+	if params.expires != 0 && time.Unix(params.expires, 0).Before(until) {
+		until = time.Unix(params.expires, 0)
+	}
+	return until.Add(time.Second)
+}
+
+// checkDigest requires a body to be covered by a matching Content-Digest. A
+// body over the maximum size fails with an *http.MaxBytesError.
+func (v *Verifier) checkDigest(r *http.Request, params *signatureParams) error {
+	var body []byte
+	if r.Body != nil && r.Body != http.NoBody {
+		var err error
+		body, err = io.ReadAll(io.LimitReader(r.Body, v.maxBodySize+1))
+		r.Body.Close()
+		if err == nil && int64(len(body)) > v.maxBodySize {
+			err = &http.MaxBytesError{Limit: v.maxBodySize}
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read request body: %w", err)
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	if !params.covers("content-digest") {
+		if len(body) > 0 {
+			return fmt.Errorf("signature does not cover the content digest")
+		}
+		return nil
+	}
+	expected := dictionaryMember(contentDigest(body), "sha-256")
+	if dictionaryMember(r.Header.Get("Content-Digest"), "sha-256") != expected {
+		return fmt.Errorf("content digest does not match the body")
+	}
+	return nil
+}
+
+// credentials decodes the credentials attached to a request, or returns nil if there are none
+func credentials(r *http.Request) (*authz.Credentials, error) {
+	header := r.Header.Get(CredentialHeader)
+	if header == "" {
+		return nil, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(header)
+	if err != nil {
+		return nil, fmt.Errorf("malformed %s header: %w", CredentialHeader, err)
+	}
+	var creds authz.Credentials
+	if err := json.Unmarshal(data, &creds); err != nil {
+		return nil, fmt.Errorf("malformed %s header: %w", CredentialHeader, err)
+	}
+	return &creds, nil
+}
+
+type contextKey struct{}
+
+// WithAgent returns a copy of ctx carrying the verified agent
+func WithAgent(ctx context.Context, agent *Agent) context.Context {
+	return context.WithValue(ctx, contextKey{}, agent)
+}
+
+// AgentFromContext returns the agent verified by the middleware, if any
+func AgentFromContext(ctx context.Context) (*Agent, bool) {
+	agent, ok := ctx.Value(contextKey{}).(*Agent)
+	return agent, ok
+}
@@ -0,0 +1,250 @@
+// Package jcs implements the JSON Canonicalization Scheme (JCS) defined in RFC 8785.
+//
+// Canonical JSON has no insignificant whitespace, object members sorted by their
+// UTF-16 code units, strings in their minimal escaped form and numbers serialized
+// the way ECMAScript's Number.prototype.toString does. Any implementation that
+// follows the RFC produces byte-identical output, so hashes over it can be
+// reproduced outside of Go.
+package jcs
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// Marshal returns the canonical JSON encoding of v
+func Marshal(v interface{}) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return Transform(data)
+}
+
+// Transform canonicalizes a JSON document
+func Transform(data []byte) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var buf bytes.Buffer
+	if err := transformValue(dec, &buf); err != nil {
+		return nil, err
+	}
+
+	// Only whitespace may follow the document
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, errors.New("jcs: unexpected data after top-level value")
+	}
+	return buf.Bytes(), nil
+}
+
+// FormatNumber serializes a float64 following ECMAScript Number.prototype.toString
+func FormatNumber(f float64) (string, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return "", fmt.Errorf("jcs: %v is not a valid JSON number", f)
+	}
+	if f == 0 {
+		return "0", nil // Also covers -0
+	}
+
+	sign := ""
+	if f < 0 {
+		sign = "-"
+		f = -f
+	}
+
+	// Shortest round-tripping digits, as d.ddde±x
+	mantissa, exp, _ := strings.Cut(strconv.FormatFloat(f, 'e', -1, 64), "e")
+	digits := strings.Replace(mantissa, ".", "", 1)
+	e, err := strconv.Atoi(exp)
+	if err != nil {
+		return "", err
+	}
+
+	// ECMAScript describes the value as 0.digits × 10^n
+	k := len(digits)
+	n := e + 1
+
+	var out string
+	switch {
+	case k <= n && n <= 21:
+		out = digits + strings.Repeat("0", n-k)
+	case 0 < n && n <= 21:
+		out = digits[:n] + "." + digits[n:]
+	case -6 < n && n <= 0:
+		out = "0." + strings.Repeat("0", -n) + digits
+	default:
+		expSign := "+"
+		if n-1 < 0 {
+			expSign = "-"
+		}
+		exponent := strconv.Itoa(abs(n - 1))
+		if k == 1 {
+			out = digits + "e" + expSign + exponent
+		} else {
+			out = digits[:1] + "." + digits[1:] + "e" + expSign + exponent
+		}
+	}
+	return sign + out, nil
+}
+
+func transformValue(dec *json.Decoder, buf *bytes.Buffer) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return fmt.Errorf("jcs: %w", err)
+	}
+
+	switch v := tok.(type) {
+	case json.Delim:
+		switch v {
+		case '{':
+			return transformObject(dec, buf)
+		case '[':
+			return transformArray(dec, buf)
+		default:
+			return fmt.Errorf("jcs: unexpected delimiter %q", v)
+		}
+	case json.Number:
+		f, err := strconv.ParseFloat(v.String(), 64)
+		if err != nil {
+			return fmt.Errorf("jcs: invalid number %s: %w", v, err)
+		}
+		s, err := FormatNumber(f)
+		if err != nil {
+			return err
+		}
+		buf.WriteString(s)
+	case string:
+		writeString(buf, v)
+	case bool:
+		buf.WriteString(strconv.FormatBool(v))
+	case nil:
+		buf.WriteString("null")
+	default:
+		return fmt.Errorf("jcs: unexpected token %v", tok)
+	}
+	return nil
+}
+
+func transformObject(dec *json.Decoder, buf *bytes.Buffer) error {
+	type member struct {
+		key   string
+		value []byte
+	}
+	var members []member
+	seen := make(map[string]bool)
+
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return fmt.Errorf("jcs: %w", err)
+		}
+		key, ok := tok.(string)
+		if !ok {
+			return fmt.Errorf("jcs: invalid object key %v", tok)
+		}
+		if seen[key] {
+			return fmt.Errorf("jcs: duplicate object key %q", key)
+		}
+		seen[key] = true
+
+		var value bytes.Buffer
+		if err := transformValue(dec, &value); err != nil {
+			return err
+		}
+		members = append(members, member{key: key, value: value.Bytes()})
+	}
+	if _, err := dec.Token(); err != nil {
+		return fmt.Errorf("jcs: %w", err)
+	}
+
+	sort.Slice(members, func(i, j int) bool {
+		return lessUTF16(members[i].key, members[j].key)
+	})
+
+	buf.WriteByte('{')
+	for i, m := range members {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		writeString(buf, m.key)
+		buf.WriteByte(':')
+		buf.Write(m.value)
+	}
+	buf.WriteByte('}')
+	return nil
+}
+
+func transformArray(dec *json.Decoder, buf *bytes.Buffer) error {
+	buf.WriteByte('[')
+	for i := 0; dec.More(); i++ {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		if err := transformValue(dec, buf); err != nil {
+			return err
+		}
+	}
+	if _, err := dec.Token(); err != nil {
+		return fmt.Errorf("jcs: %w", err)
+	}
+	buf.WriteByte(']')
+	return nil
+}
+
+// writeString writes s with the minimal escaping required by RFC 8785
+func writeString(buf *bytes.Buffer, s string) {
+	buf.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			buf.WriteString(`\"`)
+		case '\\':
+			buf.WriteString(`\\`)
+		case '\b':
+			buf.WriteString(`\b`)
+		case '\f':
+			buf.WriteString(`\f`)
+		case '\n':
+			buf.WriteString(`\n`)
+		case '\r':
+			buf.WriteString(`\r`)
+		case '\t':
+			buf.WriteString(`\t`)
+		default:
+			if r < 0x20 {
+				fmt.Fprintf(buf, `\u%04x`, r)
+			} else {
+				buf.WriteRune(r)
+			}
+		}
+	}
+	buf.WriteByte('"')
+}
+
+// lessUTF16 orders strings by their UTF-16 code units
+func lessUTF16(a, b string) bool {
+	ua := utf16.Encode([]rune(a))
+	ub := utf16.Encode([]rune(b))
+	for i := 0; i < len(ua) && i < len(ub); i++ {
+		if ua[i] != ub[i] {
+			return ua[i] < ub[i]
+		}
+	}
+	return len(ua) < len(ub)
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
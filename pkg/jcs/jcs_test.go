@@ -0,0 +1,107 @@
+package jcs
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/hex"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestTransformVectors runs the cross-language vectors in testdata/input,
+// comparing against the canonical bytes in testdata/output
+func TestTransformVectors(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join("testdata", "input", "*.json"))
+	require.NoError(t, err)
+	require.NotEmpty(t, files)
+
+	for _, file := range files {
+		name := filepath.Base(file)
+		t.Run(name, func(t *testing.T) {
+			input, err := os.ReadFile(file)
+			require.NoError(t, err)
+			expected, err := os.ReadFile(filepath.Join("testdata", "output", name))
+			require.NoError(t, err)
+
+			actual, err := Transform(input)
+			require.NoError(t, err)
+			assert.Equal(t, string(expected), string(actual))
+
+			// Canonical output is a fixed point
+			again, err := Transform(actual)
+			require.NoError(t, err)
+			assert.Equal(t, actual, again)
+		})
+	}
+}
+
+// TestFormatNumberVectors checks the IEEE-754 vectors from RFC 8785 Appendix B
+func TestFormatNumberVectors(t *testing.T) {
+	f, err := os.Open(filepath.Join("testdata", "numbers.txt"))
+	require.NoError(t, err)
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		bits, expected, ok := strings.Cut(line, ",")
+		require.True(t, ok, "malformed vector %q", line)
+
+		raw, err := hex.DecodeString(bits)
+		require.NoError(t, err)
+		value := math.Float64frombits(binary.BigEndian.Uint64(raw))
+
+		actual, err := FormatNumber(value)
+		require.NoError(t, err)
+		assert.Equal(t, expected, actual, "bits %s", bits)
+	}
+	require.NoError(t, scanner.Err())
+}
+
+func TestFormatNumberRejectsNonFinite(t *testing.T) {
+	for _, f := range []float64{math.NaN(), math.Inf(1), math.Inf(-1)} {
+		_, err := FormatNumber(f)
+		assert.Error(t, err)
+	}
+}
+
+func TestTransformErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"duplicate key", `{"a":1,"a":2}`},
+		{"trailing data", `{"a":1} {}`},
+		{"truncated", `{"a":`},
+		{"out of range number", `1e400`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Transform([]byte(tt.input))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	value := map[string]interface{}{
+		"b":      []int{3, 2, 1},
+		"a":      1.50,
+		"nested": map[string]string{"z": "<&>", "y": " "},
+	}
+
+	actual, err := Marshal(value)
+	require.NoError(t, err)
+	// encoding/json's HTML escaping must be undone
+	assert.Equal(t, "{\"a\":1.5,\"b\":[3,2,1],\"nested\":{\"y\":\" \",\"z\":\"<&>\"}}", string(actual))
+}
@@ -0,0 +1,162 @@
+package key
+
+import (
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/tyler-smith/go-bip39"
+)
+
+// DefaultAgentDerivationPath is the BIP-44 Ethereum path under which agent i is m/44'/60'/0'/0/i
+const DefaultAgentDerivationPath = "m/44'/60'/0'/0"
+
+// hardenedOffset is added to an index to select hardened derivation
+const hardenedOffset = 0x80000000
+
+// masterKeySalt is the HMAC key BIP-32 uses to derive the master key from a seed
+var masterKeySalt = []byte("Bitcoin seed")
+
+// NewMnemonic generates a BIP-39 mnemonic from bits of entropy (128 to 256, a multiple of 32)
+func NewMnemonic(bits int) (string, error) {
+	entropy, err := bip39.NewEntropy(bits)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate entropy: %w", err)
+	}
+	return bip39.NewMnemonic(entropy)
+}
+
+// ValidateMnemonic reports whether a mnemonic has valid words and checksum
+func ValidateMnemonic(mnemonic string) bool {
+	return bip39.IsMnemonicValid(normalizeMnemonic(mnemonic))
+}
+
+// HDWallet derives agent keys from a single BIP-32 seed
+type HDWallet struct {
+	master   *extendedKey
+	basePath accounts.DerivationPath
+}
+
+// NewHDWalletFromMnemonic creates a wallet from a BIP-39 mnemonic and optional passphrase
+func NewHDWalletFromMnemonic(mnemonic, passphrase string) (*HDWallet, error) {
+	mnemonic = normalizeMnemonic(mnemonic)
+	seed, err := bip39.NewSeedWithErrorChecking(mnemonic, passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("invalid mnemonic: %w", err)
+	}
+	return NewHDWalletFromSeed(seed)
+}
+
+// NewHDWalletFromSeed creates a wallet from a raw BIP-32 seed (16 to 64 bytes)
+func NewHDWalletFromSeed(seed []byte) (*HDWallet, error) {
+	if len(seed) < 16 || len(seed) > 64 {
+		return nil, fmt.Errorf("seed must be 16 to 64 bytes, got %d", len(seed))
+	}
+
+	master, err := newExtendedKey(hmacSHA512(masterKeySalt, seed))
+	if err != nil {
+		return nil, fmt.Errorf("invalid master key: %w", err)
+	}
+	basePath, _ := accounts.ParseDerivationPath(DefaultAgentDerivationPath)
+	return &HDWallet{master: master, basePath: basePath}, nil
+}
+
+// SetBasePath changes the path under which DeriveAgent appends the agent index
+func (w *HDWallet) SetBasePath(path string) error {
+	basePath, err := accounts.ParseDerivationPath(path)
+	if err != nil {
+		return fmt.Errorf("invalid derivation path: %w", err)
+	}
+	w.basePath = basePath
+	return nil
+}
+
+// Derive returns the agent key at a derivation path such as m/44'/60'/0'/0/0
+func (w *HDWallet) Derive(path string) (*AgentKey, error) {
+	derivationPath, err := accounts.ParseDerivationPath(path)
+	if err != nil {
+		return nil, fmt.Errorf("invalid derivation path: %w", err)
+	}
+	return w.derivePath(derivationPath)
+}
+
+// DeriveAgent returns the agent key at index under the wallet's base path
+func (w *HDWallet) DeriveAgent(index uint32) (*AgentKey, error) {
+	path := make(accounts.DerivationPath, len(w.basePath), len(w.basePath)+1)
+	copy(path, w.basePath)
+	return w.derivePath(append(path, index))
+}
+
+func (w *HDWallet) derivePath(path accounts.DerivationPath) (*AgentKey, error) {
+	k := w.master
+	for _, index := range path {
+		child, err := k.child(index)
+		if err != nil {
+			return nil, fmt.Errorf("failed to derive %s: %w", path, err)
+		}
+		k = child
+	}
+	return fromPrivateKey(k.privateKey), nil
+}
+
+// extendedKey is a BIP-32 private key with its chain code
+type extendedKey struct {
+	privateKey *ecdsa.PrivateKey
+	chainCode  []byte
+}
+
+// newExtendedKey splits a 64-byte HMAC output into key and chain code
+func newExtendedKey(i []byte) (*extendedKey, error) {
+	privateKey, err := crypto.ToECDSA(i[:32])
+	if err != nil {
+		return nil, err
+	}
+	return &extendedKey{privateKey: privateKey, chainCode: i[32:]}, nil
+}
+
+// child derives the private child key at index (hardened if index >= 2^31)
+func (k *extendedKey) child(index uint32) (*extendedKey, error) {
+	data := make([]byte, 0, 37)
+	if index >= hardenedOffset {
+		data = append(data, 0x00)
+		data = append(data, crypto.FromECDSA(k.privateKey)...)
+	} else {
+		data = append(data, crypto.CompressPubkey(&k.privateKey.PublicKey)...)
+	}
+	data = binary.BigEndian.AppendUint32(data, index)
+
+	i := hmacSHA512(k.chainCode, data)
+
+	// The child key is (IL + k) mod n; BIP-32 rejects IL >= n and a zero key
+	n := crypto.S256().Params().N
+	il := new(big.Int).SetBytes(i[:32])
+	if il.Cmp(n) >= 0 {
+		return nil, fmt.Errorf("invalid child key at index %d", index)
+	}
+	childKey := il.Add(il, k.privateKey.D)
+	childKey.Mod(childKey, n)
+	if childKey.Sign() == 0 {
+		return nil, fmt.Errorf("invalid child key at index %d", index)
+	}
+
+	keyBytes := make([]byte, 32)
+	childKey.FillBytes(keyBytes)
+	return newExtendedKey(append(keyBytes, i[32:]...))
+}
+
+func hmacSHA512(key, data []byte) []byte {
+	mac := hmac.New(sha512.New, key)
+	mac.Write(data)
+	return mac.Sum(nil)
+}
+
+// normalizeMnemonic collapses whitespace so phrases read from files still validate
+func normalizeMnemonic(mnemonic string) string {
+	return strings.Join(strings.Fields(mnemonic), " ")
+}
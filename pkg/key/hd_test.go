@@ -0,0 +1,118 @@
+package key
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+// Hardhat and Anvil's default development mnemonic
+const testMnemonic = "test test test test test test test test test test test junk"
+
+func TestDeriveBIP32Vector(t *testing.T) {
+	// Test vector 1 from BIP-32
+	seed, _ := hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+	wallet, err := NewHDWalletFromSeed(seed)
+	if err != nil {
+		t.Fatalf("Failed to create wallet: %v", err)
+	}
+
+	vectors := map[string]string{
+		"m/0'":                   "edb2e14f9ee77d26dd93b4ecede8d16ed408ce149b6cd80b0715a2d911a0afea",
+		"m/0'/1":                 "3c6cb8d0f6a264c91ea8b5030fadaa8e538b020f0a387421a12de9319dc93368",
+		"m/0'/1/2'/2/1000000000": "471b76e389e528d6de6d816857e012c5455051cad6660850e58372a6c3e6e7c8",
+	}
+	for path, want := range vectors {
+		agentKey, err := wallet.Derive(path)
+		if err != nil {
+			t.Fatalf("Failed to derive %s: %v", path, err)
+		}
+		if agentKey.GetPrivateKeyHex() != want {
+			t.Errorf("Key mismatch at %s: %s", path, agentKey.GetPrivateKeyHex())
+		}
+	}
+}
+
+func TestDeriveAgentFromMnemonic(t *testing.T) {
+	wallet, err := NewHDWalletFromMnemonic(testMnemonic, "")
+	if err != nil {
+		t.Fatalf("Failed to create wallet: %v", err)
+	}
+
+	// Well-known Hardhat accounts at m/44'/60'/0'/0/i
+	accounts := []struct {
+		address    string
+		privateKey string
+	}{
+		{"0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266", "ac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80"},
+		{"0x70997970C51812dc3A010C7d01b50e0d17dc79C8", "59c6995e998f97a5a0044966f0945389dc9e86dae88c7a8412f4603b6b78690d"},
+	}
+	for i, account := range accounts {
+		agentKey, err := wallet.DeriveAgent(uint32(i))
+		if err != nil {
+			t.Fatalf("Failed to derive agent %d: %v", i, err)
+		}
+		if agentKey.Address.Hex() != account.address {
+			t.Errorf("Address mismatch for agent %d: %s", i, agentKey.Address.Hex())
+		}
+		if agentKey.GetPrivateKeyHex() != account.privateKey {
+			t.Errorf("Key mismatch for agent %d", i)
+		}
+		if agentKey.DID != "did:ackid:"+account.address {
+			t.Errorf("DID mismatch for agent %d: %s", i, agentKey.DID)
+		}
+	}
+
+	// DeriveAgent is the same as the full path
+	byPath, err := wallet.Derive("m/44'/60'/0'/0/1")
+	if err != nil {
+		t.Fatalf("Failed to derive by path: %v", err)
+	}
+	if byPath.Address.Hex() != accounts[1].address {
+		t.Errorf("Path derivation mismatch: %s", byPath.Address.Hex())
+	}
+
+	// A different base path yields a different fleet
+	if err := wallet.SetBasePath("m/44'/60'/1'/0"); err != nil {
+		t.Fatalf("Failed to set base path: %v", err)
+	}
+	other, err := wallet.DeriveAgent(0)
+	if err != nil {
+		t.Fatalf("Failed to derive agent: %v", err)
+	}
+	if other.Address.Hex() == accounts[0].address {
+		t.Error("Base path should change the derived key")
+	}
+}
+
+func TestMnemonic(t *testing.T) {
+	mnemonic, err := NewMnemonic(256)
+	if err != nil {
+		t.Fatalf("Failed to generate mnemonic: %v", err)
+	}
+	if len(strings.Fields(mnemonic)) != 24 {
+		t.Errorf("Expected 24 words, got %q", mnemonic)
+	}
+	if !ValidateMnemonic(mnemonic) {
+		t.Error("Generated mnemonic should be valid")
+	}
+	if !ValidateMnemonic("  " + strings.ReplaceAll(testMnemonic, " ", "\n") + "\n") {
+		t.Error("Whitespace should not affect validation")
+	}
+
+	// A passphrase changes every derived key
+	plain, _ := NewHDWalletFromMnemonic(mnemonic, "")
+	protected, _ := NewHDWalletFromMnemonic(mnemonic, "extra")
+	a, _ := plain.DeriveAgent(0)
+	b, _ := protected.DeriveAgent(0)
+	if a.Address == b.Address {
+		t.Error("Passphrase should change the derived key")
+	}
+
+	if _, err := NewHDWalletFromMnemonic("test test test test test test test test test test test test", ""); err == nil {
+		t.Error("Expected error for bad checksum")
+	}
+	if _, err := NewMnemonic(100); err == nil {
+		t.Error("Expected error for invalid entropy size")
+	}
+}
@@ -1,6 +1,7 @@
 package key
 
 import (
+	"context"
 	"strings"
 	"testing"
 
@@ -94,4 +95,36 @@ func TestDIDFormat(t *testing.T) {
 	if !strings.Contains(agentKey.DID, agentKey.Address.Hex()) {
 		t.Errorf("DID should contain address %s, got %s", agentKey.Address.Hex(), agentKey.DID)
 	}
-}
\ No newline at end of file
+}
+
+func TestSignHash(t *testing.T) {
+	agentKey, err := GenerateAgentKey()
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+
+	var signer Signer = agentKey
+	if signer.GetDID() != agentKey.DID {
+		t.Errorf("Signer DID mismatch: %s != %s", signer.GetDID(), agentKey.DID)
+	}
+
+	messageHash := crypto.Keccak256([]byte("Hello, Agent!"))
+	signature, err := signer.SignHash(context.Background(), messageHash)
+	if err != nil {
+		t.Fatalf("Failed to sign hash: %v", err)
+	}
+
+	publicKey, err := crypto.SigToPub(messageHash, signature)
+	if err != nil {
+		t.Fatalf("Failed to recover public key: %v", err)
+	}
+	if crypto.PubkeyToAddress(*publicKey).Hex() != signer.GetAddress() {
+		t.Error("Recovered address does not match signer address")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := signer.SignHash(ctx, messageHash); err == nil {
+		t.Error("Expected error for cancelled context")
+	}
+}
@@ -0,0 +1,309 @@
+package key
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/crypto/scrypt"
+)
+
+// Key derivation functions supported by the keystore format
+const (
+	KDFScrypt = "scrypt"
+	KDFPBKDF2 = "pbkdf2"
+)
+
+const (
+	keystoreVersion = 3
+	keystoreCipher  = "aes-128-ctr"
+	keystoreDKLen   = 32
+	scryptR         = 8
+)
+
+// Limits on the KDF parameters of a keystore, so that a crafted file cannot make
+// decrypting it take unbounded time or memory
+const (
+	maxScryptN          = 1 << 20 // With r = 8, 1GB of memory
+	maxScryptMemory     = 1 << 30 // 128 * N * r bytes
+	maxScryptRP         = 64
+	maxPBKDF2Iterations = 1 << 22
+)
+
+// KeystoreParams controls the key derivation used when encrypting a keystore
+type KeystoreParams struct {
+	KDF              string // KDFScrypt or KDFPBKDF2
+	ScryptN          int    // CPU/memory cost, power of two
+	ScryptP          int    // Parallelization
+	PBKDF2Iterations int    // Iteration count for PBKDF2-HMAC-SHA256
+}
+
+var (
+	// StandardKeystoreParams matches geth's default (about 1s and 256MB to decrypt)
+	StandardKeystoreParams = KeystoreParams{KDF: KDFScrypt, ScryptN: 1 << 18, ScryptP: 1}
+
+	// LightKeystoreParams matches geth's --lightkdf, for constrained environments
+	LightKeystoreParams = KeystoreParams{KDF: KDFScrypt, ScryptN: 1 << 12, ScryptP: 6}
+
+	// PBKDF2KeystoreParams uses PBKDF2-HMAC-SHA256 instead of scrypt
+	PBKDF2KeystoreParams = KeystoreParams{KDF: KDFPBKDF2, PBKDF2Iterations: 262144}
+)
+
+// keystoreJSON is the Web3 Secret Storage v3 file format used by geth and MetaMask
+type keystoreJSON struct {
+	Address string         `json:"address"`
+	Crypto  keystoreCrypto `json:"crypto"`
+	ID      string         `json:"id"`
+	Version int            `json:"version"`
+}
+
+type keystoreCrypto struct {
+	Cipher       string                 `json:"cipher"`
+	CipherText   string                 `json:"ciphertext"`
+	CipherParams keystoreCipherParams   `json:"cipherparams"`
+	KDF          string                 `json:"kdf"`
+	KDFParams    map[string]interface{} `json:"kdfparams"`
+	MAC          string                 `json:"mac"`
+}
+
+type keystoreCipherParams struct {
+	IV string `json:"iv"`
+}
+
+// EncryptKey encrypts an agent key into Web3 Secret Storage v3 JSON
+func EncryptKey(ak *AgentKey, password string, params KeystoreParams) ([]byte, error) {
+	salt := make([]byte, 32)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("failed to generate salt: %w", err)
+	}
+
+	kdfParams := map[string]interface{}{
+		"dklen": keystoreDKLen,
+		"salt":  hex.EncodeToString(salt),
+	}
+	switch params.KDF {
+	case KDFScrypt:
+		kdfParams["n"] = params.ScryptN
+		kdfParams["r"] = scryptR
+		kdfParams["p"] = params.ScryptP
+	case KDFPBKDF2:
+		kdfParams["c"] = params.PBKDF2Iterations
+		kdfParams["prf"] = "hmac-sha256"
+	default:
+		return nil, fmt.Errorf("unsupported keystore KDF %q", params.KDF)
+	}
+
+	derivedKey, err := deriveKeystoreKey(password, params.KDF, kdfParams)
+	if err != nil {
+		return nil, err
+	}
+
+	iv := make([]byte, aes.BlockSize)
+	if _, err := rand.Read(iv); err != nil {
+		return nil, fmt.Errorf("failed to generate IV: %w", err)
+	}
+	cipherText, err := aesCTR(derivedKey[:16], iv, crypto.FromECDSA(ak.PrivateKey))
+	if err != nil {
+		return nil, err
+	}
+	mac := crypto.Keccak256(derivedKey[16:32], cipherText)
+
+	id, err := newUUID()
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(keystoreJSON{
+		Address: hex.EncodeToString(ak.Address.Bytes()),
+		Crypto: keystoreCrypto{
+			Cipher:       keystoreCipher,
+			CipherText:   hex.EncodeToString(cipherText),
+			CipherParams: keystoreCipherParams{IV: hex.EncodeToString(iv)},
+			KDF:          params.KDF,
+			KDFParams:    kdfParams,
+			MAC:          hex.EncodeToString(mac),
+		},
+		ID:      id,
+		Version: keystoreVersion,
+	})
+}
+
+// DecryptKey decrypts Web3 Secret Storage v3 JSON (scrypt or pbkdf2) into an agent key
+func DecryptKey(keyJSON []byte, password string) (*AgentKey, error) {
+	var ks keystoreJSON
+	if err := json.Unmarshal(keyJSON, &ks); err != nil {
+		return nil, fmt.Errorf("invalid keystore JSON: %w", err)
+	}
+	if ks.Version != keystoreVersion {
+		return nil, fmt.Errorf("unsupported keystore version %d", ks.Version)
+	}
+	if ks.Crypto.Cipher != keystoreCipher {
+		return nil, fmt.Errorf("unsupported keystore cipher %q", ks.Crypto.Cipher)
+	}
+
+	cipherText, err := hex.DecodeString(ks.Crypto.CipherText)
+	if err != nil {
+		return nil, fmt.Errorf("invalid keystore ciphertext: %w", err)
+	}
+	iv, err := hex.DecodeString(ks.Crypto.CipherParams.IV)
+	if err != nil {
+		return nil, fmt.Errorf("invalid keystore IV: %w", err)
+	}
+	if len(iv) != aes.BlockSize {
+		return nil, fmt.Errorf("invalid keystore IV: must be %d bytes, got %d", aes.BlockSize, len(iv))
+	}
+	mac, err := hex.DecodeString(ks.Crypto.MAC)
+	if err != nil {
+		return nil, fmt.Errorf("invalid keystore MAC: %w", err)
+	}
+
+	derivedKey, err := deriveKeystoreKey(password, ks.Crypto.KDF, ks.Crypto.KDFParams)
+	if err != nil {
+		return nil, err
+	}
+	if subtle.ConstantTimeCompare(crypto.Keccak256(derivedKey[16:32], cipherText), mac) != 1 {
+		return nil, fmt.Errorf("could not decrypt key with given password")
+	}
+
+	keyBytes, err := aesCTR(derivedKey[:16], iv, cipherText)
+	if err != nil {
+		return nil, err
+	}
+	ak, err := ImportFromBytes(keyBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	if ks.Address != "" && common.HexToAddress(ks.Address) != ak.Address {
+		return nil, fmt.Errorf("keystore address %s does not match decrypted key %s", ks.Address, ak.Address.Hex())
+	}
+	return ak, nil
+}
+
+// SaveKeystore encrypts an agent key and writes it to path with owner-only permissions
+func SaveKeystore(path string, ak *AgentKey, password string, params KeystoreParams) error {
+	keyJSON, err := EncryptKey(ak, password, params)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, keyJSON, 0600); err != nil {
+		return fmt.Errorf("failed to write keystore: %w", err)
+	}
+	return nil
+}
+
+// LoadKeystore reads and decrypts a keystore file
+func LoadKeystore(path string, password string) (*AgentKey, error) {
+	keyJSON, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read keystore: %w", err)
+	}
+	return DecryptKey(keyJSON, password)
+}
+
+// ReadPasswordFile reads a password from the first line of a file
+func ReadPasswordFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read password file: %w", err)
+	}
+	line, _, _ := strings.Cut(string(data), "\n")
+	return strings.TrimRight(line, "\r"), nil
+}
+
+// deriveKeystoreKey runs the keystore KDF with the parameters stored in the file
+func deriveKeystoreKey(password, kdf string, params map[string]interface{}) ([]byte, error) {
+	salt, err := hex.DecodeString(stringParam(params, "salt"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid keystore salt: %w", err)
+	}
+	dkLen := intParam(params, "dklen")
+	if dkLen != keystoreDKLen {
+		return nil, fmt.Errorf("invalid keystore dklen %d: must be %d", dkLen, keystoreDKLen)
+	}
+
+	switch kdf {
+	case KDFScrypt:
+		n, r, p := intParam(params, "n"), intParam(params, "r"), intParam(params, "p")
+		if n < 2 || n > maxScryptN || n&(n-1) != 0 {
+			return nil, fmt.Errorf("invalid scrypt N %d: must be a power of two up to %d", n, maxScryptN)
+		}
+		if r < 1 || p < 1 || r > maxScryptRP || p > maxScryptRP || r*p > maxScryptRP {
+			return nil, fmt.Errorf("invalid scrypt r %d and p %d: r*p must be between 1 and %d", r, p, maxScryptRP)
+		}
+		if 128*n*r > maxScryptMemory {
+			return nil, fmt.Errorf("invalid scrypt N %d and r %d: needs more than %d bytes", n, r, maxScryptMemory)
+		}
+		key, err := scrypt.Key([]byte(password), salt, n, r, p, dkLen)
+		if err != nil {
+			return nil, fmt.Errorf("scrypt failed: %w", err)
+		}
+		return key, nil
+	case KDFPBKDF2:
+		if prf := stringParam(params, "prf"); prf != "hmac-sha256" {
+			return nil, fmt.Errorf("unsupported PBKDF2 PRF %q", prf)
+		}
+		iterations := intParam(params, "c")
+		if iterations <= 0 || iterations > maxPBKDF2Iterations {
+			return nil, fmt.Errorf("invalid PBKDF2 iteration count %d: must be between 1 and %d", iterations, maxPBKDF2Iterations)
+		}
+		return pbkdf2.Key([]byte(password), salt, iterations, dkLen, sha256.New), nil
+	default:
+		return nil, fmt.Errorf("unsupported keystore KDF %q", kdf)
+	}
+}
+
+func aesCTR(key, iv, input []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	output := make([]byte, len(input))
+	cipher.NewCTR(block, iv).XORKeyStream(output, input)
+	return output, nil
+}
+
+// newUUID returns a random RFC 4122 version 4 UUID
+func newUUID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate keystore ID: %w", err)
+	}
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	h := hex.EncodeToString(b)
+	return strings.Join([]string{h[0:8], h[8:12], h[12:16], h[16:20], h[20:32]}, "-"), nil
+}
+
+// Helpers for reading kdfparams, which decode as float64 from JSON
+func stringParam(m map[string]interface{}, name string) string {
+	if val, ok := m[name].(string); ok {
+		return val
+	}
+	return ""
+}
+
+// intParam returns -1 for values that are not integers that fit in 32 bits
+func intParam(m map[string]interface{}, name string) int {
+	switch val := m[name].(type) {
+	case float64:
+		if val != math.Trunc(val) || val < math.MinInt32 || val > math.MaxInt32 {
+			return -1
+		}
+		return int(val)
+	case int:
+		return val
+	}
+	return 0
+}
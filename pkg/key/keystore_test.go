@@ -0,0 +1,265 @@
+package key
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+)
+
+// Test vectors from the Web3 Secret Storage Definition
+const (
+	vectorPassword   = "testpassword"
+	vectorPrivateKey = "7a28b5ba57c53603b0b07b56bba752f7784bf506fa95edc395f5cf6c7514fe9d"
+
+	vectorScrypt = `{
+		"crypto": {
+			"cipher": "aes-128-ctr",
+			"cipherparams": {"iv": "83dbcc02d8ccb40e466191a123791e0e"},
+			"ciphertext": "d172bf743a674da9cdad04534d56926ef8358534d458fffccd4e6ad2fbde479c",
+			"kdf": "scrypt",
+			"kdfparams": {"dklen": 32, "n": 262144, "r": 1, "p": 8, "salt": "ab0c7876052600dd703518d6fc3fe8984592145b591fc8fb5c6d43190334ba19"},
+			"mac": "2103ac29920d71da29f15d75b4a16dbe95cfd7ff8faea1056c33131d846e3097"
+		},
+		"id": "3198bc9c-6672-5ab3-d995-4942343ae5b6",
+		"version": 3
+	}`
+
+	vectorPBKDF2 = `{
+		"crypto": {
+			"cipher": "aes-128-ctr",
+			"cipherparams": {"iv": "6087dab2f9fdbbfaddc31a909735c1e6"},
+			"ciphertext": "5318b4d5bcd28de64ee5559e671353e16f075ecae9f99c7a79a38af5f869aa46",
+			"kdf": "pbkdf2",
+			"kdfparams": {"c": 262144, "dklen": 32, "prf": "hmac-sha256", "salt": "ae3cd4e7013836a3df6bd7241b12db061dbe2c6785853cce422d148a624ce0bd"},
+			"mac": "517ead924a9d0dc3124507e3393d175ce3ff7c1e96529c6c555ce9e51205e9b2"
+		},
+		"id": "3198bc9c-6672-5ab3-d995-4942343ae5b6",
+		"version": 3
+	}`
+)
+
+// Cheap parameters so the tests stay fast
+var testKeystoreParams = KeystoreParams{KDF: KDFScrypt, ScryptN: 1 << 10, ScryptP: 1}
+
+func TestDecryptKeyVectors(t *testing.T) {
+	for name, vector := range map[string]string{"scrypt": vectorScrypt, "pbkdf2": vectorPBKDF2} {
+		t.Run(name, func(t *testing.T) {
+			agentKey, err := DecryptKey([]byte(vector), vectorPassword)
+			if err != nil {
+				t.Fatalf("Failed to decrypt vector: %v", err)
+			}
+			if agentKey.GetPrivateKeyHex() != vectorPrivateKey {
+				t.Errorf("Decrypted key mismatch: %s", agentKey.GetPrivateKeyHex())
+			}
+
+			if _, err := DecryptKey([]byte(vector), "wrong"); err == nil {
+				t.Error("Expected error for wrong password")
+			}
+		})
+	}
+}
+
+func TestEncryptDecryptRoundTrip(t *testing.T) {
+	agentKey, err := GenerateAgentKey()
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+
+	params := []KeystoreParams{
+		testKeystoreParams,
+		{KDF: KDFPBKDF2, PBKDF2Iterations: 1024},
+	}
+	for _, p := range params {
+		t.Run(p.KDF, func(t *testing.T) {
+			keyJSON, err := EncryptKey(agentKey, "secret", p)
+			if err != nil {
+				t.Fatalf("Failed to encrypt key: %v", err)
+			}
+
+			decrypted, err := DecryptKey(keyJSON, "secret")
+			if err != nil {
+				t.Fatalf("Failed to decrypt key: %v", err)
+			}
+			if decrypted.DID != agentKey.DID {
+				t.Errorf("DIDs don't match: %s != %s", decrypted.DID, agentKey.DID)
+			}
+		})
+	}
+
+	if _, err := EncryptKey(agentKey, "secret", KeystoreParams{KDF: "argon2"}); err == nil {
+		t.Error("Expected error for unsupported KDF")
+	}
+}
+
+func TestKeystoreGethCompatibility(t *testing.T) {
+	agentKey, err := GenerateAgentKey()
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+
+	// Our keystore opens in geth
+	keyJSON, err := EncryptKey(agentKey, "secret", testKeystoreParams)
+	if err != nil {
+		t.Fatalf("Failed to encrypt key: %v", err)
+	}
+	gethKey, err := keystore.DecryptKey(keyJSON, "secret")
+	if err != nil {
+		t.Fatalf("geth failed to decrypt keystore: %v", err)
+	}
+	if gethKey.Address != agentKey.Address {
+		t.Errorf("geth address mismatch: %s != %s", gethKey.Address.Hex(), agentKey.Address.Hex())
+	}
+
+	// geth's keystore opens here
+	gethJSON, err := keystore.EncryptKey(gethKey, "secret", 1<<10, 1)
+	if err != nil {
+		t.Fatalf("geth failed to encrypt key: %v", err)
+	}
+	imported, err := DecryptKey(gethJSON, "secret")
+	if err != nil {
+		t.Fatalf("Failed to decrypt geth keystore: %v", err)
+	}
+	if imported.GetPrivateKeyHex() != agentKey.GetPrivateKeyHex() {
+		t.Error("Imported key does not match original")
+	}
+}
+
+func TestDecryptKeyAddressMismatch(t *testing.T) {
+	agentKey, err := GenerateAgentKey()
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	other, err := GenerateAgentKey()
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+
+	keyJSON, err := EncryptKey(agentKey, "secret", testKeystoreParams)
+	if err != nil {
+		t.Fatalf("Failed to encrypt key: %v", err)
+	}
+
+	var fields map[string]interface{}
+	if err := json.Unmarshal(keyJSON, &fields); err != nil {
+		t.Fatalf("Failed to parse keystore: %v", err)
+	}
+	fields["address"] = hex.EncodeToString(other.Address.Bytes())
+	tampered, err := json.Marshal(fields)
+	if err != nil {
+		t.Fatalf("Failed to encode keystore: %v", err)
+	}
+
+	if _, err := DecryptKey(tampered, "secret"); err == nil {
+		t.Error("Expected address mismatch error")
+	}
+}
+
+func TestDecryptKeyInvalidIV(t *testing.T) {
+	agentKey, err := GenerateAgentKey()
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	keyJSON, err := EncryptKey(agentKey, "secret", testKeystoreParams)
+	if err != nil {
+		t.Fatalf("Failed to encrypt key: %v", err)
+	}
+
+	var ks keystoreJSON
+	if err := json.Unmarshal(keyJSON, &ks); err != nil {
+		t.Fatalf("Failed to parse keystore: %v", err)
+	}
+	for _, iv := range []string{"", "00", ks.Crypto.CipherParams.IV + "00"} {
+		ks.Crypto.CipherParams.IV = iv
+		tampered, err := json.Marshal(ks)
+		if err != nil {
+			t.Fatalf("Failed to encode keystore: %v", err)
+		}
+		if _, err := DecryptKey(tampered, "secret"); err == nil {
+			t.Errorf("Expected an error for IV %q", iv)
+		}
+	}
+}
+
+func TestDecryptKeyInvalidKDFParams(t *testing.T) {
+	tests := []struct {
+		name   string
+		vector string
+		params map[string]interface{}
+	}{
+		{"short dklen", vectorScrypt, map[string]interface{}{"dklen": 0}},
+		{"long dklen", vectorScrypt, map[string]interface{}{"dklen": 64}},
+		{"N not a power of two", vectorScrypt, map[string]interface{}{"n": 262143}},
+		{"N too large", vectorScrypt, map[string]interface{}{"n": 1 << 21}},
+		{"N fractional", vectorScrypt, map[string]interface{}{"n": 262144.5}},
+		{"N overflowing", vectorScrypt, map[string]interface{}{"n": 1e300}},
+		{"no r", vectorScrypt, map[string]interface{}{"r": 0}},
+		{"r*p too large", vectorScrypt, map[string]interface{}{"p": 1 << 20}},
+		{"too much memory", vectorScrypt, map[string]interface{}{"n": 1 << 20, "r": 16, "p": 1}},
+		{"no iterations", vectorPBKDF2, map[string]interface{}{"c": 0}},
+		{"too many iterations", vectorPBKDF2, map[string]interface{}{"c": 1 << 30}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ks keystoreJSON
+			if err := json.Unmarshal([]byte(tt.vector), &ks); err != nil {
+				t.Fatalf("Failed to parse keystore: %v", err)
+			}
+			for name, value := range tt.params {
+				ks.Crypto.KDFParams[name] = value
+			}
+			tampered, err := json.Marshal(ks)
+			if err != nil {
+				t.Fatalf("Failed to encode keystore: %v", err)
+			}
+			if _, err := DecryptKey(tampered, vectorPassword); err == nil || !strings.Contains(err.Error(), "invalid") {
+				t.Errorf("Expected the parameters to be rejected, got %v", err)
+			}
+		})
+	}
+}
+
+func TestSaveAndLoadKeystore(t *testing.T) {
+	dir := t.TempDir()
+	agentKey, err := GenerateAgentKey()
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+
+	passwordFile := filepath.Join(dir, "password.txt")
+	if err := os.WriteFile(passwordFile, []byte("secret\n"), 0600); err != nil {
+		t.Fatalf("Failed to write password file: %v", err)
+	}
+	password, err := ReadPasswordFile(passwordFile)
+	if err != nil {
+		t.Fatalf("Failed to read password file: %v", err)
+	}
+	if password != "secret" {
+		t.Errorf("Password should not include the newline, got %q", password)
+	}
+
+	path := filepath.Join(dir, "agent.json")
+	if err := SaveKeystore(path, agentKey, password, testKeystoreParams); err != nil {
+		t.Fatalf("Failed to save keystore: %v", err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("Failed to stat keystore: %v", err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("Keystore should be owner-only, got %v", info.Mode().Perm())
+	}
+
+	loaded, err := LoadKeystore(path, password)
+	if err != nil {
+		t.Fatalf("Failed to load keystore: %v", err)
+	}
+	if loaded.Address != agentKey.Address {
+		t.Errorf("Addresses don't match: %s != %s", loaded.Address.Hex(), agentKey.Address.Hex())
+	}
+}
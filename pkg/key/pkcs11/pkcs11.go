@@ -0,0 +1,396 @@
+// Package pkcs11 implements key.Signer on top of a PKCS#11 token (HSM, SoftHSM,
+// cloud HSM client libraries), so the agent's private key never leaves the device.
+//
+// The token must support secp256k1 EC keys and the CKM_ECDSA mechanism.
+package pkcs11
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/ak68a/agentid-core/pkg/key"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	p11 "github.com/miekg/pkcs11"
+)
+
+// secp256k1OID is the DER-encoded object identifier 1.3.132.0.10 used in CKA_EC_PARAMS
+var secp256k1OID = []byte{0x06, 0x05, 0x2b, 0x81, 0x04, 0x00, 0x0a}
+
+// Config locates a key on a PKCS#11 token
+type Config struct {
+	LibraryPath string // Path to the PKCS#11 module, e.g. /usr/lib/softhsm/libsofthsm2.so
+	TokenLabel  string // Label of the token holding the key
+	PIN         string // User PIN of the token
+	KeyLabel    string // CKA_LABEL of the key pair
+}
+
+// modules holds one initialized context per PKCS#11 library path. C_Initialize
+// and C_Finalize apply to the whole process, so Signers on the same library
+// share a context and only the last one to close finalizes it.
+var (
+	modulesMu sync.Mutex
+	modules   = make(map[string]*module)
+)
+
+type module struct {
+	path     string
+	ctx      *p11.Ctx
+	finalize bool         // Whether the module was initialized by us rather than elsewhere in the process
+	refs     int          // Signers using the module
+	logins   map[uint]int // Signers logged in to the token of each slot
+}
+
+// Signer signs with a secp256k1 private key held on a PKCS#11 token
+type Signer struct {
+	module     *module
+	ctx        *p11.Ctx
+	slot       uint
+	session    p11.SessionHandle
+	privateKey p11.ObjectHandle
+	publicKey  *ecdsa.PublicKey
+	address    common.Address
+	did        string
+
+	mu sync.Mutex // PKCS#11 sessions do not allow concurrent operations
+}
+
+var _ key.Signer = (*Signer)(nil)
+
+// NewSigner opens a session on the configured token and loads an existing key pair
+func NewSigner(cfg Config) (*Signer, error) {
+	s, err := open(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	privateKey, err := s.findObject(p11.CKO_PRIVATE_KEY, cfg.KeyLabel)
+	if err != nil {
+		s.Close()
+		return nil, err
+	}
+	publicKey, err := s.findObject(p11.CKO_PUBLIC_KEY, cfg.KeyLabel)
+	if err != nil {
+		s.Close()
+		return nil, err
+	}
+
+	if err := s.load(privateKey, publicKey); err != nil {
+		s.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// GenerateKey creates a new secp256k1 key pair on the token and returns a Signer for it.
+// The private key is marked sensitive and non-extractable.
+func GenerateKey(cfg Config) (*Signer, error) {
+	s, err := open(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	publicTemplate := []*p11.Attribute{
+		p11.NewAttribute(p11.CKA_TOKEN, true),
+		p11.NewAttribute(p11.CKA_VERIFY, true),
+		p11.NewAttribute(p11.CKA_EC_PARAMS, secp256k1OID),
+		p11.NewAttribute(p11.CKA_LABEL, cfg.KeyLabel),
+	}
+	privateTemplate := []*p11.Attribute{
+		p11.NewAttribute(p11.CKA_TOKEN, true),
+		p11.NewAttribute(p11.CKA_PRIVATE, true),
+		p11.NewAttribute(p11.CKA_SIGN, true),
+		p11.NewAttribute(p11.CKA_SENSITIVE, true),
+		p11.NewAttribute(p11.CKA_EXTRACTABLE, false),
+		p11.NewAttribute(p11.CKA_LABEL, cfg.KeyLabel),
+	}
+
+	publicKey, privateKey, err := s.ctx.GenerateKeyPair(s.session,
+		[]*p11.Mechanism{p11.NewMechanism(p11.CKM_EC_KEY_PAIR_GEN, nil)},
+		publicTemplate, privateTemplate)
+	if err != nil {
+		s.Close()
+		return nil, fmt.Errorf("failed to generate key pair: %w", err)
+	}
+
+	if err := s.load(privateKey, publicKey); err != nil {
+		s.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// GetAddress returns the Ethereum address of the token key as a hex string
+func (s *Signer) GetAddress() string {
+	return s.address.Hex()
+}
+
+// GetDID returns the did:ackid DID of the token key
+func (s *Signer) GetDID() string {
+	return s.did
+}
+
+// SignHash signs a hash on the token and returns a 65-byte [R || S || V] signature
+func (s *Signer) SignHash(ctx context.Context, hash []byte) ([]byte, error) {
+	if len(hash) != 32 {
+		return nil, fmt.Errorf("hash must be 32 bytes, got %d", len(hash))
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.ctx.SignInit(s.session, []*p11.Mechanism{p11.NewMechanism(p11.CKM_ECDSA, nil)}, s.privateKey); err != nil {
+		return nil, fmt.Errorf("failed to initialize signing: %w", err)
+	}
+	raw, err := s.ctx.Sign(s.session, hash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign hash: %w", err)
+	}
+	if len(raw) != 64 {
+		return nil, fmt.Errorf("token returned a %d-byte signature, expected 64", len(raw))
+	}
+
+	return toRecoverable(hash, raw, s.address)
+}
+
+// Close releases the PKCS#11 session, and the module once no other Signer uses it.
+// Logging out applies to every session on the token, so only the last Signer
+// logged in to the token does.
+func (s *Signer) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	modulesMu.Lock()
+	defer modulesMu.Unlock()
+	if s.module == nil {
+		return nil
+	}
+	defer func() { s.module = nil }()
+
+	var errs []error
+	s.module.logins[s.slot]--
+	if s.module.logins[s.slot] == 0 {
+		delete(s.module.logins, s.slot)
+		if err := s.ctx.Logout(s.session); err != nil && !isError(err, p11.CKR_USER_NOT_LOGGED_IN) {
+			errs = append(errs, err)
+		}
+	}
+	if err := s.ctx.CloseSession(s.session); err != nil {
+		errs = append(errs, err)
+	}
+	if err := s.module.release(); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// open loads the module, finds the token and logs in
+func open(cfg Config) (*Signer, error) {
+	modulesMu.Lock()
+	defer modulesMu.Unlock()
+
+	m, err := acquireModule(cfg.LibraryPath)
+	if err != nil {
+		return nil, err
+	}
+	ctx := m.ctx
+
+	slot, err := findSlot(ctx, cfg.TokenLabel)
+	if err != nil {
+		m.release()
+		return nil, err
+	}
+
+	session, err := ctx.OpenSession(slot, p11.CKF_SERIAL_SESSION|p11.CKF_RW_SESSION)
+	if err != nil {
+		m.release()
+		return nil, fmt.Errorf("failed to open session: %w", err)
+	}
+
+	s := &Signer{module: m, ctx: ctx, slot: slot, session: session}
+	if err := ctx.Login(session, p11.CKU_USER, cfg.PIN); err != nil && !isError(err, p11.CKR_USER_ALREADY_LOGGED_IN) {
+		ctx.CloseSession(session)
+		m.release()
+		return nil, fmt.Errorf("failed to log in to token: %w", err)
+	}
+	m.logins[slot]++
+	return s, nil
+}
+
+// acquireModule returns the shared context of a library, loading and
+// initializing it on first use. The caller must hold modulesMu.
+func acquireModule(path string) (*module, error) {
+	if m, ok := modules[path]; ok {
+		m.refs++
+		return m, nil
+	}
+
+	ctx := p11.New(path)
+	if ctx == nil {
+		return nil, fmt.Errorf("failed to load PKCS#11 module %s", path)
+	}
+	m := &module{path: path, ctx: ctx, finalize: true, refs: 1, logins: make(map[uint]int)}
+	if err := ctx.Initialize(); err != nil {
+		if !isError(err, p11.CKR_CRYPTOKI_ALREADY_INITIALIZED) {
+			ctx.Destroy()
+			return nil, fmt.Errorf("failed to initialize PKCS#11 module: %w", err)
+		}
+		m.finalize = false
+	}
+	modules[path] = m
+	return m, nil
+}
+
+// release drops a reference to the module, finalizing and unloading it with
+// the last one. The caller must hold modulesMu.
+func (m *module) release() error {
+	m.refs--
+	if m.refs > 0 {
+		return nil
+	}
+	delete(modules, m.path)
+
+	var err error
+	if m.finalize {
+		err = m.ctx.Finalize()
+	}
+	m.ctx.Destroy()
+	return err
+}
+
+// findSlot returns the slot holding the token with the given label
+func findSlot(ctx *p11.Ctx, label string) (uint, error) {
+	slots, err := ctx.GetSlotList(true)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list slots: %w", err)
+	}
+	for _, slot := range slots {
+		info, err := ctx.GetTokenInfo(slot)
+		if err != nil {
+			continue
+		}
+		if info.Label == label {
+			return slot, nil
+		}
+	}
+	return 0, fmt.Errorf("token %q not found", label)
+}
+
+// findObject returns the single object of a class with the given label
+func (s *Signer) findObject(class uint, label string) (p11.ObjectHandle, error) {
+	template := []*p11.Attribute{
+		p11.NewAttribute(p11.CKA_CLASS, class),
+		p11.NewAttribute(p11.CKA_KEY_TYPE, p11.CKK_EC),
+		p11.NewAttribute(p11.CKA_LABEL, label),
+	}
+	if err := s.ctx.FindObjectsInit(s.session, template); err != nil {
+		return 0, fmt.Errorf("failed to search token: %w", err)
+	}
+	objects, _, err := s.ctx.FindObjects(s.session, 2)
+	if finalErr := s.ctx.FindObjectsFinal(s.session); err == nil {
+		err = finalErr
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to search token: %w", err)
+	}
+
+	switch len(objects) {
+	case 0:
+		return 0, fmt.Errorf("key %q not found on token", label)
+	case 1:
+		return objects[0], nil
+	default:
+		return 0, fmt.Errorf("key label %q is not unique on token", label)
+	}
+}
+
+// load reads the public key and derives the address and DID
+func (s *Signer) load(privateKey, publicKey p11.ObjectHandle) error {
+	attrs, err := s.ctx.GetAttributeValue(s.session, publicKey, []*p11.Attribute{
+		p11.NewAttribute(p11.CKA_EC_PARAMS, nil),
+		p11.NewAttribute(p11.CKA_EC_POINT, nil),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to read public key: %w", err)
+	}
+
+	var params, point []byte
+	for _, attr := range attrs {
+		switch attr.Type {
+		case p11.CKA_EC_PARAMS:
+			params = attr.Value
+		case p11.CKA_EC_POINT:
+			point = attr.Value
+		}
+	}
+	if string(params) != string(secp256k1OID) {
+		return fmt.Errorf("token key is not a secp256k1 key")
+	}
+
+	pub, err := parseECPoint(point)
+	if err != nil {
+		return err
+	}
+
+	address := crypto.PubkeyToAddress(*pub)
+	s.privateKey = privateKey
+	s.publicKey = pub
+	s.address = address
+	s.did = fmt.Sprintf("did:ackid:%s", address.Hex())
+	return nil
+}
+
+// parseECPoint decodes CKA_EC_POINT, which is a DER OCTET STRING wrapping the
+// uncompressed point (some modules omit the wrapper)
+func parseECPoint(data []byte) (*ecdsa.PublicKey, error) {
+	var raw []byte
+	if rest, err := asn1.Unmarshal(data, &raw); err != nil || len(rest) != 0 {
+		raw = data
+	}
+
+	pub, err := crypto.UnmarshalPubkey(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid EC point: %w", err)
+	}
+	return pub, nil
+}
+
+// toRecoverable converts a raw R || S signature into Ethereum's [R || S || V] form.
+// S is normalized to the lower half of the curve order, and V is found by
+// recovering the public key with each candidate.
+func toRecoverable(hash, raw []byte, address common.Address) ([]byte, error) {
+	n := crypto.S256().Params().N
+	halfN := new(big.Int).Rsh(n, 1)
+
+	r := new(big.Int).SetBytes(raw[:32])
+	sVal := new(big.Int).SetBytes(raw[32:])
+	if sVal.Cmp(halfN) > 0 {
+		sVal.Sub(n, sVal)
+	}
+
+	signature := make([]byte, 65)
+	r.FillBytes(signature[:32])
+	sVal.FillBytes(signature[32:64])
+
+	for v := byte(0); v < 2; v++ {
+		signature[64] = v
+		pub, err := crypto.SigToPub(hash, signature)
+		if err == nil && crypto.PubkeyToAddress(*pub) == address {
+			return signature, nil
+		}
+	}
+	return nil, fmt.Errorf("token signature does not recover to %s", address.Hex())
+}
+
+// isError reports whether err is the given PKCS#11 return value
+func isError(err error, code uint) bool {
+	var p11Err p11.Error
+	return errors.As(err, &p11Err) && uint(p11Err) == code
+}
@@ -0,0 +1,148 @@
+package pkcs11
+
+import (
+	"context"
+	"encoding/asn1"
+	"fmt"
+	"math/big"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/ak68a/agentid-core/pkg/key"
+	"github.com/ak68a/agentid-core/pkg/models"
+	"github.com/ak68a/agentid-core/pkg/signer"
+	"github.com/ethereum/go-ethereum/crypto"
+	p11 "github.com/miekg/pkcs11"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToRecoverable(t *testing.T) {
+	agentKey, err := key.GenerateAgentKey()
+	require.NoError(t, err)
+	hash := crypto.Keccak256([]byte("hsm"))
+
+	signature, err := agentKey.Sign(hash)
+	require.NoError(t, err)
+
+	// Tokens return plain R || S, possibly with a high S value
+	n := crypto.S256().Params().N
+	highS := new(big.Int).Sub(n, new(big.Int).SetBytes(signature[32:64]))
+	raw := make([]byte, 64)
+	copy(raw, signature[:32])
+	highS.FillBytes(raw[32:])
+
+	for _, candidate := range [][]byte{signature[:64], raw} {
+		recovered, err := toRecoverable(hash, candidate, agentKey.Address)
+		require.NoError(t, err)
+		assert.Equal(t, signature, recovered)
+	}
+
+	other, err := key.GenerateAgentKey()
+	require.NoError(t, err)
+	_, err = toRecoverable(hash, signature[:64], other.Address)
+	assert.Error(t, err)
+}
+
+func TestParseECPoint(t *testing.T) {
+	agentKey, err := key.GenerateAgentKey()
+	require.NoError(t, err)
+	point := crypto.FromECDSAPub(agentKey.PublicKey)
+
+	wrapped, err := asn1.Marshal(point)
+	require.NoError(t, err)
+
+	for _, data := range [][]byte{wrapped, point} {
+		pub, err := parseECPoint(data)
+		require.NoError(t, err)
+		assert.Equal(t, agentKey.Address, crypto.PubkeyToAddress(*pub))
+	}
+
+	_, err = parseECPoint([]byte{0x04, 0x01})
+	assert.Error(t, err)
+}
+
+// TestSoftHSM runs against a real PKCS#11 module. Prepare a token with
+//
+//	softhsm2-util --init-token --free --label agentid-test --so-pin 0000 --pin 1234
+//
+// and set AGENTID_PKCS11_LIB to the module path (e.g. /usr/lib/softhsm/libsofthsm2.so).
+// Set AGENTID_PKCS11_TOKEN2 to the label of a second token to test logins across tokens.
+func TestSoftHSM(t *testing.T) {
+	library := os.Getenv("AGENTID_PKCS11_LIB")
+	if library == "" {
+		t.Skip("AGENTID_PKCS11_LIB not set")
+	}
+	cfg := Config{
+		LibraryPath: library,
+		TokenLabel:  envOr("AGENTID_PKCS11_TOKEN", "agentid-test"),
+		PIN:         envOr("AGENTID_PKCS11_PIN", "1234"),
+		KeyLabel:    fmt.Sprintf("agent-%d", time.Now().UnixNano()),
+	}
+
+	hsmSigner, err := GenerateKey(cfg)
+	require.NoError(t, err)
+
+	// The ClaimSigner works unchanged on top of the token key
+	claim := &models.DelegationClaim{
+		DelegatorDID: hsmSigner.GetDID(),
+		DelegateDID:  "did:ackid:0x742d35Cc6634C0532925a3b844Bc454e4438f44e",
+		Action:       models.ActionTransfer,
+		Scope:        models.ScopeETH,
+		IssuedAt:     time.Now().Unix(),
+		ExpiresAt:    time.Now().Add(time.Hour).Unix(),
+		Nonce:        "hsm",
+	}
+	require.NoError(t, signer.NewClaimSigner(hsmSigner).SignDelegationClaim(claim))
+
+	valid, err := signer.NewClaimSigner(nil).VerifyDelegationClaim(claim, hsmSigner.GetDID())
+	require.NoError(t, err)
+	assert.True(t, valid)
+
+	// Reopening by label finds the same key
+	require.NoError(t, hsmSigner.Close())
+	reopened, err := NewSigner(cfg)
+	require.NoError(t, err)
+	defer reopened.Close()
+	assert.Equal(t, hsmSigner.GetAddress(), reopened.GetAddress())
+
+	// Signers share the module, so closing one leaves the others working
+	other, err := NewSigner(cfg)
+	require.NoError(t, err)
+	require.NoError(t, other.Close())
+	require.NoError(t, other.Close())
+
+	signature, err := reopened.SignHash(context.Background(), crypto.Keccak256([]byte("again")))
+	require.NoError(t, err)
+	assert.Len(t, signature, 65)
+
+	// Logins are per token: closing the last signer on a token logs it out
+	// even while a signer on another token holds the module
+	otherToken := os.Getenv("AGENTID_PKCS11_TOKEN2")
+	if otherToken == "" {
+		return
+	}
+	otherCfg := cfg
+	otherCfg.TokenLabel = otherToken
+	onOther, err := GenerateKey(otherCfg)
+	require.NoError(t, err)
+	defer onOther.Close()
+	require.NoError(t, reopened.Close())
+
+	slot, err := findSlot(onOther.ctx, cfg.TokenLabel)
+	require.NoError(t, err)
+	session, err := onOther.ctx.OpenSession(slot, p11.CKF_SERIAL_SESSION)
+	require.NoError(t, err)
+	defer onOther.ctx.CloseSession(session)
+	info, err := onOther.ctx.GetSessionInfo(session)
+	require.NoError(t, err)
+	assert.Equal(t, uint(p11.CKS_RO_PUBLIC_SESSION), info.State)
+}
+
+func envOr(name, fallback string) string {
+	if value := os.Getenv(name); value != "" {
+		return value
+	}
+	return fallback
+}
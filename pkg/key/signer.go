@@ -0,0 +1,34 @@
+package key
+
+import "context"
+
+// Signer signs hashes on behalf of an agent identity.
+// Implementations may keep the private key in process (AgentKey) or in an
+// external KMS/HSM; callers only ever see the resulting signature.
+type Signer interface {
+	// GetAddress returns the Ethereum address of the signing key as a hex string
+	GetAddress() string
+
+	// GetDID returns the DID the signatures are made for
+	GetDID() string
+
+	// SignHash signs a 32-byte hash and returns a 65-byte [R || S || V]
+	// signature where V is the 0/1 recovery ID
+	SignHash(ctx context.Context, hash []byte) ([]byte, error)
+}
+
+// AgentKey is the in-memory Signer implementation
+var _ Signer = (*AgentKey)(nil)
+
+// GetDID returns the agent's DID
+func (ak *AgentKey) GetDID() string {
+	return ak.DID
+}
+
+// SignHash signs a hash with the in-memory private key
+func (ak *AgentKey) SignHash(ctx context.Context, hash []byte) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return ak.Sign(hash)
+}
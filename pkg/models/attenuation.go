@@ -0,0 +1,222 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+)
+
+// Well-known constraint keys in DelegationClaim.Constraints
+const (
+	ConstraintMaxAmount = "max_amount"
+	ConstraintTime      = "time"
+	ConstraintScope     = "scope"
+	// ConstraintBudgetPeriod resets max_amount every period ("daily" or "weekly")
+	ConstraintBudgetPeriod = "budget_period"
+	// ConstraintRateLimit caps how many requests are made in a window
+	ConstraintRateLimit = "rate_limit"
+	// ConstraintCounterparties limits which requesters the delegate may act for
+	ConstraintCounterparties = "counterparties"
+	// ConstraintGeo limits the regions requests may come from
+	ConstraintGeo = "geo"
+)
+
+// Wildcard matches any action or scope. A trailing * matches a prefix, e.g. "hotels/*".
+const Wildcard = "*"
+
+// CheckAttenuation returns a ChainError if child grants anything its parent did not:
+// a wider action or scope, a larger max_amount, a longer lifetime or time window,
+// more resources, or a weaker version of any other constraint
+func CheckAttenuation(parent, child *DelegationClaim) error {
+	if !PatternCovers(parent.Action, child.Action) {
+		return &ChainError{
+			Code:    ChainErrActionEscalation,
+			Message: fmt.Sprintf("action %q is not within %q", child.Action, parent.Action),
+		}
+	}
+	if !PatternCovers(parent.Scope, child.Scope) {
+		return &ChainError{
+			Code:    ChainErrScopeEscalation,
+			Message: fmt.Sprintf("scope %q is not within %q", child.Scope, parent.Scope),
+		}
+	}
+
+	if parent.ExpiresAt != 0 && (child.ExpiresAt == 0 || child.ExpiresAt > parent.ExpiresAt) {
+		return &ChainError{
+			Code:    ChainErrExpiryEscalation,
+			Message: fmt.Sprintf("expires at %d, after its parent at %d", child.ExpiresAt, parent.ExpiresAt),
+		}
+	}
+	if child.IssuedAt < parent.IssuedAt {
+		return &ChainError{
+			Code:    ChainErrExpiryEscalation,
+			Message: fmt.Sprintf("issued at %d, before its parent at %d", child.IssuedAt, parent.IssuedAt),
+		}
+	}
+
+	if err := checkCritical(parent, child); err != nil {
+		return err
+	}
+
+	parentConstraints, err := parent.ParseConstraints()
+	if err != nil {
+		return withMessagePrefix(err, "parent ")
+	}
+	childConstraints, err := child.ParseConstraints()
+	if err != nil {
+		return err
+	}
+	for name, parentConstraint := range parentConstraints {
+		childConstraint, ok := childConstraints[name]
+		if !ok {
+			return &ChainError{
+				Code:    constraintCode(name),
+				Message: fmt.Sprintf("drops the %q constraint set by its parent", name),
+			}
+		}
+		if err := childConstraint.IsSubsetOf(parentConstraint); err != nil {
+			return &ChainError{Code: constraintCode(name), Message: err.Error()}
+		}
+	}
+	return nil
+}
+
+// checkCritical requires a child to keep every constraint its parent marks critical
+func checkCritical(parent, child *DelegationClaim) error {
+	parentCritical, err := parent.CriticalConstraints()
+	if err != nil {
+		return &ChainError{Code: ChainErrConstraint, Message: "parent " + err.Error()}
+	}
+	childCritical, err := child.CriticalConstraints()
+	if err != nil {
+		return &ChainError{Code: ChainErrConstraint, Message: err.Error()}
+	}
+	for _, name := range parentCritical {
+		if !containsString(childCritical, name) {
+			return &ChainError{
+				Code:    ChainErrConstraint,
+				Message: fmt.Sprintf("no longer marks %q as critical", name),
+			}
+		}
+	}
+	return nil
+}
+
+// withMessagePrefix prefixes the message of a ChainError
+func withMessagePrefix(err error, prefix string) error {
+	var chainErr *ChainError
+	if errors.As(err, &chainErr) {
+		return &ChainError{Code: chainErr.Code, Message: prefix + chainErr.Message}
+	}
+	return err
+}
+
+// PatternCovers reports whether every value matched by child is also matched by parent.
+// Patterns are exact values, "*", or a prefix ending in "*".
+func PatternCovers(parent, child string) bool {
+	if parent == Wildcard {
+		return true
+	}
+	if prefix, ok := strings.CutSuffix(parent, Wildcard); ok {
+		return strings.HasPrefix(child, prefix)
+	}
+	return parent == child
+}
+
+// ParseAmount parses an amount constraint given as a decimal string, an integer or a JSON number
+func ParseAmount(value interface{}) (*big.Int, error) {
+	var text string
+	switch v := value.(type) {
+	case string:
+		text = v
+	case json.Number:
+		// Exponent forms such as 5e20 are accepted if they are whole numbers
+		f, ok := new(big.Float).SetPrec(512).SetString(v.String())
+		if !ok || !f.IsInt() {
+			return nil, fmt.Errorf("invalid amount %q", v.String())
+		}
+		text = f.Text('f', 0)
+	case float64:
+		text = big.NewFloat(v).Text('f', -1)
+	case int:
+		return big.NewInt(int64(v)), nil
+	case int64:
+		return big.NewInt(v), nil
+	case *big.Int:
+		return v, nil
+	default:
+		return nil, fmt.Errorf("unsupported amount type %T", value)
+	}
+
+	amount, ok := new(big.Int).SetString(text, 10)
+	if !ok || amount.Sign() < 0 {
+		return nil, fmt.Errorf("invalid amount %q", text)
+	}
+	return amount, nil
+}
+
+// constraintCode returns the ChainError code used for a constraint key
+func constraintCode(name string) string {
+	switch name {
+	case ConstraintMaxAmount:
+		return ChainErrAmountEscalation
+	case ConstraintTime:
+		return ChainErrTimeConstraint
+	case ConstraintScope:
+		return ChainErrScopeConstraint
+	default:
+		return ChainErrConstraint
+	}
+}
+
+// intSubset reports whether child only contains values from parent; an empty parent allows everything
+func intSubset(parent, child []int) bool {
+	if len(parent) == 0 {
+		return true
+	}
+	if len(child) == 0 {
+		return false
+	}
+	allowed := make(map[int]bool, len(parent))
+	for _, v := range parent {
+		allowed[v] = true
+	}
+	for _, v := range child {
+		if !allowed[v] {
+			return false
+		}
+	}
+	return true
+}
+
+func containsString(values []string, v string) bool {
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+	return false
+}
+
+func anyPatternCovers(patterns []string, value string) bool {
+	for _, pattern := range patterns {
+		if PatternCovers(pattern, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// jsonEqual compares two constraint values by their JSON encoding, so that
+// numbers decoded as float64 still equal the ints they were created from
+func jsonEqual(a, b interface{}) bool {
+	aJSON, errA := json.Marshal(a)
+	bJSON, errB := json.Marshal(b)
+	if errA != nil || errB != nil {
+		return reflect.DeepEqual(a, b)
+	}
+	return string(aJSON) == string(bJSON)
+}
@@ -0,0 +1,171 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func newTestDelegation(action, scope string, expiresAt int64, constraints map[string]interface{}) *DelegationClaim {
+	if constraints == nil {
+		constraints = map[string]interface{}{}
+	}
+	return &DelegationClaim{
+		DelegatorDID: "did:ackid:0xa",
+		DelegateDID:  "did:ackid:0xb",
+		Action:       action,
+		Scope:        scope,
+		Constraints:  constraints,
+		IssuedAt:     1000,
+		ExpiresAt:    expiresAt,
+		Nonce:        "n",
+		MaxDepth:     2,
+	}
+}
+
+func TestCheckAttenuation(t *testing.T) {
+	window := map[string]interface{}{"valid_from": 100, "valid_until": 200, "days": []int{1, 2, 3}, "hours": []int{9, 10, 11}}
+	resources := map[string]interface{}{
+		"allowed_resources": []string{"hotels/*", "flights/eu"},
+		"denied_resources":  []string{"hotels/luxury"},
+	}
+
+	tests := []struct {
+		name   string
+		parent *DelegationClaim
+		child  *DelegationClaim
+		code   string // "" means the child is a valid attenuation
+	}{
+		{"identical", newTestDelegation("transfer", "ETH", 5000, nil), newTestDelegation("transfer", "ETH", 5000, nil), ""},
+		{"wildcard action", newTestDelegation("*", "ETH", 5000, nil), newTestDelegation("transfer", "ETH", 5000, nil), ""},
+		{"wider action", newTestDelegation("quote", "ETH", 5000, nil), newTestDelegation("transfer", "ETH", 5000, nil), ChainErrActionEscalation},
+		{"wildcard under exact action", newTestDelegation("transfer", "ETH", 5000, nil), newTestDelegation("*", "ETH", 5000, nil), ChainErrActionEscalation},
+		{"prefix scope", newTestDelegation("booking", "hotels/*", 5000, nil), newTestDelegation("booking", "hotels/eu", 5000, nil), ""},
+		{"narrower prefix scope", newTestDelegation("booking", "hotels/*", 5000, nil), newTestDelegation("booking", "hotels/eu/*", 5000, nil), ""},
+		{"wider scope", newTestDelegation("booking", "hotels/eu", 5000, nil), newTestDelegation("booking", "hotels/*", 5000, nil), ChainErrScopeEscalation},
+		{"later expiry", newTestDelegation("transfer", "ETH", 5000, nil), newTestDelegation("transfer", "ETH", 6000, nil), ChainErrExpiryEscalation},
+		{"never expires under expiring parent", newTestDelegation("transfer", "ETH", 5000, nil), newTestDelegation("transfer", "ETH", 0, nil), ChainErrExpiryEscalation},
+		{
+			"smaller amount",
+			newTestDelegation("transfer", "ETH", 5000, map[string]interface{}{"max_amount": "1000000000000000000000"}),
+			newTestDelegation("transfer", "ETH", 5000, map[string]interface{}{"max_amount": 5e20}),
+			"",
+		},
+		{
+			"larger amount",
+			newTestDelegation("transfer", "ETH", 5000, map[string]interface{}{"max_amount": "1000000000000000000000"}),
+			newTestDelegation("transfer", "ETH", 5000, map[string]interface{}{"max_amount": "1000000000000000000001"}),
+			ChainErrAmountEscalation,
+		},
+		{
+			"dropped amount",
+			newTestDelegation("transfer", "ETH", 5000, map[string]interface{}{"max_amount": "100"}),
+			newTestDelegation("transfer", "ETH", 5000, nil),
+			ChainErrAmountEscalation,
+		},
+		{
+			"amount added by child",
+			newTestDelegation("transfer", "ETH", 5000, nil),
+			newTestDelegation("transfer", "ETH", 5000, map[string]interface{}{"max_amount": "100"}),
+			"",
+		},
+		{
+			"narrower time window",
+			newTestDelegation("transfer", "ETH", 5000, map[string]interface{}{"time": window}),
+			newTestDelegation("transfer", "ETH", 5000, map[string]interface{}{"time": map[string]interface{}{"valid_from": 150, "valid_until": 200, "days": []int{2}, "hours": []int{9}}}),
+			"",
+		},
+		{
+			"wider time window",
+			newTestDelegation("transfer", "ETH", 5000, map[string]interface{}{"time": window}),
+			newTestDelegation("transfer", "ETH", 5000, map[string]interface{}{"time": map[string]interface{}{"valid_from": 100, "valid_until": 300, "days": []int{1}, "hours": []int{9}}}),
+			ChainErrTimeConstraint,
+		},
+		{
+			"extra day",
+			newTestDelegation("transfer", "ETH", 5000, map[string]interface{}{"time": window}),
+			newTestDelegation("transfer", "ETH", 5000, map[string]interface{}{"time": map[string]interface{}{"valid_from": 100, "valid_until": 200, "days": []int{1, 6}, "hours": []int{9}}}),
+			ChainErrTimeConstraint,
+		},
+		{
+			"fewer resources",
+			newTestDelegation("booking", "*", 5000, map[string]interface{}{"scope": resources}),
+			newTestDelegation("booking", "*", 5000, map[string]interface{}{"scope": map[string]interface{}{
+				"allowed_resources": []string{"hotels/eu"},
+				"denied_resources":  []string{"hotels/*"},
+			}}),
+			"",
+		},
+		{
+			"extra resource",
+			newTestDelegation("booking", "*", 5000, map[string]interface{}{"scope": resources}),
+			newTestDelegation("booking", "*", 5000, map[string]interface{}{"scope": map[string]interface{}{
+				"allowed_resources": []string{"cars/*"},
+				"denied_resources":  []string{"hotels/luxury"},
+			}}),
+			ChainErrScopeConstraint,
+		},
+		{
+			"lifted denial",
+			newTestDelegation("booking", "*", 5000, map[string]interface{}{"scope": resources}),
+			newTestDelegation("booking", "*", 5000, map[string]interface{}{"scope": map[string]interface{}{
+				"allowed_resources": []string{"hotels/eu"},
+			}}),
+			ChainErrScopeConstraint,
+		},
+		{
+			"changed unknown constraint",
+			newTestDelegation("transfer", "ETH", 5000, map[string]interface{}{"network": "mainnet"}),
+			newTestDelegation("transfer", "ETH", 5000, map[string]interface{}{"network": "sepolia"}),
+			ChainErrConstraint,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckAttenuation(tt.parent, tt.child)
+			if tt.code == "" {
+				if err != nil {
+					t.Fatalf("Expected valid attenuation, got %v", err)
+				}
+				return
+			}
+			var chainErr *ChainError
+			if !errors.As(err, &chainErr) || chainErr.Code != tt.code {
+				t.Fatalf("Expected %s, got %v", tt.code, err)
+			}
+		})
+	}
+}
+
+func TestValidateChainConstraintsAfterJSON(t *testing.T) {
+	parent := newTestDelegation("transfer", "ETH", 5000, map[string]interface{}{
+		"max_amount": "100",
+		"time":       map[string]interface{}{"valid_from": 100, "valid_until": 200},
+		"network":    map[string]interface{}{"chain_id": 1},
+	})
+	child := newTestDelegation("transfer", "ETH", 5000, map[string]interface{}{
+		"max_amount": "50",
+		"time":       map[string]interface{}{"valid_from": 120, "valid_until": 180},
+		"network":    map[string]interface{}{"chain_id": 1},
+	})
+
+	// Constraints read back from JSON use float64 and []interface{} but compare the same
+	var chain DelegationChain
+	data, err := json.Marshal(DelegationChain{Delegations: []*DelegationClaim{parent, child}})
+	if err != nil {
+		t.Fatalf("Failed to marshal chain: %v", err)
+	}
+	if err := json.Unmarshal(data, &chain); err != nil {
+		t.Fatalf("Failed to unmarshal chain: %v", err)
+	}
+	if err := chain.ValidateChainConstraints(); err != nil {
+		t.Fatalf("Expected valid chain, got %v", err)
+	}
+
+	chain.Delegations[1].Constraints["max_amount"] = "500"
+	var chainErr *ChainError
+	if err := chain.ValidateChainConstraints(); !errors.As(err, &chainErr) || chainErr.Code != ChainErrAmountEscalation {
+		t.Fatalf("Expected %s, got %v", ChainErrAmountEscalation, err)
+	}
+}
@@ -31,6 +31,9 @@ type AgentClaim struct {
 	Issuer   string   `json:"issuer,omitempty"`   // DID of the issuer (usually the Owner)
 	Subject  string   `json:"subject,omitempty"`  // DID of the subject (usually the Agent)
 	
+	// Status list entry used to revoke or suspend the claim
+	CredentialStatus *CredentialStatus `json:"credentialStatus,omitempty"`
+
 	// Cryptographic Proof
 	Proof *CredentialProof `json:"proof,omitempty"`
 }
@@ -49,6 +52,7 @@ type OwnershipClaim struct {
 	Context []string `json:"@context,omitempty"`
 	Issuer  string   `json:"issuer,omitempty"`
 	Subject string   `json:"subject,omitempty"`
+	CredentialStatus *CredentialStatus `json:"credentialStatus,omitempty"`
 	Proof   *CredentialProof `json:"proof,omitempty"`
 }
 
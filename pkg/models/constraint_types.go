@@ -0,0 +1,313 @@
+package models
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+)
+
+func init() {
+	RegisterConstraint(ConstraintMaxAmount, decodeAmountConstraint)
+	RegisterConstraint(ConstraintTime, decodeTimeConstraint)
+	RegisterConstraint(ConstraintScope, decodeScopeConstraint)
+	RegisterConstraint(ConstraintBudgetPeriod, decodeBudgetPeriodConstraint)
+	RegisterConstraint(ConstraintRateLimit, decodeRateLimitConstraint)
+	RegisterConstraint(ConstraintCounterparties, decodeCounterpartyConstraint)
+	RegisterConstraint(ConstraintGeo, decodeGeoConstraint)
+}
+
+// AmountConstraint caps the amount of each request (max_amount)
+type AmountConstraint struct {
+	Max *big.Int
+}
+
+func decodeAmountConstraint(raw json.RawMessage) (Constraint, error) {
+	decoder := json.NewDecoder(strings.NewReader(string(raw)))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	max, err := ParseAmount(value)
+	if err != nil {
+		return nil, err
+	}
+	return &AmountConstraint{Max: max}, nil
+}
+
+// MarshalJSON encodes the amount as a decimal string
+func (c *AmountConstraint) MarshalJSON() ([]byte, error) {
+	return json.Marshal(c.Max.String())
+}
+
+// Evaluate rejects requests for more than the maximum amount
+func (c *AmountConstraint) Evaluate(_ context.Context, req *ConstraintRequest) error {
+	if req.Amount == "" {
+		return nil
+	}
+	amount, err := ParseAmount(req.Amount)
+	if err != nil {
+		return fmt.Errorf("request amount: %w", err)
+	}
+	if amount.Cmp(c.Max) > 0 {
+		return fmt.Errorf("amount %s exceeds the maximum of %s", amount, c.Max)
+	}
+	return nil
+}
+
+// IsSubsetOf requires the maximum to be no larger than the parent's
+func (c *AmountConstraint) IsSubsetOf(parent Constraint) error {
+	p, ok := parent.(*AmountConstraint)
+	if !ok {
+		return fmt.Errorf("cannot compare with %T", parent)
+	}
+	if c.Max.Cmp(p.Max) > 0 {
+		return fmt.Errorf("max_amount %s exceeds its parent's %s", c.Max, p.Max)
+	}
+	return nil
+}
+
+func decodeScopeConstraint(raw json.RawMessage) (Constraint, error) {
+	var c ScopeConstraint
+	if err := decodeConstraint(raw, &c); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// Evaluate rejects request scopes that are not allowed or are denied
+func (c *ScopeConstraint) Evaluate(_ context.Context, req *ConstraintRequest) error {
+	if c.AllowedResources != nil && !anyPatternCovers(c.AllowedResources, req.TargetScope) {
+		return fmt.Errorf("resource %q is not in the allowed resources %v", req.TargetScope, c.AllowedResources)
+	}
+	if anyPatternCovers(c.DeniedResources, req.TargetScope) {
+		return fmt.Errorf("resource %q is denied", req.TargetScope)
+	}
+	return nil
+}
+
+// IsSubsetOf requires every allowed resource to be allowed by the parent and
+// every resource the parent denies to stay denied
+func (c *ScopeConstraint) IsSubsetOf(parent Constraint) error {
+	p, ok := parent.(*ScopeConstraint)
+	if !ok {
+		return fmt.Errorf("cannot compare with %T", parent)
+	}
+	if p.AllowedResources != nil {
+		if c.AllowedResources == nil {
+			return fmt.Errorf("drops its parent's allowed resources")
+		}
+		for _, resource := range c.AllowedResources {
+			if !anyPatternCovers(p.AllowedResources, resource) {
+				return fmt.Errorf("allows resource %q not allowed by its parent", resource)
+			}
+		}
+	}
+	for _, resource := range p.DeniedResources {
+		if !anyPatternCovers(c.DeniedResources, resource) {
+			return fmt.Errorf("no longer denies resource %q", resource)
+		}
+	}
+	return nil
+}
+
+// BudgetPeriodConstraint sets how often max_amount budgets reset (budget_period)
+type BudgetPeriodConstraint string
+
+func decodeBudgetPeriodConstraint(raw json.RawMessage) (Constraint, error) {
+	var period string
+	if err := decodeConstraint(raw, &period); err != nil {
+		return nil, err
+	}
+	switch period {
+	case "", "daily", "weekly":
+		c := BudgetPeriodConstraint(period)
+		return &c, nil
+	default:
+		return nil, fmt.Errorf("unknown budget period %q", period)
+	}
+}
+
+// Evaluate allows every request; budgets are enforced by a ledger
+func (c *BudgetPeriodConstraint) Evaluate(context.Context, *ConstraintRequest) error {
+	return nil
+}
+
+// IsSubsetOf requires the same period as the parent, since budgets are shared along a chain
+func (c *BudgetPeriodConstraint) IsSubsetOf(parent Constraint) error {
+	p, ok := parent.(*BudgetPeriodConstraint)
+	if !ok {
+		return fmt.Errorf("cannot compare with %T", parent)
+	}
+	if *c != *p {
+		return fmt.Errorf("budget period %q differs from its parent's %q", *c, *p)
+	}
+	return nil
+}
+
+// RateLimitConstraint allows at most MaxRequests requests in any window of WindowSeconds
+type RateLimitConstraint struct {
+	MaxRequests   int   `json:"max_requests"`
+	WindowSeconds int64 `json:"window_seconds"`
+}
+
+func decodeRateLimitConstraint(raw json.RawMessage) (Constraint, error) {
+	var c RateLimitConstraint
+	if err := decodeConstraint(raw, &c); err != nil {
+		return nil, err
+	}
+	if c.MaxRequests <= 0 || c.WindowSeconds <= 0 {
+		return nil, fmt.Errorf("max_requests and window_seconds must be positive")
+	}
+	return &c, nil
+}
+
+// Evaluate takes a request slot from the request's rate counter
+func (c *RateLimitConstraint) Evaluate(ctx context.Context, req *ConstraintRequest) error {
+	if req.Rates == nil {
+		return fmt.Errorf("no rate counter is available to enforce the limit")
+	}
+	window := time.Duration(c.WindowSeconds) * time.Second
+	ok, err := req.Rates.Take(ctx, req.CredentialID+"#"+ConstraintRateLimit, c.MaxRequests, window, req.Time)
+	if err != nil {
+		return fmt.Errorf("rate limit is unavailable: %w", err)
+	}
+	if !ok {
+		return fmt.Errorf("more than %d requests in %s", c.MaxRequests, window)
+	}
+	return nil
+}
+
+// IsSubsetOf requires no more requests over a window at least as long as the
+// parent's, so every parent window also holds at most the parent's maximum
+func (c *RateLimitConstraint) IsSubsetOf(parent Constraint) error {
+	p, ok := parent.(*RateLimitConstraint)
+	if !ok {
+		return fmt.Errorf("cannot compare with %T", parent)
+	}
+	if c.MaxRequests > p.MaxRequests || c.WindowSeconds < p.WindowSeconds {
+		return fmt.Errorf("%d requests per %ds exceeds its parent's %d per %ds",
+			c.MaxRequests, c.WindowSeconds, p.MaxRequests, p.WindowSeconds)
+	}
+	return nil
+}
+
+// CounterpartyConstraint limits which requesters an agent may act for. Entries
+// are DIDs or DID patterns such as "did:web:acme-corp.com*".
+type CounterpartyConstraint struct {
+	Allowed []string `json:"allowed,omitempty"`
+	Denied  []string `json:"denied,omitempty"`
+}
+
+func decodeCounterpartyConstraint(raw json.RawMessage) (Constraint, error) {
+	var c CounterpartyConstraint
+	if err := decodeConstraint(raw, &c); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// Evaluate rejects requesters that are not allowed or are denied
+func (c *CounterpartyConstraint) Evaluate(_ context.Context, req *ConstraintRequest) error {
+	if len(c.Allowed) > 0 && !anyPatternCovers(c.Allowed, req.RequesterDID) {
+		return fmt.Errorf("counterparty %q is not allowed", req.RequesterDID)
+	}
+	if anyPatternCovers(c.Denied, req.RequesterDID) {
+		return fmt.Errorf("counterparty %q is denied", req.RequesterDID)
+	}
+	return nil
+}
+
+// IsSubsetOf requires allowed counterparties to be allowed by the parent and
+// the parent's denied counterparties to stay denied
+func (c *CounterpartyConstraint) IsSubsetOf(parent Constraint) error {
+	p, ok := parent.(*CounterpartyConstraint)
+	if !ok {
+		return fmt.Errorf("cannot compare with %T", parent)
+	}
+	if len(p.Allowed) > 0 {
+		if len(c.Allowed) == 0 {
+			return fmt.Errorf("drops its parent's allowed counterparties")
+		}
+		for _, counterparty := range c.Allowed {
+			if !anyPatternCovers(p.Allowed, counterparty) {
+				return fmt.Errorf("allows counterparty %q not allowed by its parent", counterparty)
+			}
+		}
+	}
+	for _, counterparty := range p.Denied {
+		if !anyPatternCovers(c.Denied, counterparty) {
+			return fmt.Errorf("no longer denies counterparty %q", counterparty)
+		}
+	}
+	return nil
+}
+
+// GeoConstraint limits the regions requests may come from. Regions are ISO 3166
+// codes; a country such as "US" also covers its subdivisions such as "US-CA".
+type GeoConstraint struct {
+	AllowedRegions []string `json:"allowed_regions,omitempty"`
+	DeniedRegions  []string `json:"denied_regions,omitempty"`
+}
+
+func decodeGeoConstraint(raw json.RawMessage) (Constraint, error) {
+	var c GeoConstraint
+	if err := decodeConstraint(raw, &c); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// Evaluate rejects requests from regions that are not allowed, are denied or are unknown
+func (c *GeoConstraint) Evaluate(_ context.Context, req *ConstraintRequest) error {
+	if req.Region == "" {
+		return fmt.Errorf("request region is unknown")
+	}
+	if len(c.AllowedRegions) > 0 && !anyRegionCovers(c.AllowedRegions, req.Region) {
+		return fmt.Errorf("region %s is not in the allowed regions %v", req.Region, c.AllowedRegions)
+	}
+	if anyRegionCovers(c.DeniedRegions, req.Region) {
+		return fmt.Errorf("region %s is denied", req.Region)
+	}
+	return nil
+}
+
+// IsSubsetOf requires allowed regions to be within the parent's and the
+// parent's denied regions to stay denied
+func (c *GeoConstraint) IsSubsetOf(parent Constraint) error {
+	p, ok := parent.(*GeoConstraint)
+	if !ok {
+		return fmt.Errorf("cannot compare with %T", parent)
+	}
+	if len(p.AllowedRegions) > 0 {
+		if len(c.AllowedRegions) == 0 {
+			return fmt.Errorf("drops its parent's allowed regions")
+		}
+		for _, region := range c.AllowedRegions {
+			if !anyRegionCovers(p.AllowedRegions, region) {
+				return fmt.Errorf("allows region %s not allowed by its parent", region)
+			}
+		}
+	}
+	for _, region := range p.DeniedRegions {
+		if !anyRegionCovers(c.DeniedRegions, region) {
+			return fmt.Errorf("no longer denies region %s", region)
+		}
+	}
+	return nil
+}
+
+// anyRegionCovers reports whether region is one of regions or a subdivision of one
+func anyRegionCovers(regions []string, region string) bool {
+	region = strings.ToUpper(region)
+	for _, r := range regions {
+		r = strings.ToUpper(r)
+		if r == region || strings.HasPrefix(region, r+"-") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,245 @@
+package models
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Constraint is a typed entry of DelegationClaim.Constraints. Each registered
+// constraint name has a Go type that decodes from and encodes to its JSON form.
+type Constraint interface {
+	// Evaluate returns an error if the constraint does not allow the request
+	Evaluate(ctx context.Context, req *ConstraintRequest) error
+
+	// IsSubsetOf returns an error describing how the constraint allows more than
+	// parent, a constraint of the same name, or nil if it is at least as strict
+	IsSubsetOf(parent Constraint) error
+}
+
+// ConstraintRequest is what a constraint is evaluated against: an authorization
+// request together with facts established by the verifier rather than the requester
+type ConstraintRequest struct {
+	*AuthorizationRequest
+	CredentialID string      // Credential the constraint belongs to, set by EvaluateConstraints
+	Time         time.Time   // Time the request is evaluated at
+	Region       string      // ISO 3166 region the request comes from, e.g. "US" or "US-CA"
+	Rates        RateCounter // Request counter for rate limits; rate limits fail without one
+}
+
+// RateCounter counts requests for rate limit constraints
+type RateCounter interface {
+	// Take records a request against key if fewer than limit were recorded in
+	// the window ending at at, and reports whether it did
+	Take(ctx context.Context, key string, limit int, window time.Duration, at time.Time) (bool, error)
+	// Release gives back a request Take recorded against key at at, for a
+	// request that was denied after its slot was taken
+	Release(ctx context.Context, key string, at time.Time) error
+}
+
+// ConstraintDecoder decodes the JSON value of a constraint into its Go type
+type ConstraintDecoder func(value json.RawMessage) (Constraint, error)
+
+// ConstraintCritical lists the constraints a verifier must understand. A verifier
+// that finds an unregistered constraint in this list rejects the delegation.
+const ConstraintCritical = "critical"
+
+var constraintRegistry = struct {
+	sync.RWMutex
+	decoders map[string]ConstraintDecoder
+}{decoders: make(map[string]ConstraintDecoder)}
+
+// RegisterConstraint makes a constraint type available under a name. It panics if
+// the name is already registered.
+func RegisterConstraint(name string, decode ConstraintDecoder) {
+	constraintRegistry.Lock()
+	defer constraintRegistry.Unlock()
+	if name == ConstraintCritical {
+		panic("models: constraint name " + name + " is reserved")
+	}
+	if _, ok := constraintRegistry.decoders[name]; ok {
+		panic("models: constraint " + name + " is already registered")
+	}
+	constraintRegistry.decoders[name] = decode
+}
+
+// IsRegisteredConstraint reports whether a constraint name has a registered type
+func IsRegisteredConstraint(name string) bool {
+	constraintRegistry.RLock()
+	defer constraintRegistry.RUnlock()
+	_, ok := constraintRegistry.decoders[name]
+	return ok
+}
+
+// ParseConstraint decodes a constraint value into its registered type. Values of
+// unregistered names decode to an OpaqueConstraint.
+func ParseConstraint(name string, value interface{}) (Constraint, error) {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("constraint %q: %w", name, err)
+	}
+
+	constraintRegistry.RLock()
+	decode, ok := constraintRegistry.decoders[name]
+	constraintRegistry.RUnlock()
+	if !ok {
+		return &OpaqueConstraint{Name: name, Value: raw}, nil
+	}
+
+	constraint, err := decode(raw)
+	if err != nil {
+		return nil, fmt.Errorf("constraint %q: %w", name, err)
+	}
+	return constraint, nil
+}
+
+// CriticalConstraints returns the names listed in the delegation's critical constraint
+func (dc *DelegationClaim) CriticalConstraints() ([]string, error) {
+	value, ok := dc.Constraints[ConstraintCritical]
+	if !ok {
+		return nil, nil
+	}
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("constraint %q: %w", ConstraintCritical, err)
+	}
+	var names []string
+	if err := decodeConstraint(raw, &names); err != nil {
+		return nil, fmt.Errorf("constraint %q: %w", ConstraintCritical, err)
+	}
+	return names, nil
+}
+
+// ParseConstraints decodes every constraint of the delegation. It fails if a
+// constraint is malformed or a critical constraint is not registered.
+func (dc *DelegationClaim) ParseConstraints() (map[string]Constraint, error) {
+	critical, err := dc.CriticalConstraints()
+	if err != nil {
+		return nil, &ChainError{Code: ChainErrConstraint, Message: err.Error()}
+	}
+	for _, name := range critical {
+		if !IsRegisteredConstraint(name) {
+			return nil, &ChainError{
+				Code:    ChainErrConstraint,
+				Message: fmt.Sprintf("unknown critical constraint %q", name),
+			}
+		}
+	}
+
+	constraints := make(map[string]Constraint, len(dc.Constraints))
+	for name, value := range dc.Constraints {
+		if name == ConstraintCritical {
+			continue
+		}
+		constraint, err := ParseConstraint(name, value)
+		if err != nil {
+			return nil, &ChainError{Code: constraintCode(name), Message: err.Error()}
+		}
+		constraints[name] = constraint
+	}
+	return constraints, nil
+}
+
+// SetConstraint stores a typed constraint in the delegation's JSON form
+func (dc *DelegationClaim) SetConstraint(name string, constraint Constraint) error {
+	raw, err := json.Marshal(constraint)
+	if err != nil {
+		return fmt.Errorf("constraint %q: %w", name, err)
+	}
+	var value interface{}
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return fmt.Errorf("constraint %q: %w", name, err)
+	}
+	if dc.Constraints == nil {
+		dc.Constraints = make(map[string]interface{})
+	}
+	dc.Constraints[name] = value
+	return nil
+}
+
+// EvaluateConstraints evaluates every constraint of the delegation against a
+// request and returns a ChainError for the first that fails. Constraints run in
+// name order, except the rate limit which runs last so that requests rejected by
+// the other constraints do not use up its slots. A caller that denies the
+// request for any other reason must release the slot (see RateCounter.Release).
+func (dc *DelegationClaim) EvaluateConstraints(ctx context.Context, req *ConstraintRequest) error {
+	constraints, err := dc.ParseConstraints()
+	if err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(constraints))
+	for name := range constraints {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if (names[i] == ConstraintRateLimit) != (names[j] == ConstraintRateLimit) {
+			return names[j] == ConstraintRateLimit
+		}
+		return names[i] < names[j]
+	})
+
+	id, err := dc.CredentialID()
+	if err != nil {
+		return err
+	}
+	scoped := *req
+	scoped.CredentialID = id
+	for _, name := range names {
+		if err := constraints[name].Evaluate(ctx, &scoped); err != nil {
+			return &ChainError{
+				Code:    constraintCode(name),
+				Message: fmt.Sprintf("%s constraint: %v", name, err),
+			}
+		}
+	}
+	return nil
+}
+
+// OpaqueConstraint is a constraint with no registered type. It places no limit
+// on requests, and a delegation may only pass it on unchanged.
+type OpaqueConstraint struct {
+	Name  string
+	Value json.RawMessage
+}
+
+// MarshalJSON encodes the constraint as its original value
+func (c *OpaqueConstraint) MarshalJSON() ([]byte, error) {
+	return c.Value, nil
+}
+
+// Evaluate allows every request
+func (c *OpaqueConstraint) Evaluate(context.Context, *ConstraintRequest) error {
+	return nil
+}
+
+// IsSubsetOf requires the constraint to equal its parent's
+func (c *OpaqueConstraint) IsSubsetOf(parent Constraint) error {
+	p, ok := parent.(*OpaqueConstraint)
+	if !ok {
+		return fmt.Errorf("cannot compare with %T", parent)
+	}
+	var a, b interface{}
+	if json.Unmarshal(c.Value, &a) != nil || json.Unmarshal(p.Value, &b) != nil || !jsonEqual(a, b) {
+		return fmt.Errorf("changes the %q constraint set by its parent", c.Name)
+	}
+	return nil
+}
+
+// decodeConstraint strictly decodes a constraint value, rejecting unknown fields
+// and values of the wrong type instead of silently zeroing them
+func decodeConstraint(raw json.RawMessage, v interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		return err
+	}
+	if decoder.More() {
+		return fmt.Errorf("unexpected data after constraint value")
+	}
+	return nil
+}
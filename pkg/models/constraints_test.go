@@ -0,0 +1,267 @@
+package models
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fixedRates allows a fixed number of requests per key, ignoring the window
+type fixedRates map[string]int
+
+func (r fixedRates) Take(_ context.Context, key string, limit int, _ time.Duration, _ time.Time) (bool, error) {
+	if r[key] >= limit {
+		return false, nil
+	}
+	r[key]++
+	return true, nil
+}
+
+func (r fixedRates) Release(_ context.Context, key string, _ time.Time) error {
+	r[key]--
+	return nil
+}
+
+func TestParseConstraintsStrict(t *testing.T) {
+	tests := map[string]interface{}{
+		ConstraintTime:           map[string]interface{}{"days": "monday"},
+		ConstraintScope:          map[string]interface{}{"allowed": []string{"hotels"}},
+		ConstraintMaxAmount:      "ten",
+		ConstraintRateLimit:      map[string]interface{}{"max_requests": 0, "window_seconds": 60},
+		ConstraintCounterparties: []string{"did:web:acme-corp.com"},
+		ConstraintGeo:            map[string]interface{}{"allowed_regions": "US"},
+		ConstraintBudgetPeriod:   "hourly",
+	}
+	for name, value := range tests {
+		_, err := ParseConstraint(name, value)
+		if err == nil {
+			t.Errorf("%s: expected malformed value %v to be rejected", name, value)
+		}
+	}
+
+	// A malformed constraint invalidates the delegation
+	delegation := newTestDelegation("transfer", "ETH", 0, map[string]interface{}{ConstraintTime: map[string]interface{}{"hours": []interface{}{9.5}}})
+	chain := &DelegationChain{Delegations: []*DelegationClaim{delegation}}
+	if chain.ValidateChain() {
+		t.Error("expected a chain with a malformed time constraint to be invalid")
+	}
+}
+
+func TestCriticalConstraints(t *testing.T) {
+	delegation := newTestDelegation("transfer", "ETH", 0, map[string]interface{}{
+		"x-loyalty-tier":    "gold",
+		ConstraintCritical:  []string{ConstraintMaxAmount},
+		ConstraintMaxAmount: "100",
+	})
+	req := &ConstraintRequest{AuthorizationRequest: &AuthorizationRequest{TargetScope: "ETH", Amount: "50"}, Time: time.Now()}
+
+	// Unknown constraints that are not critical are carried but not enforced
+	constraints, err := delegation.ParseConstraints()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := constraints["x-loyalty-tier"].(*OpaqueConstraint); !ok {
+		t.Errorf("expected an opaque constraint, got %T", constraints["x-loyalty-tier"])
+	}
+	if err := delegation.EvaluateConstraints(context.Background(), req); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	// Once marked critical, a verifier that does not understand it fails closed
+	delegation.Constraints[ConstraintCritical] = []string{ConstraintMaxAmount, "x-loyalty-tier"}
+	err = delegation.EvaluateConstraints(context.Background(), req)
+	if err == nil || !strings.Contains(err.Error(), `unknown critical constraint "x-loyalty-tier"`) {
+		t.Errorf("expected unknown critical constraint error, got %v", err)
+	}
+	chain := &DelegationChain{Delegations: []*DelegationClaim{delegation}}
+	if chain.ValidateChain() {
+		t.Error("expected a chain with an unknown critical constraint to be invalid")
+	}
+
+	// A child cannot drop its parent's critical marks
+	parent := newTestDelegation("transfer", "ETH", 0, map[string]interface{}{
+		ConstraintMaxAmount: "100",
+		ConstraintCritical:  []string{ConstraintMaxAmount},
+	})
+	child := newTestDelegation("transfer", "ETH", 0, map[string]interface{}{ConstraintMaxAmount: "100"})
+	var chainErr *ChainError
+	if err := CheckAttenuation(parent, child); !errors.As(err, &chainErr) || chainErr.Code != ChainErrConstraint {
+		t.Errorf("expected a constraint violation, got %v", err)
+	}
+}
+
+func TestEvaluateConstraints(t *testing.T) {
+	constraints := map[string]interface{}{
+		ConstraintMaxAmount:      "100",
+		ConstraintScope:          map[string]interface{}{"allowed_resources": []string{"hotels/*"}, "denied_resources": []string{"hotels/luxury"}},
+		ConstraintRateLimit:      map[string]interface{}{"max_requests": 2, "window_seconds": 3600},
+		ConstraintCounterparties: map[string]interface{}{"allowed": []string{"did:web:acme-corp.com*"}},
+		ConstraintGeo:            map[string]interface{}{"allowed_regions": []string{"US", "CA"}, "denied_regions": []string{"US-NY"}},
+	}
+	delegation := newTestDelegation("booking", "hotels/*", 0, constraints)
+	rates := fixedRates{}
+
+	request := func() *ConstraintRequest {
+		return &ConstraintRequest{
+			AuthorizationRequest: &AuthorizationRequest{
+				TargetAction: "booking",
+				TargetScope:  "hotels/paris",
+				Amount:       "80",
+				RequesterDID: "did:web:acme-corp.com:travel",
+			},
+			Time:   time.Now(),
+			Region: "US-CA",
+			Rates:  rates,
+		}
+	}
+
+	tests := []struct {
+		name   string
+		modify func(req *ConstraintRequest)
+		reason string
+	}{
+		{"allowed", func(*ConstraintRequest) {}, ""},
+		{"amount", func(req *ConstraintRequest) { req.Amount = "101" }, "exceeds the maximum"},
+		{"denied resource", func(req *ConstraintRequest) { req.TargetScope = "hotels/luxury" }, "is denied"},
+		{"resource outside scope", func(req *ConstraintRequest) { req.TargetScope = "flights/paris" }, "not in the allowed resources"},
+		{"counterparty", func(req *ConstraintRequest) { req.RequesterDID = "did:web:evil.com" }, "counterparty"},
+		{"denied region", func(req *ConstraintRequest) { req.Region = "US-NY" }, "region US-NY is denied"},
+		{"unknown region", func(req *ConstraintRequest) { req.Region = "" }, "region is unknown"},
+		{"no rate counter", func(req *ConstraintRequest) { req.Rates = nil }, "no rate counter"},
+		{"rate limit", func(*ConstraintRequest) {}, "more than 2 requests"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := request()
+			tt.modify(req)
+			err := delegation.EvaluateConstraints(context.Background(), req)
+			if tt.reason == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				// Use up the second request so the rate limit case is exceeded
+				if err := delegation.EvaluateConstraints(context.Background(), request()); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.reason) {
+				t.Errorf("expected %q, got %v", tt.reason, err)
+			}
+		})
+	}
+	id, err := delegation.CredentialID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rates[id+"#rate_limit"] != 2 {
+		t.Errorf("expected the rate limit to be counted under the credential, got %v", rates)
+	}
+}
+
+func TestConstraintSubsets(t *testing.T) {
+	tests := []struct {
+		name          string
+		kind          string
+		parent, child interface{}
+		ok            bool
+	}{
+		{"slower rate", ConstraintRateLimit, map[string]int{"max_requests": 10, "window_seconds": 60}, map[string]int{"max_requests": 5, "window_seconds": 120}, true},
+		{"shorter window", ConstraintRateLimit, map[string]int{"max_requests": 10, "window_seconds": 60}, map[string]int{"max_requests": 1, "window_seconds": 1}, false},
+		{"more requests", ConstraintRateLimit, map[string]int{"max_requests": 10, "window_seconds": 60}, map[string]int{"max_requests": 11, "window_seconds": 60}, false},
+		{"fewer counterparties", ConstraintCounterparties, map[string][]string{"allowed": {"did:web:*"}}, map[string][]string{"allowed": {"did:web:acme-corp.com"}}, true},
+		{"other counterparties", ConstraintCounterparties, map[string][]string{"allowed": {"did:web:*"}}, map[string][]string{"allowed": {"did:key:z6Mk"}}, false},
+		{"undenied counterparty", ConstraintCounterparties, map[string][]string{"denied": {"did:web:evil.com"}}, map[string][]string{}, false},
+		{"subdivision", ConstraintGeo, map[string][]string{"allowed_regions": {"US"}}, map[string][]string{"allowed_regions": {"US-CA"}}, true},
+		{"wider region", ConstraintGeo, map[string][]string{"allowed_regions": {"US-CA"}}, map[string][]string{"allowed_regions": {"US"}}, false},
+		{"dropped regions", ConstraintGeo, map[string][]string{"allowed_regions": {"US"}}, map[string][]string{}, false},
+		{"same period", ConstraintBudgetPeriod, "daily", "daily", true},
+		{"other period", ConstraintBudgetPeriod, "daily", "weekly", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parent, err := ParseConstraint(tt.kind, tt.parent)
+			if err != nil {
+				t.Fatal(err)
+			}
+			child, err := ParseConstraint(tt.kind, tt.child)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := child.IsSubsetOf(parent); (err == nil) != tt.ok {
+				t.Errorf("IsSubsetOf = %v, want ok=%v", err, tt.ok)
+			}
+		})
+	}
+}
+
+// allowlistConstraint is a custom constraint limiting the request action
+type allowlistConstraint struct {
+	Actions []string `json:"actions"`
+}
+
+func (c *allowlistConstraint) Evaluate(_ context.Context, req *ConstraintRequest) error {
+	if !containsString(c.Actions, req.TargetAction) {
+		return errors.New("action not on the allowlist")
+	}
+	return nil
+}
+
+func (c *allowlistConstraint) IsSubsetOf(parent Constraint) error {
+	for _, action := range c.Actions {
+		if !containsString(parent.(*allowlistConstraint).Actions, action) {
+			return errors.New("wider allowlist")
+		}
+	}
+	return nil
+}
+
+func TestRegisterConstraint(t *testing.T) {
+	RegisterConstraint("x-test-allowlist", func(raw json.RawMessage) (Constraint, error) {
+		var c allowlistConstraint
+		return &c, decodeConstraint(raw, &c)
+	})
+
+	delegation := newTestDelegation("transfer", "ETH", 0, nil)
+	if err := delegation.SetConstraint("x-test-allowlist", &allowlistConstraint{Actions: []string{"quote"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := delegation.SetConstraint(ConstraintMaxAmount, &AmountConstraint{Max: big.NewInt(500)}); err != nil {
+		t.Fatal(err)
+	}
+	delegation.Constraints[ConstraintCritical] = []string{"x-test-allowlist"}
+	if delegation.Constraints[ConstraintMaxAmount] != "500" {
+		t.Errorf("amount stored as %#v", delegation.Constraints[ConstraintMaxAmount])
+	}
+
+	// Survives the JSON round trip a signed delegation goes through
+	data, err := json.Marshal(delegation)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded DelegationClaim
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	req := &ConstraintRequest{AuthorizationRequest: &AuthorizationRequest{TargetAction: "transfer"}, Time: time.Now()}
+	err = decoded.EvaluateConstraints(context.Background(), req)
+	if err == nil || !strings.Contains(err.Error(), "x-test-allowlist constraint: action not on the allowlist") {
+		t.Errorf("unexpected error %v", err)
+	}
+	req.TargetAction = "quote"
+	if err := decoded.EvaluateConstraints(context.Background(), req); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected registering a name twice to panic")
+		}
+	}()
+	RegisterConstraint(ConstraintTime, decodeTimeConstraint)
+}
@@ -0,0 +1,111 @@
+package models
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/ak68a/agentid-core/pkg/jcs"
+	"golang.org/x/crypto/sha3"
+)
+
+// Multihash header of a keccak-256 digest: code 0x1b, length 32
+const keccakMultihashPrefix = "1b20"
+
+// credentialIDLength is the length of a 0x-prefixed hex credential ID
+const credentialIDLength = 2 + len(keccakMultihashPrefix) + 64
+
+// Credential is implemented by every model with a content-addressed ID
+type Credential interface {
+	CredentialID() (string, error)
+}
+
+// CanonicalForm returns the RFC 8785 canonical JSON of a credential without
+// its proof or signature, i.e. exactly what its issuer signed. Re-signing a
+// credential therefore never changes its canonical form.
+func CanonicalForm(credential interface{}) ([]byte, error) {
+	data, err := json.Marshal(credential)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode credential: %w", err)
+	}
+
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(data, &members); err != nil {
+		return nil, fmt.Errorf("credential is not a JSON object: %w", err)
+	}
+	delete(members, "proof")
+	delete(members, "signature")
+
+	canonical, err := jcs.Marshal(members)
+	if err != nil {
+		return nil, fmt.Errorf("failed to canonicalize credential: %w", err)
+	}
+	return canonical, nil
+}
+
+// ComputeCredentialID returns the content-addressed ID of a credential: the
+// 0x-prefixed hex multihash of the keccak-256 digest of its canonical form
+func ComputeCredentialID(credential interface{}) (string, error) {
+	canonical, err := CanonicalForm(credential)
+	if err != nil {
+		return "", err
+	}
+	hash := sha3.NewLegacyKeccak256()
+	hash.Write(canonical)
+	return "0x" + keccakMultihashPrefix + hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// IsCredentialID reports whether ref has the form of a credential ID
+func IsCredentialID(ref string) bool {
+	if len(ref) != credentialIDLength || !strings.HasPrefix(strings.ToLower(ref), "0x"+keccakMultihashPrefix) {
+		return false
+	}
+	_, err := hex.DecodeString(ref[2:])
+	return err == nil
+}
+
+// CredentialID returns the content-addressed ID of the claim
+func (ac *AgentClaim) CredentialID() (string, error) {
+	return ComputeCredentialID(ac)
+}
+
+// CredentialID returns the content-addressed ID of the claim
+func (oc *OwnershipClaim) CredentialID() (string, error) {
+	return ComputeCredentialID(oc)
+}
+
+// CredentialID returns the content-addressed ID of the delegation
+func (dc *DelegationClaim) CredentialID() (string, error) {
+	return ComputeCredentialID(dc)
+}
+
+// CredentialID returns the content-addressed ID of the revocation
+func (rc *RevocationClaim) CredentialID() (string, error) {
+	return ComputeCredentialID(rc)
+}
+
+// CredentialID returns the content-addressed ID of the list snapshot
+func (rl *RevocationList) CredentialID() (string, error) {
+	return ComputeCredentialID(rl)
+}
+
+// CredentialID returns the content-addressed ID of the delta
+func (d *RevocationDelta) CredentialID() (string, error) {
+	return ComputeCredentialID(d)
+}
+
+// CredentialID returns the content-addressed ID of the status list credential
+func (c *StatusList2021Credential) CredentialID() (string, error) {
+	return ComputeCredentialID(c)
+}
+
+// CredentialID returns the content-addressed ID of the request
+func (r *AuthorizationRequest) CredentialID() (string, error) {
+	return ComputeCredentialID(r)
+}
+
+// CredentialID returns the content-addressed ID of the response
+func (r *AuthorizationResponse) CredentialID() (string, error) {
+	return ComputeCredentialID(r)
+}
@@ -0,0 +1,68 @@
+package models
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCredentialID(t *testing.T) {
+	claim := NewTransferClaim("did:ackid:0xa", "did:ackid:0xo", ScopeETH, "1000", 1700000000, "n1")
+	claim.IssuedAt = 1690000000
+
+	id, err := claim.CredentialID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(id, "0x1b20") || len(id) != 70 || !IsCredentialID(id) {
+		t.Fatalf("expected a keccak-256 multihash, got %s", id)
+	}
+
+	// The proof is not part of the ID, so re-signing keeps it stable
+	claim.Proof = &CredentialProof{Type: string(EcdsaSecp256k1Signature2019), ProofValue: "00"}
+	if resigned, _ := claim.CredentialID(); resigned != id {
+		t.Errorf("proof changed the ID: %s != %s", resigned, id)
+	}
+
+	// Another issuer reusing the nonce gets a different ID
+	other := *claim
+	other.OwnerDID = "did:ackid:0xother"
+	if otherID, _ := other.CredentialID(); otherID == id {
+		t.Error("different credentials share an ID")
+	}
+
+	for _, ref := range []string{"n1", "0x1b20", "0x1220" + id[6:], id[:69] + "z"} {
+		if IsCredentialID(ref) {
+			t.Errorf("%q should not be a credential ID", ref)
+		}
+	}
+}
+
+func TestChainParentCommitment(t *testing.T) {
+	root := &DelegationClaim{DelegatorDID: "did:ackid:0xa", DelegateDID: "did:ackid:0xb", Nonce: "n", MaxDepth: 2}
+	leaf := &DelegationClaim{DelegatorDID: "did:ackid:0xb", DelegateDID: "did:ackid:0xc", Nonce: "n", MaxDepth: 2}
+	if err := leaf.SetParent(root); err != nil {
+		t.Fatal(err)
+	}
+	if leaf.CurrentDepth != 1 {
+		t.Errorf("expected depth 1, got %d", leaf.CurrentDepth)
+	}
+
+	chain := &DelegationChain{Delegations: []*DelegationClaim{root, leaf}}
+	if !chain.ValidateChain() {
+		t.Fatalf("expected a valid chain: %s", chain.Reason)
+	}
+
+	// A parent swapped for another delegation between the same agents breaks the chain
+	swapped := *root
+	swapped.Action = ActionTransfer
+	chain.Delegations[0] = &swapped
+	if chain.ValidateChain() || !strings.Contains(chain.Reason, "references parent") {
+		t.Errorf("expected a parent mismatch, got %q", chain.Reason)
+	}
+
+	leaf.ParentDelegation = nil
+	chain.Delegations[0] = root
+	if chain.ValidateChain() {
+		t.Error("expected a chain without parent references to be invalid")
+	}
+}
@@ -1,7 +1,9 @@
 package models
 
 import (
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 )
 
@@ -23,7 +25,7 @@ type DelegationClaim struct {
 	Nonce     string `json:"nonce"`
 	
 	// Chain tracking
-	ParentDelegation *string `json:"parent_delegation,omitempty"` // Reference to parent in chain
+	ParentDelegation *string `json:"parent_delegation,omitempty"` // Credential ID of the parent in the chain
 	MaxDepth         int     `json:"max_depth"`                   // How many levels can be sub-delegated
 	CurrentDepth     int     `json:"current_depth"`               // Current position in chain
 	
@@ -32,6 +34,7 @@ type DelegationClaim struct {
 	Context []string `json:"@context,omitempty"`
 	Issuer  string   `json:"issuer,omitempty"`
 	Subject string   `json:"subject,omitempty"`
+	CredentialStatus *CredentialStatus `json:"credentialStatus,omitempty"`
 	Proof   *CredentialProof `json:"proof,omitempty"`
 }
 
@@ -64,16 +67,41 @@ type ChainError struct {
 	Message string
 }
 
+// ChainError codes
+const (
+	ChainErrEmptyChain       = "EMPTY_CHAIN"
+	ChainErrInvalidChain     = "INVALID_CHAIN"
+	ChainErrParentNotFound   = "PARENT_NOT_FOUND"
+	ChainErrCycle            = "CHAIN_CYCLE"
+	ChainErrDepthExceeded    = "DEPTH_EXCEEDED"
+	ChainErrInvalidDepth     = "INVALID_DEPTH"
+	ChainErrActionEscalation = "ACTION_ESCALATION"
+	ChainErrScopeEscalation  = "SCOPE_ESCALATION"
+	ChainErrAmountEscalation = "AMOUNT_ESCALATION"
+	ChainErrExpiryEscalation = "EXPIRY_ESCALATION"
+	ChainErrTimeConstraint   = "TIME_CONSTRAINT_VIOLATION"
+	ChainErrScopeConstraint  = "SCOPE_CONSTRAINT_VIOLATION"
+	ChainErrConstraint       = "CONSTRAINT_VIOLATION"
+	ChainErrRevoked          = "REVOKED"
+	ChainErrNotRevoker       = "NOT_REVOKER"
+	ChainErrParentMismatch   = "PARENT_MISMATCH"
+)
+
 func (e *ChainError) Error() string {
 	return fmt.Sprintf("%s: %s", e.Code, e.Message)
 }
 
 // IsExpired checks if a delegation has expired
 func (dc *DelegationClaim) IsExpired() bool {
+	return dc.IsExpiredAt(time.Now())
+}
+
+// IsExpiredAt checks if a delegation has expired at time t
+func (dc *DelegationClaim) IsExpiredAt(t time.Time) bool {
 	if dc.ExpiresAt == 0 {
 		return false // Never expires
 	}
-	return time.Now().Unix() > dc.ExpiresAt
+	return t.Unix() > dc.ExpiresAt
 }
 
 // CanSubDelegate checks if this delegation allows further sub-delegation
@@ -81,15 +109,12 @@ func (dc *DelegationClaim) CanSubDelegate() bool {
 	return dc.CurrentDepth < dc.MaxDepth
 }
 
-// GetTimeConstraint extracts time constraint from the delegation
+// GetTimeConstraint returns the delegation's time constraint, or nil if it has
+// none or it is malformed; ParseConstraints reports the error
 func (dc *DelegationClaim) GetTimeConstraint() *TimeConstraint {
-	if timeConstr, ok := dc.Constraints["time"].(map[string]interface{}); ok {
-		return &TimeConstraint{
-			ValidFrom:  getInt64(timeConstr, "valid_from"),
-			ValidUntil: getInt64(timeConstr, "valid_until"),
-			TimeZone:   getString(timeConstr, "timezone"),
-			Days:       getIntSlice(timeConstr, "days"),
-			Hours:      getIntSlice(timeConstr, "hours"),
+	if value, ok := dc.Constraints[ConstraintTime]; ok {
+		if constraint, err := ParseConstraint(ConstraintTime, value); err == nil {
+			return constraint.(*TimeConstraint)
 		}
 	}
 	return nil
@@ -97,6 +122,12 @@ func (dc *DelegationClaim) GetTimeConstraint() *TimeConstraint {
 
 // ValidateChain validates an entire delegation chain
 func (chain *DelegationChain) ValidateChain() bool {
+	return chain.ValidateChainAt(time.Now())
+}
+
+// ValidateChainAt validates an entire delegation chain for use at time t,
+// including each delegation's time constraint
+func (chain *DelegationChain) ValidateChainAt(t time.Time) bool {
 	if len(chain.Delegations) == 0 {
 		chain.Valid = false
 		chain.Reason = "empty delegation chain"
@@ -106,12 +137,27 @@ func (chain *DelegationChain) ValidateChain() bool {
 	// Check each delegation in the chain
 	for i, delegation := range chain.Delegations {
 		// Check expiration
-		if delegation.IsExpired() {
+		if delegation.IsExpiredAt(t) {
 			chain.Valid = false
 			chain.Reason = fmt.Sprintf("delegation %d is expired", i)
 			return false
 		}
 
+		// Check that the constraints are understood and the time window is open
+		constraints, err := delegation.ParseConstraints()
+		if err != nil {
+			chain.Valid = false
+			chain.Reason = fmt.Sprintf("delegation %d: %v", i, err)
+			return false
+		}
+		if timeConstraint, ok := constraints[ConstraintTime].(*TimeConstraint); ok {
+			if err := timeConstraint.Check(t); err != nil {
+				chain.Valid = false
+				chain.Reason = fmt.Sprintf("delegation %d: %v", i, err)
+				return false
+			}
+		}
+
 		// Check depth constraints
 		if delegation.CurrentDepth > delegation.MaxDepth {
 			chain.Valid = false
@@ -127,6 +173,11 @@ func (chain *DelegationChain) ValidateChain() bool {
 				chain.Reason = fmt.Sprintf("broken chain at delegation %d", i)
 				return false
 			}
+			if err := checkParent(prevDelegation, delegation); err != nil {
+				chain.Valid = false
+				chain.Reason = fmt.Sprintf("delegation %d: %v", i, err)
+				return false
+			}
 		}
 	}
 
@@ -134,60 +185,76 @@ func (chain *DelegationChain) ValidateChain() bool {
 	return true
 }
 
-// Helper functions for extracting values from constraint maps
-func getString(m map[string]interface{}, key string) string {
-	if val, ok := m[key].(string); ok {
-		return val
-	}
-	return ""
-}
+// maxChainLength bounds how many parents GetChain follows, whatever the claims' MaxDepth says
+const maxChainLength = 64
 
-func getInt64(m map[string]interface{}, key string) int64 {
-	if val, ok := m[key].(float64); ok {
-		return int64(val)
-	}
-	if val, ok := m[key].(int64); ok {
-		return val
+// GetChain builds and returns the complete delegation chain for a claim
+// by following parent references through the store, root first
+func (dc *DelegationClaim) GetChain(store CredentialStore) (*DelegationChain, error) {
+	id, err := dc.CredentialID()
+	if err != nil {
+		return nil, err
 	}
-	return 0
-}
+	delegations := []*DelegationClaim{dc}
+	seen := map[string]bool{id: true}
 
-func getIntSlice(m map[string]interface{}, key string) []int {
-	if val, ok := m[key].([]interface{}); ok {
-		result := make([]int, len(val))
-		for i, v := range val {
-			if num, ok := v.(float64); ok {
-				result[i] = int(num)
+	// Follow parent references to build the chain
+	current := dc
+	for current.ParentDelegation != nil {
+		ref := *current.ParentDelegation
+		if store == nil {
+			return nil, &ChainError{
+				Code:    ChainErrParentNotFound,
+				Message: fmt.Sprintf("no credential store to resolve parent %s", ref),
 			}
 		}
-		return result
-	}
-	return nil
-}
 
-// GetChain builds and returns the complete delegation chain for a claim
-// by following parent references
-func (dc *DelegationClaim) GetChain() (*DelegationChain, error) {
-	chain := &DelegationChain{
-		Delegations: []*DelegationClaim{dc},
+		parent, err := store.GetDelegation(ref)
+		if err != nil {
+			return nil, &ChainError{
+				Code:    ChainErrParentNotFound,
+				Message: fmt.Sprintf("parent delegation %s: %v", ref, err),
+			}
+		}
+		if err := checkParent(parent, current); err != nil {
+			return nil, err
+		}
+		if seen[ref] {
+			return nil, &ChainError{
+				Code:    ChainErrCycle,
+				Message: fmt.Sprintf("delegation %s appears twice in the chain", ref),
+			}
+		}
+		seen[ref] = true
+
+		if err := checkDepth(parent, current); err != nil {
+			return nil, err
+		}
+
+		delegations = append([]*DelegationClaim{parent}, delegations...)
+		if len(delegations) > maxChainLength {
+			return nil, &ChainError{
+				Code:    ChainErrDepthExceeded,
+				Message: fmt.Sprintf("delegation chain is longer than %d", maxChainLength),
+			}
+		}
+		current = parent
 	}
 
-	// Follow parent references to build the chain
-	current := dc
-	for current.ParentDelegation != nil {
-		// In a real implementation, you would fetch the parent delegation
-		// from storage using the ParentDelegation reference
-		// For now, we'll return an error
+	// The root has no parent, so it must sit at depth zero
+	if current.CurrentDepth != 0 {
 		return nil, &ChainError{
-			Code:    "PARENT_NOT_FOUND",
-			Message: "parent delegation reference not implemented",
+			Code:    ChainErrInvalidDepth,
+			Message: fmt.Sprintf("root delegation has depth %d, expected 0", current.CurrentDepth),
 		}
 	}
 
+	chain := &DelegationChain{Delegations: delegations}
+
 	// Validate the chain
 	if !chain.ValidateChain() {
 		return nil, &ChainError{
-			Code:    "INVALID_CHAIN",
+			Code:    ChainErrInvalidChain,
 			Message: chain.Reason,
 		}
 	}
@@ -195,9 +262,65 @@ func (dc *DelegationClaim) GetChain() (*DelegationChain, error) {
 	return chain, nil
 }
 
+// checkParent checks that a child's ParentDelegation commits to its parent's credential ID
+func checkParent(parent, child *DelegationClaim) error {
+	if child.ParentDelegation == nil {
+		return &ChainError{
+			Code:    ChainErrParentMismatch,
+			Message: "delegation does not reference its parent",
+		}
+	}
+	id, err := parent.CredentialID()
+	if err != nil {
+		return err
+	}
+	if !strings.EqualFold(*child.ParentDelegation, id) {
+		return &ChainError{
+			Code:    ChainErrParentMismatch,
+			Message: fmt.Sprintf("delegation references parent %s, not %s", *child.ParentDelegation, id),
+		}
+	}
+	return nil
+}
+
+// SetParent makes the delegation a child of parent: it commits to the parent's
+// credential ID and sits one level below it. Call it before signing.
+func (dc *DelegationClaim) SetParent(parent *DelegationClaim) error {
+	id, err := parent.CredentialID()
+	if err != nil {
+		return err
+	}
+	dc.ParentDelegation = &id
+	dc.CurrentDepth = parent.CurrentDepth + 1
+	return nil
+}
+
+// checkDepth enforces a child's depth limits against its parent's values
+func checkDepth(parent, child *DelegationClaim) error {
+	if !parent.CanSubDelegate() {
+		return &ChainError{
+			Code:    ChainErrDepthExceeded,
+			Message: fmt.Sprintf("parent at depth %d does not allow sub-delegation (max depth %d)", parent.CurrentDepth, parent.MaxDepth),
+		}
+	}
+	if child.CurrentDepth != parent.CurrentDepth+1 {
+		return &ChainError{
+			Code:    ChainErrInvalidDepth,
+			Message: fmt.Sprintf("delegation has depth %d but its parent is at depth %d", child.CurrentDepth, parent.CurrentDepth),
+		}
+	}
+	if child.MaxDepth > parent.MaxDepth {
+		return &ChainError{
+			Code:    ChainErrDepthExceeded,
+			Message: fmt.Sprintf("delegation raises max depth from %d to %d", parent.MaxDepth, child.MaxDepth),
+		}
+	}
+	return nil
+}
+
 // ValidateInChain validates this delegation in the context of its chain
-func (dc *DelegationClaim) ValidateInChain() (*DelegationChain, error) {
-	chain, err := dc.GetChain()
+func (dc *DelegationClaim) ValidateInChain(store CredentialStore) (*DelegationChain, error) {
+	chain, err := dc.GetChain(store)
 	if err != nil {
 		return nil, err
 	}
@@ -226,141 +349,97 @@ func (chain *DelegationChain) GetLeafDelegation() *DelegationClaim {
 	return chain.Delegations[len(chain.Delegations)-1]
 }
 
-// ValidateChainConstraints performs chain-wide constraint validation
+// ValidateChainConstraints checks that every delegation attenuates its parent:
+// no action, scope, amount, time window or constraint may be wider than the parent's
 func (chain *DelegationChain) ValidateChainConstraints() error {
 	if len(chain.Delegations) == 0 {
 		return &ChainError{
-			Code:    "EMPTY_CHAIN",
+			Code:    ChainErrEmptyChain,
 			Message: "delegation chain is empty",
 		}
 	}
 
-	// Track chain-wide constraints
-	var earliestValidFrom int64
-	var latestValidUntil int64
-	var allowedResources map[string]bool
-	var deniedResources map[string]bool
-
-	// Initialize constraints from first delegation
-	root := chain.GetRootDelegation()
-	if timeConstr := root.GetTimeConstraint(); timeConstr != nil {
-		earliestValidFrom = timeConstr.ValidFrom
-		latestValidUntil = timeConstr.ValidUntil
-	}
-	if scopeConstr := root.GetScopeConstraint(); scopeConstr != nil {
-		allowedResources = make(map[string]bool)
-		deniedResources = make(map[string]bool)
-		for _, resource := range scopeConstr.AllowedResources {
-			allowedResources[resource] = true
-		}
-		for _, resource := range scopeConstr.DeniedResources {
-			deniedResources[resource] = true
-		}
-	}
-
-	// Validate each delegation in the chain
-	for i, delegation := range chain.Delegations {
-		// Skip root delegation as we used it for initialization
-		if i == 0 {
-			continue
-		}
-
-		// Validate time constraints
-		if timeConstr := delegation.GetTimeConstraint(); timeConstr != nil {
-			if timeConstr.ValidFrom < earliestValidFrom {
+	for i := 1; i < len(chain.Delegations); i++ {
+		if err := CheckAttenuation(chain.Delegations[i-1], chain.Delegations[i]); err != nil {
+			var chainErr *ChainError
+			if errors.As(err, &chainErr) {
 				return &ChainError{
-					Code:    "TIME_CONSTRAINT_VIOLATION",
-					Message: fmt.Sprintf("delegation %d starts before chain start time", i),
-				}
-			}
-			if timeConstr.ValidUntil > latestValidUntil {
-				return &ChainError{
-					Code:    "TIME_CONSTRAINT_VIOLATION",
-					Message: fmt.Sprintf("delegation %d ends after chain end time", i),
-				}
-			}
-		}
-
-		// Validate scope constraints
-		if scopeConstr := delegation.GetScopeConstraint(); scopeConstr != nil {
-			for _, resource := range scopeConstr.AllowedResources {
-				if deniedResources[resource] {
-					return &ChainError{
-						Code:    "SCOPE_CONSTRAINT_VIOLATION",
-						Message: fmt.Sprintf("delegation %d allows denied resource: %s", i, resource),
-					}
-				}
-			}
-			for _, resource := range scopeConstr.DeniedResources {
-				if allowedResources[resource] {
-					return &ChainError{
-						Code:    "SCOPE_CONSTRAINT_VIOLATION",
-						Message: fmt.Sprintf("delegation %d denies allowed resource: %s", i, resource),
-					}
+					Code:    chainErr.Code,
+					Message: fmt.Sprintf("delegation %d: %s", i, chainErr.Message),
 				}
 			}
+			return err
 		}
 	}
 
 	return nil
 }
 
-// RevokeChain generates revocation claims for the entire chain
-func (chain *DelegationChain) RevokeChain(reason string) []*RevocationClaim {
+// RevokeChain generates revocation claims for the part of the chain that
+// revokerDID granted: the first delegation it issued and every delegation
+// below it. Any delegator in the chain may revoke its own subtree. The claims
+// are unsigned; sign them with the revoker's key before publishing.
+func (chain *DelegationChain) RevokeChain(revokerDID, reason string) ([]*RevocationClaim, error) {
+	from := -1
+	for i, delegation := range chain.Delegations {
+		if delegation.DelegatorDID == revokerDID {
+			from = i
+			break
+		}
+	}
+	if from < 0 {
+		return nil, &ChainError{
+			Code:    ChainErrNotRevoker,
+			Message: fmt.Sprintf("%s did not issue any delegation in the chain", revokerDID),
+		}
+	}
+
 	var revocations []*RevocationClaim
 	now := time.Now().Unix()
 
-	for _, delegation := range chain.Delegations {
+	for _, delegation := range chain.Delegations[from:] {
+		id, err := delegation.CredentialID()
+		if err != nil {
+			return nil, err
+		}
 		// Create a revocation claim for each delegation
 		revocation := &RevocationClaim{
-			RevokedCredentialID: delegation.Nonce, // Using nonce as credential ID
+			RevokedCredentialID: id,
 			RevokedAgentDID:     delegation.DelegateDID,
-			RevokerDID:          chain.GetRootDelegation().DelegatorDID, // Root delegator revokes
+			RevokerDID:          revokerDID,
 			Reason:              reason,
 			RevokedAt:           now,
 			EffectiveAt:         now, // Immediate effect
 			Nonce:              fmt.Sprintf("revoke_%d_%s", now, delegation.Nonce),
 			Type:               RevocationCredentialType,
 			Context:            StandardContexts,
-			Issuer:             chain.GetRootDelegation().DelegatorDID,
+			Issuer:             revokerDID,
 			Subject:            delegation.DelegateDID,
 		}
 		revocations = append(revocations, revocation)
 	}
 
-	return revocations
+	return revocations, nil
 }
 
-// GetScopeConstraint extracts scope constraint from the delegation
-func (dc *DelegationClaim) GetScopeConstraint() *ScopeConstraint {
-	if scope, ok := dc.Constraints["scope"].(map[string]interface{}); ok {
-		return &ScopeConstraint{
-			AllowedResources: getStringSlice(scope, "allowed_resources"),
-			DeniedResources:  getStringSlice(scope, "denied_resources"),
-			Metadata:         getMap(scope, "metadata"),
+// CanRevoke reports whether revokerDID may revoke the delegation at index:
+// it must have issued that delegation or one above it in the chain
+func (chain *DelegationChain) CanRevoke(revokerDID string, index int) bool {
+	for i := 0; i <= index && i < len(chain.Delegations); i++ {
+		if chain.Delegations[i].DelegatorDID == revokerDID {
+			return true
 		}
 	}
-	return nil
+	return false
 }
 
-// Helper function to get string slice from interface map
-func getStringSlice(m map[string]interface{}, key string) []string {
-	if val, ok := m[key].([]interface{}); ok {
-		result := make([]string, len(val))
-		for i, v := range val {
-			if str, ok := v.(string); ok {
-				result[i] = str
-			}
+// GetScopeConstraint returns the delegation's scope constraint, or nil if it has
+// none or it is malformed; ParseConstraints reports the error
+func (dc *DelegationClaim) GetScopeConstraint() *ScopeConstraint {
+	if value, ok := dc.Constraints[ConstraintScope]; ok {
+		if constraint, err := ParseConstraint(ConstraintScope, value); err == nil {
+			return constraint.(*ScopeConstraint)
 		}
-		return result
 	}
 	return nil
 }
-
-// Helper function to get map from interface map
-func getMap(m map[string]interface{}, key string) map[string]interface{} {
-	if val, ok := m[key].(map[string]interface{}); ok {
-		return val
-	}
-	return nil
-}
\ No newline at end of file
@@ -10,6 +10,7 @@ type CredentialProof struct {
 	ProofValue         string     `json:"proofValue"`                    // Base64 encoded signature
 	Challenge          string     `json:"challenge,omitempty"`           // Random nonce for proof of possession
 	Domain             EIP712Domain `json:"domain,omitempty"`            // EIP-712 domain for typed data signing
+	Canonicalization   string     `json:"canonicalization,omitempty"`    // How the credential was hashed (empty = eip712)
 }
 
 // ProofSuite defines the supported cryptographic proof types
@@ -30,4 +31,12 @@ const (
 	KeyAgreement         ProofPurpose = "keyAgreement"
 	CapabilityInvocation ProofPurpose = "capabilityInvocation"
 	CapabilityDelegation ProofPurpose = "capabilityDelegation"
-)
\ No newline at end of file
+)
+
+// Canonicalization defines how a credential is turned into the bytes that get signed
+type Canonicalization string
+
+const (
+	CanonicalizationEIP712 Canonicalization = "eip712" // EIP-712 typed data hash (default)
+	CanonicalizationJCS    Canonicalization = "jcs"    // Keccak256 of the RFC 8785 canonical JSON
+)
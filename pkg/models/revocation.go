@@ -1,6 +1,9 @@
 package models
 
-import "time"
+import (
+	"context"
+	"time"
+)
 
 // RevocationClaim represents a revocation of a previously issued credential
 type RevocationClaim struct {
@@ -33,6 +36,7 @@ type RevocationList struct {
 	ListID      string             `json:"list_id"`      // Unique identifier for this revocation list
 	IssuerDID   string             `json:"issuer_did"`   // Who maintains this list
 	LastUpdated int64              `json:"last_updated"` // When list was last updated
+	Sequence    uint64             `json:"sequence,omitempty"` // Incremented on every published update
 	Revocations []*RevocationClaim `json:"revocations"`  // All revocations in this list
 	
 	// W3C compliance
@@ -41,6 +45,29 @@ type RevocationList struct {
 	Proof   *CredentialProof `json:"proof,omitempty"`
 }
 
+// RevocationDelta is a signed, incremental update to a revocation list. It
+// carries the revocations that take the list from Sequence-1 to Sequence.
+type RevocationDelta struct {
+	ListID      string             `json:"list_id"`
+	IssuerDID   string             `json:"issuer_did"`
+	Sequence    uint64             `json:"sequence"`    // Sequence of the list after applying this delta
+	Timestamp   int64              `json:"timestamp"`   // When the delta was published
+	Revocations []*RevocationClaim `json:"revocations"` // Revocations added by this delta
+
+	// W3C compliance
+	Type    []string `json:"type,omitempty"`
+	Context []string `json:"@context,omitempty"`
+	Proof   *CredentialProof `json:"proof,omitempty"`
+}
+
+// RevocationChecker looks up whether a credential has been revoked
+type RevocationChecker interface {
+	// CheckRevocation returns the effective revocation of a credential or of
+	// its agent as a whole, or nil if neither is revoked. An empty credential
+	// ID only looks for revocations of the agent.
+	CheckRevocation(ctx context.Context, credentialID, agentDID string) (*RevocationClaim, error)
+}
+
 // RevocationQuery represents a query to check if a credential is revoked
 type RevocationQuery struct {
 	CredentialID string `json:"credential_id"` // ID of credential to check
@@ -80,6 +107,7 @@ const (
 var (
 	RevocationCredentialType = []string{"VerifiableCredential", "RevocationCredential"}
 	RevocationListType       = []string{"VerifiableCredential", "RevocationList2020"}
+	RevocationDeltaType      = []string{"VerifiableCredential", "RevocationListDelta"}
 )
 
 // IsEffective checks if a revocation is currently in effect
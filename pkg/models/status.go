@@ -0,0 +1,47 @@
+package models
+
+// Status list purposes
+const (
+	StatusPurposeRevocation = "revocation"
+	StatusPurposeSuspension = "suspension"
+)
+
+// Status list types
+const (
+	StatusList2021EntryType = "StatusList2021Entry"
+	StatusList2021Type      = "StatusList2021"
+	StatusList2021Context   = "https://w3id.org/vc/status-list/2021/v1"
+)
+
+// StatusList2021CredentialType is the VC type of a published status list
+var StatusList2021CredentialType = []string{"VerifiableCredential", "StatusList2021Credential"}
+
+// CredentialStatus points at a credential's bit in a status list (StatusList2021Entry)
+type CredentialStatus struct {
+	ID                   string `json:"id"`                   // {statusListCredential}#{statusListIndex}
+	Type                 string `json:"type"`                 // "StatusList2021Entry"
+	StatusPurpose        string `json:"statusPurpose"`        // "revocation" or "suspension"
+	StatusListIndex      string `json:"statusListIndex"`      // Bit position, as a decimal string
+	StatusListCredential string `json:"statusListCredential"` // URL of the StatusList2021Credential
+}
+
+// StatusList2021Credential publishes the status bits of an issuer's credentials.
+// Bit i of the decoded list is set when the credential at index i is revoked (or suspended).
+type StatusList2021Credential struct {
+	Context           []string              `json:"@context"`
+	ID                string                `json:"id"`
+	Type              []string              `json:"type"`
+	Issuer            string                `json:"issuer"`
+	ValidFrom         int64                 `json:"validFrom"`
+	ValidUntil        int64                 `json:"validUntil,omitempty"`
+	CredentialSubject StatusList2021Subject `json:"credentialSubject"`
+	Proof             *CredentialProof      `json:"proof,omitempty"`
+}
+
+// StatusList2021Subject carries the compressed bitstring of a status list
+type StatusList2021Subject struct {
+	ID            string `json:"id"`
+	Type          string `json:"type"`          // "StatusList2021"
+	StatusPurpose string `json:"statusPurpose"` // "revocation" or "suspension"
+	EncodedList   string `json:"encodedList"`   // GZIP-compressed, base64url-encoded bitstring
+}
@@ -0,0 +1,16 @@
+package models
+
+import "errors"
+
+// ErrCredentialNotFound is returned by a CredentialStore when no credential matches a reference
+var ErrCredentialNotFound = errors.New("credential not found")
+
+// CredentialStore holds issued delegations so that ParentDelegation references can be resolved.
+// A reference is the delegation's content-addressed credential ID.
+type CredentialStore interface {
+	// GetDelegation returns the delegation with the given credential ID, or ErrCredentialNotFound
+	GetDelegation(ref string) (*DelegationClaim, error)
+
+	// PutDelegation stores a delegation under its credential ID
+	PutDelegation(claim *DelegationClaim) error
+}
@@ -0,0 +1,164 @@
+package models
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+func decodeTimeConstraint(raw json.RawMessage) (Constraint, error) {
+	var c TimeConstraint
+	if err := decodeConstraint(raw, &c); err != nil {
+		return nil, err
+	}
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// Location returns the time zone the constraint's days and hours are in, UTC if unset
+func (tc *TimeConstraint) Location() (*time.Location, error) {
+	if tc.TimeZone == "" {
+		return time.UTC, nil
+	}
+	loc, err := time.LoadLocation(tc.TimeZone)
+	if err != nil {
+		return nil, fmt.Errorf("unknown time zone %q: %w", tc.TimeZone, err)
+	}
+	return loc, nil
+}
+
+// Validate checks that the zone is known and the days (0-6) and hours (0-23) are in range
+func (tc *TimeConstraint) Validate() error {
+	if _, err := tc.Location(); err != nil {
+		return err
+	}
+	for _, day := range tc.Days {
+		if day < 0 || day > 6 {
+			return fmt.Errorf("day %d is out of range 0-6", day)
+		}
+	}
+	for _, hour := range tc.Hours {
+		if hour < 0 || hour > 23 {
+			return fmt.Errorf("hour %d is out of range 0-23", hour)
+		}
+	}
+	if tc.ValidUntil != 0 && tc.ValidUntil < tc.ValidFrom {
+		return fmt.Errorf("valid_until %d is before valid_from %d", tc.ValidUntil, tc.ValidFrom)
+	}
+	return nil
+}
+
+// Allows reports whether the constraint permits acting at t
+func (tc *TimeConstraint) Allows(t time.Time) bool {
+	return tc.Check(t) == nil
+}
+
+// Check returns a ChainError naming the violated window if the constraint does not
+// permit acting at t. Days and hours are evaluated on the wall clock of the
+// constraint's time zone, so an hour h covers h:00 up to (h+1):00 local time,
+// whatever the UTC offset is on that date.
+func (tc *TimeConstraint) Check(t time.Time) error {
+	if err := tc.Validate(); err != nil {
+		return &ChainError{Code: ChainErrTimeConstraint, Message: err.Error()}
+	}
+
+	if tc.ValidFrom != 0 && t.Unix() < tc.ValidFrom {
+		return &ChainError{
+			Code:    ChainErrTimeConstraint,
+			Message: fmt.Sprintf("not valid before %s", time.Unix(tc.ValidFrom, 0).UTC().Format(time.RFC3339)),
+		}
+	}
+	if tc.ValidUntil != 0 && t.Unix() > tc.ValidUntil {
+		return &ChainError{
+			Code:    ChainErrTimeConstraint,
+			Message: fmt.Sprintf("not valid after %s", time.Unix(tc.ValidUntil, 0).UTC().Format(time.RFC3339)),
+		}
+	}
+
+	loc, _ := tc.Location()
+	local := t.In(loc)
+	if len(tc.Days) > 0 && !containsInt(tc.Days, int(local.Weekday())) {
+		return &ChainError{
+			Code:    ChainErrTimeConstraint,
+			Message: fmt.Sprintf("%s is outside the allowed days %s (%s)", local.Format("Monday"), formatDays(tc.Days), loc),
+		}
+	}
+	if len(tc.Hours) > 0 && !containsInt(tc.Hours, local.Hour()) {
+		return &ChainError{
+			Code:    ChainErrTimeConstraint,
+			Message: fmt.Sprintf("%s is outside the allowed hours %v (%s)", local.Format("15:04 MST"), tc.Hours, loc),
+		}
+	}
+	return nil
+}
+
+// Evaluate rejects requests made outside the window
+func (tc *TimeConstraint) Evaluate(_ context.Context, req *ConstraintRequest) error {
+	if err := tc.Check(req.Time); err != nil {
+		var chainErr *ChainError
+		if errors.As(err, &chainErr) {
+			return errors.New(chainErr.Message)
+		}
+		return err
+	}
+	return nil
+}
+
+// IsSubsetOf requires the window to lie within the parent's. Days and hours are
+// only comparable on the same wall clock, so a parent that restricts them fixes the zone.
+func (tc *TimeConstraint) IsSubsetOf(parent Constraint) error {
+	p, ok := parent.(*TimeConstraint)
+	if !ok {
+		return fmt.Errorf("cannot compare with %T", parent)
+	}
+	if tc.ValidFrom < p.ValidFrom {
+		return fmt.Errorf("valid from %d, before its parent's %d", tc.ValidFrom, p.ValidFrom)
+	}
+	if p.ValidUntil != 0 && (tc.ValidUntil == 0 || tc.ValidUntil > p.ValidUntil) {
+		return fmt.Errorf("valid until %d, after its parent's %d", tc.ValidUntil, p.ValidUntil)
+	}
+	if (len(p.Days) > 0 || len(p.Hours) > 0) && zoneName(tc.TimeZone) != zoneName(p.TimeZone) {
+		return fmt.Errorf("time zone %q differs from its parent's %q", tc.TimeZone, p.TimeZone)
+	}
+	if !intSubset(p.Days, tc.Days) {
+		return fmt.Errorf("days %v are not within its parent's %v", tc.Days, p.Days)
+	}
+	if !intSubset(p.Hours, tc.Hours) {
+		return fmt.Errorf("hours %v are not within its parent's %v", tc.Hours, p.Hours)
+	}
+	return nil
+}
+
+// zoneName returns the name of a constraint time zone, where "" means UTC
+func zoneName(zone string) string {
+	if zone == "" {
+		return "UTC"
+	}
+	return zone
+}
+
+// formatDays names a list of weekdays, e.g. [Monday Tuesday]
+func formatDays(days []int) string {
+	sorted := append([]int(nil), days...)
+	sort.Ints(sorted)
+	names := make([]string, len(sorted))
+	for i, day := range sorted {
+		names[i] = time.Weekday(day).String()
+	}
+	return "[" + strings.Join(names, " ") + "]"
+}
+
+func containsInt(values []int, v int) bool {
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+	return false
+}
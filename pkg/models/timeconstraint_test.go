@@ -0,0 +1,111 @@
+package models
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTimeConstraintAllows(t *testing.T) {
+	// Weekdays 9-17 New York time
+	businessHours := &TimeConstraint{
+		TimeZone: "America/New_York",
+		Days:     []int{1, 2, 3, 4, 5},
+		Hours:    []int{9, 10, 11, 12, 13, 14, 15, 16},
+	}
+
+	tests := []struct {
+		name    string
+		tc      *TimeConstraint
+		at      time.Time
+		allowed bool
+	}{
+		{"weekday morning", businessHours, time.Date(2025, 6, 11, 13, 30, 0, 0, time.UTC), true},
+		{"weekday before opening", businessHours, time.Date(2025, 6, 11, 12, 59, 0, 0, time.UTC), false},
+		{"weekday after closing", businessHours, time.Date(2025, 6, 11, 21, 0, 0, 0, time.UTC), false},
+		{"saturday", businessHours, time.Date(2025, 6, 14, 15, 0, 0, 0, time.UTC), false},
+		// Friday 23:30 in New York is already Saturday in UTC
+		{"friday evening across UTC midnight", &TimeConstraint{TimeZone: "America/New_York", Days: []int{5}}, time.Date(2025, 6, 14, 3, 30, 0, 0, time.UTC), true},
+		// 13:30 UTC is 8:30 EST the Friday before the spring change and 9:30 EDT the Monday after
+		{"before spring DST change", businessHours, time.Date(2025, 3, 7, 13, 30, 0, 0, time.UTC), false},
+		{"after spring DST change", businessHours, time.Date(2025, 3, 10, 13, 30, 0, 0, time.UTC), true},
+		// On the fall change 1:30 happens twice, first in EDT and then in EST
+		{"first 1:30 on fall DST change", &TimeConstraint{TimeZone: "America/New_York", Hours: []int{1}}, time.Date(2025, 11, 2, 5, 30, 0, 0, time.UTC), true},
+		{"second 1:30 on fall DST change", &TimeConstraint{TimeZone: "America/New_York", Hours: []int{1}}, time.Date(2025, 11, 2, 6, 30, 0, 0, time.UTC), true},
+		{"2:30 on fall DST change", &TimeConstraint{TimeZone: "America/New_York", Hours: []int{1}}, time.Date(2025, 11, 2, 7, 30, 0, 0, time.UTC), false},
+		{"UTC by default", &TimeConstraint{Hours: []int{13}}, time.Date(2025, 6, 11, 13, 30, 0, 0, time.UTC), true},
+		{"before valid_from", &TimeConstraint{ValidFrom: 2000}, time.Unix(1999, 0), false},
+		{"after valid_until", &TimeConstraint{ValidUntil: 2000}, time.Unix(2001, 0), false},
+		{"unknown time zone", &TimeConstraint{TimeZone: "Mars/Olympus_Mons"}, time.Now(), false},
+		{"hour out of range", &TimeConstraint{Hours: []int{24}}, time.Now(), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.tc.Allows(tt.at); got != tt.allowed {
+				t.Errorf("Allows(%s) = %v, want %v (%v)", tt.at, got, tt.allowed, tt.tc.Check(tt.at))
+			}
+		})
+	}
+}
+
+func TestTimeConstraintReason(t *testing.T) {
+	tc := &TimeConstraint{TimeZone: "America/New_York", Days: []int{5, 1}, Hours: []int{9}}
+
+	err := tc.Check(time.Date(2025, 6, 14, 15, 0, 0, 0, time.UTC))
+	var chainErr *ChainError
+	if !errors.As(err, &chainErr) || chainErr.Code != ChainErrTimeConstraint {
+		t.Fatalf("expected a time constraint violation, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "Saturday is outside the allowed days [Monday Friday] (America/New_York)") {
+		t.Errorf("reason does not name the window: %v", err)
+	}
+
+	err = tc.Check(time.Date(2025, 6, 13, 15, 0, 0, 0, time.UTC))
+	if err == nil || !strings.Contains(err.Error(), "11:00 EDT is outside the allowed hours [9]") {
+		t.Errorf("reason does not name the window: %v", err)
+	}
+}
+
+func TestValidateChainAtTimeWindow(t *testing.T) {
+	delegation := newTestDelegation("transfer", "ETH", 0, map[string]interface{}{
+		"time": map[string]interface{}{"timezone": "Europe/Berlin", "days": []interface{}{1.0, 2.0, 3.0, 4.0, 5.0}},
+	})
+	chain := &DelegationChain{Delegations: []*DelegationClaim{delegation}}
+
+	if !chain.ValidateChainAt(time.Date(2025, 6, 11, 12, 0, 0, 0, time.UTC)) {
+		t.Errorf("chain should be valid on a Wednesday: %s", chain.Reason)
+	}
+	if chain.ValidateChainAt(time.Date(2025, 6, 15, 12, 0, 0, 0, time.UTC)) {
+		t.Error("chain should be invalid on a Sunday")
+	}
+	if !strings.Contains(chain.Reason, "delegation 0: TIME_CONSTRAINT_VIOLATION: Sunday") {
+		t.Errorf("unexpected reason %q", chain.Reason)
+	}
+}
+
+func TestCheckAttenuationTimeZone(t *testing.T) {
+	parent := newTestDelegation("transfer", "ETH", 5000, map[string]interface{}{
+		"time": map[string]interface{}{"days": []int{1, 2, 3, 4, 5}},
+	})
+	child := newTestDelegation("transfer", "ETH", 5000, map[string]interface{}{
+		"time": map[string]interface{}{"timezone": "Pacific/Auckland", "days": []int{1}},
+	})
+	// Monday in Auckland is partly Sunday in UTC, so the days are not a subset
+	if err := CheckAttenuation(parent, child); err == nil {
+		t.Error("expected a time zone change under a days restriction to be rejected")
+	}
+
+	child.Constraints["time"] = map[string]interface{}{"timezone": "UTC", "days": []int{1}}
+	if err := CheckAttenuation(parent, child); err != nil {
+		t.Errorf("UTC should match an unset parent zone: %v", err)
+	}
+
+	// Without days or hours the parent does not care about the zone
+	parent.Constraints["time"] = map[string]interface{}{"valid_from": 100}
+	child.Constraints["time"] = map[string]interface{}{"valid_from": 100, "timezone": "Pacific/Auckland", "hours": []int{9}}
+	if err := CheckAttenuation(parent, child); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -47,6 +47,7 @@ const (
 	DIDMethodAckid = "did:ackid:"
 	DIDMethodWeb    = "did:web:"
 	DIDMethodKey    = "did:key:"
+	DIDMethodPkh    = "did:pkh:"
 )
 
 type ClaimStatus string
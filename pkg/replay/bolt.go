@@ -0,0 +1,145 @@
+package replay
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	noncesBucket   = []byte("nonces")   // key -> expiry in Unix nanoseconds, 0 if it never expires
+	expiriesBucket = []byte("expiries") // expiry || key -> nothing, for pruning in expiry order
+)
+
+// maxPrune bounds how many expired nonces one Record deletes, so a backlog is
+// cleared over several calls rather than in one long transaction
+const maxPrune = 1000
+
+// BoltStore is a NonceStore persisted to a bbolt database, so recorded nonces
+// survive restarts. Expired nonces are pruned as new ones are recorded.
+type BoltStore struct {
+	db  *bolt.DB
+	now func() time.Time
+}
+
+var _ NonceStore = (*BoltStore)(nil)
+
+// NewBoltStore opens the nonce database at path, creating it if it does not exist
+func NewBoltStore(path string, opts ...Option) (*BoltStore, error) {
+	o := newOptions(opts)
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open nonce database: %w", err)
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, name := range [][]byte{noncesBucket, expiriesBucket} {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to initialize nonce database: %w", err)
+	}
+	return &BoltStore{db: db, now: o.now}, nil
+}
+
+// Record marks nonce as used within scope until expiresAt
+func (b *BoltStore) Record(_ context.Context, scope, nonce string, expiresAt time.Time) error {
+	now := b.now()
+	if err := checkExpiry(expiresAt, now); err != nil {
+		return err
+	}
+	k := []byte(key(scope, nonce))
+
+	return b.db.Update(func(tx *bolt.Tx) error {
+		nonces, expiries := tx.Bucket(noncesBucket), tx.Bucket(expiriesBucket)
+		if err := prune(nonces, expiries, now); err != nil {
+			return fmt.Errorf("failed to prune nonces: %w", err)
+		}
+
+		if value := nonces.Get(k); value != nil {
+			at := binary.BigEndian.Uint64(value)
+			if at == 0 || at > uint64(now.UnixNano()) {
+				return fmt.Errorf("%w: %s", ErrReplayed, nonce)
+			}
+		}
+
+		var at uint64
+		if !expiresAt.IsZero() {
+			at = uint64(expiresAt.UnixNano())
+			if err := expiries.Put(expiryKey(at, k), nil); err != nil {
+				return fmt.Errorf("failed to record nonce: %w", err)
+			}
+		}
+		value := make([]byte, 8)
+		binary.BigEndian.PutUint64(value, at)
+		if err := nonces.Put(k, value); err != nil {
+			return fmt.Errorf("failed to record nonce: %w", err)
+		}
+		return nil
+	})
+}
+
+// Release forgets a recorded nonce
+func (b *BoltStore) Release(_ context.Context, scope, nonce string) error {
+	k := []byte(key(scope, nonce))
+
+	return b.db.Update(func(tx *bolt.Tx) error {
+		nonces, expiries := tx.Bucket(noncesBucket), tx.Bucket(expiriesBucket)
+		value := nonces.Get(k)
+		if value == nil {
+			return nil
+		}
+		if at := binary.BigEndian.Uint64(value); at != 0 {
+			if err := expiries.Delete(expiryKey(at, k)); err != nil {
+				return fmt.Errorf("failed to release nonce: %w", err)
+			}
+		}
+		if err := nonces.Delete(k); err != nil {
+			return fmt.Errorf("failed to release nonce: %w", err)
+		}
+		return nil
+	})
+}
+
+// Close closes the database
+func (b *BoltStore) Close() error {
+	return b.db.Close()
+}
+
+// prune deletes up to maxPrune nonces that expired at or before now
+func prune(nonces, expiries *bolt.Bucket, now time.Time) error {
+	limit := make([]byte, 8)
+	binary.BigEndian.PutUint64(limit, uint64(now.UnixNano()))
+
+	var expired [][]byte
+	c := expiries.Cursor()
+	for k, _ := c.First(); k != nil && len(expired) < maxPrune && bytes.Compare(k[:8], limit) <= 0; k, _ = c.Next() {
+		expired = append(expired, append([]byte(nil), k...))
+	}
+	for _, k := range expired {
+		if err := expiries.Delete(k); err != nil {
+			return err
+		}
+		// The nonce may have been recorded again since, with a later expiry
+		if value := nonces.Get(k[8:]); value != nil && bytes.Equal(value, k[:8]) {
+			if err := nonces.Delete(k[8:]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func expiryKey(at uint64, k []byte) []byte {
+	ek := make([]byte, 8, 8+len(k))
+	binary.BigEndian.PutUint64(ek, at)
+	return append(ek, k...)
+}
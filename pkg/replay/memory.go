@@ -0,0 +1,118 @@
+package replay
+
+import (
+	"container/heap"
+	"context"
+	"fmt"
+	"hash/fnv"
+	"sync"
+	"time"
+)
+
+// MemoryStore is a NonceStore held in memory. Nonces are spread over shards
+// that are locked independently, and each shard evicts its expired nonces in
+// expiry order as new ones are recorded.
+type MemoryStore struct {
+	shards []*shard
+	now    func() time.Time
+}
+
+var _ NonceStore = (*MemoryStore)(nil)
+
+type shard struct {
+	mu       sync.Mutex
+	entries  map[string]time.Time // Expiry of each nonce, zero if it never expires
+	expiries expiryHeap
+}
+
+// NewMemoryStore creates an empty in-memory nonce store
+func NewMemoryStore(opts ...Option) *MemoryStore {
+	o := newOptions(opts)
+	m := &MemoryStore{shards: make([]*shard, o.shards), now: o.now}
+	for i := range m.shards {
+		m.shards[i] = &shard{entries: make(map[string]time.Time)}
+	}
+	return m
+}
+
+// Record marks nonce as used within scope until expiresAt
+func (m *MemoryStore) Record(_ context.Context, scope, nonce string, expiresAt time.Time) error {
+	now := m.now()
+	if err := checkExpiry(expiresAt, now); err != nil {
+		return err
+	}
+	k := key(scope, nonce)
+	s := m.shard(k)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.evict(now)
+	if _, ok := s.entries[k]; ok {
+		return fmt.Errorf("%w: %s", ErrReplayed, nonce)
+	}
+	s.entries[k] = expiresAt
+	if !expiresAt.IsZero() {
+		heap.Push(&s.expiries, expiry{key: k, at: expiresAt})
+	}
+	return nil
+}
+
+// Release forgets a recorded nonce
+func (m *MemoryStore) Release(_ context.Context, scope, nonce string) error {
+	k := key(scope, nonce)
+	s := m.shard(k)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.entries, k)
+	return nil
+}
+
+// Len returns the number of nonces recorded and not yet evicted
+func (m *MemoryStore) Len() int {
+	n := 0
+	for _, s := range m.shards {
+		s.mu.Lock()
+		n += len(s.entries)
+		s.mu.Unlock()
+	}
+	return n
+}
+
+func (m *MemoryStore) shard(k string) *shard {
+	hash := fnv.New32a()
+	hash.Write([]byte(k))
+	return m.shards[hash.Sum32()%uint32(len(m.shards))]
+}
+
+// evict removes every nonce that expired at or before now
+func (s *shard) evict(now time.Time) {
+	for len(s.expiries) > 0 && !s.expiries[0].at.After(now) {
+		e := heap.Pop(&s.expiries).(expiry)
+		// The nonce may have been released and recorded again since, with a later expiry
+		if at, ok := s.entries[e.key]; ok && at.Equal(e.at) {
+			delete(s.entries, e.key)
+		}
+	}
+}
+
+type expiry struct {
+	key string
+	at  time.Time
+}
+
+// expiryHeap orders nonces by expiry, earliest first
+type expiryHeap []expiry
+
+func (h expiryHeap) Len() int           { return len(h) }
+func (h expiryHeap) Less(i, j int) bool { return h[i].at.Before(h[j].at) }
+func (h expiryHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+
+func (h *expiryHeap) Push(x interface{}) { *h = append(*h, x.(expiry)) }
+
+func (h *expiryHeap) Pop() interface{} {
+	old := *h
+	e := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return e
+}
@@ -0,0 +1,91 @@
+// Package replay records the nonces of requests and credentials so each can
+// only be used once. A nonce is kept until the request or credential it came
+// from expires, after which a replay is rejected by the expiry check instead.
+package replay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// DefaultClockSkew is how far a request timestamp may be from the local clock
+// when no other window is configured
+const DefaultClockSkew = 5 * time.Minute
+
+var (
+	// ErrReplayed is returned when a nonce has already been recorded and has not expired
+	ErrReplayed = errors.New("nonce has already been used")
+	// ErrExpired is returned when a nonce is recorded after its expiry
+	ErrExpired = errors.New("nonce has expired")
+	// ErrClockSkew is returned when a timestamp is outside the allowed clock skew
+	ErrClockSkew = errors.New("timestamp is outside the allowed clock skew")
+)
+
+// NonceStore records nonces until they expire. Implementations must be safe
+// for concurrent use, and Record must be atomic: of several concurrent calls
+// with the same scope and nonce, at most one may succeed.
+type NonceStore interface {
+	// Record marks nonce as used within scope until expiresAt, or forever if
+	// expiresAt is zero. It fails with ErrReplayed if the nonce is still recorded.
+	Record(ctx context.Context, scope, nonce string, expiresAt time.Time) error
+	// Release forgets a recorded nonce so it may be recorded again, e.g. when
+	// the request it was recorded for could not be completed
+	Release(ctx context.Context, scope, nonce string) error
+}
+
+// CheckTimestamp checks that a Unix timestamp is within skew of now
+func CheckTimestamp(timestamp int64, now time.Time, skew time.Duration) error {
+	at := time.Unix(timestamp, 0)
+	if at.Before(now.Add(-skew)) || at.After(now.Add(skew)) {
+		return fmt.Errorf("%w: %d is more than %s from %d", ErrClockSkew, timestamp, skew, now.Unix())
+	}
+	return nil
+}
+
+// Option configures a nonce store
+type Option func(*options)
+
+type options struct {
+	now    func() time.Time
+	shards int
+}
+
+// WithClock sets the time source used to decide which nonces have expired
+func WithClock(now func() time.Time) Option {
+	return func(o *options) {
+		o.now = now
+	}
+}
+
+// WithShards sets how many independently locked shards a MemoryStore uses
+func WithShards(shards int) Option {
+	return func(o *options) {
+		o.shards = shards
+	}
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{now: time.Now, shards: 64}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.shards < 1 {
+		o.shards = 1
+	}
+	return o
+}
+
+// key joins a scope and a nonce so that no two pairs share a key
+func key(scope, nonce string) string {
+	return scope + "\x00" + nonce
+}
+
+// checkExpiry rejects expiries that have already passed
+func checkExpiry(expiresAt, now time.Time) error {
+	if !expiresAt.IsZero() && !expiresAt.After(now) {
+		return fmt.Errorf("%w at %d", ErrExpired, expiresAt.Unix())
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package replay
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNonceStores(t *testing.T) {
+	stores := map[string]func(t *testing.T, now func() time.Time) NonceStore{
+		"memory": func(_ *testing.T, now func() time.Time) NonceStore {
+			return NewMemoryStore(WithClock(now), WithShards(4))
+		},
+		"bolt": func(t *testing.T, now func() time.Time) NonceStore {
+			store, err := NewBoltStore(filepath.Join(t.TempDir(), "nonces.db"), WithClock(now))
+			require.NoError(t, err)
+			t.Cleanup(func() { store.Close() })
+			return store
+		},
+	}
+	for name, newStore := range stores {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			clock := time.Unix(1700000000, 0)
+			store := newStore(t, func() time.Time { return clock })
+
+			require.NoError(t, store.Record(ctx, "did:ackid:0xa", "n-1", clock.Add(time.Minute)))
+			assert.ErrorIs(t, store.Record(ctx, "did:ackid:0xa", "n-1", clock.Add(time.Hour)), ErrReplayed)
+
+			// Nonces are only unique within their scope
+			require.NoError(t, store.Record(ctx, "did:ackid:0xb", "n-1", clock.Add(time.Minute)))
+
+			// Nonces without an expiry are kept forever
+			require.NoError(t, store.Record(ctx, "did:ackid:0xa", "n-2", time.Time{}))
+
+			assert.ErrorIs(t, store.Record(ctx, "did:ackid:0xa", "n-3", clock), ErrExpired)
+
+			// Once expired, a nonce is evicted and may be recorded again
+			clock = clock.Add(2 * time.Minute)
+			require.NoError(t, store.Record(ctx, "did:ackid:0xa", "n-1", clock.Add(time.Minute)))
+			assert.ErrorIs(t, store.Record(ctx, "did:ackid:0xa", "n-1", clock.Add(time.Minute)), ErrReplayed)
+			assert.ErrorIs(t, store.Record(ctx, "did:ackid:0xa", "n-2", clock.Add(time.Minute)), ErrReplayed)
+
+			// A released nonce may be recorded again, and keeps its new expiry
+			require.NoError(t, store.Release(ctx, "did:ackid:0xa", "n-1"))
+			require.NoError(t, store.Release(ctx, "did:ackid:0xa", "n-unknown"))
+			require.NoError(t, store.Record(ctx, "did:ackid:0xa", "n-1", clock.Add(time.Hour)))
+			clock = clock.Add(2 * time.Minute)
+			assert.ErrorIs(t, store.Record(ctx, "did:ackid:0xa", "n-1", clock.Add(time.Hour)), ErrReplayed)
+		})
+	}
+}
+
+func TestMemoryStoreEvicts(t *testing.T) {
+	ctx := context.Background()
+	clock := time.Unix(1700000000, 0)
+	store := NewMemoryStore(WithClock(func() time.Time { return clock }), WithShards(1))
+
+	for i := 0; i < 100; i++ {
+		require.NoError(t, store.Record(ctx, "scope", fmt.Sprintf("n-%d", i), clock.Add(time.Duration(i+1)*time.Second)))
+	}
+	assert.Equal(t, 100, store.Len())
+
+	clock = clock.Add(50 * time.Second)
+	require.NoError(t, store.Record(ctx, "scope", "last", clock.Add(time.Hour)))
+	assert.Equal(t, 51, store.Len())
+}
+
+func TestMemoryStoreConcurrentRecord(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+	expiresAt := time.Now().Add(time.Hour)
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		accepted int
+	)
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if store.Record(ctx, "scope", "nonce", expiresAt) == nil {
+				mu.Lock()
+				accepted++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 1, accepted)
+}
+
+func TestBoltStorePersists(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "nonces.db")
+	expiresAt := time.Now().Add(time.Hour)
+
+	store, err := NewBoltStore(path)
+	require.NoError(t, err)
+	require.NoError(t, store.Record(ctx, "scope", "nonce", expiresAt))
+	require.NoError(t, store.Close())
+
+	store, err = NewBoltStore(path)
+	require.NoError(t, err)
+	defer store.Close()
+	assert.ErrorIs(t, store.Record(ctx, "scope", "nonce", expiresAt), ErrReplayed)
+}
+
+func TestCheckTimestamp(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	assert.NoError(t, CheckTimestamp(now.Unix(), now, time.Minute))
+	assert.NoError(t, CheckTimestamp(now.Unix()-60, now, time.Minute))
+	assert.NoError(t, CheckTimestamp(now.Unix()+60, now, time.Minute))
+	assert.ErrorIs(t, CheckTimestamp(now.Unix()-61, now, time.Minute), ErrClockSkew)
+	assert.ErrorIs(t, CheckTimestamp(now.Unix()+61, now, time.Minute), ErrClockSkew)
+}
@@ -0,0 +1,117 @@
+package revocation
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ak68a/agentid-core/pkg/models"
+)
+
+var (
+	// ErrNotPublished is returned before the first update of a list is published
+	ErrNotPublished = errors.New("revocation list has not been published")
+	// ErrSequenceGap is returned when an update skips one or more sequence numbers
+	ErrSequenceGap = errors.New("revocation list sequence gap")
+	// ErrRollback is returned when a list goes back to an earlier sequence number
+	ErrRollback = errors.New("revocation list rollback")
+)
+
+// Feed is the published state of a revocation list: its latest signed snapshot
+// and the signed deltas that built it, one per sequence number
+type Feed struct {
+	Snapshot *models.RevocationList    `json:"snapshot"`
+	Deltas   []*models.RevocationDelta `json:"deltas"`
+}
+
+// DeltasSince returns the deltas after sequence since, oldest first
+func (f *Feed) DeltasSince(since uint64) ([]*models.RevocationDelta, error) {
+	if f.Snapshot == nil {
+		return nil, ErrNotPublished
+	}
+	if since > f.Snapshot.Sequence {
+		return nil, fmt.Errorf("%w: list %s is at sequence %d, not %d", ErrRollback, f.Snapshot.ListID, f.Snapshot.Sequence, since)
+	}
+
+	var deltas []*models.RevocationDelta
+	for _, delta := range f.Deltas {
+		if delta.Sequence > since {
+			deltas = append(deltas, delta)
+		}
+	}
+	return deltas, nil
+}
+
+// validate checks that the deltas are numbered 1..n and lead up to the snapshot
+func (f *Feed) validate() error {
+	if f.Snapshot == nil {
+		if len(f.Deltas) > 0 {
+			return fmt.Errorf("revocation feed has deltas but no snapshot")
+		}
+		return nil
+	}
+	for i, delta := range f.Deltas {
+		if delta.ListID != f.Snapshot.ListID || delta.IssuerDID != f.Snapshot.IssuerDID {
+			return fmt.Errorf("delta %d belongs to list %s of %s", delta.Sequence, delta.ListID, delta.IssuerDID)
+		}
+		if delta.Sequence != uint64(i)+1 {
+			return fmt.Errorf("%w: delta %d is at position %d", ErrSequenceGap, delta.Sequence, i+1)
+		}
+	}
+	if uint64(len(f.Deltas)) != f.Snapshot.Sequence {
+		return fmt.Errorf("%w: snapshot is at sequence %d but there are %d deltas", ErrSequenceGap, f.Snapshot.Sequence, len(f.Deltas))
+	}
+	return nil
+}
+
+// ReadFeed reads a feed written by WriteFeed
+func ReadFeed(path string) (*Feed, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read revocation feed: %w", err)
+	}
+	var feed Feed
+	if err := json.Unmarshal(data, &feed); err != nil {
+		return nil, fmt.Errorf("failed to parse revocation feed: %w", err)
+	}
+	return &feed, nil
+}
+
+// WriteFeed replaces the feed at path through a temporary file and rename,
+// so readers never see a partially written feed
+func WriteFeed(path string, feed *Feed) error {
+	data, err := json.MarshalIndent(feed, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal revocation feed: %w", err)
+	}
+
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create feed directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(dir, ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write revocation feed: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write revocation feed: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write revocation feed: %w", err)
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return fmt.Errorf("failed to write revocation feed: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write revocation feed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,174 @@
+package revocation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ak68a/agentid-core/pkg/key"
+	"github.com/ak68a/agentid-core/pkg/models"
+	"github.com/ak68a/agentid-core/pkg/signer"
+)
+
+// Publisher maintains an issuer's revocation list. Every update is published
+// as a signed delta with the next sequence number and a new signed snapshot.
+type Publisher struct {
+	signer    *signer.ClaimSigner
+	issuerDID string
+	listID    string
+
+	mu   sync.RWMutex
+	feed Feed
+}
+
+var _ Source = (*Publisher)(nil)
+
+// NewPublisher creates a publisher for an empty list signed by issuer
+func NewPublisher(issuer key.Signer, listID string, opts ...signer.Option) *Publisher {
+	return &Publisher{
+		signer:    signer.NewClaimSigner(issuer, opts...),
+		issuerDID: issuer.GetDID(),
+		listID:    listID,
+	}
+}
+
+// Resume continues publishing from a previously published feed, e.g. one read
+// back with ReadFeed after a restart, so sequence numbers never go backwards
+func (p *Publisher) Resume(feed *Feed) error {
+	if err := feed.validate(); err != nil {
+		return err
+	}
+	if feed.Snapshot != nil && (feed.Snapshot.ListID != p.listID || feed.Snapshot.IssuerDID != p.issuerDID) {
+		return fmt.Errorf("feed is for list %s of %s", feed.Snapshot.ListID, feed.Snapshot.IssuerDID)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.feed.Snapshot != nil && (feed.Snapshot == nil || feed.Snapshot.Sequence < p.feed.Snapshot.Sequence) {
+		return fmt.Errorf("%w: feed is behind the published list", ErrRollback)
+	}
+	p.feed = Feed{Snapshot: feed.Snapshot, Deltas: append([]*models.RevocationDelta(nil), feed.Deltas...)}
+	return nil
+}
+
+// Publish adds revocations to the list and signs the resulting delta and snapshot.
+// Publishing no revocations still advances the sequence, which lets verifiers
+// with a maximum age tell a quiet list from a withheld one.
+func (p *Publisher) Publish(ctx context.Context, revocations []*models.RevocationClaim, at time.Time) (*models.RevocationDelta, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var previous []*models.RevocationClaim
+	var sequence uint64 = 1
+	if p.feed.Snapshot != nil {
+		previous = p.feed.Snapshot.Revocations
+		sequence = p.feed.Snapshot.Sequence + 1
+	}
+
+	delta := &models.RevocationDelta{
+		ListID:      p.listID,
+		IssuerDID:   p.issuerDID,
+		Sequence:    sequence,
+		Timestamp:   at.Unix(),
+		Revocations: append([]*models.RevocationClaim{}, revocations...),
+		Type:        models.RevocationDeltaType,
+	}
+	if err := p.signer.SignCredentialContext(ctx, delta); err != nil {
+		return nil, fmt.Errorf("failed to sign revocation delta: %w", err)
+	}
+
+	// Snapshots are never modified once published, so readers can share them
+	all := make([]*models.RevocationClaim, 0, len(previous)+len(revocations))
+	snapshot := &models.RevocationList{
+		ListID:      p.listID,
+		IssuerDID:   p.issuerDID,
+		LastUpdated: at.Unix(),
+		Sequence:    sequence,
+		Revocations: append(append(all, previous...), revocations...),
+		Type:        models.RevocationListType,
+	}
+	if err := p.signer.SignCredentialContext(ctx, snapshot); err != nil {
+		return nil, fmt.Errorf("failed to sign revocation list: %w", err)
+	}
+
+	p.feed.Snapshot = snapshot
+	p.feed.Deltas = append(p.feed.Deltas, delta)
+	return delta, nil
+}
+
+// Feed returns the published snapshot and deltas
+func (p *Publisher) Feed() *Feed {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return &Feed{Snapshot: p.feed.Snapshot, Deltas: append([]*models.RevocationDelta(nil), p.feed.Deltas...)}
+}
+
+// Snapshot returns the latest signed snapshot of the list
+func (p *Publisher) Snapshot(_ context.Context) (*models.RevocationList, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.feed.Snapshot == nil {
+		return nil, ErrNotPublished
+	}
+	return p.feed.Snapshot, nil
+}
+
+// Deltas returns the signed deltas after sequence since, oldest first
+func (p *Publisher) Deltas(_ context.Context, since uint64) ([]*models.RevocationDelta, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.feed.DeltasSince(since)
+}
+
+// Handler serves the list for HTTPSource: GET /snapshot returns the latest
+// snapshot and GET /deltas?since=N the deltas after sequence N
+func (p *Publisher) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /snapshot", func(w http.ResponseWriter, r *http.Request) {
+		snapshot, err := p.Snapshot(r.Context())
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, snapshot)
+	})
+	mux.HandleFunc("GET /deltas", func(w http.ResponseWriter, r *http.Request) {
+		since, err := strconv.ParseUint(r.URL.Query().Get("since"), 10, 64)
+		if err != nil {
+			http.Error(w, "invalid since parameter", http.StatusBadRequest)
+			return
+		}
+		deltas, err := p.Deltas(r.Context(), since)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		if deltas == nil {
+			deltas = []*models.RevocationDelta{}
+		}
+		writeJSON(w, deltas)
+	})
+	return mux
+}
+
+// writeError maps publisher errors to the status codes HTTPSource understands
+func writeError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, ErrNotPublished):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, ErrRollback):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
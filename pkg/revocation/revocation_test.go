@@ -0,0 +1,234 @@
+package revocation
+
+import (
+	"context"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ak68a/agentid-core/pkg/authz"
+	"github.com/ak68a/agentid-core/pkg/key"
+	"github.com/ak68a/agentid-core/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var _ authz.RevocationChecker = (*Syncer)(nil)
+
+// staticSource serves fixed updates, standing in for a misbehaving publisher
+type staticSource struct {
+	snapshot *models.RevocationList
+	deltas   []*models.RevocationDelta
+}
+
+func (s *staticSource) Snapshot(context.Context) (*models.RevocationList, error) {
+	return s.snapshot, nil
+}
+
+func (s *staticSource) Deltas(context.Context, uint64) ([]*models.RevocationDelta, error) {
+	return s.deltas, nil
+}
+
+func newKey(t *testing.T) *key.AgentKey {
+	k, err := key.GenerateAgentKey()
+	require.NoError(t, err)
+	return k
+}
+
+func revoke(issuer *key.AgentKey, credentialID, agentDID string) *models.RevocationClaim {
+	return &models.RevocationClaim{
+		RevokedCredentialID: credentialID,
+		RevokedAgentDID:     agentDID,
+		RevokerDID:          issuer.DID,
+		Reason:              models.RevocationReasonCompromised,
+		RevokedAt:           time.Now().Unix(),
+		Nonce:               "rev-" + credentialID,
+	}
+}
+
+func TestPublishAndSync(t *testing.T) {
+	ctx := context.Background()
+	issuer := newKey(t)
+	publisher := NewPublisher(issuer, "list-1")
+	syncer := NewSyncer(publisher, issuer.DID, "list-1")
+
+	// Nothing can be checked before a snapshot has been loaded
+	assert.ErrorIs(t, syncer.Sync(ctx), ErrNotPublished)
+	_, err := syncer.CheckRevocation(ctx, "cred-1", "did:ackid:0xa")
+	assert.Error(t, err)
+
+	_, err = publisher.Publish(ctx, []*models.RevocationClaim{revoke(issuer, "cred-1", "did:ackid:0xa")}, time.Now())
+	require.NoError(t, err)
+	require.NoError(t, syncer.Sync(ctx))
+	assert.Equal(t, uint64(1), syncer.Sequence())
+
+	revocation, err := syncer.CheckRevocation(ctx, "cred-1", "did:ackid:0xa")
+	require.NoError(t, err)
+	require.NotNil(t, revocation)
+	assert.Equal(t, "cred-1", revocation.RevokedCredentialID)
+
+	// Later updates arrive as deltas, including agent-wide revocations
+	_, err = publisher.Publish(ctx, []*models.RevocationClaim{revoke(issuer, "cred-2", "did:ackid:0xb")}, time.Now())
+	require.NoError(t, err)
+	delta, err := publisher.Publish(ctx, []*models.RevocationClaim{revoke(issuer, "", "did:ackid:0xc")}, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3), delta.Sequence)
+
+	require.NoError(t, syncer.Sync(ctx))
+	assert.Equal(t, uint64(3), syncer.Sequence())
+	revocation, err = syncer.CheckRevocation(ctx, "cred-2", "did:ackid:0xb")
+	require.NoError(t, err)
+	assert.NotNil(t, revocation)
+	revocation, err = syncer.CheckRevocation(ctx, "any", "did:ackid:0xc")
+	require.NoError(t, err)
+	assert.NotNil(t, revocation)
+	revocation, err = syncer.CheckRevocation(ctx, "cred-3", "did:ackid:0xa")
+	require.NoError(t, err)
+	assert.Nil(t, revocation)
+
+	snapshot, err := publisher.Snapshot(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3), snapshot.Sequence)
+	assert.Len(t, snapshot.Revocations, 3)
+}
+
+func TestSources(t *testing.T) {
+	ctx := context.Background()
+	issuer := newKey(t)
+	publisher := NewPublisher(issuer, "list-1")
+	_, err := publisher.Publish(ctx, []*models.RevocationClaim{revoke(issuer, "cred-1", "did:ackid:0xa")}, time.Now())
+	require.NoError(t, err)
+
+	server := httptest.NewServer(publisher.Handler())
+	defer server.Close()
+	path := filepath.Join(t.TempDir(), "revocations.json")
+	require.NoError(t, WriteFeed(path, publisher.Feed()))
+
+	sources := map[string]Source{
+		"http": &HTTPSource{URL: server.URL, Client: server.Client()},
+		"file": &FileSource{Path: path},
+	}
+	for name, source := range sources {
+		t.Run(name, func(t *testing.T) {
+			syncer := NewSyncer(source, issuer.DID, "list-1")
+			require.NoError(t, syncer.Sync(ctx))
+
+			_, err := publisher.Publish(ctx, []*models.RevocationClaim{revoke(issuer, "cred-"+name, "did:ackid:0xb")}, time.Now())
+			require.NoError(t, err)
+			require.NoError(t, WriteFeed(path, publisher.Feed()))
+			require.NoError(t, syncer.Sync(ctx))
+
+			revocation, err := syncer.CheckRevocation(ctx, "cred-"+name, "did:ackid:0xb")
+			require.NoError(t, err)
+			assert.NotNil(t, revocation)
+		})
+	}
+}
+
+func TestSyncRejectsBadUpdates(t *testing.T) {
+	ctx := context.Background()
+	issuer := newKey(t)
+	publisher := NewPublisher(issuer, "list-1")
+	for i := 0; i < 4; i++ {
+		_, err := publisher.Publish(ctx, []*models.RevocationClaim{revoke(issuer, "cred", "did:ackid:0xa")}, time.Now())
+		require.NoError(t, err)
+	}
+	feed := publisher.Feed()
+
+	// A snapshot at sequence 1, rebuilt and signed by the issuer
+	base := NewPublisher(issuer, "list-1")
+	_, err := base.Publish(ctx, feed.Deltas[0].Revocations, time.Now())
+	require.NoError(t, err)
+	snapshot, err := base.Snapshot(ctx)
+	require.NoError(t, err)
+
+	forged := NewPublisher(newKey(t), "list-1")
+	_, err = forged.Publish(ctx, nil, time.Now())
+	require.NoError(t, err)
+	forgedSnapshot, err := forged.Snapshot(ctx)
+	require.NoError(t, err)
+
+	tampered := *feed.Deltas[1]
+	tampered.Revocations = nil
+
+	tests := []struct {
+		name   string
+		source *staticSource
+		err    error
+	}{
+		{"gap", &staticSource{snapshot, []*models.RevocationDelta{feed.Deltas[2]}}, ErrSequenceGap},
+		{"rollback", &staticSource{snapshot, []*models.RevocationDelta{feed.Deltas[0]}}, ErrRollback},
+		{"tampered delta", &staticSource{snapshot, []*models.RevocationDelta{&tampered}}, ErrInvalidUpdate},
+		{"snapshot from another issuer", &staticSource{forgedSnapshot, nil}, ErrInvalidUpdate},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			syncer := NewSyncer(tt.source, issuer.DID, "list-1")
+			assert.ErrorIs(t, syncer.Sync(ctx), tt.err)
+		})
+	}
+
+	// A publisher that lost its state cannot serve a syncer that is ahead of it
+	syncer := NewSyncer(publisher, issuer.DID, "list-1")
+	require.NoError(t, syncer.Sync(ctx))
+	server := httptest.NewServer(base.Handler())
+	defer server.Close()
+	syncer.source = &HTTPSource{URL: server.URL, Client: server.Client()}
+	assert.ErrorIs(t, syncer.Sync(ctx), ErrRollback)
+	assert.Equal(t, uint64(4), syncer.Sequence())
+}
+
+func TestSyncMaxAge(t *testing.T) {
+	ctx := context.Background()
+	issuer := newKey(t)
+	publisher := NewPublisher(issuer, "list-1")
+	now := time.Now()
+	_, err := publisher.Publish(ctx, nil, now)
+	require.NoError(t, err)
+
+	clock := now
+	syncer := NewSyncer(publisher, issuer.DID, "list-1", WithMaxAge(time.Hour), WithClock(func() time.Time { return clock }))
+	require.NoError(t, syncer.Sync(ctx))
+	_, err = syncer.CheckRevocation(ctx, "cred", "did:ackid:0xa")
+	require.NoError(t, err)
+
+	// Without a fresh update the index is no longer trusted
+	clock = now.Add(2 * time.Hour)
+	require.NoError(t, syncer.Sync(ctx))
+	_, err = syncer.CheckRevocation(ctx, "cred", "did:ackid:0xa")
+	assert.ErrorIs(t, err, ErrStale)
+
+	// An empty heartbeat delta refreshes it
+	_, err = publisher.Publish(ctx, nil, clock)
+	require.NoError(t, err)
+	require.NoError(t, syncer.Sync(ctx))
+	_, err = syncer.CheckRevocation(ctx, "cred", "did:ackid:0xa")
+	assert.NoError(t, err)
+}
+
+func TestResume(t *testing.T) {
+	ctx := context.Background()
+	issuer := newKey(t)
+	publisher := NewPublisher(issuer, "list-1")
+	for i := 0; i < 2; i++ {
+		_, err := publisher.Publish(ctx, []*models.RevocationClaim{revoke(issuer, "cred", "did:ackid:0xa")}, time.Now())
+		require.NoError(t, err)
+	}
+	path := filepath.Join(t.TempDir(), "revocations.json")
+	require.NoError(t, WriteFeed(path, publisher.Feed()))
+
+	feed, err := ReadFeed(path)
+	require.NoError(t, err)
+	restarted := NewPublisher(issuer, "list-1")
+	require.NoError(t, restarted.Resume(feed))
+	delta, err := restarted.Publish(ctx, nil, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3), delta.Sequence)
+
+	// Older or inconsistent feeds are refused
+	assert.ErrorIs(t, restarted.Resume(feed), ErrRollback)
+	feed.Deltas = feed.Deltas[1:]
+	assert.ErrorIs(t, NewPublisher(issuer, "list-1").Resume(feed), ErrSequenceGap)
+	assert.Error(t, NewPublisher(issuer, "list-2").Resume(publisher.Feed()))
+}
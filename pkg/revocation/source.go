@@ -0,0 +1,111 @@
+package revocation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ak68a/agentid-core/pkg/models"
+)
+
+// maxResponseSize bounds the size of a fetched snapshot or delta batch
+const maxResponseSize = 16 << 20
+
+// Source serves a published revocation list. Sources are not trusted: the
+// Syncer verifies everything they return.
+type Source interface {
+	// Snapshot returns the latest signed snapshot of the list
+	Snapshot(ctx context.Context) (*models.RevocationList, error)
+	// Deltas returns the signed deltas after sequence since, oldest first
+	Deltas(ctx context.Context, since uint64) ([]*models.RevocationDelta, error)
+}
+
+// FileSource reads a feed written by WriteFeed, e.g. to a shared volume
+type FileSource struct {
+	Path string
+}
+
+// Snapshot reads the feed's snapshot
+func (f *FileSource) Snapshot(_ context.Context) (*models.RevocationList, error) {
+	feed, err := ReadFeed(f.Path)
+	if err != nil {
+		return nil, err
+	}
+	if feed.Snapshot == nil {
+		return nil, ErrNotPublished
+	}
+	return feed.Snapshot, nil
+}
+
+// Deltas reads the feed's deltas after sequence since
+func (f *FileSource) Deltas(_ context.Context, since uint64) ([]*models.RevocationDelta, error) {
+	feed, err := ReadFeed(f.Path)
+	if err != nil {
+		return nil, err
+	}
+	return feed.DeltasSince(since)
+}
+
+// HTTPSource fetches a list served by Publisher.Handler
+type HTTPSource struct {
+	URL    string       // base URL of the handler
+	Client *http.Client // defaults to a client with a 10s timeout
+}
+
+// Snapshot fetches URL/snapshot
+func (h *HTTPSource) Snapshot(ctx context.Context) (*models.RevocationList, error) {
+	var snapshot models.RevocationList
+	if err := h.get(ctx, "/snapshot", &snapshot); err != nil {
+		return nil, err
+	}
+	return &snapshot, nil
+}
+
+// Deltas fetches URL/deltas?since=N
+func (h *HTTPSource) Deltas(ctx context.Context, since uint64) ([]*models.RevocationDelta, error) {
+	var deltas []*models.RevocationDelta
+	if err := h.get(ctx, "/deltas?since="+strconv.FormatUint(since, 10), &deltas); err != nil {
+		return nil, err
+	}
+	return deltas, nil
+}
+
+// get fetches and decodes a JSON document below the base URL
+func (h *HTTPSource) get(ctx context.Context, path string, v interface{}) error {
+	url := strings.TrimSuffix(h.URL, "/") + path
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	client := h.Client
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to fetch %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return fmt.Errorf("%w: %s", ErrNotPublished, url)
+	case http.StatusConflict:
+		return fmt.Errorf("%w: %s", ErrRollback, url)
+	default:
+		return fmt.Errorf("failed to fetch %s: %s", url, resp.Status)
+	}
+
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxResponseSize)).Decode(v); err != nil {
+		return fmt.Errorf("invalid revocation data at %s: %w", url, err)
+	}
+	return nil
+}
@@ -0,0 +1,237 @@
+package revocation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ak68a/agentid-core/pkg/did"
+	"github.com/ak68a/agentid-core/pkg/models"
+	"github.com/ak68a/agentid-core/pkg/signer"
+)
+
+var (
+	// ErrInvalidUpdate is returned when a snapshot or delta is not signed by the list's issuer
+	ErrInvalidUpdate = errors.New("invalid revocation list update")
+	// ErrStale is returned when the local index is older than the allowed maximum age
+	ErrStale = errors.New("revocation index is stale")
+)
+
+// Syncer keeps a local index of an issuer's revocation list up to date by
+// pulling signed deltas from a Source. It bootstraps from a signed snapshot,
+// then only accepts deltas that continue its sequence. It implements
+// authz.RevocationChecker.
+type Syncer struct {
+	source    Source
+	issuerDID string
+	listID    string
+	verifier  *signer.ClaimSigner
+	maxAge    time.Duration
+	now       func() time.Time
+
+	syncMu sync.Mutex // serializes Sync
+
+	mu          sync.RWMutex
+	synced      bool
+	sequence    uint64
+	updatedAt   int64
+	credentials map[string][]*models.RevocationClaim
+	agents      map[string][]*models.RevocationClaim
+}
+
+// SyncerOption configures a Syncer
+type SyncerOption func(*Syncer)
+
+// WithResolver sets the DID resolver used to verify list signatures
+func WithResolver(resolver did.Resolver) SyncerOption {
+	return func(s *Syncer) {
+		s.verifier = signer.NewClaimSigner(nil, signer.WithResolver(resolver))
+	}
+}
+
+// WithMaxAge makes lookups fail once the newest update is older than maxAge.
+// The issuer must then publish at least once per maxAge, even with nothing to
+// revoke, but a source that withholds updates can no longer go unnoticed.
+func WithMaxAge(maxAge time.Duration) SyncerOption {
+	return func(s *Syncer) {
+		s.maxAge = maxAge
+	}
+}
+
+// WithClock sets the time source used for the maximum age check
+func WithClock(now func() time.Time) SyncerOption {
+	return func(s *Syncer) {
+		s.now = now
+	}
+}
+
+// NewSyncer creates a Syncer for the list listID of issuerDID
+func NewSyncer(source Source, issuerDID, listID string, opts ...SyncerOption) *Syncer {
+	s := &Syncer{
+		source:      source,
+		issuerDID:   issuerDID,
+		listID:      listID,
+		verifier:    signer.NewClaimSigner(nil),
+		now:         time.Now,
+		credentials: make(map[string][]*models.RevocationClaim),
+		agents:      make(map[string][]*models.RevocationClaim),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Sequence returns the sequence number of the local index, 0 before the first sync
+func (s *Syncer) Sequence() uint64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.sequence
+}
+
+// Sync brings the index up to date. The first call loads the latest snapshot;
+// later calls apply the deltas published since. A delta that skips a sequence
+// number fails with ErrSequenceGap and one that goes backwards with ErrRollback;
+// the index keeps every update applied before the failure.
+func (s *Syncer) Sync(ctx context.Context) error {
+	s.syncMu.Lock()
+	defer s.syncMu.Unlock()
+
+	if !s.isSynced() {
+		snapshot, err := s.source.Snapshot(ctx)
+		if err != nil {
+			return err
+		}
+		if err := s.verify(ctx, snapshot, snapshot.ListID, snapshot.IssuerDID); err != nil {
+			return err
+		}
+		s.load(snapshot)
+	}
+
+	deltas, err := s.source.Deltas(ctx, s.Sequence())
+	if err != nil {
+		return err
+	}
+	for _, delta := range deltas {
+		current := s.Sequence()
+		if delta.Sequence <= current {
+			return fmt.Errorf("%w: delta %d after sequence %d", ErrRollback, delta.Sequence, current)
+		}
+		if delta.Sequence != current+1 {
+			return fmt.Errorf("%w: delta %d after sequence %d", ErrSequenceGap, delta.Sequence, current)
+		}
+		if err := s.verify(ctx, delta, delta.ListID, delta.IssuerDID); err != nil {
+			return err
+		}
+		s.apply(delta)
+	}
+	return nil
+}
+
+// Run syncs every interval until ctx is done. Integrity failures (gaps,
+// rollbacks and invalid updates) stop it and are returned; other failures,
+// such as an unreachable source, are retried on the next tick.
+func (s *Syncer) Run(ctx context.Context, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		err := s.Sync(ctx)
+		if errors.Is(err, ErrSequenceGap) || errors.Is(err, ErrRollback) || errors.Is(err, ErrInvalidUpdate) {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// CheckRevocation returns the effective revocation of a credential or of every
+// credential of its agent, or nil if neither is revoked
+func (s *Syncer) CheckRevocation(_ context.Context, credentialID, agentDID string) (*models.RevocationClaim, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if !s.synced {
+		return nil, fmt.Errorf("revocation list %s has not been synced", s.listID)
+	}
+	if s.maxAge > 0 && s.now().Sub(time.Unix(s.updatedAt, 0)) > s.maxAge {
+		return nil, fmt.Errorf("%w: last update of %s was at %d", ErrStale, s.listID, s.updatedAt)
+	}
+
+	if credentialID != "" {
+		if revocation := effective(s.credentials[credentialID]); revocation != nil {
+			return revocation, nil
+		}
+	}
+	return effective(s.agents[agentDID]), nil
+}
+
+func (s *Syncer) isSynced() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.synced
+}
+
+// verify checks that an update belongs to the synced list and is signed by its issuer
+func (s *Syncer) verify(ctx context.Context, update interface{}, listID, issuerDID string) error {
+	if listID != s.listID || issuerDID != s.issuerDID {
+		return fmt.Errorf("%w: update for list %s of %s", ErrInvalidUpdate, listID, issuerDID)
+	}
+	valid, err := s.verifier.VerifyCredentialContext(ctx, update)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidUpdate, err)
+	}
+	if !valid {
+		return fmt.Errorf("%w: invalid signature", ErrInvalidUpdate)
+	}
+	return nil
+}
+
+// load replaces the index with a snapshot
+func (s *Syncer) load(snapshot *models.RevocationList) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.credentials = make(map[string][]*models.RevocationClaim)
+	s.agents = make(map[string][]*models.RevocationClaim)
+	s.index(snapshot.Revocations)
+	s.synced = true
+	s.sequence = snapshot.Sequence
+	s.updatedAt = snapshot.LastUpdated
+}
+
+// apply adds a verified delta to the index
+func (s *Syncer) apply(delta *models.RevocationDelta) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.index(delta.Revocations)
+	s.sequence = delta.Sequence
+	s.updatedAt = delta.Timestamp
+}
+
+// index adds revocations to the lookup maps. A revocation with an empty
+// credential ID revokes every credential of its agent.
+func (s *Syncer) index(revocations []*models.RevocationClaim) {
+	for _, revocation := range revocations {
+		if revocation.RevokedCredentialID == "" {
+			s.agents[revocation.RevokedAgentDID] = append(s.agents[revocation.RevokedAgentDID], revocation)
+			continue
+		}
+		s.credentials[revocation.RevokedCredentialID] = append(s.credentials[revocation.RevokedCredentialID], revocation)
+	}
+}
+
+// effective returns the first revocation that is in effect
+func effective(revocations []*models.RevocationClaim) *models.RevocationClaim {
+	for _, revocation := range revocations {
+		if revocation.IsEffective() {
+			return revocation
+		}
+	}
+	return nil
+}
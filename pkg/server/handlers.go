@@ -0,0 +1,333 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/ak68a/agentid-core/pkg/authz"
+	"github.com/ak68a/agentid-core/pkg/models"
+	"github.com/ak68a/agentid-core/pkg/signer"
+)
+
+// TypeDelegationChain is the /v1/verify type of a delegation chain, root first
+const TypeDelegationChain = "DelegationChain"
+
+// VerifyRequest is the body of /v1/verify. Type is the credential's EIP-712
+// primary type, such as signer.TypeAgentClaim, or TypeDelegationChain.
+type VerifyRequest struct {
+	Type       string          `json:"type"`
+	Credential json.RawMessage `json:"credential"`
+}
+
+// VerifyResponse is the answer of /v1/verify
+type VerifyResponse struct {
+	Valid        bool   `json:"valid"`
+	CredentialID string `json:"credential_id,omitempty"` // ID of the credential, or of the chain's leaf
+	Reason       string `json:"reason,omitempty"`        // Why the credential is not valid
+}
+
+// AuthorizeRequest is the body of /v1/authorize: a request and the credentials presented for it
+type AuthorizeRequest struct {
+	Request       *models.AuthorizationRequest `json:"request"`
+	Ownership     *models.OwnershipClaim       `json:"ownership,omitempty"`
+	Authorization *models.AgentClaim           `json:"authorization,omitempty"`
+	Delegations   *models.DelegationChain      `json:"delegations,omitempty"`
+}
+
+// ErrorResponse is the body of every error answer
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+// newCredential returns an empty credential model for a /v1/verify type
+func newCredential(typ string) (interface{}, error) {
+	switch typ {
+	case signer.TypeAgentClaim:
+		return &models.AgentClaim{}, nil
+	case signer.TypeOwnershipClaim:
+		return &models.OwnershipClaim{}, nil
+	case signer.TypeDelegationClaim:
+		return &models.DelegationClaim{}, nil
+	case signer.TypeRevocationClaim:
+		return &models.RevocationClaim{}, nil
+	case signer.TypeRevocationList:
+		return &models.RevocationList{}, nil
+	case signer.TypeRevocationDelta:
+		return &models.RevocationDelta{}, nil
+	case signer.TypeStatusListCredential:
+		return &models.StatusList2021Credential{}, nil
+	case signer.TypeRevocationStatus:
+		return &models.RevocationStatus{}, nil
+	case signer.TypeAuthorizationResponse:
+		return &models.AuthorizationResponse{}, nil
+	case TypeDelegationChain:
+		return &models.DelegationChain{}, nil
+	default:
+		return nil, fmt.Errorf("unsupported credential type %q", typ)
+	}
+}
+
+func (s *Server) handleVerify(w http.ResponseWriter, r *http.Request) {
+	var req VerifyRequest
+	if !s.decode(w, r, &req) {
+		return
+	}
+	credential, err := newCredential(req.Type)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if len(req.Credential) == 0 {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("no credential given"))
+		return
+	}
+	if err := json.Unmarshal(req.Credential, credential); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid %s: %w", req.Type, err))
+		return
+	}
+
+	var response VerifyResponse
+	if chain, ok := credential.(*models.DelegationChain); ok {
+		response = s.verifyChain(r, chain)
+	} else {
+		response = s.verifyCredential(r, credential)
+	}
+	writeJSON(w, http.StatusOK, &response)
+}
+
+// verifyCredential checks a credential's signature, expiry, status and revocation
+func (s *Server) verifyCredential(r *http.Request, credential interface{}) VerifyResponse {
+	var response VerifyResponse
+	if c, ok := credential.(models.Credential); ok {
+		if id, err := c.CredentialID(); err == nil {
+			response.CredentialID = id
+		}
+	}
+
+	valid, err := s.verifier.VerifyCredentialContext(r.Context(), credential)
+	if err != nil {
+		response.Reason = err.Error()
+		return response
+	}
+	if !valid {
+		response.Reason = "invalid signature"
+		return response
+	}
+
+	now := s.now().Unix()
+	expiresAt, agentDID := credentialSubject(credential)
+	if expiresAt != 0 && now > expiresAt {
+		response.Reason = fmt.Sprintf("expired at %d", expiresAt)
+		return response
+	}
+	if claim, ok := credential.(*models.AgentClaim); ok && (claim.Status == models.StatusRevoked || claim.Status == models.StatusSuspended) {
+		response.Reason = fmt.Sprintf("claim is %s", claim.Status)
+		return response
+	}
+	if err := s.checkStatus(r, credential, "credential"); err != nil {
+		response.Reason = err.Error()
+		return response
+	}
+	if s.revocations != nil && agentDID != "" {
+		revocation, err := s.revocations.CheckRevocation(r.Context(), response.CredentialID, agentDID)
+		if err != nil {
+			response.Reason = fmt.Sprintf("revocation status is unavailable: %v", err)
+			return response
+		}
+		if revocation != nil {
+			response.Reason = fmt.Sprintf("revoked: %s", revocation.Reason)
+			return response
+		}
+	}
+	response.Valid = true
+	return response
+}
+
+// verifyChain checks every signature in a chain, its status and revocations, its
+// structure and that every delegation attenuates its parent
+func (s *Server) verifyChain(r *http.Request, chain *models.DelegationChain) VerifyResponse {
+	var response VerifyResponse
+	if leaf := chain.GetLeafDelegation(); leaf != nil {
+		if id, err := leaf.CredentialID(); err == nil {
+			response.CredentialID = id
+		}
+	}
+
+	valid, err := s.verifier.VerifyDelegationChainContext(r.Context(), chain)
+	if err != nil {
+		response.Reason = err.Error()
+		return response
+	}
+	if !valid {
+		response.Reason = "invalid signature"
+		return response
+	}
+	for i, delegation := range chain.Delegations {
+		if err := s.checkStatus(r, delegation, fmt.Sprintf("delegation %d", i)); err != nil {
+			response.Reason = err.Error()
+			return response
+		}
+	}
+	if !chain.ValidateChainAt(s.now()) {
+		response.Reason = fmt.Sprintf("invalid delegation chain: %s", chain.Reason)
+		return response
+	}
+	if err := chain.ValidateChainConstraints(); err != nil {
+		response.Reason = fmt.Sprintf("invalid delegation chain: %v", err)
+		return response
+	}
+	response.Valid = true
+	return response
+}
+
+// credentialSubject returns when a credential expires (0 = never) and the agent it is about, if any
+func credentialSubject(credential interface{}) (int64, string) {
+	switch c := credential.(type) {
+	case *models.AgentClaim:
+		return c.ExpiresAt, c.AgentDID
+	case *models.OwnershipClaim:
+		return c.ExpiresAt, c.AgentDID
+	case *models.DelegationClaim:
+		return c.ExpiresAt, c.DelegateDID
+	case *models.StatusList2021Credential:
+		return c.ValidUntil, ""
+	case *models.AuthorizationResponse:
+		return c.ValidUntil, ""
+	default:
+		return 0, ""
+	}
+}
+
+// checkStatus checks the status list entry of a credential that has one, if a
+// status checker is configured
+func (s *Server) checkStatus(r *http.Request, credential interface{}, name string) error {
+	if s.statuses == nil {
+		return nil
+	}
+	var entry *models.CredentialStatus
+	switch c := credential.(type) {
+	case *models.AgentClaim:
+		entry = c.CredentialStatus
+	case *models.OwnershipClaim:
+		entry = c.CredentialStatus
+	case *models.DelegationClaim:
+		entry = c.CredentialStatus
+	}
+	return authz.CheckStatus(r.Context(), s.statuses, credential, entry, name)
+}
+
+func (s *Server) handleAuthorize(w http.ResponseWriter, r *http.Request) {
+	if s.engine == nil {
+		writeError(w, http.StatusServiceUnavailable, fmt.Errorf("authorization is disabled"))
+		return
+	}
+	var req AuthorizeRequest
+	if !s.decode(w, r, &req) {
+		return
+	}
+	if req.Request == nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("no authorization request given"))
+		return
+	}
+
+	response, err := s.engine.Authorize(r.Context(), req.Request, &authz.Credentials{
+		Ownership:     req.Ownership,
+		Authorization: req.Authorization,
+		Delegations:   req.Delegations,
+	})
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (s *Server) handleRevocation(w http.ResponseWriter, r *http.Request) {
+	if s.revocations == nil {
+		writeError(w, http.StatusServiceUnavailable, fmt.Errorf("no revocation source is configured"))
+		return
+	}
+	var query models.RevocationQuery
+	if !s.decode(w, r, &query) {
+		return
+	}
+	credentialID := r.PathValue("id")
+	if query.CredentialID != "" && !strings.EqualFold(query.CredentialID, credentialID) {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("query is for %s, not %s", query.CredentialID, credentialID))
+		return
+	}
+
+	revocation, err := s.revocations.CheckRevocation(r.Context(), credentialID, query.AgentDID)
+	if err != nil {
+		writeError(w, http.StatusServiceUnavailable, fmt.Errorf("revocation status is unavailable: %w", err))
+		return
+	}
+	// A query about the past ignores revocations made since
+	if revocation != nil && query.AsOfTime != 0 && revocationTime(revocation) > query.AsOfTime {
+		revocation = nil
+	}
+
+	status := &models.RevocationStatus{
+		CredentialID: credentialID,
+		AgentDID:     query.AgentDID,
+		IsRevoked:    revocation != nil,
+		QueryID:      query.QueryID,
+		ResponderDID: s.responderDID,
+		Timestamp:    s.now().Unix(),
+	}
+	if revocation != nil {
+		status.RevokedAt = revocationTime(revocation)
+		status.Reason = revocation.Reason
+	}
+	if err := s.responder.SignCredentialContext(r.Context(), status); err != nil {
+		writeError(w, http.StatusInternalServerError, fmt.Errorf("failed to sign revocation status: %w", err))
+		return
+	}
+	writeJSON(w, http.StatusOK, status)
+}
+
+// revocationTime returns when a revocation took effect
+func revocationTime(revocation *models.RevocationClaim) int64 {
+	if revocation.EffectiveAt != 0 {
+		return revocation.EffectiveAt
+	}
+	return revocation.RevokedAt
+}
+
+func (s *Server) handleDID(w http.ResponseWriter, r *http.Request) {
+	doc, err := s.resolver.Resolve(r.Context(), r.PathValue("did"))
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, doc)
+}
+
+// decode reads a JSON body of at most the maximum body size into v, answering
+// with an error and returning false if it cannot
+func (s *Server) decode(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	r.Body = http.MaxBytesReader(w, r.Body, s.maxBodySize)
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			writeError(w, http.StatusRequestEntityTooLarge, fmt.Errorf("request body exceeds %d bytes", tooLarge.Limit))
+			return false
+		}
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid request body: %w", err))
+		return false
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, &ErrorResponse{Error: err.Error()})
+}
@@ -0,0 +1,217 @@
+// Package server exposes credential verification, authorization decisions,
+// revocation status and DID resolution as a JSON HTTP service.
+package server
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/ak68a/agentid-core/pkg/authz"
+	"github.com/ak68a/agentid-core/pkg/did"
+	"github.com/ak68a/agentid-core/pkg/key"
+	"github.com/ak68a/agentid-core/pkg/models"
+	"github.com/ak68a/agentid-core/pkg/signer"
+)
+
+// DefaultMaxBodySize is the largest request body accepted unless WithMaxBodySize is given
+const DefaultMaxBodySize = 1 << 20
+
+// DefaultShutdownTimeout is how long Serve waits for in-flight requests once its context is done
+const DefaultShutdownTimeout = 10 * time.Second
+
+// DefaultDIDCacheTTL is how long the default resolver keeps resolved DID documents
+const DefaultDIDCacheTTL = 5 * time.Minute
+
+// Server answers verification, authorization and revocation queries, signing
+// its authorization responses and revocation statuses with the responder's key
+type Server struct {
+	responder       *signer.ClaimSigner
+	responderDID    string
+	verifier        *signer.ClaimSigner
+	engine          *authz.Engine
+	revocations     models.RevocationChecker
+	statuses        authz.StatusChecker
+	resolver        did.Resolver
+	maxBodySize     int64
+	shutdownTimeout time.Duration
+	now             func() time.Time
+}
+
+// Option configures a Server
+type Option func(*options)
+
+type options struct {
+	signerOpts      []signer.Option
+	engineOpts      []authz.Option
+	revocations     models.RevocationChecker
+	statuses        authz.StatusChecker
+	resolver        did.Resolver
+	maxBodySize     int64
+	shutdownTimeout time.Duration
+	now             func() time.Time
+	noAuthorization bool
+}
+
+// WithResolver sets the DID resolver used to verify signatures and serve /v1/dids.
+// The default resolver caches documents for DefaultDIDCacheTTL and only fetches
+// did:web documents from public addresses.
+func WithResolver(resolver did.Resolver) Option {
+	return func(o *options) {
+		o.resolver = resolver
+		o.signerOpts = append(o.signerOpts, signer.WithResolver(resolver))
+		o.engineOpts = append(o.engineOpts, authz.WithResolver(resolver))
+	}
+}
+
+// WithDomain sets the EIP-712 domain responses are signed and checked under
+func WithDomain(domain models.EIP712Domain) Option {
+	return func(o *options) {
+		o.signerOpts = append(o.signerOpts, signer.WithDomain(domain))
+		o.engineOpts = append(o.engineOpts, authz.WithDomain(domain))
+	}
+}
+
+// WithRevocationChecker sets the revocation source used by every endpoint.
+// Without one, /v1/revocations answers 503 Service Unavailable.
+func WithRevocationChecker(checker models.RevocationChecker) Option {
+	return func(o *options) {
+		o.revocations = checker
+		o.signerOpts = append(o.signerOpts, signer.WithRevocationChecker(checker))
+		o.engineOpts = append(o.engineOpts, authz.WithRevocationChecker(checker))
+	}
+}
+
+// WithStatusChecker makes /v1/verify and /v1/authorize check the status list
+// entries (credentialStatus) of credentials, such as with a *status.Verifier
+func WithStatusChecker(checker authz.StatusChecker) Option {
+	return func(o *options) {
+		o.statuses = checker
+		o.engineOpts = append(o.engineOpts, authz.WithStatusChecker(checker))
+	}
+}
+
+// WithEngineOptions configures the authorization engine behind /v1/authorize,
+// e.g. with a ledger, trusted issuers or a nonce store
+func WithEngineOptions(opts ...authz.Option) Option {
+	return func(o *options) {
+		o.engineOpts = append(o.engineOpts, opts...)
+	}
+}
+
+// WithoutAuthorization disables /v1/authorize, which then answers 503 Service
+// Unavailable, e.g. for a verification-only service with no trusted issuers
+func WithoutAuthorization() Option {
+	return func(o *options) {
+		o.noAuthorization = true
+	}
+}
+
+// WithMaxBodySize sets the largest request body accepted, in bytes
+func WithMaxBodySize(size int64) Option {
+	return func(o *options) {
+		o.maxBodySize = size
+	}
+}
+
+// WithShutdownTimeout sets how long Serve waits for in-flight requests when stopping
+func WithShutdownTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.shutdownTimeout = timeout
+	}
+}
+
+// WithClock sets the time source used for expiry checks and timestamps
+func WithClock(now func() time.Time) Option {
+	return func(o *options) {
+		o.now = now
+		o.engineOpts = append(o.engineOpts, authz.WithClock(now))
+	}
+}
+
+// New creates a Server that signs its answers with the responder's key
+func New(responder key.Signer, opts ...Option) *Server {
+	resolver := did.NewCache(did.NewResolver(), DefaultDIDCacheTTL)
+	o := &options{
+		signerOpts:      []signer.Option{signer.WithResolver(resolver)},
+		engineOpts:      []authz.Option{authz.WithResolver(resolver)},
+		resolver:        resolver,
+		maxBodySize:     DefaultMaxBodySize,
+		shutdownTimeout: DefaultShutdownTimeout,
+		now:             time.Now,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	var engine *authz.Engine
+	if !o.noAuthorization {
+		engine = authz.NewEngine(responder, o.engineOpts...)
+	}
+	return &Server{
+		responder:       signer.NewClaimSigner(responder, o.signerOpts...),
+		responderDID:    responder.GetDID(),
+		verifier:        signer.NewClaimSigner(nil, o.signerOpts...),
+		engine:          engine,
+		revocations:     o.revocations,
+		statuses:        o.statuses,
+		resolver:        o.resolver,
+		maxBodySize:     o.maxBodySize,
+		shutdownTimeout: o.shutdownTimeout,
+		now:             o.now,
+	}
+}
+
+// Handler returns the HTTP handler serving the API
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v1/verify", s.handleVerify)
+	mux.HandleFunc("POST /v1/authorize", s.handleAuthorize)
+	mux.HandleFunc("POST /v1/revocations/{id}", s.handleRevocation)
+	mux.HandleFunc("GET /v1/dids/{did}", s.handleDID)
+	return mux
+}
+
+// Serve serves the API on l until ctx is done, then stops accepting connections
+// and waits up to the shutdown timeout for in-flight requests to finish
+func (s *Server) Serve(ctx context.Context, l net.Listener) error {
+	srv := &http.Server{
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+		ReadTimeout:       30 * time.Second,
+		WriteTimeout:      30 * time.Second,
+		IdleTimeout:       2 * time.Minute,
+	}
+
+	errc := make(chan error, 1)
+	go func() {
+		errc <- srv.Serve(l)
+	}()
+
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), s.shutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return err
+	}
+	if err := <-errc; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// ListenAndServe listens on addr and serves the API until ctx is done (see Serve)
+func (s *Server) ListenAndServe(ctx context.Context, addr string) error {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	return s.Serve(ctx, l)
+}
@@ -0,0 +1,350 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ak68a/agentid-core/pkg/authz"
+	"github.com/ak68a/agentid-core/pkg/key"
+	"github.com/ak68a/agentid-core/pkg/models"
+	"github.com/ak68a/agentid-core/pkg/signer"
+	"github.com/ak68a/agentid-core/pkg/status"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testEnv struct {
+	owner, agent, responder *key.AgentKey
+	claim                   *models.AgentClaim
+	server                  *httptest.Server
+}
+
+func newKey(t *testing.T) *key.AgentKey {
+	k, err := key.GenerateAgentKey()
+	require.NoError(t, err)
+	return k
+}
+
+func setup(t *testing.T, opts ...Option) *testEnv {
+	env := &testEnv{owner: newKey(t), agent: newKey(t), responder: newKey(t)}
+	env.claim = models.NewTransferClaim(env.agent.DID, env.owner.DID, models.ScopeETH, "1000", time.Now().Add(time.Hour).Unix(), "claim-1")
+	require.NoError(t, signer.NewClaimSigner(env.owner).SignCredential(env.claim))
+
+	opts = append([]Option{WithEngineOptions(authz.WithTrustedIssuers(env.owner.DID))}, opts...)
+	env.server = httptest.NewServer(New(env.responder, opts...).Handler())
+	t.Cleanup(env.server.Close)
+	return env
+}
+
+// post sends body as JSON and decodes the answer into out, returning the status code
+func (env *testEnv) post(t *testing.T, path string, body, out interface{}) int {
+	data, err := json.Marshal(body)
+	require.NoError(t, err)
+	resp, err := env.server.Client().Post(env.server.URL+path, "application/json", bytes.NewReader(data))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(out))
+	return resp.StatusCode
+}
+
+func verifyRequest(t *testing.T, typ string, credential interface{}) *VerifyRequest {
+	data, err := json.Marshal(credential)
+	require.NoError(t, err)
+	return &VerifyRequest{Type: typ, Credential: data}
+}
+
+func TestVerify(t *testing.T) {
+	env := setup(t)
+
+	var response VerifyResponse
+	require.Equal(t, http.StatusOK, env.post(t, "/v1/verify", verifyRequest(t, signer.TypeAgentClaim, env.claim), &response))
+	assert.True(t, response.Valid, response.Reason)
+	id, err := env.claim.CredentialID()
+	require.NoError(t, err)
+	assert.Equal(t, id, response.CredentialID)
+
+	tampered := *env.claim
+	tampered.MaxAmount = "1000000"
+	response = VerifyResponse{}
+	require.Equal(t, http.StatusOK, env.post(t, "/v1/verify", verifyRequest(t, signer.TypeAgentClaim, &tampered), &response))
+	assert.False(t, response.Valid)
+	assert.NotEmpty(t, response.Reason)
+
+	// Chains are verified as a whole
+	delegation := &models.DelegationClaim{
+		DelegatorDID: env.owner.DID,
+		DelegateDID:  env.agent.DID,
+		Action:       models.ActionTransfer,
+		Scope:        models.ScopeETH,
+		IssuedAt:     time.Now().Unix(),
+		ExpiresAt:    time.Now().Add(time.Hour).Unix(),
+		Nonce:        "delegation-1",
+		MaxDepth:     1,
+	}
+	require.NoError(t, signer.NewClaimSigner(env.owner).SignDelegationClaim(delegation))
+	chain := &models.DelegationChain{Delegations: []*models.DelegationClaim{delegation}}
+	response = VerifyResponse{}
+	require.Equal(t, http.StatusOK, env.post(t, "/v1/verify", verifyRequest(t, TypeDelegationChain, chain), &response))
+	assert.True(t, response.Valid, response.Reason)
+
+	// A delegate cannot pass on more than it was given
+	delegation.MaxDepth = 2
+	delegation.Constraints = map[string]interface{}{models.ConstraintMaxAmount: "1000"}
+	require.NoError(t, signer.NewClaimSigner(env.owner).SignDelegationClaim(delegation))
+	redelegation := &models.DelegationClaim{
+		DelegatorDID: env.agent.DID,
+		DelegateDID:  env.responder.DID,
+		Action:       models.ActionTransfer,
+		Scope:        models.ScopeETH,
+		Constraints:  map[string]interface{}{models.ConstraintMaxAmount: "1000000"},
+		IssuedAt:     time.Now().Unix(),
+		ExpiresAt:    time.Now().Add(time.Hour).Unix(),
+		Nonce:        "delegation-2",
+		MaxDepth:     2,
+	}
+	require.NoError(t, redelegation.SetParent(delegation))
+	require.NoError(t, signer.NewClaimSigner(env.agent).SignDelegationClaim(redelegation))
+	chain = &models.DelegationChain{Delegations: []*models.DelegationClaim{delegation, redelegation}}
+	response = VerifyResponse{}
+	require.Equal(t, http.StatusOK, env.post(t, "/v1/verify", verifyRequest(t, TypeDelegationChain, chain), &response))
+	assert.False(t, response.Valid)
+	assert.Contains(t, response.Reason, models.ChainErrAmountEscalation)
+
+	var errResponse ErrorResponse
+	assert.Equal(t, http.StatusBadRequest, env.post(t, "/v1/verify", verifyRequest(t, "Unknown", env.claim), &errResponse))
+	assert.Contains(t, errResponse.Error, "unsupported credential type")
+}
+
+func TestVerifyExpiredAndRevoked(t *testing.T) {
+	revoked := authz.RevocationLists{{}}
+	env := setup(t, WithRevocationChecker(revoked))
+	id, err := env.claim.CredentialID()
+	require.NoError(t, err)
+
+	later := setup(t, WithClock(func() time.Time { return time.Now().Add(2 * time.Hour) }))
+	var response VerifyResponse
+	require.Equal(t, http.StatusOK, later.post(t, "/v1/verify", verifyRequest(t, signer.TypeAgentClaim, env.claim), &response))
+	assert.False(t, response.Valid)
+	assert.Contains(t, response.Reason, "expired")
+
+	revoked[0].Revocations = []*models.RevocationClaim{{RevokedCredentialID: id, RevokedAgentDID: env.agent.DID, Reason: models.RevocationReasonCompromised}}
+	response = VerifyResponse{}
+	require.Equal(t, http.StatusOK, env.post(t, "/v1/verify", verifyRequest(t, signer.TypeAgentClaim, env.claim), &response))
+	assert.False(t, response.Valid)
+	assert.Contains(t, response.Reason, "revoked")
+}
+
+func TestVerifyStatus(t *testing.T) {
+	list, err := status.NewList("https://example.com/status/1", models.StatusPurposeRevocation, 0)
+	require.NoError(t, err)
+	var env *testEnv
+	fetcher := status.FetcherFunc(func(context.Context, string) (*models.StatusList2021Credential, error) {
+		credential, err := list.Credential(env.owner.DID, time.Now(), time.Hour)
+		if err != nil {
+			return nil, err
+		}
+		return credential, signer.NewClaimSigner(env.owner).SignCredential(credential)
+	})
+	env = setup(t, WithStatusChecker(status.NewVerifier(status.WithFetcher(fetcher))))
+	owner := signer.NewClaimSigner(env.owner)
+
+	verify := func(typ string, credential interface{}) VerifyResponse {
+		var response VerifyResponse
+		require.Equal(t, http.StatusOK, env.post(t, "/v1/verify", verifyRequest(t, typ, credential), &response))
+		return response
+	}
+	// Revoke before the first check, as the verifier caches the list
+	entry := func(revoked bool) *models.CredentialStatus {
+		entry, err := list.Assign()
+		require.NoError(t, err)
+		if revoked {
+			require.NoError(t, list.Revoke(entry))
+		}
+		return entry
+	}
+	newClaim := func(nonce string, revoked bool) *models.AgentClaim {
+		claim := models.NewTransferClaim(env.agent.DID, env.owner.DID, models.ScopeETH, "1000", 0, nonce)
+		claim.CredentialStatus = entry(revoked)
+		require.NoError(t, owner.SignCredential(claim))
+		return claim
+	}
+	newChain := func(nonce string, revoked bool) *models.DelegationChain {
+		delegation := &models.DelegationClaim{
+			DelegatorDID:     env.owner.DID,
+			DelegateDID:      env.agent.DID,
+			Action:           models.ActionTransfer,
+			Scope:            models.ScopeETH,
+			IssuedAt:         time.Now().Unix(),
+			ExpiresAt:        time.Now().Add(time.Hour).Unix(),
+			Nonce:            nonce,
+			MaxDepth:         1,
+			CredentialStatus: entry(revoked),
+		}
+		require.NoError(t, owner.SignDelegationClaim(delegation))
+		return &models.DelegationChain{Delegations: []*models.DelegationClaim{delegation}}
+	}
+	active, revoked := newClaim("claim-active", false), newClaim("claim-revoked", true)
+	activeChain, revokedChain := newChain("delegation-active", false), newChain("delegation-revoked", true)
+
+	response := verify(signer.TypeAgentClaim, active)
+	assert.True(t, response.Valid, response.Reason)
+
+	response = verify(signer.TypeAgentClaim, revoked)
+	assert.False(t, response.Valid)
+	assert.Equal(t, "credential was revoked", response.Reason)
+
+	// The status the owner signed into the claim counts too
+	suspended := newClaim("claim-suspended", false)
+	suspended.Status = models.StatusSuspended
+	require.NoError(t, owner.SignCredential(suspended))
+	response = verify(signer.TypeAgentClaim, suspended)
+	assert.False(t, response.Valid)
+	assert.Equal(t, "claim is suspended", response.Reason)
+
+	// So do the entries of every delegation in a chain
+	response = verify(TypeDelegationChain, activeChain)
+	assert.True(t, response.Valid, response.Reason)
+
+	response = verify(TypeDelegationChain, revokedChain)
+	assert.False(t, response.Valid)
+	assert.Equal(t, "delegation 0 was revoked", response.Reason)
+}
+
+func TestAuthorize(t *testing.T) {
+	env := setup(t)
+	req := models.NewAuthorizationRequest(env.agent.DID, models.ActionTransfer, models.ScopeETH, env.responder.DID, "req-1")
+	req.Amount = "400"
+
+	var response models.AuthorizationResponse
+	require.Equal(t, http.StatusOK, env.post(t, "/v1/authorize", &AuthorizeRequest{Request: req, Authorization: env.claim}, &response))
+	assert.True(t, response.Authorized, response.Reason)
+	assert.Equal(t, "600", response.RemainingBudget)
+	valid, err := signer.NewClaimSigner(nil).VerifyCredential(&response)
+	require.NoError(t, err)
+	assert.True(t, valid)
+
+	// Denials are signed answers too
+	req.Amount = "4000"
+	response = models.AuthorizationResponse{}
+	require.Equal(t, http.StatusOK, env.post(t, "/v1/authorize", &AuthorizeRequest{Request: req, Authorization: env.claim}, &response))
+	assert.False(t, response.Authorized)
+	assert.NotEmpty(t, response.Signature)
+
+	var errResponse ErrorResponse
+	assert.Equal(t, http.StatusBadRequest, env.post(t, "/v1/authorize", &AuthorizeRequest{}, &errResponse))
+	assert.Equal(t, http.StatusServiceUnavailable, setup(t, WithoutAuthorization()).post(t, "/v1/authorize", &AuthorizeRequest{Request: req, Authorization: env.claim}, &errResponse))
+}
+
+func TestRevocationStatus(t *testing.T) {
+	revokedAt := time.Now().Add(-time.Minute).Unix()
+	lists := authz.RevocationLists{{Revocations: []*models.RevocationClaim{
+		{RevokedCredentialID: "0xrevoked", RevokedAgentDID: "did:ackid:0xa", Reason: models.RevocationReasonCompromised, RevokedAt: revokedAt},
+	}}}
+	env := setup(t, WithRevocationChecker(lists))
+
+	tests := []struct {
+		name    string
+		id      string
+		query   models.RevocationQuery
+		revoked bool
+	}{
+		{"revoked", "0xrevoked", models.RevocationQuery{AgentDID: "did:ackid:0xa", QueryID: "q-1"}, true},
+		{"not revoked", "0xother", models.RevocationQuery{AgentDID: "did:ackid:0xa", QueryID: "q-2"}, false},
+		{"before the revocation", "0xrevoked", models.RevocationQuery{AgentDID: "did:ackid:0xa", QueryID: "q-3", AsOfTime: revokedAt - 1}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var status models.RevocationStatus
+			require.Equal(t, http.StatusOK, env.post(t, "/v1/revocations/"+tt.id, &tt.query, &status))
+			assert.Equal(t, tt.revoked, status.IsRevoked)
+			assert.Equal(t, tt.id, status.CredentialID)
+			assert.Equal(t, tt.query.QueryID, status.QueryID)
+			assert.Equal(t, env.responder.DID, status.ResponderDID)
+			valid, err := signer.NewClaimSigner(nil).VerifyCredential(&status)
+			require.NoError(t, err)
+			assert.True(t, valid)
+		})
+	}
+
+	var errResponse ErrorResponse
+	assert.Equal(t, http.StatusBadRequest, env.post(t, "/v1/revocations/0xrevoked", &models.RevocationQuery{CredentialID: "0xother"}, &errResponse))
+	assert.Equal(t, http.StatusServiceUnavailable, setup(t).post(t, "/v1/revocations/0xrevoked", &models.RevocationQuery{}, &errResponse))
+}
+
+func TestResolveDID(t *testing.T) {
+	env := setup(t)
+
+	resp, err := env.server.Client().Get(env.server.URL + "/v1/dids/" + env.agent.DID)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var doc struct {
+		ID string `json:"id"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&doc))
+	assert.Equal(t, env.agent.DID, doc.ID)
+
+	resp, err = env.server.Client().Get(env.server.URL + "/v1/dids/did:unknown:123")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	// did:web DIDs naming the server's own network are not fetched
+	resp, err = env.server.Client().Get(env.server.URL + "/v1/dids/did:web:127.0.0.1%3A8443")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	var errResponse ErrorResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&errResponse))
+	assert.Contains(t, errResponse.Error, "non-public address")
+}
+
+func TestRequestLimits(t *testing.T) {
+	env := setup(t, WithMaxBodySize(128))
+
+	var errResponse ErrorResponse
+	assert.Equal(t, http.StatusRequestEntityTooLarge, env.post(t, "/v1/verify", verifyRequest(t, signer.TypeAgentClaim, env.claim), &errResponse))
+	assert.Contains(t, errResponse.Error, "exceeds 128 bytes")
+
+	resp, err := env.server.Client().Post(env.server.URL+"/v1/verify", "application/json", strings.NewReader("{"))
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	resp, err = env.server.Client().Get(env.server.URL + "/v1/verify")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+}
+
+func TestServeShutdown(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	srv := New(newKey(t), WithShutdownTimeout(time.Second))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- srv.Serve(ctx, l)
+	}()
+
+	resp, err := http.Get("http://" + l.Addr().String() + "/v1/dids/did:unknown:123")
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	cancel()
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("server did not shut down")
+	}
+}
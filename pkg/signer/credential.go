@@ -0,0 +1,282 @@
+package signer
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ak68a/agentid-core/pkg/did"
+	"github.com/ak68a/agentid-core/pkg/models"
+)
+
+// SignerDID returns the DID that is expected to have signed a credential:
+// the owner for agent and ownership claims, the delegator for delegations,
+// the revoker for revocations, the issuer for revocation lists, deltas and status lists
+// and the responder for responses.
+func SignerDID(credential interface{}) (string, error) {
+	switch c := credential.(type) {
+	case *models.AgentClaim:
+		// Only the owner can authorize its agent
+		if c.Issuer != "" && c.Issuer != c.OwnerDID {
+			return "", fmt.Errorf("agent claim issuer %s is not the owner %s", c.Issuer, c.OwnerDID)
+		}
+		return c.OwnerDID, nil
+	case *models.OwnershipClaim:
+		// Ownership can only be asserted by the owner itself
+		if c.Issuer != "" && c.Issuer != c.OwnerDID {
+			return "", fmt.Errorf("ownership claim issuer %s is not the owner %s", c.Issuer, c.OwnerDID)
+		}
+		return c.OwnerDID, nil
+	case *models.DelegationClaim:
+		return c.DelegatorDID, nil
+	case *models.RevocationClaim:
+		return c.RevokerDID, nil
+	case *models.RevocationList:
+		return c.IssuerDID, nil
+	case *models.RevocationDelta:
+		return c.IssuerDID, nil
+	case *models.StatusList2021Credential:
+		return c.Issuer, nil
+	case *models.RevocationStatus:
+		return c.ResponderDID, nil
+	case *models.AuthorizationResponse:
+		return c.ResponderDID, nil
+	default:
+		return "", fmt.Errorf("unsupported credential type %T", credential)
+	}
+}
+
+// SignCredential signs any credential model from pkg/models.
+// Models with a Proof get a CredentialProof; responses, which only carry a
+// Signature field, get an EIP-712 signature under the signer's domain.
+func (cs *ClaimSigner) SignCredential(credential interface{}) error {
+	return cs.SignCredentialContext(context.Background(), credential)
+}
+
+// SignCredentialContext is like SignCredential but passes ctx to the underlying
+// key.Signer, so remote signers can be cancelled or time out
+func (cs *ClaimSigner) SignCredentialContext(ctx context.Context, credential interface{}) error {
+	if cs.signer == nil {
+		return fmt.Errorf("claim signer has no key")
+	}
+
+	signerDID, err := SignerDID(credential)
+	if err != nil {
+		return err
+	}
+	if signerDID != cs.signer.GetDID() {
+		return fmt.Errorf("signer %s cannot sign a credential expected from %s", cs.signer.GetDID(), signerDID)
+	}
+
+	if signature := signatureField(credential); signature != nil {
+		hash, err := HashTypedData(credential, cs.domain)
+		if err != nil {
+			return fmt.Errorf("failed to hash credential: %w", err)
+		}
+		sig, err := cs.signHash(ctx, hash)
+		if err != nil {
+			return err
+		}
+		*signature = hex.EncodeToString(sig)
+		return nil
+	}
+
+	proofPtr := proofField(credential)
+	proof := cs.newProof()
+	hash, err := hashForProof(credential, proof)
+	if err != nil {
+		return fmt.Errorf("failed to hash credential: %w", err)
+	}
+	sig, err := cs.signHash(ctx, hash)
+	if err != nil {
+		return err
+	}
+	proof.ProofValue = hex.EncodeToString(sig)
+	*proofPtr = proof
+
+	return nil
+}
+
+// VerifyCredential verifies the proof or signature on any credential model
+// against the DID expected to have signed it (see SignerDID)
+func (cs *ClaimSigner) VerifyCredential(credential interface{}) (bool, error) {
+	return cs.VerifyCredentialContext(context.Background(), credential)
+}
+
+// VerifyCredentialContext is like VerifyCredential but passes ctx to the DID resolver.
+// The signer's keys come from its DID document: a proof must name one of its
+// verification methods, and a bare signature may come from any assertion method.
+func (cs *ClaimSigner) VerifyCredentialContext(ctx context.Context, credential interface{}) (bool, error) {
+	signerDID, err := SignerDID(credential)
+	if err != nil {
+		return false, err
+	}
+
+	var (
+		hash      []byte
+		signature []byte
+		methods   []*did.VerificationMethod
+	)
+	if sigField := signatureField(credential); sigField != nil {
+		if *sigField == "" {
+			return false, fmt.Errorf("%T has no signature", credential)
+		}
+		doc, err := cs.resolver.Resolve(ctx, signerDID)
+		if err != nil {
+			return false, err
+		}
+		methods, err = doc.VerificationMethods(string(models.AssertionMethod))
+		if err != nil {
+			return false, err
+		}
+
+		hash, err = HashTypedData(credential, cs.domain)
+		if err != nil {
+			return false, fmt.Errorf("failed to hash credential: %w", err)
+		}
+		signature, err = decodeSignature(*sigField)
+		if err != nil {
+			return false, fmt.Errorf("failed to decode signature: %w", err)
+		}
+	} else {
+		proof := *proofField(credential)
+		if proof == nil {
+			return false, fmt.Errorf("%T has no proof", credential)
+		}
+
+		// The proof must name a key controlled by the expected signer
+		if methodDID, _, _ := strings.Cut(proof.VerificationMethod, "#"); methodDID != signerDID {
+			return false, fmt.Errorf("verification method %s does not belong to %s", proof.VerificationMethod, signerDID)
+		}
+		purpose := proof.ProofPurpose
+		if purpose == "" {
+			purpose = string(models.AssertionMethod)
+		}
+		vm, err := did.ResolveVerificationMethod(ctx, cs.resolver, proof.VerificationMethod, purpose)
+		if err != nil {
+			return false, err
+		}
+		methods = []*did.VerificationMethod{vm}
+
+		hash, err = hashForProof(credential, proof)
+		if err != nil {
+			return false, fmt.Errorf("failed to hash credential: %w", err)
+		}
+		signature, err = decodeSignature(proof.ProofValue)
+		if err != nil {
+			return false, fmt.Errorf("failed to decode signature: %w", err)
+		}
+	}
+
+	recovered, err := recoverAddress(hash, signature)
+	if err != nil {
+		return false, err
+	}
+	for _, vm := range methods {
+		if address, err := vm.Address(); err == nil && address == recovered {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// VerifyCredentialOnce verifies a credential like VerifyCredentialContext, then
+// records its nonce under its signer's DID until it expires, so a credential
+// meant for a single use is rejected with replay.ErrReplayed when presented again.
+// It needs a nonce store (see WithNonceStore).
+func (cs *ClaimSigner) VerifyCredentialOnce(ctx context.Context, credential interface{}) (bool, error) {
+	if cs.nonces == nil {
+		return false, fmt.Errorf("no nonce store configured")
+	}
+	nonce, expiresAt, err := credentialNonce(credential)
+	if err != nil {
+		return false, err
+	}
+	if nonce == "" {
+		return false, fmt.Errorf("%T has no nonce", credential)
+	}
+
+	valid, err := cs.VerifyCredentialContext(ctx, credential)
+	if err != nil || !valid {
+		return valid, err
+	}
+
+	// Only verified credentials are recorded, so forgeries cannot use up nonces
+	signerDID, err := SignerDID(credential)
+	if err != nil {
+		return false, err
+	}
+	var expiry time.Time
+	if expiresAt != 0 {
+		expiry = time.Unix(expiresAt, 0)
+	}
+	if err := cs.nonces.Record(ctx, signerDID, nonce, expiry); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// credentialNonce returns the nonce of a credential and when it expires (0 = never)
+func credentialNonce(credential interface{}) (string, int64, error) {
+	switch c := credential.(type) {
+	case *models.AgentClaim:
+		return c.Nonce, c.ExpiresAt, nil
+	case *models.OwnershipClaim:
+		return c.Nonce, c.ExpiresAt, nil
+	case *models.DelegationClaim:
+		return c.Nonce, c.ExpiresAt, nil
+	case *models.RevocationClaim:
+		return c.Nonce, 0, nil
+	default:
+		return "", 0, fmt.Errorf("credential type %T has no nonce", credential)
+	}
+}
+
+// signHash signs a hash and returns it with the 27/28 recovery ID expected by wallets and ecrecover
+func (cs *ClaimSigner) signHash(ctx context.Context, hash []byte) ([]byte, error) {
+	signature, err := cs.signer.SignHash(ctx, hash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign credential: %w", err)
+	}
+	if len(signature) != 65 || signature[64] > 1 {
+		return nil, fmt.Errorf("signer returned a malformed signature")
+	}
+	signature[64] += 27
+	return signature, nil
+}
+
+// proofField returns a pointer to the Proof field of a credential model,
+// or nil if the model carries a bare signature instead
+func proofField(credential interface{}) **models.CredentialProof {
+	switch c := credential.(type) {
+	case *models.AgentClaim:
+		return &c.Proof
+	case *models.OwnershipClaim:
+		return &c.Proof
+	case *models.DelegationClaim:
+		return &c.Proof
+	case *models.RevocationClaim:
+		return &c.Proof
+	case *models.RevocationList:
+		return &c.Proof
+	case *models.RevocationDelta:
+		return &c.Proof
+	case *models.StatusList2021Credential:
+		return &c.Proof
+	}
+	return nil
+}
+
+// signatureField returns a pointer to the Signature field of a response model,
+// or nil if the model carries a CredentialProof instead
+func signatureField(credential interface{}) *string {
+	switch c := credential.(type) {
+	case *models.AuthorizationResponse:
+		return &c.Signature
+	case *models.RevocationStatus:
+		return &c.Signature
+	}
+	return nil
+}
@@ -0,0 +1,278 @@
+package signer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ak68a/agentid-core/pkg/did"
+	"github.com/ak68a/agentid-core/pkg/key"
+	"github.com/ak68a/agentid-core/pkg/models"
+	"github.com/ak68a/agentid-core/pkg/replay"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignAndVerifyCredential(t *testing.T) {
+	ownerKey, agentKey, _ := setupTestKeys(t)
+	ownerSigner := NewClaimSigner(ownerKey)
+	agentSigner := NewClaimSigner(agentKey)
+	now := time.Now().Unix()
+
+	tests := []struct {
+		name       string
+		signer     *ClaimSigner
+		credential interface{}
+		tamper     func(credential interface{})
+	}{
+		{
+			name:       "AgentClaim signed by issuer",
+			signer:     ownerSigner,
+			credential: models.NewTransferClaim(agentKey.DID, ownerKey.DID, models.ScopeETH, "1000", now+3600, "n1"),
+			tamper:     func(c interface{}) { c.(*models.AgentClaim).MaxAmount = "1000000" },
+		},
+		{
+			name:       "OwnershipClaim signed by owner",
+			signer:     ownerSigner,
+			credential: models.NewOwnershipClaim(agentKey.DID, ownerKey.DID, "n2"),
+			tamper:     func(c interface{}) { c.(*models.OwnershipClaim).AgentDID = ownerKey.DID },
+		},
+		{
+			name:   "RevocationClaim signed by revoker",
+			signer: ownerSigner,
+			credential: &models.RevocationClaim{
+				RevokedCredentialID: "n1",
+				RevokedAgentDID:     agentKey.DID,
+				RevokerDID:          ownerKey.DID,
+				Reason:              models.RevocationReasonCompromised,
+				RevokedAt:           now,
+				Nonce:               "n3",
+			},
+			tamper: func(c interface{}) { c.(*models.RevocationClaim).RevokedCredentialID = "other" },
+		},
+		{
+			name:   "RevocationList signed by issuer",
+			signer: ownerSigner,
+			credential: &models.RevocationList{
+				ListID:      "list-1",
+				IssuerDID:   ownerKey.DID,
+				LastUpdated: now,
+				Revocations: []*models.RevocationClaim{{RevokedCredentialID: "n1", RevokerDID: ownerKey.DID, RevokedAt: now}},
+				Type:        models.RevocationListType,
+			},
+			tamper: func(c interface{}) { c.(*models.RevocationList).Revocations[0].RevokedCredentialID = "other" },
+		},
+		{
+			name:   "sequenced RevocationList signed by issuer",
+			signer: ownerSigner,
+			credential: &models.RevocationList{
+				ListID:      "list-1",
+				IssuerDID:   ownerKey.DID,
+				LastUpdated: now,
+				Sequence:    7,
+				Type:        models.RevocationListType,
+			},
+			tamper: func(c interface{}) { c.(*models.RevocationList).Sequence = 6 },
+		},
+		{
+			name:   "RevocationDelta signed by issuer",
+			signer: ownerSigner,
+			credential: &models.RevocationDelta{
+				ListID:      "list-1",
+				IssuerDID:   ownerKey.DID,
+				Sequence:    8,
+				Timestamp:   now,
+				Revocations: []*models.RevocationClaim{{RevokedCredentialID: "n1", RevokerDID: ownerKey.DID, RevokedAt: now}},
+				Type:        models.RevocationDeltaType,
+			},
+			tamper: func(c interface{}) { c.(*models.RevocationDelta).Sequence = 9 },
+		},
+		{
+			name:       "AuthorizationResponse signed by responder",
+			signer:     agentSigner,
+			credential: models.NewAuthorizationResponse(true, "ok", agentKey.DID),
+			tamper:     func(c interface{}) { c.(*models.AuthorizationResponse).Authorized = false },
+		},
+		{
+			name:   "RevocationStatus signed by responder",
+			signer: agentSigner,
+			credential: &models.RevocationStatus{
+				CredentialID: "n1",
+				IsRevoked:    true,
+				ResponderDID: agentKey.DID,
+				Timestamp:    now,
+			},
+			tamper: func(c interface{}) { c.(*models.RevocationStatus).IsRevoked = false },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.NoError(t, tt.signer.SignCredential(tt.credential))
+
+			valid, err := NewClaimSigner(nil).VerifyCredential(tt.credential)
+			require.NoError(t, err)
+			assert.True(t, valid)
+
+			tt.tamper(tt.credential)
+			valid, _ = NewClaimSigner(nil).VerifyCredential(tt.credential)
+			assert.False(t, valid)
+		})
+	}
+}
+
+func TestSignCredentialWrongSigner(t *testing.T) {
+	ownerKey, agentKey, _ := setupTestKeys(t)
+
+	// An agent cannot issue its own authorization on behalf of the owner
+	claim := models.NewAgentClaim(agentKey.DID, ownerKey.DID, models.ActionTransfer, models.ScopeETH, 0, "n")
+	assert.Error(t, NewClaimSigner(agentKey).SignCredential(claim))
+
+	// Nor can it name itself as the issuer
+	selfIssued := models.NewAgentClaim(agentKey.DID, ownerKey.DID, models.ActionTransfer, models.ScopeETH, 0, "n")
+	selfIssued.Issuer = agentKey.DID
+	assert.Error(t, NewClaimSigner(agentKey).SignCredential(selfIssued))
+
+	// Ownership claims must be issued by the owner
+	ownership := models.NewOwnershipClaim(agentKey.DID, ownerKey.DID, "n")
+	ownership.Issuer = agentKey.DID
+	assert.Error(t, NewClaimSigner(agentKey).SignCredential(ownership))
+
+	// Responses are checked against the responder
+	response := models.NewAuthorizationResponse(true, "", ownerKey.DID)
+	assert.Error(t, NewClaimSigner(agentKey).SignCredential(response))
+
+	assert.Error(t, NewClaimSigner(nil).SignCredential(claim))
+	assert.Error(t, NewClaimSigner(ownerKey).SignCredential(&models.AuthorizationRequest{}))
+}
+
+func TestVerifyCredentialRejectsForeignVerificationMethod(t *testing.T) {
+	ownerKey, agentKey, _ := setupTestKeys(t)
+
+	claim := models.NewAgentClaim(agentKey.DID, ownerKey.DID, models.ActionTransfer, models.ScopeETH, 0, "n")
+	require.NoError(t, NewClaimSigner(ownerKey).SignCredential(claim))
+
+	claim.Proof.VerificationMethod = agentKey.DID + "#key-1"
+	_, err := NewClaimSigner(nil).VerifyCredential(claim)
+	assert.Error(t, err)
+}
+
+func TestVerifyCredentialMissingProof(t *testing.T) {
+	ownerKey, agentKey, _ := setupTestKeys(t)
+
+	_, err := NewClaimSigner(nil).VerifyCredential(models.NewOwnershipClaim(agentKey.DID, ownerKey.DID, "n"))
+	assert.Error(t, err)
+
+	_, err = NewClaimSigner(nil).VerifyCredential(models.NewAuthorizationResponse(true, "", ownerKey.DID))
+	assert.Error(t, err)
+}
+
+// didSigner signs with an agent key on behalf of another DID, such as a did:web owner
+type didSigner struct {
+	*key.AgentKey
+	did string
+}
+
+func (s didSigner) GetDID() string { return s.did }
+
+func TestVerifyCredentialResolvesDID(t *testing.T) {
+	ownerKey, agentKey, otherKey := setupTestKeys(t)
+	ownerDID := "did:web:acme-corp.com"
+
+	// The owner's DID document lists the key it signs with
+	signingKey := ownerKey
+	resolver := did.NewResolver()
+	resolver.Register("web", did.ResolverFunc(func(_ context.Context, id string) (*did.Document, error) {
+		return &did.Document{
+			ID: id,
+			VerificationMethod: []did.VerificationMethod{{
+				ID:                  id + "#key-1",
+				Type:                did.EcdsaSecp256k1RecoveryMethod2020,
+				Controller:          id,
+				BlockchainAccountID: "eip155:1:" + signingKey.Address.Hex(),
+			}},
+			AssertionMethod: []did.VerificationRelationship{{Reference: id + "#key-1"}},
+		}, nil
+	}))
+	verifier := NewClaimSigner(nil, WithResolver(resolver))
+	owner := NewClaimSigner(didSigner{AgentKey: ownerKey, did: ownerDID})
+
+	claim := models.NewOwnershipClaim(agentKey.DID, ownerDID, "n")
+	require.NoError(t, owner.SignCredential(claim))
+	response := models.NewAuthorizationResponse(true, "ok", ownerDID)
+	require.NoError(t, owner.SignCredential(response))
+
+	for _, credential := range []interface{}{claim, response} {
+		valid, err := verifier.VerifyCredential(credential)
+		require.NoError(t, err)
+		assert.True(t, valid, "%T", credential)
+	}
+
+	// After the owner rotates its key, old signatures no longer verify
+	signingKey = otherKey
+	for _, credential := range []interface{}{claim, response} {
+		valid, err := verifier.VerifyCredential(credential)
+		require.NoError(t, err)
+		assert.False(t, valid, "%T", credential)
+	}
+
+	// Keys not listed in the document are rejected
+	claim.Proof.VerificationMethod = ownerDID + "#key-2"
+	_, err := verifier.VerifyCredential(claim)
+	assert.Error(t, err)
+}
+
+func TestCredentialStatusIsSigned(t *testing.T) {
+	ownerKey, agentKey, _ := setupTestKeys(t)
+	owner := NewClaimSigner(ownerKey)
+
+	claim := models.NewAgentClaim(agentKey.DID, ownerKey.DID, models.ActionTransfer, models.ScopeETH, 0, "n")
+	unsigned, err := HashTypedData(claim, DefaultDomain)
+	require.NoError(t, err)
+
+	claim.CredentialStatus = &models.CredentialStatus{
+		ID:                   "https://example.com/status/1#94567",
+		Type:                 models.StatusList2021EntryType,
+		StatusPurpose:        models.StatusPurposeRevocation,
+		StatusListIndex:      "94567",
+		StatusListCredential: "https://example.com/status/1",
+	}
+	withStatus, err := HashTypedData(claim, DefaultDomain)
+	require.NoError(t, err)
+	assert.NotEqual(t, unsigned, withStatus)
+
+	require.NoError(t, owner.SignCredential(claim))
+	valid, err := NewClaimSigner(nil).VerifyCredential(claim)
+	require.NoError(t, err)
+	assert.True(t, valid)
+
+	// Pointing the claim at another bit invalidates it
+	claim.CredentialStatus.StatusListIndex = "1"
+	valid, _ = NewClaimSigner(nil).VerifyCredential(claim)
+	assert.False(t, valid)
+}
+
+func TestVerifyCredentialOnce(t *testing.T) {
+	ownerKey, agentKey, _ := setupTestKeys(t)
+	claim := models.NewTransferClaim(agentKey.DID, ownerKey.DID, models.ScopeETH, "100", time.Now().Add(time.Hour).Unix(), "once-1")
+	require.NoError(t, NewClaimSigner(ownerKey).SignCredential(claim))
+
+	_, err := NewClaimSigner(nil).VerifyCredentialOnce(context.Background(), claim)
+	assert.Error(t, err, "a nonce store is required")
+
+	verifier := NewClaimSigner(nil, WithNonceStore(replay.NewMemoryStore()))
+
+	// A forgery does not use up the nonce
+	forged := *claim
+	forged.MaxAmount = "1000000"
+	valid, err := verifier.VerifyCredentialOnce(context.Background(), &forged)
+	require.NoError(t, err)
+	assert.False(t, valid)
+
+	valid, err = verifier.VerifyCredentialOnce(context.Background(), claim)
+	require.NoError(t, err)
+	assert.True(t, valid)
+
+	_, err = verifier.VerifyCredentialOnce(context.Background(), claim)
+	assert.ErrorIs(t, err, replay.ErrReplayed)
+}
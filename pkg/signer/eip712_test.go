@@ -0,0 +1,127 @@
+package signer
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ak68a/agentid-core/pkg/models"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDomainSeparator(t *testing.T) {
+	domain := models.EIP712Domain{
+		Name:              "AgentID",
+		Version:           "1",
+		ChainID:           1,
+		VerifyingContract: "0xCcCCccccCCCCcCCCCCCcCcCccCcCCCcCcccccccC",
+	}
+	typedDomain, domainType := typedDataDomain(domain)
+	typedData := apitypes.TypedData{
+		Types:  apitypes.Types{"EIP712Domain": domainType},
+		Domain: typedDomain,
+	}
+
+	separator, err := typedData.HashStruct("EIP712Domain", typedDomain.Map())
+	require.NoError(t, err)
+
+	// Compute the separator by hand, as a Solidity contract would
+	typeHash := crypto.Keccak256([]byte("EIP712Domain(string name,string version,uint256 chainId,address verifyingContract)"))
+	encoded := append([]byte{}, typeHash...)
+	encoded = append(encoded, crypto.Keccak256([]byte("AgentID"))...)
+	encoded = append(encoded, crypto.Keccak256([]byte("1"))...)
+	encoded = append(encoded, math.U256Bytes(big.NewInt(1))...)
+	encoded = append(encoded, common.LeftPadBytes(common.HexToAddress(domain.VerifyingContract).Bytes(), 32)...)
+
+	assert.Equal(t, crypto.Keccak256(encoded), []byte(separator))
+}
+
+func TestDelegationClaimTypeHash(t *testing.T) {
+	typedData, err := NewTypedData(createTestDelegationClaim("did:ackid:0x1", "did:ackid:0x2", "transfer", "ETH"), DefaultDomain)
+	require.NoError(t, err)
+
+	expected := "DelegationClaim(string delegatorDid,string delegateDid,string action,string scope,string constraints," +
+		"uint64 issuedAt,uint64 expiresAt,string nonce,string parentDelegation,uint32 maxDepth,uint32 currentDepth," +
+		"string[] type,string[] context,string issuer,string subject)"
+	assert.Equal(t, expected, string(typedData.EncodeType(TypeDelegationClaim)))
+}
+
+func TestTypedDataWalletRoundTrip(t *testing.T) {
+	rootKey, delegateKey, _ := setupTestKeys(t)
+	signer := NewClaimSigner(rootKey)
+
+	claim := createTestDelegationClaim(rootKey.DID, delegateKey.DID, "transfer", "ETH")
+	claim.Constraints = map[string]interface{}{"max_amount": "1000", "scope": map[string]interface{}{"allowed_resources": []interface{}{"ETH"}}}
+	require.NoError(t, signer.SignDelegationClaim(claim))
+
+	// Serialize the typed data the way it would be handed to eth_signTypedData_v4
+	typedData, err := NewTypedData(claim, claim.Proof.Domain)
+	require.NoError(t, err)
+	payload, err := json.Marshal(typedData)
+	require.NoError(t, err)
+
+	var walletData apitypes.TypedData
+	require.NoError(t, json.Unmarshal(payload, &walletData))
+	walletHash, _, err := apitypes.TypedDataAndHash(walletData)
+	require.NoError(t, err)
+
+	hash, err := HashTypedData(claim, claim.Proof.Domain)
+	require.NoError(t, err)
+	assert.Equal(t, hash, walletHash)
+
+	// The signature must carry the 27/28 recovery ID used by ecrecover
+	signature, err := hex.DecodeString(claim.Proof.ProofValue)
+	require.NoError(t, err)
+	require.Len(t, signature, 65)
+	assert.Contains(t, []byte{27, 28}, signature[64])
+
+	sig := append([]byte{}, signature...)
+	sig[64] -= 27
+	pubKey, err := crypto.SigToPub(walletHash, sig)
+	require.NoError(t, err)
+	assert.Equal(t, rootKey.Address, crypto.PubkeyToAddress(*pubKey))
+}
+
+func TestVerifyUsesProofDomain(t *testing.T) {
+	rootKey, delegateKey, _ := setupTestKeys(t)
+	domain := models.EIP712Domain{Name: "AgentID", Version: "1", ChainID: 8453}
+	signer := NewClaimSigner(rootKey, WithDomain(domain))
+
+	claim := createTestDelegationClaim(rootKey.DID, delegateKey.DID, "transfer", "ETH")
+	require.NoError(t, signer.SignDelegationClaim(claim))
+	assert.Equal(t, domain, claim.Proof.Domain)
+
+	valid, err := NewClaimSigner(nil).VerifyDelegationClaim(claim, rootKey.DID)
+	require.NoError(t, err)
+	assert.True(t, valid)
+
+	// Replaying the signature under another chain must fail
+	claim.Proof.Domain.ChainID = 1
+	valid, err = NewClaimSigner(nil).VerifyDelegationClaim(claim, rootKey.DID)
+	require.NoError(t, err)
+	assert.False(t, valid)
+}
+
+func TestNewTypedDataAllClaimTypes(t *testing.T) {
+	credentials := []interface{}{
+		models.NewAgentClaim("did:ackid:0x1", "did:web:example.com", models.ActionTransfer, models.ScopeETH, 0, "n1"),
+		models.NewOwnershipClaim("did:ackid:0x1", "did:web:example.com", "n2"),
+		createTestDelegationClaim("did:ackid:0x1", "did:ackid:0x2", "transfer", "ETH"),
+		&models.RevocationClaim{RevokedCredentialID: "abc", RevokerDID: "did:ackid:0x1", Nonce: "n3"},
+	}
+
+	for _, credential := range credentials {
+		hash, err := HashTypedData(credential, DefaultDomain)
+		require.NoError(t, err)
+		assert.Len(t, hash, 32)
+	}
+
+	_, err := HashTypedData(&models.AuthorizationRequest{}, DefaultDomain)
+	assert.Error(t, err)
+}
@@ -0,0 +1,30 @@
+package signer
+
+import (
+	"fmt"
+
+	"github.com/ak68a/agentid-core/pkg/models"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// HashJCS returns the Keccak256 hash of a credential's RFC 8785 canonical JSON.
+// The proof and signature members are removed first, so signed models hash to what was signed.
+func HashJCS(credential interface{}) ([]byte, error) {
+	canonical, err := models.CanonicalForm(credential)
+	if err != nil {
+		return nil, err
+	}
+	return crypto.Keccak256(canonical), nil
+}
+
+// hashForProof hashes a credential using the canonicalization recorded in its proof
+func hashForProof(credential interface{}, proof *models.CredentialProof) ([]byte, error) {
+	switch models.Canonicalization(proof.Canonicalization) {
+	case "", models.CanonicalizationEIP712:
+		return HashTypedData(credential, proof.Domain)
+	case models.CanonicalizationJCS:
+		return HashJCS(credential)
+	default:
+		return nil, fmt.Errorf("unsupported canonicalization %q", proof.Canonicalization)
+	}
+}
@@ -0,0 +1,84 @@
+package signer
+
+import (
+	"testing"
+
+	"github.com/ak68a/agentid-core/pkg/models"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHashJCS(t *testing.T) {
+	parent := "root"
+	claim := &models.DelegationClaim{
+		DelegatorDID:     "did:ackid:0x1",
+		DelegateDID:      "did:ackid:0x2",
+		Action:           "transfer",
+		Scope:            "ETH",
+		Constraints:      map[string]interface{}{"max_amount": "100", "ratio": 1e21},
+		IssuedAt:         1700000000,
+		ExpiresAt:        1700003600,
+		Nonce:            "n",
+		ParentDelegation: &parent,
+		MaxDepth:         2,
+		CurrentDepth:     1,
+		Proof:            &models.CredentialProof{ProofValue: "ignored"},
+	}
+
+	hash, err := HashJCS(claim)
+	require.NoError(t, err)
+
+	// The same bytes any RFC 8785 implementation produces for this claim
+	expected := `{"action":"transfer","constraints":{"max_amount":"100","ratio":1e+21},"current_depth":1,` +
+		`"delegate_did":"did:ackid:0x2","delegator_did":"did:ackid:0x1","expires_at":1700003600,` +
+		`"issued_at":1700000000,"max_depth":2,"nonce":"n","parent_delegation":"root","scope":"ETH"}`
+	assert.Equal(t, crypto.Keccak256([]byte(expected)), hash)
+}
+
+func TestHashJCSAnyModel(t *testing.T) {
+	list := &models.RevocationList{
+		ListID:    "list-1",
+		IssuerDID: "did:ackid:0x1",
+		Revocations: []*models.RevocationClaim{
+			{RevokedCredentialID: "abc", RevokerDID: "did:ackid:0x1", Reason: models.RevocationReasonCompromised},
+		},
+	}
+
+	hash1, err := HashJCS(list)
+	require.NoError(t, err)
+
+	list.Proof = &models.CredentialProof{ProofValue: "deadbeef"}
+	hash2, err := HashJCS(list)
+	require.NoError(t, err)
+	assert.Equal(t, hash1, hash2, "proof must not affect the hash")
+
+	_, err = HashJCS([]string{"not", "an", "object"})
+	assert.Error(t, err)
+}
+
+func TestSignDelegationClaimJCS(t *testing.T) {
+	rootKey, delegateKey, _ := setupTestKeys(t)
+	signer := NewClaimSigner(rootKey, WithCanonicalization(models.CanonicalizationJCS))
+
+	claim := createTestDelegationClaim(rootKey.DID, delegateKey.DID, "transfer", "ETH")
+	claim.Constraints = map[string]interface{}{"max_amount": 0.1}
+	require.NoError(t, signer.SignDelegationClaim(claim))
+	assert.Equal(t, string(models.CanonicalizationJCS), claim.Proof.Canonicalization)
+	assert.Equal(t, models.EIP712Domain{}, claim.Proof.Domain)
+
+	valid, err := NewClaimSigner(nil).VerifyDelegationClaim(claim, rootKey.DID)
+	require.NoError(t, err)
+	assert.True(t, valid)
+
+	// Switching the recorded canonicalization invalidates the signature
+	claim.Proof.Canonicalization = string(models.CanonicalizationEIP712)
+	claim.Proof.Domain = DefaultDomain
+	valid, err = NewClaimSigner(nil).VerifyDelegationClaim(claim, rootKey.DID)
+	require.NoError(t, err)
+	assert.False(t, valid)
+
+	claim.Proof.Canonicalization = "urdna2015"
+	_, err = NewClaimSigner(nil).VerifyDelegationClaim(claim, rootKey.DID)
+	assert.Error(t, err)
+}
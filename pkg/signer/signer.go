@@ -1,128 +1,152 @@
 package signer
 
 import (
+	"context"
 	"encoding/hex"
-	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
+	"github.com/ak68a/agentid-core/pkg/did"
 	"github.com/ak68a/agentid-core/pkg/key"
 	"github.com/ak68a/agentid-core/pkg/models"
+	"github.com/ak68a/agentid-core/pkg/replay"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
 )
 
 // ClaimSigner handles signing and verification of claims
 type ClaimSigner struct {
-	agentKey *key.AgentKey
+	signer           key.Signer
+	domain           models.EIP712Domain
+	canonicalization models.Canonicalization
+	resolver         did.Resolver
+	revocations      models.RevocationChecker
+	nonces           replay.NonceStore
 }
 
-// NewClaimSigner creates a new ClaimSigner with the given agent key
-func NewClaimSigner(agentKey *key.AgentKey) *ClaimSigner {
-	return &ClaimSigner{
-		agentKey: agentKey,
+// Option configures a ClaimSigner
+type Option func(*ClaimSigner)
+
+// WithDomain sets the EIP-712 domain used when signing claims.
+// Responses carry no proof, so it is also the domain their signatures are checked against.
+func WithDomain(domain models.EIP712Domain) Option {
+	return func(cs *ClaimSigner) {
+		cs.domain = domain
 	}
 }
 
-// verifySignatureAgainstAddress verifies a signature against an Ethereum address
-func verifySignatureAgainstAddress(hash []byte, signature []byte, address string) (bool, error) {
-	fmt.Printf("DEBUG: Verifying signature of length %d\n", len(signature))
-	
-	// Ensure signature is 65 bytes (including recovery ID)
-	if len(signature) != 65 {
-		return false, fmt.Errorf("invalid signature length: expected 65 bytes, got %d", len(signature))
+// WithCanonicalization selects how claims are hashed before signing.
+// The choice is recorded in the proof so verifiers can reproduce it.
+func WithCanonicalization(canonicalization models.Canonicalization) Option {
+	return func(cs *ClaimSigner) {
+		cs.canonicalization = canonicalization
 	}
+}
 
-	// Recover the public key from the signature (using full 65-byte signature)
-	pubKey, err := crypto.SigToPub(hash, signature)
-	if err != nil {
-		return false, fmt.Errorf("failed to recover public key: %w", err)
+// WithResolver sets the DID resolver used to find the keys allowed to sign for a DID
+func WithResolver(resolver did.Resolver) Option {
+	return func(cs *ClaimSigner) {
+		cs.resolver = resolver
 	}
-
-	// Get the address from the public key
-	recoveredAddr := crypto.PubkeyToAddress(*pubKey)
-	fmt.Printf("DEBUG: Recovered address: %s, Expected: %s\n", recoveredAddr.Hex(), address)
-
-	// Compare with expected address
-	return recoveredAddr == common.HexToAddress(address), nil
 }
 
-// SignDelegationClaim signs a DelegationClaim and adds the cryptographic proof
-func (cs *ClaimSigner) SignDelegationClaim(claim *models.DelegationClaim) error {
-	// Create canonical hash of the claim (without proof)
-	hash, err := cs.hashDelegationClaim(claim)
-	if err != nil {
-		return fmt.Errorf("failed to hash delegation claim: %w", err)
+// WithRevocationChecker makes delegation chain verification consult checker, so a
+// signed revocation of any delegation also invalidates every delegation below it
+func WithRevocationChecker(checker models.RevocationChecker) Option {
+	return func(cs *ClaimSigner) {
+		cs.revocations = checker
 	}
+}
 
-	// Sign the hash
-	signature, err := cs.agentKey.Sign(hash)
-	if err != nil {
-		return fmt.Errorf("failed to sign delegation claim: %w", err)
+// WithNonceStore sets the store VerifyCredentialOnce records credential nonces in
+func WithNonceStore(store replay.NonceStore) Option {
+	return func(cs *ClaimSigner) {
+		cs.nonces = store
 	}
+}
 
-	fmt.Printf("DEBUG: Original signature length: %d\n", len(signature))
-	fmt.Printf("DEBUG: Original signature (hex): %x\n", signature)
-
-	// Add proof to the claim
-	claim.Proof = &models.CredentialProof{
-		Type:               string(models.EcdsaSecp256k1Signature2019),
-		Created:            time.Now().Format(time.RFC3339),
-		VerificationMethod: fmt.Sprintf("%s#key-1", cs.agentKey.DID),
-		ProofPurpose:       string(models.AssertionMethod),
-		ProofValue:         hex.EncodeToString(signature),
-		Domain: models.EIP712Domain{
-			Name:    "AgentID",
-			Version: "1",
-			ChainID: 1, // Mainnet
-		},
+// NewClaimSigner creates a new ClaimSigner backed by the given key.
+// Any key.Signer works, e.g. an in-memory *key.AgentKey or an HSM-backed signer;
+// a nil signer gives a verification-only ClaimSigner.
+func NewClaimSigner(signer key.Signer, opts ...Option) *ClaimSigner {
+	cs := &ClaimSigner{
+		signer:           signer,
+		domain:           DefaultDomain,
+		canonicalization: models.CanonicalizationEIP712,
+		resolver:         did.NewResolver(),
 	}
-
-	return nil
+	for _, opt := range opts {
+		opt(cs)
+	}
+	return cs
 }
 
-// VerifyDelegationClaim verifies the signature on a DelegationClaim
-func (cs *ClaimSigner) VerifyDelegationClaim(claim *models.DelegationClaim, expectedDelegatorDID string) (bool, error) {
-	if claim.Proof == nil {
-		return false, fmt.Errorf("delegation claim has no proof")
+// recoverAddress recovers the Ethereum address that produced a signature over hash
+func recoverAddress(hash []byte, signature []byte) (common.Address, error) {
+	// Ensure signature is 65 bytes (including recovery ID)
+	if len(signature) != 65 {
+		return common.Address{}, fmt.Errorf("invalid signature length: expected 65 bytes, got %d", len(signature))
 	}
 
-	// Verify the claim is from the expected delegator
-	if claim.DelegatorDID != expectedDelegatorDID {
-		return false, fmt.Errorf("claim delegator DID mismatch: expected %s, got %s", 
-			expectedDelegatorDID, claim.DelegatorDID)
+	// Accept both the raw recovery ID (0/1) and the Ethereum form (27/28)
+	sig := make([]byte, 65)
+	copy(sig, signature)
+	if sig[64] >= 27 {
+		sig[64] -= 27
 	}
 
-	// Extract address from delegator DID
-	address, err := key.ExtractAddressFromDID(claim.DelegatorDID)
+	// Recover the public key from the signature (using full 65-byte signature)
+	pubKey, err := crypto.SigToPub(hash, sig)
 	if err != nil {
-		return false, fmt.Errorf("failed to extract address from delegator DID: %w", err)
+		return common.Address{}, fmt.Errorf("failed to recover public key: %w", err)
 	}
+	return crypto.PubkeyToAddress(*pubKey), nil
+}
 
-	// Create hash of claim (without proof)
-	tempClaim := *claim
-	tempClaim.Proof = nil
-	hash, err := hashDelegationClaimStruct(&tempClaim)
-	if err != nil {
-		return false, fmt.Errorf("failed to hash delegation claim: %w", err)
-	}
+// decodeSignature decodes a hex proof value, with or without 0x prefix
+func decodeSignature(proofValue string) ([]byte, error) {
+	return hex.DecodeString(strings.TrimPrefix(proofValue, "0x"))
+}
 
-	// Decode signature
-	signature, err := hex.DecodeString(claim.Proof.ProofValue)
-	if err != nil {
-		return false, fmt.Errorf("failed to decode signature: %w", err)
+// SignDelegationClaim signs a DelegationClaim and adds the cryptographic proof
+func (cs *ClaimSigner) SignDelegationClaim(claim *models.DelegationClaim) error {
+	if err := cs.SignCredential(claim); err != nil {
+		return fmt.Errorf("failed to sign delegation claim: %w", err)
 	}
+	return nil
+}
 
-	fmt.Printf("DEBUG: Decoded signature length: %d\n", len(signature))
-	fmt.Printf("DEBUG: Decoded signature (hex): %x\n", signature)
-	fmt.Printf("DEBUG: Proof value from claim: %s\n", claim.Proof.ProofValue)
+// newProof creates an unsigned proof for the signer's key and canonicalization
+func (cs *ClaimSigner) newProof() *models.CredentialProof {
+	proof := &models.CredentialProof{
+		Type:               string(models.EcdsaSecp256k1Signature2019),
+		Created:            time.Now().Format(time.RFC3339),
+		VerificationMethod: fmt.Sprintf("%s#key-1", cs.signer.GetDID()),
+		ProofPurpose:       string(models.AssertionMethod),
+		Canonicalization:   string(cs.canonicalization),
+	}
+	// The EIP-712 domain only applies to typed data signatures
+	if cs.canonicalization == models.CanonicalizationEIP712 {
+		proof.Domain = cs.domain
+	}
+	return proof
+}
 
-	// Verify signature matches the expected address
-	return verifySignatureAgainstAddress(hash, signature, address.Hex())
+// VerifyDelegationClaim verifies the signature on a DelegationClaim
+func (cs *ClaimSigner) VerifyDelegationClaim(claim *models.DelegationClaim, expectedDelegatorDID string) (bool, error) {
+	return cs.verifyDelegationClaim(context.Background(), claim, expectedDelegatorDID)
 }
 
-// VerifyDelegationChain verifies all signatures in a delegation chain
+// VerifyDelegationChain verifies all signatures in a delegation chain and,
+// with a revocation checker, that no delegation in it has been revoked
 func (cs *ClaimSigner) VerifyDelegationChain(chain *models.DelegationChain) (bool, error) {
+	return cs.VerifyDelegationChainContext(context.Background(), chain)
+}
+
+// VerifyDelegationChainContext is like VerifyDelegationChain but passes ctx to
+// the DID resolver and the revocation checker
+func (cs *ClaimSigner) VerifyDelegationChainContext(ctx context.Context, chain *models.DelegationChain) (bool, error) {
 	if len(chain.Delegations) == 0 {
 		return false, fmt.Errorf("empty delegation chain")
 	}
@@ -131,7 +155,7 @@ func (cs *ClaimSigner) VerifyDelegationChain(chain *models.DelegationChain) (boo
 	for i, delegation := range chain.Delegations {
 		// For first delegation, verify against root delegator
 		if i == 0 {
-			valid, err := cs.VerifyDelegationClaim(delegation, delegation.DelegatorDID)
+			valid, err := cs.verifyDelegationClaim(ctx, delegation, delegation.DelegatorDID)
 			if err != nil {
 				return false, fmt.Errorf("failed to verify root delegation: %w", err)
 			}
@@ -143,7 +167,7 @@ func (cs *ClaimSigner) VerifyDelegationChain(chain *models.DelegationChain) (boo
 
 		// For subsequent delegations, verify against previous delegate
 		prevDelegation := chain.Delegations[i-1]
-		valid, err := cs.VerifyDelegationClaim(delegation, prevDelegation.DelegateDID)
+		valid, err := cs.verifyDelegationClaim(ctx, delegation, prevDelegation.DelegateDID)
 		if err != nil {
 			return false, fmt.Errorf("failed to verify delegation %d: %w", i, err)
 		}
@@ -152,27 +176,121 @@ func (cs *ClaimSigner) VerifyDelegationChain(chain *models.DelegationChain) (boo
 		}
 	}
 
+	if err := cs.CheckChainRevocations(ctx, chain); err != nil {
+		return false, err
+	}
 	return true, nil
 }
 
-// hashDelegationClaim creates a canonical hash of a DelegationClaim
-func (cs *ClaimSigner) hashDelegationClaim(claim *models.DelegationClaim) ([]byte, error) {
-	// Create a copy without the proof for hashing
-	tempClaim := *claim
-	tempClaim.Proof = nil
-	
-	return hashDelegationClaimStruct(&tempClaim)
+// RevokeDelegationChain creates and signs revocations for the part of a chain
+// issued by the signer: its own delegation and everything delegated below it
+func (cs *ClaimSigner) RevokeDelegationChain(chain *models.DelegationChain, reason string) ([]*models.RevocationClaim, error) {
+	if cs.signer == nil {
+		return nil, fmt.Errorf("claim signer has no key")
+	}
+	revocations, err := chain.RevokeChain(cs.signer.GetDID(), reason)
+	if err != nil {
+		return nil, err
+	}
+	for _, revocation := range revocations {
+		if err := cs.SignCredential(revocation); err != nil {
+			return nil, fmt.Errorf("failed to sign revocation of %s: %w", revocation.RevokedCredentialID, err)
+		}
+	}
+	return revocations, nil
 }
 
-// hashDelegationClaimStruct creates a deterministic hash of a DelegationClaim struct
-func hashDelegationClaimStruct(claim *models.DelegationClaim) ([]byte, error) {
-	// Create deterministic JSON (sorted keys)
-	jsonBytes, err := json.Marshal(claim)
+// CheckChainRevocations looks up every delegation of a chain, root first, with
+// the signer's revocation checker. A revocation of a delegation only counts if
+// it is signed by a delegator at or above it; since a revoked delegation fails
+// the whole chain, revoking a parent cascades to all its descendants. A
+// revocation of an agent as a whole, delegator or delegate, always counts: it
+// is vouched for by the issuer of the list the checker found it in, such as an
+// indexer publishing revocations made on-chain. It does nothing if the signer
+// has no revocation checker.
+func (cs *ClaimSigner) CheckChainRevocations(ctx context.Context, chain *models.DelegationChain) error {
+	if cs.revocations == nil || len(chain.Delegations) == 0 {
+		return nil
+	}
+	rootDID := chain.Delegations[0].DelegatorDID
+	revocation, err := cs.revocations.CheckRevocation(ctx, "", rootDID)
 	if err != nil {
-		return nil, err
+		return fmt.Errorf("revocation status of %s is unavailable: %w", rootDID, err)
 	}
-	
-	// Hash with Keccak256 (Ethereum standard)
-	hash := crypto.Keccak256(jsonBytes)
-	return hash, nil
-} 
\ No newline at end of file
+	if isAgentRevocation(revocation, rootDID) {
+		return &models.ChainError{
+			Code:    models.ChainErrRevoked,
+			Message: fmt.Sprintf("delegator %s was revoked by %s: %s", rootDID, revocation.RevokerDID, revocation.Reason),
+		}
+	}
+
+	for i, delegation := range chain.Delegations {
+		id, err := delegation.CredentialID()
+		if err != nil {
+			return err
+		}
+		// Agent-wide revocations are looked up on their own, so that an ignored
+		// revocation of the credential, which a checker may return first, cannot hide one
+		revocation, err := cs.revocations.CheckRevocation(ctx, "", delegation.DelegateDID)
+		if err != nil {
+			return fmt.Errorf("revocation status of delegation %d is unavailable: %w", i, err)
+		}
+		if !isAgentRevocation(revocation, delegation.DelegateDID) {
+			revocation, err = cs.revocations.CheckRevocation(ctx, id, delegation.DelegateDID)
+			if err != nil {
+				return fmt.Errorf("revocation status of delegation %d is unavailable: %w", i, err)
+			}
+			if revocation == nil || !cs.isAuthorizedRevocation(ctx, chain, i, id, revocation) {
+				continue
+			}
+		}
+		return &models.ChainError{
+			Code:    models.ChainErrRevoked,
+			Message: fmt.Sprintf("delegation %d was revoked by %s: %s", i, revocation.RevokerDID, revocation.Reason),
+		}
+	}
+	return nil
+}
+
+// isAgentRevocation reports whether a revocation revokes agentDID as a whole
+func isAgentRevocation(revocation *models.RevocationClaim, agentDID string) bool {
+	return revocation != nil && revocation.RevokedCredentialID == "" && revocation.RevokedAgentDID == agentDID
+}
+
+// isAuthorizedRevocation reports whether a revocation of the delegation at index
+// is validly signed by a party allowed to revoke it
+func (cs *ClaimSigner) isAuthorizedRevocation(ctx context.Context, chain *models.DelegationChain, index int, credentialID string, revocation *models.RevocationClaim) bool {
+	delegation := chain.Delegations[index]
+	if revocation.RevokedCredentialID != "" && !strings.EqualFold(revocation.RevokedCredentialID, credentialID) {
+		return false
+	}
+	if revocation.RevokedCredentialID == "" && revocation.RevokedAgentDID != delegation.DelegateDID {
+		return false
+	}
+	if !chain.CanRevoke(revocation.RevokerDID, index) {
+		return false
+	}
+	valid, err := cs.VerifyCredentialContext(ctx, revocation)
+	return err == nil && valid
+}
+
+// verifyDelegationClaim is VerifyDelegationClaim with a context
+func (cs *ClaimSigner) verifyDelegationClaim(ctx context.Context, claim *models.DelegationClaim, expectedDelegatorDID string) (bool, error) {
+	if claim.Proof == nil {
+		return false, fmt.Errorf("delegation claim has no proof")
+	}
+
+	// Verify the claim is from the expected delegator
+	if claim.DelegatorDID != expectedDelegatorDID {
+		return false, fmt.Errorf("claim delegator DID mismatch: expected %s, got %s",
+			expectedDelegatorDID, claim.DelegatorDID)
+	}
+
+	return cs.VerifyCredentialContext(ctx, claim)
+}
+
+// hashDelegationClaim creates a canonical hash of a DelegationClaim using the signer's settings.
+// The proof is never part of the hashed form.
+func (cs *ClaimSigner) hashDelegationClaim(claim *models.DelegationClaim) ([]byte, error) {
+	return hashForProof(claim, cs.newProof())
+}
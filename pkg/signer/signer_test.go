@@ -1,6 +1,7 @@
 package signer
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -287,4 +288,82 @@ func TestHashDelegationClaim(t *testing.T) {
 	hash3, err := signer.hashDelegationClaim(claim)
 	require.NoError(t, err, "Failed to hash claim with proof")
 	assert.Equal(t, hash1, hash3, "Hash should not change with proof")
-} 
\ No newline at end of file
+} 
+// revocationMap is a RevocationChecker over revocations keyed by credential ID,
+// or by agent DID for revocations of an agent as a whole
+type revocationMap map[string]*models.RevocationClaim
+
+func (m revocationMap) CheckRevocation(_ context.Context, credentialID, agentDID string) (*models.RevocationClaim, error) {
+	if revocation := m[credentialID]; credentialID != "" && revocation != nil {
+		return revocation, nil
+	}
+	return m[agentDID], nil
+}
+
+func TestRevokeDelegationChain(t *testing.T) {
+	rootKey, intermediateKey, finalKey := setupTestKeys(t)
+
+	root := createTestDelegationClaim(rootKey.DID, intermediateKey.DID, "transfer", "ETH")
+	require.NoError(t, NewClaimSigner(rootKey).SignDelegationClaim(root))
+	leaf := createTestDelegationClaim(intermediateKey.DID, finalKey.DID, "transfer", "ETH")
+	require.NoError(t, leaf.SetParent(root))
+	require.NoError(t, NewClaimSigner(intermediateKey).SignDelegationClaim(leaf))
+	chain := &models.DelegationChain{Delegations: []*models.DelegationClaim{root, leaf}}
+
+	// Each delegator revokes its own subtree
+	byRoot, err := NewClaimSigner(rootKey).RevokeDelegationChain(chain, models.RevocationReasonCompromised)
+	require.NoError(t, err)
+	require.Len(t, byRoot, 2)
+	byIntermediate, err := NewClaimSigner(intermediateKey).RevokeDelegationChain(chain, models.RevocationReasonPolicyChange)
+	require.NoError(t, err)
+	require.Len(t, byIntermediate, 1)
+	leafID, err := leaf.CredentialID()
+	require.NoError(t, err)
+	rootID, err := root.CredentialID()
+	require.NoError(t, err)
+	assert.Equal(t, leafID, byIntermediate[0].RevokedCredentialID)
+	for _, revocation := range append(byRoot, byIntermediate...) {
+		valid, err := NewClaimSigner(nil).VerifyCredential(revocation)
+		require.NoError(t, err)
+		assert.True(t, valid)
+	}
+
+	// The final delegate issued nothing it could revoke
+	_, err = NewClaimSigner(finalKey).RevokeDelegationChain(chain, models.RevocationReasonCompromised)
+	var chainErr *models.ChainError
+	require.ErrorAs(t, err, &chainErr)
+	assert.Equal(t, models.ChainErrNotRevoker, chainErr.Code)
+
+	// A delegate cannot revoke the delegation above it
+	upward := *byIntermediate[0]
+	upward.RevokedCredentialID = rootID
+	require.NoError(t, NewClaimSigner(intermediateKey).SignCredential(&upward))
+
+	unsigned := *byRoot[0]
+	unsigned.Proof = nil
+
+	tests := []struct {
+		name    string
+		revoked revocationMap
+		valid   bool
+	}{
+		{"no revocations", revocationMap{}, true},
+		{"root revocation cascades to the leaf", revocationMap{rootID: byRoot[0]}, false},
+		{"intermediate revokes its subtree", revocationMap{leafID: byIntermediate[0]}, false},
+		{"revocation by a delegate below is ignored", revocationMap{rootID: &upward}, true},
+		{"unsigned revocation is ignored", revocationMap{rootID: &unsigned}, true},
+		{"revoked delegate", revocationMap{finalKey.DID: {RevokedAgentDID: finalKey.DID, RevokerDID: "did:pkh:eip155:1:0x1"}}, false},
+		{"revoked root delegator", revocationMap{rootKey.DID: {RevokedAgentDID: rootKey.DID, RevokerDID: "did:pkh:eip155:1:0x1"}}, false},
+		{"ignored revocation does not mask a revoked delegate", revocationMap{rootID: &upward, intermediateKey.DID: {RevokedAgentDID: intermediateKey.DID, RevokerDID: "did:pkh:eip155:1:0x1"}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			valid, err := NewClaimSigner(nil, WithRevocationChecker(tt.revoked)).VerifyDelegationChain(chain)
+			assert.Equal(t, tt.valid, valid)
+			if !tt.valid {
+				require.ErrorAs(t, err, &chainErr)
+				assert.Equal(t, models.ChainErrRevoked, chainErr.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+// Package status implements W3C StatusList2021 credential status: issuers give
+// each credential a bit in a compressed bitstring and publish it as a signed
+// StatusList2021Credential, and verifiers check a credential with one bit lookup.
+package status
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// MinListSize is the minimum number of entries in a list (16KB of bits). Small
+// lists would let observers tell which credential a status lookup is about.
+const MinListSize = 131072
+
+// maxDecodedSize bounds the decompressed size of an encoded list (128M entries)
+const maxDecodedSize = 16 << 20
+
+// Bitstring is a fixed-size list of status bits. Index 0 is the most significant
+// bit of the first byte, as StatusList2021 specifies.
+type Bitstring struct {
+	bits []byte
+}
+
+// NewBitstring creates a bitstring of at least size bits, all unset
+func NewBitstring(size int) *Bitstring {
+	return &Bitstring{bits: make([]byte, (size+7)/8)}
+}
+
+// Len returns the number of bits in the bitstring
+func (b *Bitstring) Len() int {
+	return len(b.bits) * 8
+}
+
+// Get returns the bit at index
+func (b *Bitstring) Get(index int) (bool, error) {
+	if index < 0 || index >= b.Len() {
+		return false, fmt.Errorf("status index %d is out of range 0-%d", index, b.Len()-1)
+	}
+	return b.bits[index/8]&(0x80>>(index%8)) != 0, nil
+}
+
+// Set sets or clears the bit at index
+func (b *Bitstring) Set(index int, value bool) error {
+	if index < 0 || index >= b.Len() {
+		return fmt.Errorf("status index %d is out of range 0-%d", index, b.Len()-1)
+	}
+	if value {
+		b.bits[index/8] |= 0x80 >> (index % 8)
+	} else {
+		b.bits[index/8] &^= 0x80 >> (index % 8)
+	}
+	return nil
+}
+
+// Encode returns the GZIP-compressed, unpadded base64url form used in encodedList
+func (b *Bitstring) Encode() (string, error) {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err := w.Write(b.bits); err != nil {
+		return "", fmt.Errorf("failed to compress status list: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return "", fmt.Errorf("failed to compress status list: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+// DecodeBitstring decodes an encodedList. Padded and standard base64 are also accepted.
+func DecodeBitstring(encoded string) (*Bitstring, error) {
+	encoded = strings.TrimRight(encoded, "=")
+	compressed, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		if compressed, err = base64.RawStdEncoding.DecodeString(encoded); err != nil {
+			return nil, fmt.Errorf("invalid status list encoding: %w", err)
+		}
+	}
+
+	r, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		return nil, fmt.Errorf("invalid status list compression: %w", err)
+	}
+	defer r.Close()
+	bits, err := io.ReadAll(io.LimitReader(r, maxDecodedSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("invalid status list compression: %w", err)
+	}
+	if len(bits) > maxDecodedSize {
+		return nil, fmt.Errorf("status list is larger than %d bytes", maxDecodedSize)
+	}
+	return &Bitstring{bits: bits}, nil
+}
@@ -0,0 +1,223 @@
+package status
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"math/bits"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ak68a/agentid-core/pkg/models"
+)
+
+// ErrListFull is returned when every index of a list has been assigned
+var ErrListFull = errors.New("status list is full")
+
+// List is an issuer's status list. Indexes are assigned at random so that a
+// credential's position does not reveal when it was issued.
+type List struct {
+	mu       sync.Mutex
+	id       string
+	purpose  string
+	status   *Bitstring
+	assigned *Bitstring
+	count    int
+}
+
+// NewList creates an empty list published at id (the URL of its credential).
+// Sizes below MinListSize are raised to it.
+func NewList(id, purpose string, size int) (*List, error) {
+	if purpose != models.StatusPurposeRevocation && purpose != models.StatusPurposeSuspension {
+		return nil, fmt.Errorf("unsupported status purpose %q", purpose)
+	}
+	if size < MinListSize {
+		size = MinListSize
+	}
+	return &List{
+		id:       id,
+		purpose:  purpose,
+		status:   NewBitstring(size),
+		assigned: NewBitstring(size),
+	}, nil
+}
+
+// ID returns the URL the list's credential is published at
+func (l *List) ID() string {
+	return l.id
+}
+
+// Assign reserves an unused index and returns the status entry to put in a new credential
+func (l *List) Assign() (*models.CredentialStatus, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	size := l.assigned.Len()
+	if l.count >= size {
+		return nil, ErrListFull
+	}
+	start, err := rand.Int(rand.Reader, big.NewInt(int64(size)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to pick a status index: %w", err)
+	}
+
+	// Probe forward from a random start to the next free index
+	for i := 0; i < size; i++ {
+		index := (int(start.Int64()) + i) % size
+		if used, _ := l.assigned.Get(index); !used {
+			l.assigned.Set(index, true)
+			l.count++
+			return l.entry(index), nil
+		}
+	}
+	return nil, ErrListFull
+}
+
+// SetStatus sets (revokes or suspends) or clears the status of an assigned index
+func (l *List) SetStatus(index int, value bool) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	assigned, err := l.assigned.Get(index)
+	if err != nil {
+		return err
+	}
+	if !assigned {
+		return fmt.Errorf("status index %d is not assigned", index)
+	}
+	// Revocation is permanent; only suspensions can be lifted
+	if !value && l.purpose == models.StatusPurposeRevocation {
+		if set, _ := l.status.Get(index); set {
+			return fmt.Errorf("status index %d is revoked and cannot be reinstated", index)
+		}
+	}
+	return l.status.Set(index, value)
+}
+
+// Revoke sets the status of a credential's entry, which must belong to this list
+func (l *List) Revoke(entry *models.CredentialStatus) error {
+	index, err := l.indexOf(entry)
+	if err != nil {
+		return err
+	}
+	return l.SetStatus(index, true)
+}
+
+// Status returns the current status bit of an index
+func (l *List) Status(index int) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.status.Get(index)
+}
+
+// Credential returns the unsigned StatusList2021Credential publishing the current
+// bits, valid from now for ttl (0 = no expiry). Sign it with the issuer's ClaimSigner.
+func (l *List) Credential(issuerDID string, now time.Time, ttl time.Duration) (*models.StatusList2021Credential, error) {
+	l.mu.Lock()
+	encoded, err := l.status.Encode()
+	l.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	credential := &models.StatusList2021Credential{
+		Context:   []string{models.W3CCredentialsContext, models.StatusList2021Context},
+		ID:        l.id,
+		Type:      models.StatusList2021CredentialType,
+		Issuer:    issuerDID,
+		ValidFrom: now.Unix(),
+		CredentialSubject: models.StatusList2021Subject{
+			ID:            l.id + "#list",
+			Type:          models.StatusList2021Type,
+			StatusPurpose: l.purpose,
+			EncodedList:   encoded,
+		},
+	}
+	if ttl > 0 {
+		credential.ValidUntil = now.Add(ttl).Unix()
+	}
+	return credential, nil
+}
+
+// entry builds the status entry for an index
+func (l *List) entry(index int) *models.CredentialStatus {
+	return &models.CredentialStatus{
+		ID:                   fmt.Sprintf("%s#%d", l.id, index),
+		Type:                 models.StatusList2021EntryType,
+		StatusPurpose:        l.purpose,
+		StatusListIndex:      strconv.Itoa(index),
+		StatusListCredential: l.id,
+	}
+}
+
+func (l *List) indexOf(entry *models.CredentialStatus) (int, error) {
+	if entry == nil || entry.StatusListCredential != l.id || entry.StatusPurpose != l.purpose {
+		return 0, fmt.Errorf("credential status does not belong to list %s", l.id)
+	}
+	return ParseIndex(entry)
+}
+
+// ParseIndex returns the bit position of a status entry
+func ParseIndex(entry *models.CredentialStatus) (int, error) {
+	index, err := strconv.Atoi(entry.StatusListIndex)
+	if err != nil || index < 0 {
+		return 0, fmt.Errorf("invalid status list index %q", entry.StatusListIndex)
+	}
+	return index, nil
+}
+
+// listJSON is the persisted form of a List
+type listJSON struct {
+	ID       string `json:"id"`
+	Purpose  string `json:"purpose"`
+	Status   string `json:"status"`
+	Assigned string `json:"assigned"`
+}
+
+// MarshalJSON encodes the list, including which indexes are assigned, for storage
+func (l *List) MarshalJSON() ([]byte, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	status, err := l.status.Encode()
+	if err != nil {
+		return nil, err
+	}
+	assigned, err := l.assigned.Encode()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(listJSON{ID: l.id, Purpose: l.purpose, Status: status, Assigned: assigned})
+}
+
+// UnmarshalJSON restores a list stored with MarshalJSON
+func (l *List) UnmarshalJSON(data []byte) error {
+	var stored listJSON
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return err
+	}
+	status, err := DecodeBitstring(stored.Status)
+	if err != nil {
+		return err
+	}
+	assigned, err := DecodeBitstring(stored.Assigned)
+	if err != nil {
+		return err
+	}
+	if status.Len() != assigned.Len() {
+		return fmt.Errorf("status list %s is corrupt: %d status bits for %d entries", stored.ID, status.Len(), assigned.Len())
+	}
+
+	count := 0
+	for _, b := range assigned.bits {
+		count += bits.OnesCount8(b)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.id, l.purpose, l.status, l.assigned, l.count = stored.ID, stored.Purpose, status, assigned, count
+	return nil
+}
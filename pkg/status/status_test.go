@@ -0,0 +1,170 @@
+package status
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ak68a/agentid-core/pkg/key"
+	"github.com/ak68a/agentid-core/pkg/models"
+	"github.com/ak68a/agentid-core/pkg/signer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBitstring(t *testing.T) {
+	// The empty 16KB list from the StatusList2021 specification
+	bits, err := DecodeBitstring("H4sIAAAAAAAAA-3BMQEAAADCoPVPbQwfoAAAAAAAAAAAAAAAAAAAAIC3AYbSVKsAQAAA")
+	require.NoError(t, err)
+	assert.Equal(t, MinListSize, bits.Len())
+
+	bits = NewBitstring(16)
+	require.NoError(t, bits.Set(0, true))
+	require.NoError(t, bits.Set(9, true))
+	assert.Equal(t, []byte{0x80, 0x40}, bits.bits)
+
+	encoded, err := bits.Encode()
+	require.NoError(t, err)
+	decoded, err := DecodeBitstring(encoded)
+	require.NoError(t, err)
+	for i := 0; i < 16; i++ {
+		set, err := decoded.Get(i)
+		require.NoError(t, err)
+		assert.Equal(t, i == 0 || i == 9, set, i)
+	}
+
+	_, err = decoded.Get(16)
+	assert.Error(t, err)
+	_, err = DecodeBitstring("not gzip")
+	assert.Error(t, err)
+}
+
+func TestListAssign(t *testing.T) {
+	list, err := NewList("https://example.com/status/1", models.StatusPurposeRevocation, 0)
+	require.NoError(t, err)
+
+	seen := make(map[string]bool)
+	for i := 0; i < 1000; i++ {
+		entry, err := list.Assign()
+		require.NoError(t, err)
+		assert.False(t, seen[entry.StatusListIndex], "index %s assigned twice", entry.StatusListIndex)
+		seen[entry.StatusListIndex] = true
+		assert.Equal(t, entry.StatusListCredential+"#"+entry.StatusListIndex, entry.ID)
+	}
+
+	entry, err := list.Assign()
+	require.NoError(t, err)
+	require.NoError(t, list.Revoke(entry))
+	index, err := ParseIndex(entry)
+	require.NoError(t, err)
+	revoked, err := list.Status(index)
+	require.NoError(t, err)
+	assert.True(t, revoked)
+
+	// Revocations are permanent and only assigned indexes can be set
+	assert.Error(t, list.SetStatus(index, false))
+	assert.Error(t, list.SetStatus((index+1)%MinListSize, true))
+
+	// The list survives storage, including which indexes are taken
+	data, err := json.Marshal(list)
+	require.NoError(t, err)
+	var restored List
+	require.NoError(t, json.Unmarshal(data, &restored))
+	assert.Equal(t, list.count, restored.count)
+	revoked, err = restored.Status(index)
+	require.NoError(t, err)
+	assert.True(t, revoked)
+}
+
+func TestListSuspension(t *testing.T) {
+	list, err := NewList("https://example.com/status/2", models.StatusPurposeSuspension, 0)
+	require.NoError(t, err)
+	entry, err := list.Assign()
+	require.NoError(t, err)
+	index, _ := ParseIndex(entry)
+
+	require.NoError(t, list.SetStatus(index, true))
+	require.NoError(t, list.SetStatus(index, false))
+	suspended, _ := list.Status(index)
+	assert.False(t, suspended)
+
+	_, err = NewList("https://example.com/status/3", "refresh", 0)
+	assert.Error(t, err)
+}
+
+func TestVerifier(t *testing.T) {
+	issuerKey, err := key.GenerateAgentKey()
+	require.NoError(t, err)
+	otherKey, err := key.GenerateAgentKey()
+	require.NoError(t, err)
+
+	var (
+		published atomic.Value
+		fetches   atomic.Int32
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		json.NewEncoder(w).Encode(published.Load())
+	}))
+	defer server.Close()
+
+	listURL := server.URL + "/status/1"
+	list, err := NewList(listURL, models.StatusPurposeRevocation, 0)
+	require.NoError(t, err)
+	publish := func(k *key.AgentKey) {
+		credential, err := list.Credential(k.DID, time.Now(), time.Hour)
+		require.NoError(t, err)
+		require.NoError(t, signer.NewClaimSigner(k).SignCredential(credential))
+		published.Store(credential)
+	}
+
+	active, err := list.Assign()
+	require.NoError(t, err)
+	revoked, err := list.Assign()
+	require.NoError(t, err)
+	require.NoError(t, list.Revoke(revoked))
+	publish(issuerKey)
+
+	now := time.Now()
+	verifier := NewVerifier(WithClock(func() time.Time { return now }))
+	ctx := context.Background()
+
+	set, err := verifier.Status(ctx, issuerKey.DID, revoked)
+	require.NoError(t, err)
+	assert.True(t, set)
+	set, err = verifier.Status(ctx, issuerKey.DID, active)
+	require.NoError(t, err)
+	assert.False(t, set)
+	assert.Equal(t, int32(1), fetches.Load(), "the verified list should be cached")
+
+	// The list must come from the credential's issuer
+	_, err = verifier.Status(ctx, otherKey.DID, active)
+	assert.ErrorContains(t, err, "not the credential issuer")
+
+	// Once the cache expires, a list signed by someone else is rejected
+	now = now.Add(10 * time.Minute)
+	publish(otherKey)
+	_, err = verifier.Status(ctx, issuerKey.DID, active)
+	assert.Error(t, err)
+
+	// A tampered list does not verify
+	credential, err := list.Credential(issuerKey.DID, time.Now(), time.Hour)
+	require.NoError(t, err)
+	require.NoError(t, signer.NewClaimSigner(issuerKey).SignCredential(credential))
+	clean := NewBitstring(MinListSize)
+	credential.CredentialSubject.EncodedList, err = clean.Encode()
+	require.NoError(t, err)
+	published.Store(credential)
+	_, err = NewVerifier().Status(ctx, issuerKey.DID, revoked)
+	assert.ErrorContains(t, err, "invalid signature")
+
+	// Lists are not used past their validity
+	publish(issuerKey)
+	late := NewVerifier(WithClock(func() time.Time { return time.Now().Add(2 * time.Hour) }))
+	_, err = late.Status(ctx, issuerKey.DID, active)
+	assert.ErrorContains(t, err, "expired")
+}
@@ -0,0 +1,206 @@
+package status
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/ak68a/agentid-core/pkg/did"
+	"github.com/ak68a/agentid-core/pkg/models"
+	"github.com/ak68a/agentid-core/pkg/signer"
+)
+
+// maxCredentialSize bounds the size of a fetched status list credential
+const maxCredentialSize = 4 << 20
+
+// Fetcher retrieves a published status list credential
+type Fetcher interface {
+	FetchStatusList(ctx context.Context, url string) (*models.StatusList2021Credential, error)
+}
+
+// FetcherFunc adapts a function to the Fetcher interface
+type FetcherFunc func(ctx context.Context, url string) (*models.StatusList2021Credential, error)
+
+// FetchStatusList calls f
+func (f FetcherFunc) FetchStatusList(ctx context.Context, url string) (*models.StatusList2021Credential, error) {
+	return f(ctx, url)
+}
+
+// HTTPFetcher fetches status list credentials over HTTP(S)
+type HTTPFetcher struct {
+	Client *http.Client // defaults to a client with a 10s timeout
+}
+
+// FetchStatusList downloads and decodes the credential at url
+func (h *HTTPFetcher) FetchStatusList(ctx context.Context, url string) (*models.StatusList2021Credential, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/vc+json, application/json")
+
+	client := h.Client
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch %s: %s", url, resp.Status)
+	}
+
+	var credential models.StatusList2021Credential
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxCredentialSize)).Decode(&credential); err != nil {
+		return nil, fmt.Errorf("invalid status list at %s: %w", url, err)
+	}
+	return &credential, nil
+}
+
+// Verifier checks credential status entries against published status lists.
+// Verified lists are cached, so most checks are a single bit lookup.
+type Verifier struct {
+	fetcher  Fetcher
+	verifier *signer.ClaimSigner
+	ttl      time.Duration
+	now      func() time.Time
+
+	mu    sync.Mutex
+	cache map[string]*cachedList
+}
+
+type cachedList struct {
+	issuer    string
+	purpose   string
+	bits      *Bitstring
+	expiresAt time.Time
+}
+
+// Option configures a Verifier
+type Option func(*Verifier)
+
+// WithFetcher sets how status lists are retrieved, HTTP by default
+func WithFetcher(fetcher Fetcher) Option {
+	return func(v *Verifier) {
+		v.fetcher = fetcher
+	}
+}
+
+// WithResolver sets the DID resolver used to verify status list signatures
+func WithResolver(resolver did.Resolver) Option {
+	return func(v *Verifier) {
+		v.verifier = signer.NewClaimSigner(nil, signer.WithResolver(resolver))
+	}
+}
+
+// WithCacheTTL sets how long a verified list is reused, 5 minutes by default.
+// A list is never used past its validUntil.
+func WithCacheTTL(ttl time.Duration) Option {
+	return func(v *Verifier) {
+		v.ttl = ttl
+	}
+}
+
+// WithClock sets the time source used for cache and validity checks
+func WithClock(now func() time.Time) Option {
+	return func(v *Verifier) {
+		v.now = now
+	}
+}
+
+// NewVerifier creates a status Verifier
+func NewVerifier(opts ...Option) *Verifier {
+	v := &Verifier{
+		fetcher:  &HTTPFetcher{},
+		verifier: signer.NewClaimSigner(nil),
+		ttl:      5 * time.Minute,
+		now:      time.Now,
+		cache:    make(map[string]*cachedList),
+	}
+	for _, opt := range opts {
+		opt(v)
+	}
+	return v
+}
+
+// Status reports whether the bit of a credential's status entry is set, i.e. the
+// credential is revoked or suspended. issuerDID is the issuer of the credential,
+// which must also have issued the status list.
+func (v *Verifier) Status(ctx context.Context, issuerDID string, entry *models.CredentialStatus) (bool, error) {
+	if entry.Type != models.StatusList2021EntryType {
+		return false, fmt.Errorf("unsupported credential status type %q", entry.Type)
+	}
+	index, err := ParseIndex(entry)
+	if err != nil {
+		return false, err
+	}
+
+	list, err := v.list(ctx, entry.StatusListCredential)
+	if err != nil {
+		return false, err
+	}
+	if list.issuer != issuerDID {
+		return false, fmt.Errorf("status list %s is issued by %s, not the credential issuer %s", entry.StatusListCredential, list.issuer, issuerDID)
+	}
+	if list.purpose != entry.StatusPurpose {
+		return false, fmt.Errorf("status list %s is for %s, not %s", entry.StatusListCredential, list.purpose, entry.StatusPurpose)
+	}
+	return list.bits.Get(index)
+}
+
+// list returns the verified, decoded status list at url, from the cache if fresh
+func (v *Verifier) list(ctx context.Context, url string) (*cachedList, error) {
+	now := v.now()
+
+	v.mu.Lock()
+	cached, ok := v.cache[url]
+	v.mu.Unlock()
+	if ok && now.Before(cached.expiresAt) {
+		return cached, nil
+	}
+
+	credential, err := v.fetcher.FetchStatusList(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	if credential.ID != url {
+		return nil, fmt.Errorf("status list fetched from %s has ID %s", url, credential.ID)
+	}
+	if credential.ValidUntil != 0 && now.Unix() > credential.ValidUntil {
+		return nil, fmt.Errorf("status list %s expired at %d", url, credential.ValidUntil)
+	}
+	valid, err := v.verifier.VerifyCredentialContext(ctx, credential)
+	if err != nil {
+		return nil, fmt.Errorf("status list %s could not be verified: %w", url, err)
+	}
+	if !valid {
+		return nil, fmt.Errorf("status list %s has an invalid signature", url)
+	}
+	bits, err := DecodeBitstring(credential.CredentialSubject.EncodedList)
+	if err != nil {
+		return nil, err
+	}
+
+	expiresAt := now.Add(v.ttl)
+	if credential.ValidUntil != 0 && time.Unix(credential.ValidUntil, 0).Before(expiresAt) {
+		expiresAt = time.Unix(credential.ValidUntil, 0)
+	}
+	list := &cachedList{
+		issuer:    credential.Issuer,
+		purpose:   credential.CredentialSubject.StatusPurpose,
+		bits:      bits,
+		expiresAt: expiresAt,
+	}
+
+	v.mu.Lock()
+	v.cache[url] = list
+	v.mu.Unlock()
+	return list, nil
+}
@@ -0,0 +1,155 @@
+package store
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ak68a/agentid-core/pkg/key"
+	"github.com/ak68a/agentid-core/pkg/models"
+	"github.com/ak68a/agentid-core/pkg/signer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// chainCode returns the ChainError code of err, or "" if it is not a ChainError
+func chainCode(err error) string {
+	var chainErr *models.ChainError
+	if errors.As(err, &chainErr) {
+		return chainErr.Code
+	}
+	return ""
+}
+
+func TestGetChainMultiHop(t *testing.T) {
+	store := NewMemoryStore()
+	var keys []*key.AgentKey
+	for i := 0; i < 4; i++ {
+		k, err := key.GenerateAgentKey()
+		require.NoError(t, err)
+		keys = append(keys, k)
+	}
+
+	// owner -> agent1 -> agent2 -> agent3, each signed and stored
+	root := newDelegation(keys[0].DID, keys[1].DID, "root", 0, 3, nil)
+	require.NoError(t, signer.NewClaimSigner(keys[0]).SignDelegationClaim(root))
+	require.NoError(t, store.PutDelegation(root))
+
+	middle := newDelegation(keys[1].DID, keys[2].DID, "middle", 0, 3, nil)
+	require.NoError(t, middle.SetParent(root))
+	require.NoError(t, signer.NewClaimSigner(keys[1]).SignDelegationClaim(middle))
+	require.NoError(t, store.PutDelegation(middle))
+
+	leaf := newDelegation(keys[2].DID, keys[3].DID, "leaf", 0, 2, nil)
+	require.NoError(t, leaf.SetParent(middle))
+	require.NoError(t, signer.NewClaimSigner(keys[2]).SignDelegationClaim(leaf))
+
+	chain, err := leaf.ValidateInChain(store)
+	require.NoError(t, err)
+	require.Len(t, chain.Delegations, 3)
+	assert.True(t, chain.Valid)
+	assert.Equal(t, root, chain.GetRootDelegation())
+	assert.Equal(t, 2, leaf.CurrentDepth)
+	assert.Equal(t, leaf, chain.GetLeafDelegation())
+
+	// The resolved chain verifies end to end
+	valid, err := signer.NewClaimSigner(nil).VerifyDelegationChain(chain)
+	require.NoError(t, err)
+	assert.True(t, valid)
+
+	// Without a store the parent cannot be found
+	_, err = leaf.GetChain(nil)
+	assert.Equal(t, "PARENT_NOT_FOUND", chainCode(err))
+}
+
+// swappedStore answers every lookup with the same delegation
+type swappedStore struct {
+	claim *models.DelegationClaim
+}
+
+func (s swappedStore) GetDelegation(string) (*models.DelegationClaim, error) {
+	return s.claim, nil
+}
+
+func (s swappedStore) PutDelegation(*models.DelegationClaim) error {
+	return nil
+}
+
+func TestGetChainErrors(t *testing.T) {
+	a, b, c := "did:ackid:0xa", "did:ackid:0xb", "did:ackid:0xc"
+	ref := func(s string) *string { return &s }
+	// child references parent by its credential ID
+	child := func(parent, claim *models.DelegationClaim) *models.DelegationClaim {
+		id, err := parent.CredentialID()
+		require.NoError(t, err)
+		claim.ParentDelegation = &id
+		return claim
+	}
+	parent := newDelegation(a, b, "p", 0, 3, nil)
+	noSubDelegation := newDelegation(a, b, "p", 0, 0, nil)
+	oneLevel := newDelegation(a, b, "p", 0, 1, nil)
+
+	tests := []struct {
+		name  string
+		store models.CredentialStore
+		claim *models.DelegationClaim
+		code  string
+	}{
+		{
+			name:  "missing parent",
+			claim: child(newDelegation(a, b, "unknown", 0, 3, nil), newDelegation(b, c, "child", 1, 2, nil)),
+			code:  "PARENT_NOT_FOUND",
+		},
+		{
+			name:  "parent referenced by nonce",
+			claim: newDelegation(b, c, "child", 1, 2, ref("p")),
+			code:  "PARENT_NOT_FOUND",
+		},
+		{
+			name:  "swapped parent",
+			store: swappedStore{newDelegation(a, b, "p", 0, 5, nil)},
+			claim: child(parent, newDelegation(b, c, "child", 1, 2, nil)),
+			code:  "PARENT_MISMATCH",
+		},
+		{
+			name:  "parent forbids sub-delegation",
+			claim: child(noSubDelegation, newDelegation(b, c, "child", 1, 0, nil)),
+			code:  "DEPTH_EXCEEDED",
+		},
+		{
+			name:  "child raises max depth",
+			claim: child(oneLevel, newDelegation(b, c, "child", 1, 5, nil)),
+			code:  "DEPTH_EXCEEDED",
+		},
+		{
+			name:  "depth does not follow parent",
+			claim: child(parent, newDelegation(b, c, "child", 0, 3, nil)),
+			code:  "INVALID_DEPTH",
+		},
+		{
+			name:  "root claims a non-zero depth",
+			claim: newDelegation(a, b, "root", 1, 3, nil),
+			code:  "INVALID_DEPTH",
+		},
+		{
+			name:  "broken chain",
+			claim: child(parent, newDelegation(c, a, "child", 1, 3, nil)),
+			code:  "INVALID_CHAIN",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := tt.store
+			if store == nil {
+				// Every test parent except "unknown" is stored
+				memory := NewMemoryStore()
+				for _, p := range []*models.DelegationClaim{parent, noSubDelegation, oneLevel} {
+					require.NoError(t, memory.PutDelegation(p))
+				}
+				store = memory
+			}
+			_, err := tt.claim.GetChain(store)
+			assert.Equal(t, tt.code, chainCode(err), "error: %v", err)
+		})
+	}
+}
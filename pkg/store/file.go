@@ -0,0 +1,88 @@
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ak68a/agentid-core/pkg/models"
+)
+
+// FileStore is a CredentialStore that keeps one JSON file per delegation in a
+// directory, named by its credential ID. The directory is indexed in memory when opened.
+type FileStore struct {
+	dir   string
+	index *MemoryStore
+}
+
+var _ models.CredentialStore = (*FileStore)(nil)
+
+// NewFileStore opens (creating if needed) a file store in dir and loads the delegations in it
+func NewFileStore(dir string) (*FileStore, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create store directory: %w", err)
+	}
+
+	paths, err := filepath.Glob(filepath.Join(dir, "0x*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list store directory: %w", err)
+	}
+
+	fs := &FileStore{dir: dir, index: NewMemoryStore()}
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		var claim models.DelegationClaim
+		if err := json.Unmarshal(data, &claim); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+		if err := fs.index.PutDelegation(&claim); err != nil {
+			return nil, fmt.Errorf("failed to index %s: %w", path, err)
+		}
+	}
+	return fs, nil
+}
+
+// GetDelegation returns the delegation with the given credential ID
+func (fs *FileStore) GetDelegation(ref string) (*models.DelegationClaim, error) {
+	return fs.index.GetDelegation(ref)
+}
+
+// PutDelegation writes a delegation to disk and indexes it
+func (fs *FileStore) PutDelegation(claim *models.DelegationClaim) error {
+	id, err := claim.CredentialID()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(claim, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal delegation: %w", err)
+	}
+
+	fs.index.mu.Lock()
+	defer fs.index.mu.Unlock()
+
+	// Write to a temporary file first so a crash never leaves a partial credential
+	path := filepath.Join(fs.dir, id+".json")
+	tmp, err := os.CreateTemp(fs.dir, ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write delegation: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write delegation: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write delegation: %w", err)
+	}
+
+	fs.index.put(claim, id)
+	return nil
+}
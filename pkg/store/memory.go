@@ -0,0 +1,53 @@
+package store
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/ak68a/agentid-core/pkg/models"
+)
+
+// MemoryStore is an in-memory CredentialStore, safe for concurrent use
+type MemoryStore struct {
+	mu   sync.RWMutex
+	byID map[string]*models.DelegationClaim
+}
+
+var _ models.CredentialStore = (*MemoryStore)(nil)
+
+// NewMemoryStore creates an empty in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		byID: make(map[string]*models.DelegationClaim),
+	}
+}
+
+// GetDelegation returns the delegation with the given credential ID
+func (s *MemoryStore) GetDelegation(ref string) (*models.DelegationClaim, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if claim, ok := s.byID[strings.ToLower(ref)]; ok {
+		return claim, nil
+	}
+	return nil, fmt.Errorf("%w: %s", models.ErrCredentialNotFound, ref)
+}
+
+// PutDelegation stores a delegation under its credential ID.
+// IDs are content-addressed, so storing the same delegation twice is a no-op.
+func (s *MemoryStore) PutDelegation(claim *models.DelegationClaim) error {
+	id, err := claim.CredentialID()
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.put(claim, id)
+	return nil
+}
+
+func (s *MemoryStore) put(claim *models.DelegationClaim, id string) {
+	s.byID[id] = claim
+}
@@ -0,0 +1,3 @@
+// Package store provides models.CredentialStore implementations for resolving
+// delegation chains: an in-memory store and a file-backed store.
+package store
@@ -0,0 +1,96 @@
+package store
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ak68a/agentid-core/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newDelegation creates an unsigned delegation for store tests
+func newDelegation(delegator, delegate, nonce string, depth, maxDepth int, parent *string) *models.DelegationClaim {
+	return &models.DelegationClaim{
+		DelegatorDID:     delegator,
+		DelegateDID:      delegate,
+		Action:           models.ActionTransfer,
+		Scope:            models.ScopeETH,
+		Constraints:      map[string]interface{}{},
+		IssuedAt:         time.Now().Unix(),
+		ExpiresAt:        time.Now().Add(time.Hour).Unix(),
+		Nonce:            nonce,
+		ParentDelegation: parent,
+		CurrentDepth:     depth,
+		MaxDepth:         maxDepth,
+	}
+}
+
+func TestStores(t *testing.T) {
+	fileStore, err := NewFileStore(filepath.Join(t.TempDir(), "delegations"))
+	require.NoError(t, err)
+
+	stores := map[string]models.CredentialStore{
+		"memory": NewMemoryStore(),
+		"file":   fileStore,
+	}
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			claim := newDelegation("did:ackid:0x1", "did:ackid:0x2", "d1", 0, 1, nil)
+			id, err := claim.CredentialID()
+			require.NoError(t, err)
+
+			require.NoError(t, store.PutDelegation(claim))
+			require.NoError(t, store.PutDelegation(claim), "storing the same delegation again is allowed")
+
+			for _, ref := range []string{id, "0x" + strings.ToUpper(id[2:])} {
+				got, err := store.GetDelegation(ref)
+				require.NoError(t, err, ref)
+				assert.Equal(t, claim.DelegateDID, got.DelegateDID)
+			}
+
+			_, err = store.GetDelegation("d1")
+			assert.True(t, errors.Is(err, models.ErrCredentialNotFound), "nonces are not references")
+
+			// Another issuer reusing the nonce gets a different ID
+			other := newDelegation("did:ackid:0x3", "did:ackid:0x2", "d1", 0, 1, nil)
+			require.NoError(t, store.PutDelegation(other))
+			otherID, err := other.CredentialID()
+			require.NoError(t, err)
+			assert.NotEqual(t, id, otherID)
+			got, err := store.GetDelegation(otherID)
+			require.NoError(t, err)
+			assert.Equal(t, other.DelegatorDID, got.DelegatorDID)
+		})
+	}
+}
+
+func TestFileStoreReopen(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewFileStore(dir)
+	require.NoError(t, err)
+
+	claim := newDelegation("did:ackid:0x1", "did:ackid:0x2", "d1", 0, 1, nil)
+	require.NoError(t, store.PutDelegation(claim))
+	id, err := claim.CredentialID()
+	require.NoError(t, err)
+
+	info, err := os.Stat(filepath.Join(dir, id+".json"))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	reopened, err := NewFileStore(dir)
+	require.NoError(t, err)
+	got, err := reopened.GetDelegation(id)
+	require.NoError(t, err)
+	assert.Equal(t, claim, got)
+
+	// A corrupted file is reported rather than silently skipped
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "0xbad.json"), []byte("{"), 0600))
+	_, err = NewFileStore(dir)
+	assert.Error(t, err)
+}
@@ -0,0 +1,121 @@
+package trust
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ak68a/agentid-core/pkg/authz"
+	"github.com/ak68a/agentid-core/pkg/capability"
+	"github.com/ak68a/agentid-core/pkg/models"
+)
+
+// grant is what an agent's verified credentials grant it
+type grant struct {
+	issued     map[string][]capability.Capability // Capabilities by the DID the credentials are rooted at
+	validUntil int64                              // Earliest expiry across the credentials, 0 if none expire
+}
+
+func (g *grant) add(issuer string, capabilities ...capability.Capability) {
+	g.issued[issuer] = append(g.issued[issuer], capabilities...)
+}
+
+func (g *grant) expires(expiresAt int64) {
+	if g.validUntil == 0 || (expiresAt != 0 && expiresAt < g.validUntil) {
+		g.validUntil = expiresAt
+	}
+}
+
+// verifyCredentials verifies the credentials an agent presents and returns
+// what they grant it. Any credential that fails verification fails them all.
+func (v *TrustVerifier) verifyCredentials(ctx context.Context, agentDID string, creds *authz.Credentials, now int64) (*grant, error) {
+	g := &grant{issued: make(map[string][]capability.Capability)}
+	if creds == nil {
+		return g, nil
+	}
+
+	if claim := creds.Authorization; claim != nil {
+		if claim.AgentDID != agentDID {
+			return nil, fmt.Errorf("authorization is for %s, not %s", claim.AgentDID, agentDID)
+		}
+		if claim.Status == models.StatusRevoked || claim.Status == models.StatusSuspended {
+			return nil, fmt.Errorf("authorization is %s", claim.Status)
+		}
+		if claim.ExpiresAt != 0 && now > claim.ExpiresAt {
+			return nil, fmt.Errorf("authorization expired at %d", claim.ExpiresAt)
+		}
+		if err := v.verify(ctx, claim, claim.AgentDID, "authorization"); err != nil {
+			return nil, err
+		}
+		issuer := claim.Issuer
+		if issuer == "" {
+			issuer = claim.OwnerDID
+		}
+		g.add(issuer, capability.Capability{Action: claim.Action, Scope: claim.Scope})
+		g.expires(claim.ExpiresAt)
+	}
+
+	if chain := creds.Delegations; chain != nil && len(chain.Delegations) > 0 {
+		leaf := chain.GetLeafDelegation()
+		if leaf.DelegateDID != agentDID {
+			return nil, fmt.Errorf("delegation chain ends at %s, not %s", leaf.DelegateDID, agentDID)
+		}
+		// Checks the signatures and, with a revocation checker, revocations
+		if _, err := v.claims.VerifyDelegationChainContext(ctx, chain); err != nil {
+			return nil, err
+		}
+		if !chain.ValidateChainAt(time.Unix(now, 0)) {
+			return nil, fmt.Errorf("invalid delegation chain: %s", chain.Reason)
+		}
+		root := chain.GetRootDelegation().DelegatorDID
+		if granted, err := capability.FromClaim(leaf); err == nil {
+			g.add(root, granted)
+		} else {
+			g.add(root)
+		}
+		for _, delegation := range chain.Delegations {
+			g.expires(delegation.ExpiresAt)
+		}
+	}
+
+	if ownership := creds.Ownership; ownership != nil {
+		if ownership.AgentDID != agentDID {
+			return nil, fmt.Errorf("ownership is of %s, not %s", ownership.AgentDID, agentDID)
+		}
+		if ownership.ExpiresAt != 0 && now > ownership.ExpiresAt {
+			return nil, fmt.Errorf("ownership expired at %d", ownership.ExpiresAt)
+		}
+		if err := v.verify(ctx, ownership, ownership.AgentDID, "ownership"); err != nil {
+			return nil, err
+		}
+		g.add(ownership.OwnerDID)
+		g.expires(ownership.ExpiresAt)
+	}
+	return g, nil
+}
+
+// verify checks the signature of a credential and whether it has been revoked
+func (v *TrustVerifier) verify(ctx context.Context, credential models.Credential, agentDID, name string) error {
+	valid, err := v.claims.VerifyCredentialContext(ctx, credential)
+	if err != nil {
+		return fmt.Errorf("%s could not be verified: %w", name, err)
+	}
+	if !valid {
+		return fmt.Errorf("%s has an invalid signature", name)
+	}
+	if v.revocations == nil {
+		return nil
+	}
+	credentialID, err := credential.CredentialID()
+	if err != nil {
+		return err
+	}
+	revocation, err := v.revocations.CheckRevocation(ctx, credentialID, agentDID)
+	if err != nil {
+		return fmt.Errorf("revocation status of %s is unavailable: %w", name, err)
+	}
+	if revocation != nil {
+		return fmt.Errorf("%s was revoked: %s", name, revocation.Reason)
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package trust
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ak68a/agentid-core/pkg/capability"
+	"github.com/ak68a/agentid-core/pkg/chain"
+)
+
+// Policy raises the requirements for the requests it applies to, e.g.
+// requiring Certified agents for transfers above an amount:
+//
+//	Policy{
+//		Name:       "large transfers",
+//		Capability: &capability.Capability{Action: "transfer", Scope: "*"},
+//		Above:      big.NewInt(10000),
+//		MinLevel:   chain.Certified,
+//	}
+type Policy struct {
+	Name       string                 `json:"name"`
+	Capability *capability.Capability `json:"capability,omitempty"` // Applies to requests for a capability it covers, or to all if nil
+	Above      *big.Int               `json:"above,omitempty"`      // Applies only to amounts above this, or to any if nil
+	MinLevel   chain.TrustLevel       `json:"min_level"`
+	MinScore   float64                `json:"min_score,omitempty"`
+}
+
+// Applies reports whether the policy applies to a request
+func (p Policy) Applies(req *Request) bool {
+	if p.Above != nil && (req.Amount == nil || req.Amount.Cmp(p.Above) <= 0) {
+		return false
+	}
+	if p.Capability == nil {
+		return true
+	}
+	for _, want := range req.Capabilities {
+		if p.Capability.Covers(want) {
+			return true
+		}
+	}
+	return false
+}
+
+// check returns why an assessment falls short of the policy, or "" if it does not
+func (p Policy) check(a *TrustAssessment) string {
+	if a.TrustLevel < p.MinLevel {
+		return fmt.Sprintf("policy %q requires %s, agent is %s", p.Name, p.MinLevel, a.TrustLevel)
+	}
+	if a.Score < p.MinScore {
+		return fmt.Sprintf("policy %q requires a score of %.2f, agent has %.2f", p.Name, p.MinScore, a.Score)
+	}
+	return ""
+}
+
+// Reputation scores the issuers of credentials from 0, untrusted, to 1
+type Reputation interface {
+	// IssuerScore returns the score of an issuer, or false if it has none
+	IssuerScore(ctx context.Context, issuerDID string) (float64, bool, error)
+}
+
+// Reputations is a fixed Reputation keyed by issuer DID
+type Reputations map[string]float64
+
+// IssuerScore returns the issuer's score from the map
+func (r Reputations) IssuerScore(_ context.Context, issuerDID string) (float64, bool, error) {
+	score, ok := r[issuerDID]
+	return score, ok, nil
+}
@@ -0,0 +1,435 @@
+// Package trust assesses how far an agent can be trusted by combining its
+// standing in the AgentRegistry with the credentials it presents, as described
+// in docs/trust-framework.md.
+package trust
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/ak68a/agentid-core/pkg/authz"
+	"github.com/ak68a/agentid-core/pkg/capability"
+	"github.com/ak68a/agentid-core/pkg/chain"
+	"github.com/ak68a/agentid-core/pkg/did"
+	"github.com/ak68a/agentid-core/pkg/models"
+	"github.com/ak68a/agentid-core/pkg/signer"
+)
+
+// ErrUnsupportedChain is returned for a chain the verifier has no client for
+var ErrUnsupportedChain = errors.New("unsupported chain")
+
+// Weights of the components of an assessment's score, which add up to 1
+const (
+	LevelWeight      = 0.4 // On-chain trust level, relative to CrossChain
+	FreshnessWeight  = 0.2 // Time left before the on-chain verification expires
+	CredentialWeight = 0.2 // Whether the agent presented verified credentials from accepted issuers
+	ReputationWeight = 0.2 // Mean reputation of the accepted issuers
+)
+
+// DefaultMaxVerificationAge is how long an on-chain verification lasts at each
+// trust level unless WithMaxVerificationAge is given
+var DefaultMaxVerificationAge = map[chain.TrustLevel]time.Duration{
+	chain.Verified:   90 * 24 * time.Hour,
+	chain.Certified:  365 * 24 * time.Hour,
+	chain.CrossChain: 2 * 365 * 24 * time.Hour,
+}
+
+// Identity is an agent and the credentials it presents
+type Identity struct {
+	DID         string
+	Credentials *authz.Credentials
+}
+
+// Request is what an agent is to be trusted with
+type Request struct {
+	Agent         *Identity
+	RequiredLevel chain.TrustLevel        // Minimum trust level, on top of the verifier's and its policies'
+	Capabilities  []capability.Capability // Capabilities the agent must hold
+	ChainID       uint64                  // Chain to read the agent's standing from, 0 if the verifier has only one
+	Amount        *big.Int                // Amount at stake, nil if none
+}
+
+// TrustAssessment is the outcome of VerifyTrust. The same request, chain state
+// and clock always produce the same assessment.
+type TrustAssessment struct {
+	AgentID       string                  `json:"agent_id"`
+	Trusted       bool                    `json:"trusted"`
+	Reason        string                  `json:"reason,omitempty"` // Why the agent is not trusted
+	TrustLevel    chain.TrustLevel        `json:"trust_level"`      // On-chain level, Unverified once its verification expires
+	RequiredLevel chain.TrustLevel        `json:"required_level"`
+	Capabilities  []capability.Capability `json:"capabilities"` // Granted by the credentials or found on-chain
+	ValidFrom     time.Time               `json:"valid_from"`
+	ValidUntil    time.Time               `json:"valid_until"` // Zero if nothing expires
+	ChainID       uint64                  `json:"chain_id"`
+	Block         uint64                  `json:"block"`
+	Score         float64                 `json:"score"` // From 0 to 1, see the weights
+	Warnings      []string                `json:"warnings,omitempty"`
+}
+
+func (a *TrustAssessment) reject(format string, args ...interface{}) {
+	// The first reason is kept, as later checks often follow from it
+	if a.Reason == "" {
+		a.Reason = fmt.Sprintf(format, args...)
+	}
+}
+
+func (a *TrustAssessment) warn(format string, args ...interface{}) {
+	a.Warnings = append(a.Warnings, fmt.Sprintf(format, args...))
+}
+
+// TrustVerifier assesses agents against its requirements and policies
+type TrustVerifier struct {
+	chains        map[uint64]*chain.Client
+	claims        *signer.ClaimSigner
+	signerOpts    []signer.Option
+	revocations   models.RevocationChecker
+	reputation    Reputation
+	trusted       map[string]bool
+	minTrustLevel chain.TrustLevel
+	required      []capability.Capability
+	policies      []Policy
+	maxAge        map[chain.TrustLevel]time.Duration
+	now           func() time.Time
+}
+
+// Option configures a TrustVerifier
+type Option func(*TrustVerifier)
+
+// WithChain reads the standing of agents on chainID through client
+func WithChain(chainID uint64, client *chain.Client) Option {
+	return func(v *TrustVerifier) {
+		v.chains[chainID] = client
+	}
+}
+
+// WithResolver sets the DID resolver used to verify credential signatures
+func WithResolver(resolver did.Resolver) Option {
+	return func(v *TrustVerifier) {
+		v.signerOpts = append(v.signerOpts, signer.WithResolver(resolver))
+	}
+}
+
+// WithRevocationChecker makes revoked agents and credentials untrusted
+func WithRevocationChecker(checker models.RevocationChecker) Option {
+	return func(v *TrustVerifier) {
+		v.revocations = checker
+		v.signerOpts = append(v.signerOpts, signer.WithRevocationChecker(checker))
+	}
+}
+
+// WithReputation sets the source of issuer reputations. Credentials only count
+// if their issuer has a reputation or is trusted.
+func WithReputation(reputation Reputation) Option {
+	return func(v *TrustVerifier) {
+		v.reputation = reputation
+	}
+}
+
+// WithTrustedIssuers accepts credentials from the given DIDs even if they have no reputation
+func WithTrustedIssuers(dids ...string) Option {
+	return func(v *TrustVerifier) {
+		for _, issuer := range dids {
+			v.trusted[issuer] = true
+		}
+	}
+}
+
+// WithMinTrustLevel sets the trust level every agent must have
+func WithMinTrustLevel(level chain.TrustLevel) Option {
+	return func(v *TrustVerifier) {
+		v.minTrustLevel = level
+	}
+}
+
+// WithRequiredCapabilities sets capabilities every agent must hold
+func WithRequiredCapabilities(capabilities ...capability.Capability) Option {
+	return func(v *TrustVerifier) {
+		v.required = append(v.required, capabilities...)
+	}
+}
+
+// WithPolicies adds policies requests are checked against
+func WithPolicies(policies ...Policy) Option {
+	return func(v *TrustVerifier) {
+		v.policies = append(v.policies, policies...)
+	}
+}
+
+// WithMaxVerificationAge sets how long an on-chain verification at level lasts. Zero means forever.
+func WithMaxVerificationAge(level chain.TrustLevel, age time.Duration) Option {
+	return func(v *TrustVerifier) {
+		v.maxAge[level] = age
+	}
+}
+
+// WithClock sets the time source used for expiry checks
+func WithClock(now func() time.Time) Option {
+	return func(v *TrustVerifier) {
+		v.now = now
+	}
+}
+
+// NewTrustVerifier creates a TrustVerifier. It needs at least one WithChain to assess agents.
+func NewTrustVerifier(opts ...Option) *TrustVerifier {
+	v := &TrustVerifier{
+		chains:  make(map[uint64]*chain.Client),
+		trusted: make(map[string]bool),
+		maxAge:  make(map[chain.TrustLevel]time.Duration),
+		now:     time.Now,
+	}
+	for level, age := range DefaultMaxVerificationAge {
+		v.maxAge[level] = age
+	}
+	for _, opt := range opts {
+		opt(v)
+	}
+	v.claims = signer.NewClaimSigner(nil, v.signerOpts...)
+	return v
+}
+
+// VerifyTrust assesses an agent at the latest block of the request's chain.
+// An agent that falls short of a requirement or policy gets an assessment with
+// Trusted false and a Reason; an error is only returned if the assessment
+// cannot be made.
+func (v *TrustVerifier) VerifyTrust(ctx context.Context, req *Request) (*TrustAssessment, error) {
+	if req == nil || req.Agent == nil || req.Agent.DID == "" {
+		return nil, fmt.Errorf("trust request has no agent")
+	}
+	chainID, client, err := v.chain(req.ChainID)
+	if err != nil {
+		return nil, err
+	}
+	head, err := client.Backend().HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the latest block: %w", err)
+	}
+	// Every read is made at the same block so the assessment is consistent
+	at := client.At(head.Number)
+	now := v.now()
+	agentDID := req.Agent.DID
+	a := &TrustAssessment{AgentID: agentDID, ChainID: chainID, Block: head.Number.Uint64()}
+
+	agent, err := at.FindAgent(ctx, agentDID)
+	if err != nil {
+		return nil, err
+	}
+	registered := agent.DID == agentDID && agent.Active
+	revoked := false
+	switch {
+	case agent.DID != "" && agent.DID != agentDID:
+		a.reject("address %s is registered as %s", agent.Address.Hex(), agent.DID)
+	case agent.DID != "" && !agent.Active:
+		a.reject("agent was revoked on chain %d", chainID)
+		revoked = true
+	case agent.DID == "":
+		a.warn("agent is not registered on chain %d", chainID)
+	}
+
+	var freshness float64
+	if registered {
+		a.TrustLevel, freshness = v.standing(a, agent, now)
+	}
+
+	if v.revocations != nil {
+		// An empty credential ID asks for revocations of the agent as a whole
+		revocation, err := v.revocations.CheckRevocation(ctx, "", agentDID)
+		if err != nil {
+			a.reject("revocation status of the agent is unavailable: %v", err)
+		} else if revocation != nil && revocation.RevokedAgentDID == agentDID && revocation.RevokedCredentialID == "" {
+			a.reject("agent was revoked: %s", revocation.Reason)
+			revoked = true
+		}
+	}
+
+	g, err := v.verifyCredentials(ctx, agentDID, req.Agent.Credentials, now.Unix())
+	if err != nil {
+		a.reject("%v", err)
+		g = &grant{}
+	}
+	// Anyone can issue credentials, so only those of accepted issuers count
+	held, issuers, reputation, err := v.acceptedCredentials(ctx, a, agentDID, g)
+	if err != nil {
+		return nil, err
+	}
+	if g.validUntil != 0 {
+		a.ValidUntil = earliest(a.ValidUntil, time.Unix(g.validUntil, 0))
+	}
+
+	// Capabilities not granted by the credentials may have been granted on-chain
+	for _, want := range append(append([]capability.Capability(nil), v.required...), req.Capabilities...) {
+		if covered(held, want) {
+			continue
+		}
+		ok := false
+		if registered {
+			if ok, err = at.HasCapability(ctx, agent.Address, want); err != nil {
+				return nil, err
+			}
+		}
+		if !ok {
+			a.reject("agent does not hold %s", want)
+			continue
+		}
+		held = append(held, want)
+	}
+	a.Capabilities = unique(held)
+
+	if !revoked {
+		a.Score = LevelWeight*float64(a.TrustLevel)/float64(chain.CrossChain) + FreshnessWeight*freshness + ReputationWeight*reputation
+		if issuers > 0 {
+			a.Score += CredentialWeight
+		}
+	}
+
+	a.RequiredLevel = req.RequiredLevel
+	if v.minTrustLevel > a.RequiredLevel {
+		a.RequiredLevel = v.minTrustLevel
+	}
+	if a.TrustLevel < a.RequiredLevel {
+		a.reject("trust level %s is below the required %s", a.TrustLevel, a.RequiredLevel)
+	}
+	for _, policy := range v.policies {
+		if !policy.Applies(req) {
+			continue
+		}
+		if policy.MinLevel > a.RequiredLevel {
+			a.RequiredLevel = policy.MinLevel
+		}
+		if reason := policy.check(a); reason != "" {
+			a.reject("%s", reason)
+		}
+	}
+
+	a.Trusted = a.Reason == ""
+	return a, nil
+}
+
+// chain returns the client of a chain, or of the only chain if chainID is 0
+func (v *TrustVerifier) chain(chainID uint64) (uint64, *chain.Client, error) {
+	if chainID == 0 && len(v.chains) == 1 {
+		for id, client := range v.chains {
+			return id, client, nil
+		}
+	}
+	client, ok := v.chains[chainID]
+	if !ok {
+		return 0, nil, fmt.Errorf("%w: %d", ErrUnsupportedChain, chainID)
+	}
+	return chainID, client, nil
+}
+
+// standing returns the trust level of a registered agent and how fresh its
+// verification is, from 1 when just verified to 0 when it expires. An expired
+// verification leaves the agent Unverified.
+func (v *TrustVerifier) standing(a *TrustAssessment, agent *chain.Agent, now time.Time) (chain.TrustLevel, float64) {
+	if agent.TrustLevel == chain.Unverified || agent.LastVerifiedAt.Unix() == 0 {
+		return chain.Unverified, 0
+	}
+	a.ValidFrom = agent.LastVerifiedAt
+	maxAge := v.maxAge[agent.TrustLevel]
+	if maxAge <= 0 {
+		return agent.TrustLevel, 1
+	}
+	expires := agent.LastVerifiedAt.Add(maxAge)
+	a.ValidUntil = expires
+	if now.After(expires) {
+		a.warn("verification at %s expired at %s", agent.TrustLevel, expires.UTC().Format(time.RFC3339))
+		return chain.Unverified, 0
+	}
+	// Blocks may be timestamped slightly ahead of the verifier's clock
+	freshness := float64(expires.Sub(now)) / float64(maxAge)
+	if freshness > 1 {
+		freshness = 1
+	}
+	return agent.TrustLevel, freshness
+}
+
+// acceptedCredentials returns the capabilities granted by accepted issuers,
+// how many there are and their mean reputation. Issuers are accepted if they
+// are trusted or have a reputation, and are not the agent itself.
+func (v *TrustVerifier) acceptedCredentials(ctx context.Context, a *TrustAssessment, agentDID string, g *grant) ([]capability.Capability, int, float64, error) {
+	issuers := make([]string, 0, len(g.issued))
+	for issuer := range g.issued {
+		issuers = append(issuers, issuer)
+	}
+	sort.Strings(issuers)
+
+	var (
+		held     []capability.Capability
+		accepted int
+		total    float64
+	)
+	for _, issuer := range issuers {
+		if issuer == agentDID {
+			a.warn("credentials issued by the agent itself are ignored")
+			continue
+		}
+		score, known := 0.0, false
+		if v.reputation != nil {
+			var err error
+			if score, known, err = v.reputation.IssuerScore(ctx, issuer); err != nil {
+				return nil, 0, 0, fmt.Errorf("failed to get the reputation of %s: %w", issuer, err)
+			}
+		}
+		switch {
+		case !known && !v.trusted[issuer]:
+			a.warn("credentials of %s are ignored, it is not trusted and has no reputation", issuer)
+			continue
+		case !known:
+			a.warn("issuer %s has no reputation", issuer)
+		}
+		held = append(held, g.issued[issuer]...)
+		accepted++
+		total += clamp(score)
+	}
+	if accepted == 0 {
+		return held, 0, 0, nil
+	}
+	return held, accepted, total / float64(accepted), nil
+}
+
+// covered reports whether any of the granted capabilities covers want
+func covered(granted []capability.Capability, want capability.Capability) bool {
+	for _, g := range granted {
+		if g.Covers(want) {
+			return true
+		}
+	}
+	return false
+}
+
+// unique returns capabilities without duplicates, sorted
+func unique(capabilities []capability.Capability) []capability.Capability {
+	seen := make(map[string]bool)
+	result := []capability.Capability{}
+	for _, c := range capabilities {
+		if !seen[c.String()] {
+			seen[c.String()] = true
+			result = append(result, c)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].String() < result[j].String() })
+	return result
+}
+
+// earliest returns the earlier of two times, where the zero time means never
+func earliest(a, b time.Time) time.Time {
+	if a.IsZero() || (!b.IsZero() && b.Before(a)) {
+		return b
+	}
+	return a
+}
+
+func clamp(score float64) float64 {
+	if score < 0 {
+		return 0
+	}
+	if score > 1 {
+		return 1
+	}
+	return score
+}
@@ -0,0 +1,235 @@
+package trust
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ak68a/agentid-core/pkg/authz"
+	"github.com/ak68a/agentid-core/pkg/capability"
+	"github.com/ak68a/agentid-core/pkg/chain"
+	"github.com/ak68a/agentid-core/pkg/chain/chaintest"
+	"github.com/ak68a/agentid-core/pkg/key"
+	"github.com/ak68a/agentid-core/pkg/models"
+	"github.com/ak68a/agentid-core/pkg/signer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var transferETH = capability.Capability{Action: "transfer", Scope: "ETH"}
+
+// authorization returns owner's signed authorization of agent for a capability
+func authorization(t *testing.T, owner, agent *key.AgentKey, granted capability.Capability, expiresAt time.Time) *authz.Credentials {
+	claim := models.NewAgentClaim(agent.DID, owner.DID, granted.Action, granted.Scope, expiresAt.Unix(), "claim-1")
+	require.NoError(t, signer.NewClaimSigner(owner).SignCredential(claim))
+	return &authz.Credentials{Authorization: claim}
+}
+
+func TestVerifyTrust(t *testing.T) {
+	ctx := context.Background()
+	owner, agent := chaintest.NewKey(t), chaintest.NewKey(t)
+	tc := chaintest.New(t, agent)
+	tc.Register(agent, chain.Certified)
+	expiresAt := time.Now().Add(time.Hour)
+	creds := authorization(t, owner, agent, capability.Capability{Action: "transfer", Scope: "*"}, expiresAt)
+
+	verifier := NewTrustVerifier(
+		WithChain(tc.ChainID.Uint64(), tc.Owner),
+		WithReputation(Reputations{owner.DID: 1}),
+		WithMinTrustLevel(chain.Verified),
+		WithPolicies(Policy{
+			Name:       "large transfers",
+			Capability: &capability.Capability{Action: "transfer", Scope: "*"},
+			Above:      big.NewInt(10000),
+			MinLevel:   chain.CrossChain,
+		}),
+	)
+	req := &Request{
+		Agent:        &Identity{DID: agent.DID, Credentials: creds},
+		Capabilities: []capability.Capability{transferETH},
+		Amount:       big.NewInt(500),
+	}
+	assessment, err := verifier.VerifyTrust(ctx, req)
+	require.NoError(t, err)
+	assert.True(t, assessment.Trusted, assessment.Reason)
+	assert.Equal(t, agent.DID, assessment.AgentID)
+	assert.Equal(t, chain.Certified, assessment.TrustLevel)
+	assert.Equal(t, chain.Verified, assessment.RequiredLevel)
+	assert.Equal(t, tc.ChainID.Uint64(), assessment.ChainID)
+	assert.Equal(t, []capability.Capability{{Action: "transfer", Scope: "*"}}, assessment.Capabilities)
+	assert.Equal(t, expiresAt.Unix(), assessment.ValidUntil.Unix())
+	assert.False(t, assessment.ValidFrom.IsZero())
+	assert.InDelta(t, LevelWeight*2/3+FreshnessWeight+CredentialWeight+ReputationWeight, assessment.Score, 0.001)
+	assert.Empty(t, assessment.Warnings)
+
+	// The assessment only depends on the chain state and the clock
+	again, err := NewTrustVerifier(
+		WithChain(tc.ChainID.Uint64(), tc.Owner),
+		WithReputation(Reputations{owner.DID: 1}),
+		WithClock(func() time.Time { return assessment.ValidFrom }),
+	).VerifyTrust(ctx, req)
+	require.NoError(t, err)
+	assert.InDelta(t, assessment.Score, again.Score, 0.001)
+	assert.Equal(t, assessment.Block, again.Block)
+
+	// Larger transfers require a higher trust level
+	req.Amount = big.NewInt(20000)
+	assessment, err = verifier.VerifyTrust(ctx, req)
+	require.NoError(t, err)
+	assert.False(t, assessment.Trusted)
+	assert.Equal(t, chain.CrossChain, assessment.RequiredLevel)
+	assert.Equal(t, `policy "large transfers" requires CrossChain, agent is Certified`, assessment.Reason)
+
+	// Capabilities not granted by the credentials must be held on-chain
+	req.Amount = nil
+	req.Capabilities = []capability.Capability{{Action: "booking", Scope: "flights"}}
+	assessment, err = verifier.VerifyTrust(ctx, req)
+	require.NoError(t, err)
+	assert.False(t, assessment.Trusted)
+	assert.Equal(t, "agent does not hold booking:flights", assessment.Reason)
+
+	_, err = verifier.VerifyTrust(ctx, &Request{Agent: req.Agent, ChainID: tc.ChainID.Uint64() + 1})
+	assert.ErrorIs(t, err, ErrUnsupportedChain)
+}
+
+func TestVerifyTrustOnChainCapabilities(t *testing.T) {
+	ctx := context.Background()
+	agent := chaintest.NewKey(t)
+	tc := chaintest.New(t, agent)
+	verifier := NewTrustVerifier(WithChain(tc.ChainID.Uint64(), tc.Owner), WithRequiredCapabilities(transferETH))
+	req := &Request{Agent: &Identity{DID: agent.DID}}
+
+	assessment, err := verifier.VerifyTrust(ctx, req)
+	require.NoError(t, err)
+	assert.False(t, assessment.Trusted)
+	assert.Equal(t, chain.Unverified, assessment.TrustLevel)
+	assert.Equal(t, 0.0, assessment.Score)
+	assert.Contains(t, assessment.Warnings, "agent is not registered on chain 1337")
+
+	tc.Register(agent, chain.Verified, transferETH)
+	assessment, err = verifier.VerifyTrust(ctx, req)
+	require.NoError(t, err)
+	assert.True(t, assessment.Trusted, assessment.Reason)
+	assert.Equal(t, []capability.Capability{transferETH}, assessment.Capabilities)
+	// Without credentials, only the on-chain standing counts
+	assert.InDelta(t, LevelWeight/3+FreshnessWeight, assessment.Score, 0.001)
+
+	// Verifications expire, leaving the agent Unverified
+	later := func() time.Time { return time.Now().Add(2 * time.Hour) }
+	assessment, err = NewTrustVerifier(WithChain(tc.ChainID.Uint64(), tc.Owner), WithMaxVerificationAge(chain.Verified, time.Hour), WithClock(later), WithMinTrustLevel(chain.Verified)).VerifyTrust(ctx, req)
+	require.NoError(t, err)
+	assert.False(t, assessment.Trusted)
+	assert.Equal(t, chain.Unverified, assessment.TrustLevel)
+	assert.Equal(t, "trust level Unverified is below the required Verified", assessment.Reason)
+	require.Len(t, assessment.Warnings, 1)
+	assert.Contains(t, assessment.Warnings[0], "verification at Verified expired")
+}
+
+func TestVerifyTrustRevocation(t *testing.T) {
+	ctx := context.Background()
+	owner, agent := chaintest.NewKey(t), chaintest.NewKey(t)
+	tc := chaintest.New(t, agent)
+	tc.Register(agent, chain.Verified)
+	creds := authorization(t, owner, agent, transferETH, time.Now().Add(time.Hour))
+	credentialID, err := creds.Authorization.CredentialID()
+	require.NoError(t, err)
+
+	// Each list also revokes another agent as a whole, which must not affect this one
+	revoked := func(credentialID string) authz.RevocationLists {
+		return authz.RevocationLists{{Revocations: []*models.RevocationClaim{{
+			RevokedAgentDID: chaintest.NewKey(t).DID,
+			Reason:          "retired",
+			RevokedAt:       time.Now().Add(-time.Minute).Unix(),
+		}, {
+			RevokedCredentialID: credentialID,
+			RevokedAgentDID:     agent.DID,
+			Reason:              "compromised",
+			RevokedAt:           time.Now().Add(-time.Minute).Unix(),
+		}}}}
+	}
+	tests := []struct {
+		name   string
+		opts   []Option
+		reason string
+	}{
+		{"credential", []Option{WithRevocationChecker(revoked(credentialID))}, "authorization was revoked: compromised"},
+		{"agent", []Option{WithRevocationChecker(revoked(""))}, "agent was revoked: compromised"},
+		{"other agent", []Option{WithRevocationChecker(authz.RevocationLists{{Revocations: revoked("")[0].Revocations[:1]}})}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verifier := NewTrustVerifier(append(tt.opts, WithChain(tc.ChainID.Uint64(), tc.Owner))...)
+			assessment, err := verifier.VerifyTrust(ctx, &Request{Agent: &Identity{DID: agent.DID, Credentials: creds}})
+			require.NoError(t, err)
+			assert.Equal(t, tt.reason, assessment.Reason)
+			assert.Equal(t, tt.reason == "", assessment.Trusted)
+		})
+	}
+
+	// An agent revoked on-chain scores nothing
+	tc.Send(tc.Owner.RevokeAgent(ctx, chaintest.Address(agent), "compromised"))
+	assessment, err := NewTrustVerifier(WithChain(tc.ChainID.Uint64(), tc.Owner)).VerifyTrust(ctx, &Request{Agent: &Identity{DID: agent.DID, Credentials: creds}})
+	require.NoError(t, err)
+	assert.False(t, assessment.Trusted)
+	assert.Equal(t, "agent was revoked on chain 1337", assessment.Reason)
+	assert.Equal(t, 0.0, assessment.Score)
+}
+
+func TestVerifyTrustIssuers(t *testing.T) {
+	ctx := context.Background()
+	owner, agent := chaintest.NewKey(t), chaintest.NewKey(t)
+	tc := chaintest.New(t, agent)
+	tc.Register(agent, chain.Verified)
+	req := &Request{Agent: &Identity{DID: agent.DID}, Capabilities: []capability.Capability{transferETH}}
+
+	tests := []struct {
+		name    string
+		issuer  *key.AgentKey
+		opts    []Option
+		trusted bool
+		warning string
+	}{
+		{"self-issued", agent, []Option{WithTrustedIssuers(agent.DID), WithReputation(Reputations{agent.DID: 1})}, false, "issued by the agent itself"},
+		{"unknown issuer", owner, []Option{WithReputation(Reputations{})}, false, "not trusted and has no reputation"},
+		{"trusted issuer", owner, []Option{WithTrustedIssuers(owner.DID)}, true, "has no reputation"},
+		{"issuer with a reputation", owner, []Option{WithReputation(Reputations{owner.DID: 0.5})}, true, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req.Agent.Credentials = authorization(t, tt.issuer, agent, transferETH, time.Now().Add(time.Hour))
+			assessment, err := NewTrustVerifier(append(tt.opts, WithChain(tc.ChainID.Uint64(), tc.Owner))...).VerifyTrust(ctx, req)
+			require.NoError(t, err)
+			assert.Equal(t, tt.trusted, assessment.Trusted, assessment.Reason)
+			if tt.warning != "" {
+				require.Len(t, assessment.Warnings, 1)
+				assert.Contains(t, assessment.Warnings[0], tt.warning)
+			} else {
+				assert.Empty(t, assessment.Warnings)
+			}
+			if !tt.trusted {
+				// Ignored credentials add nothing to the score
+				assert.Equal(t, "agent does not hold transfer:ETH", assessment.Reason)
+				assert.InDelta(t, LevelWeight/3+FreshnessWeight, assessment.Score, 0.001)
+			}
+		})
+	}
+}
+
+func TestPolicyApplies(t *testing.T) {
+	policy := Policy{Capability: &capability.Capability{Action: "transfer", Scope: "*"}, Above: big.NewInt(100)}
+	tests := []struct {
+		capabilities []capability.Capability
+		amount       *big.Int
+		applies      bool
+	}{
+		{[]capability.Capability{transferETH}, big.NewInt(101), true},
+		{[]capability.Capability{transferETH}, big.NewInt(100), false},
+		{[]capability.Capability{transferETH}, nil, false},
+		{[]capability.Capability{{Action: "booking", Scope: "flights"}}, big.NewInt(101), false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.applies, policy.Applies(&Request{Capabilities: tt.capabilities, Amount: tt.amount}))
+	}
+	assert.True(t, Policy{}.Applies(&Request{}))
+}